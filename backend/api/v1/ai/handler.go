@@ -14,6 +14,8 @@ import (
 	"rag-searchbot-backend/internal/llm_types"
 	"rag-searchbot-backend/internal/models"
 	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/series"
+	"rag-searchbot-backend/pkg/errs"
 	"rag-searchbot-backend/pkg/ginctx"
 	"rag-searchbot-backend/pkg/response"
 	"rag-searchbot-backend/pkg/tiptap"
@@ -37,12 +39,15 @@ type AIHandler struct {
 	agentAgentToolWebSearchService ai.AgentToolWebSearch
 	llmClient                      llm.LLM
 	publicSearchLimiter            *publicSearchRateLimiter
+	seriesService                  series.ServiceInterface
+	postAccess                     *post.PostAccess
 }
 
 func NewAIHandler(aiService *ai.AIService,
 	agentIntentClassifierService ai.AgentIntentClassifierServiceInterface,
 	posRepo post.PostRepositoryInterface, logger *zap.Logger,
-	agentAgentToolWebSearchService ai.AgentToolWebSearch, llmClient llm.LLM) *AIHandler {
+	agentAgentToolWebSearchService ai.AgentToolWebSearch, llmClient llm.LLM,
+	seriesService series.ServiceInterface, postAccess *post.PostAccess) *AIHandler {
 	return &AIHandler{
 		AIService:                      aiService,
 		PosRepo:                        posRepo,
@@ -51,6 +56,8 @@ func NewAIHandler(aiService *ai.AIService,
 		agentAgentToolWebSearchService: agentAgentToolWebSearchService,
 		llmClient:                      llmClient,
 		publicSearchLimiter:            newPublicSearchRateLimiter(),
+		seriesService:                  seriesService,
+		postAccess:                     postAccess,
 	}
 }
 
@@ -123,7 +130,8 @@ func (a *AIHandler) DisableOpenAIMode(c *gin.Context) {
 }
 
 type ChatRequestDTO struct {
-	Prompt string `json:"prompt"`
+	Prompt       string `json:"prompt"`
+	SearchSeries bool   `json:"search_series"` // ค้น context จากทุกตอนใน series เดียวกัน
 }
 
 type AskRequest struct {
@@ -176,7 +184,7 @@ func (a *AIHandler) Chat(c *gin.Context) {
 		return
 	}
 
-	post, err := a.validatePost(c, postID)
+	post, err := a.validatePost(c, postID, user)
 
 	a.logger.Info("Post validated for AI chat",
 		zap.String("post_id", postID),
@@ -291,7 +299,8 @@ func (a *AIHandler) Chat(c *gin.Context) {
 		config := a.getRAGConfig()
 
 		// 5. Process RAG pipeline
-		context, err := a.processRAGPipeline(c, postID, req.Prompt, config)
+		postIDs := a.retrievalPostIDs(post, req.SearchSeries)
+		context, err := a.processRAGPipeline(c, postIDs, req.Prompt, config)
 		if err != nil {
 			return // Error already handled in processRAGPipeline
 		}
@@ -358,7 +367,7 @@ func (a *AIHandler) validateChatRequest(c *gin.Context) (*ChatRequestDTO, string
 	return &req, postID, user, nil
 }
 
-func (a *AIHandler) validatePost(c *gin.Context, postID string) (*models.Post, error) {
+func (a *AIHandler) validatePost(c *gin.Context, postID string, user *models.User) (*models.Post, error) {
 	post, err := a.PosRepo.GetByID(postID)
 	if err != nil {
 		a.logger.Error("Error fetching post", zap.Error(err))
@@ -372,9 +381,25 @@ func (a *AIHandler) validatePost(c *gin.Context, postID string) (*models.Post, e
 		return nil, fmt.Errorf("post not available")
 	}
 
+	// โพสต์ protected ต้องปลดล็อกก่อนเหมือนหน้าอ่านโพสต์
+	if !a.canReadPost(c, post, user) {
+		a.logger.Warn("Protected post is locked for AI chat", zap.String("post_id", postID))
+		a.writeErrorEvent(c, "This post is protected")
+		return nil, errs.ErrPostProtected
+	}
+
 	return post, nil
 }
 
+// canReadPost ตรวจ visibility ของโพสต์ด้วย cookie ที่ได้จากการปลดล็อก
+func (a *AIHandler) canReadPost(c *gin.Context, p *models.Post, user *models.User) bool {
+	if a.postAccess == nil {
+		return p.Visibility != models.VisibilityProtected
+	}
+	token, _ := c.Cookie(post.PostAccessCookieName(p.ID.String()))
+	return a.postAccess.CanRead(p, user, token)
+}
+
 func (a *AIHandler) setupStreamingHeaders(c *gin.Context) {
 	origin := c.Request.Header.Get("Origin")
 
@@ -440,8 +465,22 @@ func (a *AIHandler) getRAGConfig() RAGConfig {
 	return config
 }
 
-func (a *AIHandler) processRAGPipeline(c *gin.Context, postID, question string, config RAGConfig) (string, error) {
-	a.logger.Info("Processing RAG pipeline", zap.String("question", question))
+// retrievalPostIDs โพสต์ที่ใช้หา context ถ้าขอ search_series จะรวมทุกตอนใน series ที่เปิด AI chat ไว้
+func (a *AIHandler) retrievalPostIDs(post *models.Post, searchSeries bool) []string {
+	if !searchSeries || a.seriesService == nil {
+		return []string{post.ID.String()}
+	}
+
+	postIDs, err := a.seriesService.GetSeriesPostIDs(post.ID)
+	if err != nil {
+		a.logger.Warn("Failed to load series posts, falling back to current post", zap.String("post_id", post.ID.String()), zap.Error(err))
+		return []string{post.ID.String()}
+	}
+	return postIDs
+}
+
+func (a *AIHandler) processRAGPipeline(c *gin.Context, postIDs []string, question string, config RAGConfig) (string, error) {
+	a.logger.Info("Processing RAG pipeline", zap.String("question", question), zap.Strings("post_ids", postIDs))
 
 	allScoredChunks := []ScoredChunk{}
 	phrases := SplitQuestionToPhrases(question)
@@ -462,18 +501,21 @@ func (a *AIHandler) processRAGPipeline(c *gin.Context, postID, question string,
 			embedding[i] = float64(v)
 		}
 
-		scoredChunks, err := a.retrieveAndScoreChunks(postID, embedding)
-		if err != nil {
-			a.logger.Warn("Chunk scoring failed for phrase", zap.String("phrase", phrase), zap.Error(err))
-			continue
-		}
+		for _, postID := range postIDs {
+			scoredChunks, err := a.retrieveAndScoreChunks(postID, embedding)
+			if err != nil {
+				a.logger.Warn("Chunk scoring failed for phrase", zap.String("phrase", phrase), zap.String("post_id", postID), zap.Error(err))
+				continue
+			}
 
-		// log the number of chunks scored
-		a.logger.Debug("Scored chunks retrieved",
-			zap.String("phrase", phrase),
-			zap.Int("chunk_count", len(scoredChunks)))
+			// log the number of chunks scored
+			a.logger.Debug("Scored chunks retrieved",
+				zap.String("phrase", phrase),
+				zap.String("post_id", postID),
+				zap.Int("chunk_count", len(scoredChunks)))
 
-		allScoredChunks = append(allScoredChunks, scoredChunks...)
+			allScoredChunks = append(allScoredChunks, scoredChunks...)
+		}
 	}
 
 	if len(allScoredChunks) == 0 {
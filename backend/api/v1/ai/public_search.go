@@ -6,6 +6,7 @@ import (
 	"net/http"
 	internalAI "rag-searchbot-backend/internal/ai"
 	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/ginctx"
 	"strings"
 	"sync"
 	"time"
@@ -109,6 +110,11 @@ func (a *AIHandler) WebSearch(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "post not available for AI search"})
 		return
 	}
+	user, _ := ginctx.GetUserFromContext(c)
+	if !a.canReadPost(c, post, user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "post is protected"})
+		return
+	}
 
 	result, err := a.agentAgentToolWebSearchService.SearchExternalWeb(query)
 	if err != nil {
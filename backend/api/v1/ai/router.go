@@ -7,6 +7,9 @@ import (
 	"rag-searchbot-backend/internal/llm"
 	"rag-searchbot-backend/internal/middleware"
 	"rag-searchbot-backend/internal/notification"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/related"
+	"rag-searchbot-backend/internal/series"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hibiken/asynq"
@@ -28,7 +31,9 @@ func RegisterRoutes(router *gin.RouterGroup, container *container.Container, mux
 	aiContentClassifier := ai.NewAgentIntentClassifier(container.Log, postRepo, llmClient)
 	aiService := ai.NewAIService(postRepo, aiTaskEnqueuer, aiRepo, aiContentClassifier, llmClient)
 	agentToolWebSearch := ai.NewAgentToolWebSearchService(container.Log, postRepo, container.Env)
-	handler := NewAIHandler(aiService, aiContentClassifier, postRepo, container.Log, agentToolWebSearch, llmClient)
+	seriesService := series.NewService(series.NewRepository(container.DB), container.UserService)
+	handler := NewAIHandler(aiService, aiContentClassifier, postRepo, container.Log, agentToolWebSearch, llmClient, seriesService,
+		post.NewPostAccess(postRepo, container.CryptoService))
 
 	authMiddleware := middleware.NewAuthMiddleware(
 		container.UserService,
@@ -45,9 +50,10 @@ func RegisterRoutes(router *gin.RouterGroup, container *container.Container, mux
 
 	// Register AI task handlers
 	mux.HandleFunc(ai.TaskTypeEmbedPost, ai.NewEmbedPostWorkerHandler(ai.EmbedPostWorker{
-		Logger:      container.Log,
-		PostRepo:    postRepo,
-		NotiService: container.NotificationService.(*notification.NotificationService),
+		Logger:         container.Log,
+		PostRepo:       postRepo,
+		NotiService:    container.NotificationService.(*notification.NotificationService),
+		RelatedService: related.NewService(related.NewRepository(container.DB), postRepo, container.CacheService),
 	}))
 
 	aiRoutes := router.Group("/ai")
@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/analytics"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	service analytics.ServiceInterface
+}
+
+func NewAnalyticsHandler(service analytics.ServiceInterface) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service}
+}
+
+// respondAnalyticsError แปลง error ของ analytics เป็น HTTP response
+func respondAnalyticsError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", "You do not have permission for this post")
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// MyOverview สถิติของโพสต์ทั้งหมดของผู้เขียน (?range=7d|30d|90d|365d)
+func (h *AnalyticsHandler) MyOverview(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	result, err := h.service.Overview(analytics.AuthorScope(user), c.Query("range"))
+	if err != nil {
+		respondAnalyticsError(c, "Failed to fetch analytics", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get analytics successfully", result)
+}
+
+// SiteOverview สถิติของทั้งเว็บ (admin)
+func (h *AnalyticsHandler) SiteOverview(c *gin.Context) {
+	result, err := h.service.Overview(analytics.SiteScope(), c.Query("range"))
+	if err != nil {
+		respondAnalyticsError(c, "Failed to fetch analytics", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get site analytics successfully", result)
+}
+
+// PostAnalytics สถิติรายวันของโพสต์เดียว
+func (h *AnalyticsHandler) PostAnalytics(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	result, err := h.service.PostAnalytics(c.Param("post_id"), user, c.Query("range"))
+	if err != nil {
+		respondAnalyticsError(c, "Failed to fetch post analytics", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get post analytics successfully", result)
+}
@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"rag-searchbot-backend/internal/analytics"
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/middleware"
+	"rag-searchbot-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container, mux *asynq.ServeMux) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	analyticsRepo := analytics.NewRepository(container.DB)
+	analyticsService := analytics.NewService(analyticsRepo, container.PostRepo)
+	handler := NewAnalyticsHandler(analyticsService)
+
+	// Background jobs (รันตามรอบจาก scheduler)
+	mux.HandleFunc(analytics.TaskTypeAggregateDaily, analytics.AggregateDailyWorkerHandler(analytics.AggregateDailyWorker{
+		Logger: container.Log,
+		Repo:   analyticsRepo,
+	}))
+
+	// สถิติของผู้เขียน
+	myRoutes := router.Group("/me/analytics")
+	myRoutes.Use(authMiddleware.Handler())
+	{
+		myRoutes.GET("", handler.MyOverview)
+		myRoutes.GET("/posts/:post_id", handler.PostAnalytics)
+	}
+
+	// สถิติทั้งเว็บ
+	adminRoutes := router.Group("/admin/analytics")
+	adminRoutes.Use(authMiddleware.Handler(), middleware.RequireRole(models.AdminUser))
+	{
+		adminRoutes.GET("", handler.SiteOverview)
+		adminRoutes.GET("/posts/:post_id", handler.PostAnalytics)
+	}
+}
@@ -0,0 +1,197 @@
+package bookmark
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"rag-searchbot-backend/internal/bookmark"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookmarkHandler struct {
+	service bookmark.ServiceInterface
+}
+
+func NewBookmarkHandler(service bookmark.ServiceInterface) *BookmarkHandler {
+	return &BookmarkHandler{service: service}
+}
+
+// respondBookmarkError แปลง error ของ bookmark เป็น HTTP response
+func respondBookmarkError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrBookmarkNotFound):
+		response.JSONError(c, http.StatusNotFound, "Bookmark not found", err.Error())
+	case errors.Is(err, errs.ErrFolderNotFound):
+		response.JSONError(c, http.StatusNotFound, "Folder not found", err.Error())
+	case errors.Is(err, errs.ErrBookmarkFolderExists):
+		response.JSONError(c, http.StatusConflict, "Folder name already exists", err.Error())
+	case errors.Is(err, errs.ErrInvalidCursor):
+		response.JSONError(c, http.StatusBadRequest, "Invalid cursor", err.Error())
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+func parseFolderID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid folder ID", err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// List bookmark ของผู้ใช้ (?folder_id=&cursor=&limit=)
+func (h *BookmarkHandler) List(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(bookmark.DefaultLimit)))
+	result, err := h.service.List(user, c.Query("folder_id"), c.Query("cursor"), limit)
+	if err != nil {
+		respondBookmarkError(c, "Failed to fetch bookmarks", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get bookmarks successfully", result)
+}
+
+// Toggle bookmark / เอา bookmark ออก (body ไม่บังคับ: folder_id, note)
+func (h *BookmarkHandler) Toggle(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req bookmark.ToggleBookmarkRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.Toggle(c.Param("post_id"), req, user)
+	if err != nil {
+		respondBookmarkError(c, "Failed to toggle bookmark", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Bookmark toggled successfully", result)
+}
+
+func (h *BookmarkHandler) Update(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req bookmark.UpdateBookmarkRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.Update(c.Param("post_id"), req, user)
+	if err != nil {
+		respondBookmarkError(c, "Failed to update bookmark", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Bookmark updated successfully", result)
+}
+
+func (h *BookmarkHandler) ListFolders(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	folders, err := h.service.ListFolders(user)
+	if err != nil {
+		respondBookmarkError(c, "Failed to fetch folders", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get folders successfully", folders)
+}
+
+func (h *BookmarkHandler) CreateFolder(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req bookmark.FolderRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	folder, err := h.service.CreateFolder(req, user)
+	if err != nil {
+		respondBookmarkError(c, "Failed to create folder", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusCreated, "Folder created successfully", folder)
+}
+
+func (h *BookmarkHandler) RenameFolder(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+	id, ok := parseFolderID(c)
+	if !ok {
+		return
+	}
+
+	var req bookmark.FolderRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	folder, err := h.service.RenameFolder(id, req, user)
+	if err != nil {
+		respondBookmarkError(c, "Failed to rename folder", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Folder renamed successfully", folder)
+}
+
+func (h *BookmarkHandler) DeleteFolder(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+	id, ok := parseFolderID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteFolder(id, user); err != nil {
+		respondBookmarkError(c, "Failed to delete folder", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Folder deleted successfully", nil)
+}
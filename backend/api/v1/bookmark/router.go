@@ -0,0 +1,34 @@
+package bookmark
+
+import (
+	"rag-searchbot-backend/internal/bookmark"
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	bookmarkService := bookmark.NewService(bookmark.NewRepository(container.DB), container.PostRepo)
+	handler := NewBookmarkHandler(bookmarkService)
+
+	bookmarkRoutes := router.Group("/me/bookmarks")
+	bookmarkRoutes.Use(authMiddleware.Handler())
+	{
+		bookmarkRoutes.GET("", handler.List)
+		bookmarkRoutes.POST("/post/:post_id", handler.Toggle)
+		bookmarkRoutes.PUT("/post/:post_id", handler.Update)
+
+		bookmarkRoutes.GET("/folders", handler.ListFolders)
+		bookmarkRoutes.POST("/folders", handler.CreateFolder)
+		bookmarkRoutes.PUT("/folders/:id", handler.RenameFolder)
+		bookmarkRoutes.DELETE("/folders/:id", handler.DeleteFolder)
+	}
+}
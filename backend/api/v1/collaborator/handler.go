@@ -0,0 +1,181 @@
+package collaborator
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/collaborator"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CollaboratorHandler struct {
+	service collaborator.ServiceInterface
+}
+
+func NewCollaboratorHandler(service collaborator.ServiceInterface) *CollaboratorHandler {
+	return &CollaboratorHandler{service: service}
+}
+
+// respondCollaboratorError แปลง error ของผู้ร่วมเขียนเป็น HTTP response
+func respondCollaboratorError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrUserNotFound):
+		response.JSONError(c, http.StatusNotFound, "User not found", err.Error())
+	case errors.Is(err, errs.ErrInvitationNotFound):
+		response.JSONError(c, http.StatusNotFound, "Invitation not found", err.Error())
+	case errors.Is(err, errs.ErrCollaboratorExists):
+		response.JSONError(c, http.StatusConflict, "Already a collaborator", err.Error())
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", err.Error())
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+func parseInvitationID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid invitation ID", err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *CollaboratorHandler) List(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	collaborators, err := h.service.List(c.Param("post_id"), user)
+	if err != nil {
+		respondCollaboratorError(c, "Failed to fetch collaborators", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get collaborators successfully", collaborators)
+}
+
+func (h *CollaboratorHandler) Invite(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req collaborator.InviteRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.Invite(c.Param("post_id"), req, user)
+	if err != nil {
+		respondCollaboratorError(c, "Failed to invite collaborator", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusCreated, "Invitation sent successfully", result)
+}
+
+func (h *CollaboratorHandler) UpdateRole(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req collaborator.UpdateRoleRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.UpdateRole(c.Param("post_id"), c.Param("user_id"), req, user)
+	if err != nil {
+		respondCollaboratorError(c, "Failed to update collaborator", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Collaborator updated successfully", result)
+}
+
+// Remove ถอดผู้ร่วมเขียน หรือออกจากโพสต์เองเมื่อ :user_id เป็นของตัวเอง
+func (h *CollaboratorHandler) Remove(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	if err := h.service.Remove(c.Param("post_id"), c.Param("user_id"), user); err != nil {
+		respondCollaboratorError(c, "Failed to remove collaborator", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Collaborator removed successfully", nil)
+}
+
+func (h *CollaboratorHandler) ListInvitations(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	invitations, err := h.service.ListInvitations(user)
+	if err != nil {
+		respondCollaboratorError(c, "Failed to fetch invitations", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get invitations successfully", invitations)
+}
+
+func (h *CollaboratorHandler) Accept(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+	id, ok := parseInvitationID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Accept(id, user)
+	if err != nil {
+		respondCollaboratorError(c, "Failed to accept invitation", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Invitation accepted successfully", result)
+}
+
+func (h *CollaboratorHandler) Decline(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+	id, ok := parseInvitationID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Decline(id, user); err != nil {
+		respondCollaboratorError(c, "Failed to decline invitation", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Invitation declined successfully", nil)
+}
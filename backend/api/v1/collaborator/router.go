@@ -0,0 +1,38 @@
+package collaborator
+
+import (
+	"rag-searchbot-backend/internal/collaborator"
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	collaboratorService := collaborator.NewService(
+		collaborator.NewRepository(container.DB),
+		container.PostRepo,
+		container.NotificationService,
+	)
+	handler := NewCollaboratorHandler(collaboratorService)
+
+	collaboratorRoutes := router.Group("/collaborators")
+	collaboratorRoutes.Use(authMiddleware.Handler())
+	{
+		collaboratorRoutes.GET("/post/:post_id", handler.List)
+		collaboratorRoutes.POST("/post/:post_id", handler.Invite)
+		collaboratorRoutes.PUT("/post/:post_id/:user_id", handler.UpdateRole)
+		collaboratorRoutes.DELETE("/post/:post_id/:user_id", handler.Remove)
+
+		collaboratorRoutes.GET("/invitations", handler.ListInvitations)
+		collaboratorRoutes.POST("/invitations/:id/accept", handler.Accept)
+		collaboratorRoutes.POST("/invitations/:id/decline", handler.Decline)
+	}
+}
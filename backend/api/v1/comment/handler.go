@@ -0,0 +1,164 @@
+package comment
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/comment"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	service comment.ServiceInterface
+}
+
+func NewCommentHandler(service comment.ServiceInterface) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+// respondCommentError แปลง error ของ comment เป็น HTTP response
+func respondCommentError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrPostProtected):
+		response.JSONError(c, http.StatusForbidden, "This post is protected", err.Error())
+	case errors.Is(err, errs.ErrCommentNotFound):
+		response.JSONError(c, http.StatusNotFound, "Comment not found", err.Error())
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", "You are not allowed to modify this comment")
+	case errors.Is(err, errs.ErrCommentsLocked):
+		response.JSONError(c, http.StatusForbidden, "Comments are locked", err.Error())
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid comment", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// postAccessToken token ปลดล็อกโพสต์ protected จาก cookie (ว่างถ้าไม่มี)
+func postAccessToken(c *gin.Context) string {
+	token, _ := c.Cookie(post.PostAccessCookieName(c.Param("post_id")))
+	return token
+}
+
+func parseCommentID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid comment ID", err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetComments ดึง comment ของโพสต์ (?page=&limit=) โพสต์ protected ต้องปลดล็อกหรือเป็นเจ้าของ
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	var user *models.User
+	if value, exists := c.Get("user"); exists {
+		user, _ = value.(*models.User)
+	}
+
+	comments, err := h.service.GetComments(c.Param("post_id"), page, limit, user, postAccessToken(c))
+	if err != nil {
+		respondCommentError(c, "Failed to fetch comments", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get comments successfully", comments)
+}
+
+func (h *CommentHandler) Create(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	var req comment.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	created, err := h.service.CreateComment(c.Param("post_id"), req, user, postAccessToken(c))
+	if err != nil {
+		respondCommentError(c, "Failed to create comment", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusCreated, "Comment created successfully", created)
+}
+
+func (h *CommentHandler) Update(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	id, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+
+	var req comment.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	updated, err := h.service.UpdateComment(id, req, user)
+	if err != nil {
+		respondCommentError(c, "Failed to update comment", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Comment updated successfully", updated)
+}
+
+func (h *CommentHandler) Delete(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	id, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteComment(id, user); err != nil {
+		respondCommentError(c, "Failed to delete comment", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Comment deleted successfully", nil)
+}
+
+// SetLocked ผู้เขียนโพสต์ปิด / เปิดการรับ comment
+func (h *CommentHandler) SetLocked(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	var req comment.LockCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.service.SetCommentsLocked(c.Param("post_id"), *req.Locked, user); err != nil {
+		respondCommentError(c, "Failed to update comment lock", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Comment lock updated successfully", gin.H{"locked": *req.Locked})
+}
@@ -0,0 +1,48 @@
+package comment
+
+import (
+	"rag-searchbot-backend/internal/comment"
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/middleware"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+	optionalAuthMiddleware := middleware.NewOptionalAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	commentService := comment.NewService(
+		comment.NewRepository(container.DB),
+		container.PostRepo,
+		container.NotificationService,
+		post.NewPostAccess(container.PostRepo, container.CryptoService),
+	)
+	handler := NewCommentHandler(commentService)
+
+	commentRoutes := router.Group("/comments")
+
+	// Public routes
+	// optional auth ให้เจ้าของและผู้ร่วมเขียนอ่าน comment ของโพสต์ protected ได้โดยไม่ต้องปลดล็อก
+	commentRoutes.GET("/post/:post_id", optionalAuthMiddleware.Handler(), handler.GetComments)
+
+	// Protected routes
+	commentRoutes.Use(authMiddleware.Handler())
+	{
+		commentRoutes.POST("/post/:post_id", handler.Create)
+		commentRoutes.PUT("/post/:post_id/lock", handler.SetLocked)
+		commentRoutes.PUT("/:id", handler.Update)
+		commentRoutes.DELETE("/:id", handler.Delete)
+	}
+}
@@ -0,0 +1,109 @@
+package feed
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/feed"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/response"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	service feed.ServiceInterface
+	coreURL string // base URL ของ API ใช้สร้าง self link ของ feed
+}
+
+func NewFeedHandler(service feed.ServiceInterface, coreURL string) *FeedHandler {
+	return &FeedHandler{service: service, coreURL: strings.TrimRight(coreURL, "/")}
+}
+
+func (h *FeedHandler) request(c *gin.Context, format feed.Format) feed.Request {
+	return feed.Request{
+		Format:  format,
+		Full:    c.Query("full") == "true",
+		FeedURL: h.coreURL + c.Request.URL.RequestURI(),
+	}
+}
+
+// notModified ตรวจ If-None-Match ก่อน แล้วค่อย If-Modified-Since ตาม RFC 9110
+func notModified(c *gin.Context, result *feed.Result) bool {
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == result.ETag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := c.GetHeader("If-Modified-Since"); since != "" && !result.LastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !result.LastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+func (h *FeedHandler) write(c *gin.Context, result *feed.Result, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUserNotFound):
+			response.JSONError(c, http.StatusNotFound, "User not found", err.Error())
+		case errors.Is(err, errs.ErrTagNotFound):
+			response.JSONError(c, http.StatusNotFound, "Tag not found", err.Error())
+		case errors.Is(err, errs.ErrSeriesNotFound):
+			response.JSONError(c, http.StatusNotFound, "Series not found", err.Error())
+		default:
+			response.JSONError(c, http.StatusInternalServerError, "Failed to build feed", err.Error())
+		}
+		return
+	}
+
+	c.Header("ETag", result.ETag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !result.LastModified.IsZero() {
+		c.Header("Last-Modified", result.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, result) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, result.ContentType, result.Body)
+}
+
+// Site feed ของทั้งเว็บ (?full=true เพื่อใส่ HTML ของบทความ)
+func (h *FeedHandler) Site(format feed.Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := h.service.SiteFeed(h.request(c, format))
+		h.write(c, result, err)
+	}
+}
+
+// Author feed ของผู้เขียนตาม :username
+func (h *FeedHandler) Author(format feed.Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := h.service.AuthorFeed(c.Param("username"), h.request(c, format))
+		h.write(c, result, err)
+	}
+}
+
+// Tag feed ของโพสต์ที่ติด tag :name
+func (h *FeedHandler) Tag(format feed.Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := h.service.TagFeed(c.Param("name"), h.request(c, format))
+		h.write(c, result, err)
+	}
+}
+
+// Series feed ของตอนใน series :username/:slug
+func (h *FeedHandler) Series(format feed.Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := h.service.SeriesFeed(c.Param("username"), c.Param("slug"), h.request(c, format))
+		h.write(c, result, err)
+	}
+}
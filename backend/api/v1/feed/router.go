@@ -0,0 +1,44 @@
+package feed
+
+import (
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/feed"
+	"rag-searchbot-backend/internal/series"
+	"rag-searchbot-backend/internal/taxonomy"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	feedService := feed.NewService(
+		container.PostRepo,
+		taxonomy.NewRepository(container.DB),
+		series.NewRepository(container.DB),
+		container.UserService,
+		container.Env.AppUrl,
+	)
+	handler := NewFeedHandler(feedService, container.Env.CoreUrl)
+
+	// Public routes
+	feedRoutes := router.Group("/feeds")
+	{
+		feedRoutes.GET("/rss.xml", handler.Site(feed.FormatRSS))
+		feedRoutes.GET("/atom.xml", handler.Site(feed.FormatAtom))
+		feedRoutes.GET("/feed.json", handler.Site(feed.FormatJSON))
+
+		feedRoutes.GET("/tags/:name/rss.xml", handler.Tag(feed.FormatRSS))
+		feedRoutes.GET("/tags/:name/atom.xml", handler.Tag(feed.FormatAtom))
+		feedRoutes.GET("/tags/:name/feed.json", handler.Tag(feed.FormatJSON))
+
+		feedRoutes.GET("/series/:username/:slug/rss.xml", handler.Series(feed.FormatRSS))
+		feedRoutes.GET("/series/:username/:slug/atom.xml", handler.Series(feed.FormatAtom))
+		feedRoutes.GET("/series/:username/:slug/feed.json", handler.Series(feed.FormatJSON))
+	}
+
+	userRoutes := router.Group("/user/profile")
+	{
+		userRoutes.GET("/:username/feed.rss", handler.Author(feed.FormatRSS))
+		userRoutes.GET("/:username/feed.atom", handler.Author(feed.FormatAtom))
+		userRoutes.GET("/:username/feed.json", handler.Author(feed.FormatJSON))
+	}
+}
@@ -0,0 +1,111 @@
+package follow
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/follow"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	service follow.ServiceInterface
+}
+
+func NewFollowHandler(service follow.ServiceInterface) *FollowHandler {
+	return &FollowHandler{service: service}
+}
+
+// respondFollowError แปลง error ของ follow เป็น HTTP response
+func respondFollowError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrUserNotFound):
+		response.JSONError(c, http.StatusNotFound, "User not found", err.Error())
+	case errors.Is(err, errs.ErrInvalidCursor):
+		response.JSONError(c, http.StatusBadRequest, "Invalid cursor", err.Error())
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+func queryLimit(c *gin.Context) int {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(follow.DefaultLimit)))
+	return limit
+}
+
+func (h *FollowHandler) Follow(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	result, err := h.service.Follow(c.Param("username"), user)
+	if err != nil {
+		respondFollowError(c, "Failed to follow user", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Followed successfully", result)
+}
+
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	result, err := h.service.Unfollow(c.Param("username"), user)
+	if err != nil {
+		respondFollowError(c, "Failed to unfollow user", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Unfollowed successfully", result)
+}
+
+// ListFollowers ผู้ติดตามของ :username (?cursor=&limit=)
+func (h *FollowHandler) ListFollowers(c *gin.Context) {
+	result, err := h.service.ListFollowers(c.Param("username"), c.Query("cursor"), queryLimit(c))
+	if err != nil {
+		respondFollowError(c, "Failed to fetch followers", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get followers successfully", result)
+}
+
+// ListFollowing ผู้เขียนที่ :username ติดตาม (?cursor=&limit=)
+func (h *FollowHandler) ListFollowing(c *gin.Context) {
+	result, err := h.service.ListFollowing(c.Param("username"), c.Query("cursor"), queryLimit(c))
+	if err != nil {
+		respondFollowError(c, "Failed to fetch following", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get following successfully", result)
+}
+
+// Feed โพสต์ล่าสุดจากผู้เขียนที่ติดตาม (?cursor=&limit=)
+func (h *FollowHandler) Feed(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	result, err := h.service.Feed(user, c.Query("cursor"), queryLimit(c))
+	if err != nil {
+		respondFollowError(c, "Failed to fetch following feed", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get following feed successfully", result)
+}
@@ -0,0 +1,33 @@
+package follow
+
+import (
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/follow"
+	"rag-searchbot-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	followService := follow.NewService(follow.NewRepository(container.DB), container.NotificationService)
+	handler := NewFollowHandler(followService)
+
+	profileRoutes := router.Group("/user/profile/:username")
+
+	// Public routes
+	profileRoutes.GET("/followers", handler.ListFollowers)
+	profileRoutes.GET("/following", handler.ListFollowing)
+
+	// Protected routes
+	profileRoutes.POST("/follow", authMiddleware.Handler(), handler.Follow)
+	profileRoutes.DELETE("/follow", authMiddleware.Handler(), handler.Unfollow)
+
+	router.GET("/feed/following", authMiddleware.Handler(), handler.Feed)
+}
@@ -0,0 +1,105 @@
+package history
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/history"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HistoryHandler struct {
+	service history.ServiceInterface
+}
+
+func NewHistoryHandler(service history.ServiceInterface) *HistoryHandler {
+	return &HistoryHandler{service: service}
+}
+
+// respondHistoryError แปลง error ของประวัติการอ่านเป็น HTTP response
+func respondHistoryError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrHistoryEntryNotFound):
+		response.JSONError(c, http.StatusNotFound, "History entry not found", err.Error())
+	case errors.Is(err, errs.ErrInvalidCursor):
+		response.JSONError(c, http.StatusBadRequest, "Invalid cursor", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// List ประวัติการอ่านของผู้ใช้ (?cursor=&limit=)
+func (h *HistoryHandler) List(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(history.DefaultLimit)))
+	result, err := h.service.List(user, c.Query("cursor"), limit)
+	if err != nil {
+		respondHistoryError(c, "Failed to fetch reading history", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get reading history successfully", result)
+}
+
+func (h *HistoryHandler) Remove(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	if err := h.service.Remove(c.Param("post_id"), user); err != nil {
+		respondHistoryError(c, "Failed to remove history entry", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "History entry removed successfully", nil)
+}
+
+func (h *HistoryHandler) Clear(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	result, err := h.service.Clear(user)
+	if err != nil {
+		respondHistoryError(c, "Failed to clear reading history", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Reading history cleared successfully", result)
+}
+
+// SetPaused หยุด / เริ่มบันทึกประวัติการอ่าน (body: {"paused": true|false})
+func (h *HistoryHandler) SetPaused(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req history.PauseRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.SetPaused(*req.Paused, user)
+	if err != nil {
+		respondHistoryError(c, "Failed to update history settings", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "History settings updated successfully", result)
+}
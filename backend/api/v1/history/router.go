@@ -0,0 +1,30 @@
+package history
+
+import (
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/history"
+	"rag-searchbot-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	historyService := history.NewService(history.NewRepository(container.DB))
+	handler := NewHistoryHandler(historyService)
+
+	historyRoutes := router.Group("/me/history")
+	historyRoutes.Use(authMiddleware.Handler())
+	{
+		historyRoutes.GET("", handler.List)
+		historyRoutes.DELETE("", handler.Clear)
+		historyRoutes.DELETE("/post/:post_id", handler.Remove)
+		historyRoutes.PUT("/pause", handler.SetPaused)
+	}
+}
@@ -1,8 +1,10 @@
 package notification
 
 import (
+	"errors"
 	"net/http"
 	"rag-searchbot-backend/internal/notification"
+	"rag-searchbot-backend/pkg/errs"
 	"rag-searchbot-backend/pkg/ginctx"
 	"rag-searchbot-backend/pkg/response"
 	"strconv"
@@ -41,12 +43,23 @@ func (h *NotificationHandler) GetNotificationsHandler(c *gin.Context) {
 		return
 	}
 
+	count, err := strconv.ParseBool(c.DefaultQuery("count", "true"))
+	if err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid count", "Count must be true or false")
+		return
+	}
+
 	user, ok := ginctx.GetUserFromContext(c)
 	if !ok || user == nil {
 		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
 	}
 
-	notifications, err := h.NotiService.GetNotifications(*user, limitStr, pageStr)
+	notifications, err := h.NotiService.GetNotifications(*user, limitStr, pageStr, c.Query("cursor"), count)
+	if errors.Is(err, errs.ErrInvalidCursor) {
+		response.JSONError(c, http.StatusBadRequest, "Invalid cursor", err.Error())
+		return
+	}
 	if err != nil {
 		response.JSONError(c, http.StatusInternalServerError, "Failed to retrieve notifications", err.Error())
 		return
@@ -2,6 +2,7 @@ package post
 
 import (
 	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/series"
 	"time"
 )
 
@@ -13,31 +14,45 @@ type PostContentStructure struct {
 	Content []PostContentStructure `json:"content,omitempty"`
 }
 type GetPublicPostBySlugAndUsernameResponse struct {
-	ID          string     `json:"id"`
-	Slug        string     `json:"slug"`
-	ShortSlug   string     `json:"short_slug"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Thumbnail   string     `json:"thumbnail"`
-	Content     string     `json:"content"`
-	Published   bool       `json:"published"`
-	PublishedAt time.Time  `json:"published_at"`
-	Likes       int        `json:"likes"`
-	Views       int        `json:"views"`
-	ReadTime    int        `json:"read_time"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at"`
-	AuthorID    string     `json:"author_id"`
-	AIChatOpen  bool       `json:"ai_chat_open"`
-	AIReady     bool       `json:"ai_ready"`
-	Author      struct {
+	ID             string     `json:"id"`
+	Slug           string     `json:"slug"`
+	ShortSlug      string     `json:"short_slug"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Thumbnail      string     `json:"thumbnail"`
+	Content        string     `json:"content"`
+	Published      bool       `json:"published"`
+	PublishedAt    time.Time  `json:"published_at"`
+	Likes          int        `json:"likes"`
+	Views          int        `json:"views"`
+	ReadTime       int        `json:"read_time"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at"`
+	AuthorID       string     `json:"author_id"`
+	AIChatOpen     bool       `json:"ai_chat_open"`
+	AIReady        bool       `json:"ai_ready"`
+	CommentsLocked bool       `json:"comments_locked"`
+	Visibility     string     `json:"visibility"` // unlisted ใช้ตั้ง noindex ฝั่ง frontend
+	Author         struct {
 		Avatar    string `json:"avatar"`
 		Username  string `json:"username"`
 		Bio       string `json:"bio"`
 		FirstName string `json:"first_name"`
 		LastName  string `json:"last_name"`
 	} `json:"author"`
+	CoAuthors []CoAuthorDTO               `json:"co_authors"`
+	Series    *series.SeriesNavigationDTO `json:"series,omitempty"` // มีเมื่อโพสต์อยู่ใน series
+}
+
+// CoAuthorDTO ผู้ร่วมเขียนที่ตอบรับคำเชิญแล้ว
+type CoAuthorDTO struct {
+	Avatar    string `json:"avatar"`
+	Username  string `json:"username"`
+	Bio       string `json:"bio"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Role      string `json:"role"`
 }
 
 func MapGetPublicPostBySlugAndUsernameResponse(post *models.Post) *GetPublicPostBySlugAndUsernameResponse {
@@ -46,23 +61,25 @@ func MapGetPublicPostBySlugAndUsernameResponse(post *models.Post) *GetPublicPost
 	}
 
 	dto := &GetPublicPostBySlugAndUsernameResponse{
-		ID:          post.ID.String(),
-		Slug:        post.Slug,
-		ShortSlug:   post.ShortSlug,
-		Title:       post.Title,
-		Description: post.Description,
-		Thumbnail:   post.Thumbnail,
-		Content:     post.Content,
-		Published:   post.Published,
-		PublishedAt: *post.PublishedAt,
-		Likes:       post.Likes,
-		Views:       post.Views,
-		ReadTime:    int(post.ReadTime),
-		CreatedAt:   post.CreatedAt,
-		UpdatedAt:   post.UpdatedAt,
-		DeletedAt:   &post.DeletedAt.Time,
-		AIChatOpen:  post.AIChatOpen,
-		AIReady:     post.AIReady,
+		ID:             post.ID.String(),
+		Slug:           post.Slug,
+		ShortSlug:      post.ShortSlug,
+		Title:          post.Title,
+		Description:    post.Description,
+		Thumbnail:      post.Thumbnail,
+		Content:        post.Content,
+		Published:      post.Published,
+		PublishedAt:    *post.PublishedAt,
+		Likes:          post.Likes,
+		Views:          post.Views,
+		ReadTime:       int(post.ReadTime),
+		CreatedAt:      post.CreatedAt,
+		UpdatedAt:      post.UpdatedAt,
+		DeletedAt:      &post.DeletedAt.Time,
+		AIChatOpen:     post.AIChatOpen,
+		AIReady:        post.AIReady,
+		CommentsLocked: post.CommentsLocked,
+		Visibility:     string(post.Visibility),
 	}
 
 	dto.Author = struct {
@@ -79,5 +96,17 @@ func MapGetPublicPostBySlugAndUsernameResponse(post *models.Post) *GetPublicPost
 		LastName:  post.Author.LastName,
 	}
 
+	dto.CoAuthors = make([]CoAuthorDTO, 0, len(post.Collaborators))
+	for _, c := range post.Collaborators {
+		dto.CoAuthors = append(dto.CoAuthors, CoAuthorDTO{
+			Avatar:    c.User.Avatar,
+			Username:  c.User.UserName,
+			Bio:       c.User.Bio,
+			FirstName: c.User.FirstName,
+			LastName:  c.User.LastName,
+			Role:      string(c.Role),
+		})
+	}
+
 	return dto
 }
@@ -0,0 +1,62 @@
+package post
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondExportError แปลง error ของการ export เป็น HTTP response
+func respondExportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", "You do not have permission for this post")
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid export request", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, "Failed to export post", err.Error())
+	}
+}
+
+func sendExportedFile(c *gin.Context, file *post.ExportedFile) {
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName}))
+	c.Data(http.StatusOK, file.ContentType, file.Body)
+}
+
+// ExportPost ดาวน์โหลดโพสต์เป็นไฟล์ ?format=md|html|json (ค่าเริ่มต้น md)
+func (h *PostHandler) ExportPost(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	file, err := h.service.ExportPost(c.Param("short_slug"), c.DefaultQuery("format", post.ExportFormatMarkdown), user)
+	if err != nil {
+		respondExportError(c, err)
+		return
+	}
+	sendExportedFile(c, file)
+}
+
+// ExportAllPosts ดาวน์โหลดโพสต์ทั้งหมดของผู้ใช้เป็น ZIP
+func (h *PostHandler) ExportAllPosts(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	file, err := h.service.ExportAllPosts(c.DefaultQuery("format", post.ExportFormatMarkdown), user)
+	if err != nil {
+		respondExportError(c, err)
+		return
+	}
+	sendExportedFile(c, file)
+}
@@ -5,25 +5,30 @@ import (
 	"net/http"
 	"rag-searchbot-backend/internal/models"
 	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/series"
 	"rag-searchbot-backend/pkg/errs"
 	"rag-searchbot-backend/pkg/ginctx"
 	"rag-searchbot-backend/pkg/response"
+	"rag-searchbot-backend/pkg/sanitize"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type PostHandler struct {
-	service *post.PostService
+	service       *post.PostService
+	seriesService series.ServiceInterface
+	unlockLimiter *unlockRateLimiter
 }
 
-func NewPostHandler(service *post.PostService) *PostHandler {
-	return &PostHandler{service: service}
+func NewPostHandler(service *post.PostService, seriesService series.ServiceInterface) *PostHandler {
+	return &PostHandler{service: service, seriesService: seriesService, unlockLimiter: newUnlockRateLimiter()}
 }
 
 func (h *PostHandler) Create(c *gin.Context) {
-	var post post.CreatePostRequest
-	if err := c.ShouldBindJSON(&post); err != nil {
+	var req post.CreatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
 		return
 	}
@@ -33,15 +38,28 @@ func (h *PostHandler) Create(c *gin.Context) {
 		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
 	}
 
-	postID, err := h.service.CreatePost(post, user)
+	saved, err := h.service.CreatePost(req, user)
+	if errors.Is(err, errs.ErrUnauthorized) {
+		response.JSONError(c, http.StatusForbidden, "You do not have permission to edit this post", err.Error())
+		return
+	}
+	// version เก่า ส่งเนื้อหาล่าสุดกลับไปให้ editor merge
+	var conflict *post.VersionConflictError
+	if errors.As(err, &conflict) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Post was modified since you loaded it",
+			"code":    err.Error(),
+			"data":    conflict.Current,
+		})
+		return
+	}
 	if err != nil {
 		response.JSONError(c, http.StatusInternalServerError, "Failed to create post", err.Error())
 		return
 	}
 
-	response.JSONSuccess(c, 201, "Post created successfully", gin.H{
-		"post_id": postID,
-	})
+	response.JSONSuccess(c, 201, "Post created successfully", saved)
 }
 
 func (h *PostHandler) GetByShortSlug(c *gin.Context) {
@@ -53,7 +71,12 @@ func (h *PostHandler) GetByShortSlug(c *gin.Context) {
 		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
 	}
 
-	post, err := h.service.GetByShortSlug(shortSlug + "-" + user.ID.String())
+	// เจ้าของและผู้ร่วมเขียนเปิดโพสต์ใน editor ได้
+	post, err := h.service.GetEditablePost(shortSlug, user)
+	if errors.Is(err, errs.ErrUnauthorized) {
+		response.JSONError(c, http.StatusForbidden, "Forbidden", err.Error())
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
 		return
@@ -72,10 +95,15 @@ func (h *PostHandler) Update(c *gin.Context) {
 
 func (h *PostHandler) GetAll(c *gin.Context) {
 	posts, err := h.service.GetPosts(c)
+	if errors.Is(err, errs.ErrInvalidCursor) {
+		response.JSONError(c, http.StatusBadRequest, "Invalid cursor", err.Error())
+		return
+	}
 	if err != nil {
-
+		response.JSONError(c, http.StatusInternalServerError, "Failed to fetch posts", err.Error())
 		return
 	}
+	h.service.MarkBookmarked(optionalUser(c), posts.Posts)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    posts,
@@ -191,7 +219,15 @@ func (h *PostHandler) Publish(c *gin.Context) {
 		return
 	}
 
-	existingPost, err := h.service.GetByShortSlug(shortSlug + "-" + userData.ID.String())
+	existingPost, err := h.service.GetEditablePost(shortSlug, userData)
+	if errors.Is(err, errs.ErrPostNotFound) {
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+		return
+	}
+	if errors.Is(err, errs.ErrUnauthorized) {
+		response.JSONError(c, http.StatusForbidden, "Forbidden", err.Error())
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -201,7 +237,39 @@ func (h *PostHandler) Publish(c *gin.Context) {
 		return
 	}
 
-	published := h.service.PublishPost(&post, userData, shortSlug)
+	rejected, published := h.service.PublishPost(&post, userData, shortSlug)
+
+	if errors.Is(published, errs.ErrUnauthorized) {
+		response.JSONError(c, http.StatusForbidden, "You do not have permission to publish this post", published.Error())
+		return
+	}
+
+	if errors.Is(published, errs.ErrInvalidSchedule) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid publish schedule",
+			"error":   published.Error(),
+		})
+		return
+	}
+
+	if errors.Is(published, errs.ErrInvalidContent) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"message": "Invalid post content",
+			"error":   published.Error(),
+		})
+		return
+	}
+
+	if errors.Is(published, errs.ErrCategoryNotFound) || errors.Is(published, errs.ErrInvalidPayload) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid tags or categories",
+			"error":   published.Error(),
+		})
+		return
+	}
 
 	if published != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -224,13 +292,26 @@ func (h *PostHandler) Publish(c *gin.Context) {
 		return
 	}
 
+	message := "Post published successfully"
+	if post.IsScheduled(time.Now()) {
+		message = "Post scheduled successfully"
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Post published successfully",
-		"data":    post,
+		"message": message,
+		"data":    newPublishResponse(post, rejected),
 	})
 }
 
+// newPublishResponse คืนข้อมูลที่ publish พร้อมรายการ HTML ที่ถูก sanitize ตัดออก (เป็น array ว่างถ้าไม่มี)
+func newPublishResponse(req post.PublishPostRequestDTO, rejected []sanitize.Rejection) post.PublishPostResponseDTO {
+	if rejected == nil {
+		rejected = []sanitize.Rejection{}
+	}
+	return post.PublishPostResponseDTO{PublishPostRequestDTO: req, RejectedElements: rejected}
+}
+
 func (h *PostHandler) Unpublish(c *gin.Context) {
 
 	var shortSlug = c.Param("short_slug")
@@ -264,6 +345,15 @@ func (h *PostHandler) Unpublish(c *gin.Context) {
 
 	unpublished := h.service.UnpublishPost(userData, shortSlug)
 
+	if errors.Is(unpublished, errs.ErrPostNotFound) {
+		response.JSONError(c, http.StatusNotFound, "Post not found", unpublished.Error())
+		return
+	}
+	if errors.Is(unpublished, errs.ErrUnauthorized) {
+		response.JSONError(c, http.StatusForbidden, "You do not have permission to unpublish this post", unpublished.Error())
+		return
+	}
+
 	if unpublished != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -292,8 +382,18 @@ func (h *PostHandler) GetPublicPostBySlugAndUsername(c *gin.Context) {
 		return
 	}
 
+	if !h.service.CanReadPost(post, optionalUser(c), postAccessToken(c, post.ID.String())) {
+		respondVisibilityError(c, "Failed to get post", errs.ErrPostProtected)
+		return
+	}
+
 	response := MapGetPublicPostBySlugAndUsernameResponse(post)
 
+	// series เป็นข้อมูลเสริม ถ้าโหลดไม่ได้ยังแสดงโพสต์ได้ตามปกติ
+	if nav, err := h.seriesService.GetNavigation(post.ID); err == nil {
+		response.Series = nav
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    response,
@@ -316,18 +416,22 @@ func (h *PostHandler) RecordPostView(c *gin.Context) {
 	}
 
 	// ดึง user จาก context (อาจเป็น nil ถ้าไม่ได้ login)
-	user, _ := c.Get("user")
-	var userData *models.User
-	if u, ok := user.(*models.User); ok {
-		userData = u
-	}
+	userData := optionalUser(c)
 
 	// ดึง IP address และ User-Agent
 	ipAddress := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
+	referrer := req.Referrer
+	if referrer == "" {
+		referrer = c.GetHeader("Referer")
+	}
 
 	// บันทึก view
-	result, err := h.service.RecordPostView(postID, userData, req.Fingerprint, ipAddress, userAgent)
+	result, err := h.service.RecordPostView(postID, userData, postAccessToken(c, postID), req.Fingerprint, ipAddress, userAgent, referrer)
+	if errors.Is(err, errs.ErrPostProtected) {
+		respondVisibilityError(c, "Failed to record post view", err)
+		return
+	}
 	if err != nil {
 		response.JSONError(c, http.StatusInternalServerError, "Failed to record post view", err.Error())
 		return
@@ -336,21 +440,58 @@ func (h *PostHandler) RecordPostView(c *gin.Context) {
 	response.JSONSuccess(c, http.StatusOK, "Post view recorded successfully", result)
 }
 
-// GetPopularPosts ดึงบทความยอดนิยมตามจำนวน view
+// GetPopularPosts ดึงบทความยอดนิยม mode=all-time (ค่าเริ่มต้น) เรียงตามจำนวน view
+// mode=trending เรียงตามคะแนน trending ของ window (24h, 7d, 30d)
 func (h *PostHandler) GetPopularPosts(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "4")
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 {
 		limit = 4
 	}
-	posts, err := h.service.GetPopularPosts(limit)
+
+	var posts *post.PostListResponse
+	switch c.DefaultQuery("mode", post.PopularModeAllTime) {
+	case post.PopularModeAllTime:
+		posts, err = h.service.GetPopularPosts(limit)
+	case post.PopularModeTrending:
+		posts, err = h.service.GetTrendingPosts(c.Query("window"), limit)
+	default:
+		response.JSONError(c, http.StatusBadRequest, "Invalid mode", "mode must be all-time or trending")
+		return
+	}
+	if errors.Is(err, errs.ErrInvalidPayload) {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch popular posts"})
 		return
 	}
+	h.service.MarkBookmarked(optionalUser(c), posts.Posts)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    posts,
 		"message": "Get popular posts successfully.",
 	})
 }
+
+// GetTrendingPosts โพสต์ที่กำลังได้รับความสนใจ (?window=24h|7d|30d)
+func (h *PostHandler) GetTrendingPosts(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	posts, err := h.service.GetTrendingPosts(c.Query("window"), limit)
+	if errors.Is(err, errs.ErrInvalidPayload) {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+	if err != nil {
+		response.JSONError(c, http.StatusInternalServerError, "Failed to fetch trending posts", err.Error())
+		return
+	}
+	h.service.MarkBookmarked(optionalUser(c), posts.Posts)
+
+	response.JSONSuccess(c, http.StatusOK, "Get trending posts successfully", posts)
+}
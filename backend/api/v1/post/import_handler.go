@@ -0,0 +1,70 @@
+package post
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readMarkdownFile อ่านไฟล์ Markdown ที่แนบมา โดยจำกัดขนาดไม่เกิน MaxMarkdownImportBytes
+func readMarkdownFile(fileHeader *multipart.FileHeader) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, post.MaxMarkdownImportBytes+1))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ImportMarkdown สร้าง draft จาก Markdown
+// รับ multipart form: "file" (ไฟล์ .md) หรือ "markdown" (ข้อความ) และ "images" (รูป local ที่ Markdown อ้างถึง)
+func (h *PostHandler) ImportMarkdown(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	markdown := c.PostForm("markdown")
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		if markdown, err = readMarkdownFile(fileHeader); err != nil {
+			response.JSONError(c, http.StatusBadRequest, "Failed to read markdown file", err.Error())
+			return
+		}
+	}
+	if markdown == "" {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", "markdown or file is required")
+		return
+	}
+
+	images := make(map[string]*multipart.FileHeader)
+	if form, err := c.MultipartForm(); err == nil {
+		for _, fileHeader := range form.File["images"] {
+			images[path.Base(fileHeader.Filename)] = fileHeader
+		}
+	}
+
+	result, err := h.service.ImportMarkdown(markdown, images, user)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidPayload) {
+			response.JSONError(c, http.StatusUnprocessableEntity, "Invalid markdown", err.Error())
+			return
+		}
+		response.JSONError(c, http.StatusInternalServerError, "Failed to import markdown", err.Error())
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusCreated, "Markdown imported successfully", result)
+}
@@ -0,0 +1,46 @@
+package post
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/related"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RelatedHandler struct {
+	service related.ServiceInterface
+}
+
+func NewRelatedHandler(service related.ServiceInterface) *RelatedHandler {
+	return &RelatedHandler{service: service}
+}
+
+// GetRelated โพสต์ที่เกี่ยวข้องสำหรับหน้าโพสต์ public (?limit=&exclude_author=true)
+// path param ชื่อ short_slug เพราะ gin บังคับให้ตรงกับ GET /:short_slug แต่ค่าที่รับคือ post id (uuid)
+func (h *RelatedHandler) GetRelated(c *gin.Context) {
+	postID := c.Param("short_slug")
+	if _, err := uuid.Parse(postID); err != nil {
+		response.JSONError(c, http.StatusNotFound, "Post not found", errs.ErrPostNotFound.Error())
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(related.DefaultLimit)))
+	excludeAuthor := c.Query("exclude_author") == "true"
+
+	result, err := h.service.GetRelated(postID, limit, excludeAuthor)
+	if err != nil {
+		if errors.Is(err, errs.ErrPostNotFound) {
+			response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+			return
+		}
+		response.JSONError(c, http.StatusInternalServerError, "Failed to fetch related posts", err.Error())
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get related posts successfully", result)
+}
@@ -0,0 +1,99 @@
+package post
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondRevisionError แปลง error ของ revision เป็น HTTP response
+func respondRevisionError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrRevisionNotFound):
+		response.JSONError(c, http.StatusNotFound, "Revision not found", err.Error())
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", "You do not have permission for this post")
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusUnprocessableEntity, "Invalid revision content", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// GetRevisions ดึงรายการ revision ของ post
+func (h *PostHandler) GetRevisions(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(c.Param("short_slug"), user)
+	if err != nil {
+		respondRevisionError(c, "Failed to fetch revisions", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get revisions successfully", revisions)
+}
+
+// GetRevision ดึง revision เดียวพร้อมเนื้อหา
+func (h *PostHandler) GetRevision(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	revision, err := h.service.GetRevision(c.Param("short_slug"), c.Param("revision_id"), user)
+	if err != nil {
+		respondRevisionError(c, "Failed to fetch revision", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get revision successfully", revision)
+}
+
+// DiffRevisions เปรียบเทียบ revision ?from=<id>&to=<id|current>
+func (h *PostHandler) DiffRevisions(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	from := c.Query("from")
+	if from == "" {
+		response.JSONError(c, http.StatusBadRequest, "from is required", "Query parameter 'from' cannot be empty")
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(c.Param("short_slug"), from, c.Query("to"), user)
+	if err != nil {
+		respondRevisionError(c, "Failed to diff revisions", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Diff revisions successfully", diff)
+}
+
+// RestoreRevision นำ revision กลับมาเป็น draft ปัจจุบัน
+func (h *PostHandler) RestoreRevision(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	postID, err := h.service.RestoreRevision(c.Param("short_slug"), c.Param("revision_id"), user)
+	if err != nil {
+		respondRevisionError(c, "Failed to restore revision", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Revision restored successfully", gin.H{
+		"post_id": postID,
+	})
+}
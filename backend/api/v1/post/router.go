@@ -4,7 +4,11 @@ import (
 	"log"
 	"rag-searchbot-backend/internal/container"
 	"rag-searchbot-backend/internal/middleware"
+	"rag-searchbot-backend/internal/notification"
 	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/related"
+	"rag-searchbot-backend/internal/series"
+	"rag-searchbot-backend/internal/sitemap"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hibiken/asynq"
@@ -23,34 +27,91 @@ func RegisterRoutes(router *gin.RouterGroup, container *container.Container, mux
 		container.Log,
 	)
 
-	// Create Service + Handler
-	taskEnqueuer := post.NewTaskEnqueuer(container.AsynqClient, container.QueueRepo)
-	postService := post.NewPostService(container.PostRepo, container.MediaService, taskEnqueuer)
+	optionalAuthMiddleware := middleware.NewOptionalAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
 
+	// ใช้ PostService ตัวเดียวกับ router อื่น (taxonomy, user) listener ที่ลงทะเบียนด้านล่างจึงทำงานทุกเส้นทาง
 	// AI moderation is disabled; publishing is handled synchronously after auth.
-	ps, ok := postService.(*post.PostService)
+	ps, ok := container.PostService.(*post.PostService)
 	if !ok {
 		log.Fatal("[FATAL] Failed to cast postService to *post.PostService")
 	}
-	handler := NewPostHandler(ps)
+	handler := NewPostHandler(ps, series.NewService(series.NewRepository(container.DB), container.UserService))
+
+	// Related posts (cache ถูกล้างเมื่อมีโพสต์ publish / unpublish)
+	relatedService := related.NewService(related.NewRepository(container.DB), container.PostRepo, container.CacheService)
+	ps.AddPublicationListener(relatedService)
+	relatedHandler := NewRelatedHandler(relatedService)
+
+	// sitemap ที่ cache ไว้จะถูกล้างเฉพาะหน้าที่ได้รับผลกระทบ
+	ps.AddPublicationListener(sitemap.NewService(
+		sitemap.NewRepository(container.DB),
+		container.CacheService,
+		container.Env.AppUrl,
+		container.Env.CoreUrl,
+	))
+
+	// Background jobs
+	mux.HandleFunc(post.TaskTypePruneRevisions, post.PruneRevisionsWorkerHandler(post.PruneRevisionsWorker{
+		Logger:   container.Log,
+		PostRepo: container.PostRepo,
+	}))
+	mux.HandleFunc(post.TaskTypePurgeTrash, post.PurgeTrashWorkerHandler(post.PurgeTrashWorker{
+		Logger:   container.Log,
+		PostRepo: container.PostRepo,
+	}))
+	mux.HandleFunc(post.TaskTypeRecomputeTrending, post.RecomputeTrendingWorkerHandler(post.RecomputeTrendingWorker{
+		Logger:   container.Log,
+		PostRepo: container.PostRepo,
+	}))
+	scheduledPostWorker := post.ScheduledPostWorker{
+		Logger:      container.Log,
+		PostService: ps,
+		NotiService: container.NotificationService.(*notification.NotificationService),
+	}
+	mux.HandleFunc(post.TaskTypeScheduledPublish, post.ScheduledPublishWorkerHandler(scheduledPostWorker))
+	mux.HandleFunc(post.TaskTypeScheduledUnpublish, post.ScheduledUnpublishWorkerHandler(scheduledPostWorker))
 
 	// Route Grouping
 	postsRoutes := router.Group("/posts")
 
-	// Public routes
-	postsRoutes.GET("", handler.GetAll)
-	postsRoutes.GET("/popular", handler.GetPopularPosts)
-	postsRoutes.GET("/public/:username/:slug", handler.GetPublicPostBySlugAndUsername)
-	postsRoutes.POST("/:id/view", handler.RecordPostView) // API สำหรับนับ view
+	// Public routes (optional auth เพื่อแสดง flag "bookmarked" เมื่อ login)
+	postsRoutes.GET("", optionalAuthMiddleware.Handler(), handler.GetAll)
+	postsRoutes.GET("/popular", optionalAuthMiddleware.Handler(), handler.GetPopularPosts)
+	postsRoutes.GET("/trending", optionalAuthMiddleware.Handler(), handler.GetTrendingPosts)
+	// optional auth ให้เจ้าของและผู้ร่วมเขียนเปิดโพสต์ protected ได้โดยไม่ต้องปลดล็อก
+	postsRoutes.GET("/public/:username/:slug", optionalAuthMiddleware.Handler(), handler.GetPublicPostBySlugAndUsername)
+	postsRoutes.POST("/public/:username/:slug/unlock", handler.UnlockPost)
+	postsRoutes.POST("/:id/view", optionalAuthMiddleware.Handler(), handler.RecordPostView) // API สำหรับนับ view
+	// gin บังคับให้ wildcard ตำแหน่งเดียวกันของ GET ใช้ชื่อเดียวกัน ค่าที่ส่งมาคือ post id
+	postsRoutes.GET("/:short_slug/related", relatedHandler.GetRelated)
 
 	// Protected routes
 	postsRoutes.Use(authMiddleware.Handler())
 	{
 		postsRoutes.POST("", handler.Create)
+		postsRoutes.POST("/import", handler.ImportMarkdown)
 		postsRoutes.GET("/:short_slug", handler.GetByShortSlug)
 		postsRoutes.GET("/my-posts", handler.MyPost)
+		postsRoutes.GET("/trash", handler.GetTrash)
+		postsRoutes.PUT("/trash/:id/restore", handler.RestorePost)
+		postsRoutes.GET("/export", handler.ExportAllPosts)
+		postsRoutes.GET("/:short_slug/export", handler.ExportPost)
 		postsRoutes.PUT("/publish/:short_slug", handler.Publish)
 		postsRoutes.PUT("/unpublish/:short_slug", handler.Unpublish)
+		postsRoutes.PUT("/visibility/:short_slug", handler.UpdateVisibility)
+		postsRoutes.PUT("/schedule/:short_slug", handler.Reschedule)
+		postsRoutes.DELETE("/schedule/:short_slug", handler.CancelSchedule)
 		postsRoutes.DELETE("/:id", handler.Delete)
+
+		// Revision history
+		postsRoutes.GET("/:short_slug/revisions", handler.GetRevisions)
+		postsRoutes.GET("/:short_slug/revisions/diff", handler.DiffRevisions)
+		postsRoutes.GET("/:short_slug/revisions/:revision_id", handler.GetRevision)
+		postsRoutes.PUT("/:short_slug/revisions/:revision_id/restore", handler.RestoreRevision)
 	}
 }
@@ -0,0 +1,66 @@
+package post
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondScheduleError แปลง error ของการตั้งเวลาเป็น HTTP response
+func respondScheduleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", "You do not have permission for this post")
+	case errors.Is(err, errs.ErrInvalidSchedule):
+		response.JSONError(c, http.StatusBadRequest, "Invalid schedule", err.Error())
+	case errors.Is(err, errs.ErrNotScheduled):
+		response.JSONError(c, http.StatusConflict, "Post is not scheduled", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// Reschedule เลื่อนเวลา publish / unpublish ของโพสต์
+func (h *PostHandler) Reschedule(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	var req post.ScheduleRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	schedule, err := h.service.ReschedulePost(c.Param("short_slug"), req, user)
+	if err != nil {
+		respondScheduleError(c, "Failed to reschedule post", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Post rescheduled successfully", schedule)
+}
+
+// CancelSchedule ยกเลิกการตั้งเวลา publish / unpublish ของโพสต์
+func (h *PostHandler) CancelSchedule(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	schedule, err := h.service.CancelSchedule(c.Param("short_slug"), user)
+	if err != nil {
+		respondScheduleError(c, "Failed to cancel schedule", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Post schedule cancelled successfully", schedule)
+}
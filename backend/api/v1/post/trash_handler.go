@@ -0,0 +1,57 @@
+package post
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondTrashError แปลง error ของถังขยะเป็น HTTP response
+func respondTrashError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", "The post is not in the trash")
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", "Only the author can restore this post")
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// GetTrash โพสต์ที่ผู้ใช้ลบไปและยังกู้คืนได้
+func (h *PostHandler) GetTrash(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	posts, err := h.service.GetTrash(user)
+	if err != nil {
+		respondTrashError(c, "Failed to fetch trash", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get trash successfully", posts)
+}
+
+// RestorePost กู้โพสต์จากถังขยะ (:id คือ post id)
+func (h *PostHandler) RestorePost(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	post, err := h.service.RestorePost(c.Param("id"), user)
+	if err != nil {
+		respondTrashError(c, "Failed to restore post", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Post restored successfully", post)
+}
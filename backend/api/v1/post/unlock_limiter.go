@@ -0,0 +1,91 @@
+package post
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ต่อ IP: กันคนเดียวไล่เดารหัสหลายโพสต์
+	unlockIPWindow = time.Minute
+	unlockIPLimit  = 10
+	// ต่อโพสต์ (รวมทุก IP): รหัสผ่านสั้นได้ถึง post.MinPostPasswordLength จึงต้องกันการเดาแบบกระจาย IP ด้วย
+	unlockPostWindow = 15 * time.Minute
+	unlockPostLimit  = 30
+)
+
+type unlockWindowState struct {
+	startedAt time.Time
+	count     int
+}
+
+type fixedWindowLimiter struct {
+	window  time.Duration
+	limit   int
+	clients map[string]unlockWindowState
+}
+
+func (l *fixedWindowLimiter) allow(key string, now time.Time) bool {
+	for k, state := range l.clients {
+		if now.Sub(state.startedAt) >= l.window {
+			delete(l.clients, k)
+		}
+	}
+
+	state, ok := l.clients[key]
+	if !ok || now.Sub(state.startedAt) >= l.window {
+		l.clients[key] = unlockWindowState{startedAt: now, count: 1}
+		return true
+	}
+	if state.count >= l.limit {
+		return false
+	}
+
+	state.count++
+	l.clients[key] = state
+	return true
+}
+
+func (l *fixedWindowLimiter) retryAfter(key string, now time.Time) time.Duration {
+	state, ok := l.clients[key]
+	if !ok {
+		return 0
+	}
+	return l.window - now.Sub(state.startedAt)
+}
+
+// unlockRateLimiter จำกัดการลองปลดล็อกโพสต์ protected ทั้งต่อ IP และต่อโพสต์
+// ponytail: process-local เหมือน publicSearchRateLimiter ของ ai ต้องย้ายไป Redis เมื่อรันหลาย instance
+type unlockRateLimiter struct {
+	mu      sync.Mutex
+	perIP   fixedWindowLimiter
+	perPost fixedWindowLimiter
+}
+
+func newUnlockRateLimiter() *unlockRateLimiter {
+	return &unlockRateLimiter{
+		perIP:   fixedWindowLimiter{window: unlockIPWindow, limit: unlockIPLimit, clients: make(map[string]unlockWindowState)},
+		perPost: fixedWindowLimiter{window: unlockPostWindow, limit: unlockPostLimit, clients: make(map[string]unlockWindowState)},
+	}
+}
+
+// allow คืน false และเวลาที่ต้องรอเมื่อเกิน limit ข้อใดข้อหนึ่ง
+func (l *unlockRateLimiter) allow(clientIP, username, slug string) (bool, time.Duration) {
+	now := time.Now()
+	if clientIP == "" {
+		clientIP = "unknown"
+	}
+	postKey := strings.ToLower(username) + "/" + strings.ToLower(slug)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.perIP.allow(clientIP, now) {
+		return false, l.perIP.retryAfter(clientIP, now)
+	}
+	if !l.perPost.allow(postKey, now) {
+		return false, l.perPost.retryAfter(postKey, now)
+	}
+	return true, 0
+}
@@ -0,0 +1,38 @@
+package post
+
+import "testing"
+
+func TestUnlockRateLimiter_PerIP(t *testing.T) {
+	limiter := newUnlockRateLimiter()
+	for i := 0; i < unlockIPLimit; i++ {
+		if ok, _ := limiter.allow("1.1.1.1", "alice", "post-"+string(rune('a'+i))); !ok {
+			t.Fatalf("attempt %d should be allowed", i+1)
+		}
+	}
+	ok, wait := limiter.allow("1.1.1.1", "bob", "another")
+	if ok {
+		t.Fatal("attempt over the IP limit should be rejected even for another post")
+	}
+	if wait <= 0 || wait > unlockIPWindow {
+		t.Fatalf("unexpected retry-after %v", wait)
+	}
+	if ok, _ := limiter.allow("2.2.2.2", "bob", "another"); !ok {
+		t.Fatal("a different IP should have its own window")
+	}
+}
+
+func TestUnlockRateLimiter_PerPost(t *testing.T) {
+	limiter := newUnlockRateLimiter()
+	for i := 0; i < unlockPostLimit; i++ {
+		ip := "10.0.0." + string(rune('0'+i%10)) + string(rune('0'+i/10))
+		if ok, _ := limiter.allow(ip, "alice", "secret"); !ok {
+			t.Fatalf("attempt %d should be allowed", i+1)
+		}
+	}
+	if ok, _ := limiter.allow("192.168.0.1", "Alice", "SECRET"); ok {
+		t.Fatal("attempts spread across IPs should still hit the per-post limit")
+	}
+	if ok, _ := limiter.allow("192.168.0.1", "alice", "open"); !ok {
+		t.Fatal("other posts should not be affected")
+	}
+}
@@ -0,0 +1,111 @@
+package post
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"rag-searchbot-backend/config"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondVisibilityError แปลง error ของ visibility / การปลดล็อกเป็น HTTP response
+func respondVisibilityError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", "You do not have permission for this post")
+	case errors.Is(err, errs.ErrInvalidPostKey):
+		response.JSONError(c, http.StatusUnauthorized, "Invalid key or password", err.Error())
+	case errors.Is(err, errs.ErrPostProtected):
+		response.JSONError(c, http.StatusForbidden, "This post is protected", err.Error())
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// optionalUser ผู้ใช้จาก OptionalAuthMiddleware (nil ถ้าไม่ได้ login)
+func optionalUser(c *gin.Context) *models.User {
+	if u, ok := c.Get("user"); ok {
+		if user, ok := u.(*models.User); ok {
+			return user
+		}
+	}
+	return nil
+}
+
+// postAccessToken token ปลดล็อกโพสต์ protected จาก cookie (ว่างถ้าไม่มี)
+func postAccessToken(c *gin.Context, postID string) string {
+	token, _ := c.Cookie(post.PostAccessCookieName(postID))
+	return token
+}
+
+func setPostAccessCookie(c *gin.Context, postID string, token string) {
+	cfg := config.LoadConfig()
+	isProd := cfg.AppEnv == "release"
+	maxAge := int(post.PostAccessTTL.Seconds())
+
+	for _, domain := range strings.Split(cfg.Domain, ",") {
+		c.SetCookie(post.PostAccessCookieName(postID), token, maxAge, "/", strings.TrimSpace(domain), isProd, true)
+	}
+}
+
+// UpdateVisibility ตั้งโพสต์เป็น public, unlisted หรือ protected
+func (h *PostHandler) UpdateVisibility(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req post.UpdateVisibilityRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.UpdateVisibility(c.Param("short_slug"), req, user)
+	if err != nil {
+		respondVisibilityError(c, "Failed to update visibility", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Post visibility updated successfully", result)
+}
+
+// UnlockPost ปลดล็อกโพสต์ protected ด้วย share key / รหัสผ่าน แล้วเก็บ token อายุสั้นไว้ใน cookie
+func (h *PostHandler) UnlockPost(c *gin.Context) {
+	if ok, wait := h.unlockLimiter.allow(c.ClientIP(), c.Param("username"), c.Param("slug")); !ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		response.JSONError(c, http.StatusTooManyRequests, "Too many unlock attempts", "Please try again later")
+		return
+	}
+
+	var req post.UnlockPostRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	token, result, err := h.service.UnlockPost(c.Param("username"), c.Param("slug"), req.Key)
+	if err != nil {
+		respondVisibilityError(c, "Failed to unlock post", err)
+		return
+	}
+
+	if token != "" {
+		setPostAccessCookie(c, result.PostID, token)
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Post unlocked successfully", result)
+}
@@ -0,0 +1,79 @@
+package reaction
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/reaction"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReactionHandler struct {
+	service reaction.ServiceInterface
+}
+
+func NewReactionHandler(service reaction.ServiceInterface) *ReactionHandler {
+	return &ReactionHandler{service: service}
+}
+
+// postAccessToken token ปลดล็อกโพสต์ protected จาก cookie (ว่างถ้าไม่มี)
+func postAccessToken(c *gin.Context) string {
+	token, _ := c.Cookie(post.PostAccessCookieName(c.Param("post_id")))
+	return token
+}
+
+func respondReactionError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrPostProtected):
+		response.JSONError(c, http.StatusForbidden, "This post is protected", err.Error())
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid reaction", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// GetSummary จำนวน reaction ของโพสต์ และ reaction ของผู้ใช้ที่ login อยู่
+func (h *ReactionHandler) GetSummary(c *gin.Context) {
+	var user *models.User
+	if value, exists := c.Get("user"); exists {
+		user, _ = value.(*models.User)
+	}
+
+	summary, err := h.service.GetSummary(c.Param("post_id"), user, postAccessToken(c))
+	if err != nil {
+		respondReactionError(c, "Failed to fetch reactions", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get reactions successfully", summary)
+}
+
+// Toggle กด / ยกเลิก reaction
+func (h *ReactionHandler) Toggle(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		return
+	}
+
+	var req reaction.ToggleReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.Toggle(c.Param("post_id"), req.Type, user, postAccessToken(c))
+	if err != nil {
+		respondReactionError(c, "Failed to toggle reaction", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Reaction toggled successfully", result)
+}
@@ -0,0 +1,52 @@
+package reaction
+
+import (
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/middleware"
+	"rag-searchbot-backend/internal/notification"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/reaction"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container, mux *asynq.ServeMux) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+	optionalAuthMiddleware := middleware.NewOptionalAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	reactionRepo := reaction.NewRepository(container.DB)
+	reactionService := reaction.NewService(
+		reactionRepo,
+		container.PostRepo,
+		reaction.NewTaskEnqueuer(container.AsynqClient),
+		post.NewPostAccess(container.PostRepo, container.CryptoService),
+	)
+	handler := NewReactionHandler(reactionService)
+
+	// Background jobs
+	mux.HandleFunc(reaction.TaskTypeReactionDigest, reaction.ReactionDigestWorkerHandler(reaction.DigestWorker{
+		Logger:       container.Log,
+		ReactionRepo: reactionRepo,
+		PostRepo:     container.PostRepo,
+		NotiService:  container.NotificationService.(*notification.NotificationService),
+	}))
+
+	reactionRoutes := router.Group("/reactions")
+
+	// Public routes with optional auth ("reacted" จะมีค่าเมื่อ login)
+	reactionRoutes.GET("/post/:post_id", optionalAuthMiddleware.Handler(), handler.GetSummary)
+
+	// Protected routes
+	reactionRoutes.POST("/post/:post_id", authMiddleware.Handler(), handler.Toggle)
+}
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/search"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	service search.ServiceInterface
+}
+
+func NewSearchHandler(service search.ServiceInterface) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+func respondSearchError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid search request", err.Error())
+	case errors.Is(err, errs.ErrSearchFailed):
+		response.JSONError(c, http.StatusBadGateway, "Semantic search unavailable", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// Semantic ค้นหาโพสต์ด้วยความหมาย (?mode=hybrid เพื่อรวมกับ keyword search)
+func (h *SearchHandler) Semantic(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(search.DefaultLimit)))
+
+	result, err := h.service.Search(c.Request.Context(), c.Query("q"), c.Query("mode"), limit)
+	if err != nil {
+		respondSearchError(c, "Failed to search posts", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Search posts successfully", result)
+}
@@ -0,0 +1,28 @@
+package search
+
+import (
+	"rag-searchbot-backend/internal/awsbedrock"
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/llm"
+	"rag-searchbot-backend/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	bedrockClient, err := awsbedrock.NewBedrockClient(*container.Env)
+	if err != nil {
+		container.Log.Fatal("Failed to create Bedrock client", zap.Error(err))
+	}
+	llmClient := llm.NewBedrockLLM(bedrockClient)
+
+	searchRepo := search.NewRepository(container.DB)
+	searchService := search.NewService(searchRepo, container.PostRepo, llmClient)
+	handler := NewSearchHandler(searchService)
+
+	searchRoutes := router.Group("/search")
+	{
+		searchRoutes.GET("/semantic", handler.Semantic)
+	}
+}
@@ -0,0 +1,160 @@
+package series
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/series"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/ginctx"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesHandler struct {
+	service series.ServiceInterface
+}
+
+func NewSeriesHandler(service series.ServiceInterface) *SeriesHandler {
+	return &SeriesHandler{service: service}
+}
+
+// respondSeriesError แปลง error ของ series เป็น HTTP response
+func respondSeriesError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrSeriesNotFound):
+		response.JSONError(c, http.StatusNotFound, "Series not found", err.Error())
+	case errors.Is(err, errs.ErrSeriesExists):
+		response.JSONError(c, http.StatusConflict, "Series slug already exists", err.Error())
+	case errors.Is(err, errs.ErrUserNotFound):
+		response.JSONError(c, http.StatusNotFound, "User not found", err.Error())
+	case errors.Is(err, errs.ErrPostNotFound):
+		response.JSONError(c, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, errs.ErrUnauthorized):
+		response.JSONError(c, http.StatusForbidden, "Forbidden", err.Error())
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// ListByAuthor series ของผู้เขียน :username
+func (h *SeriesHandler) ListByAuthor(c *gin.Context) {
+	list, err := h.service.ListByAuthor(c.Param("username"))
+	if err != nil {
+		respondSeriesError(c, "Failed to fetch series", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get series successfully", list)
+}
+
+// GetPage หน้า series พร้อมโพสต์ตามลำดับ
+func (h *SeriesHandler) GetPage(c *gin.Context) {
+	page, err := h.service.GetPage(c.Param("username"), c.Param("slug"))
+	if err != nil {
+		respondSeriesError(c, "Failed to fetch series", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get series successfully", page)
+}
+
+func (h *SeriesHandler) MySeries(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	list, err := h.service.GetMySeries(user)
+	if err != nil {
+		respondSeriesError(c, "Failed to fetch series", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get my series successfully", list)
+}
+
+func (h *SeriesHandler) Create(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req series.SeriesRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.Create(req, user)
+	if err != nil {
+		respondSeriesError(c, "Failed to create series", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusCreated, "Series created successfully", result)
+}
+
+func (h *SeriesHandler) Update(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req series.SeriesRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.Update(c.Param("id"), req, user)
+	if err != nil {
+		respondSeriesError(c, "Failed to update series", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Series updated successfully", result)
+}
+
+// SetPosts เพิ่ม / ลบ / จัดลำดับโพสต์ใน series ด้วยรายการ post_ids ตามลำดับที่ต้องการ
+func (h *SeriesHandler) SetPosts(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	var req series.SeriesPostsRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.SetPosts(c.Param("id"), req, user)
+	if err != nil {
+		respondSeriesError(c, "Failed to update series posts", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Series posts updated successfully", result)
+}
+
+func (h *SeriesHandler) Delete(c *gin.Context) {
+	user, ok := ginctx.GetUserFromContext(c)
+	if !ok || user == nil {
+		response.JSONError(c, http.StatusUnauthorized, "User not found in context", "User context is missing")
+		return
+	}
+
+	if err := h.service.Delete(c.Param("id"), user); err != nil {
+		respondSeriesError(c, "Failed to delete series", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Series deleted successfully", nil)
+}
@@ -0,0 +1,37 @@
+package series
+
+import (
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/middleware"
+	"rag-searchbot-backend/internal/series"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	seriesService := series.NewService(series.NewRepository(container.DB), container.UserService)
+	handler := NewSeriesHandler(seriesService)
+
+	seriesRoutes := router.Group("/series")
+
+	// Public routes
+	seriesRoutes.GET("/:username", handler.ListByAuthor)
+	seriesRoutes.GET("/:username/:slug", handler.GetPage)
+
+	// Protected routes
+	seriesRoutes.Use(authMiddleware.Handler())
+	{
+		seriesRoutes.GET("/my-series", handler.MySeries)
+		seriesRoutes.POST("", handler.Create)
+		seriesRoutes.PUT("/:id", handler.Update)
+		seriesRoutes.PUT("/:id/posts", handler.SetPosts)
+		seriesRoutes.DELETE("/:id", handler.Delete)
+	}
+}
@@ -0,0 +1,64 @@
+package sitemap
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/sitemap"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SitemapHandler struct {
+	service sitemap.ServiceInterface
+}
+
+func NewSitemapHandler(service sitemap.ServiceInterface) *SitemapHandler {
+	return &SitemapHandler{service: service}
+}
+
+// pageParam แปลง ":page" รูปแบบ "2.xml" เป็นเลขหน้า (0 ถ้าไม่ถูกต้อง)
+func pageParam(c *gin.Context) int {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil {
+		return 0
+	}
+	return page
+}
+
+func writeSitemap(c *gin.Context, body []byte, err error) {
+	if err != nil {
+		if errors.Is(err, errs.ErrPageNotFound) {
+			response.JSONError(c, http.StatusNotFound, "Sitemap not found", err.Error())
+			return
+		}
+		response.JSONError(c, http.StatusInternalServerError, "Failed to build sitemap", err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+func (h *SitemapHandler) Index(c *gin.Context) {
+	body, err := h.service.Index()
+	writeSitemap(c, body, err)
+}
+
+func (h *SitemapHandler) Posts(c *gin.Context) {
+	body, err := h.service.PostsPage(pageParam(c))
+	writeSitemap(c, body, err)
+}
+
+func (h *SitemapHandler) Authors(c *gin.Context) {
+	body, err := h.service.AuthorsPage(pageParam(c))
+	writeSitemap(c, body, err)
+}
+
+func (h *SitemapHandler) Robots(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.String(http.StatusOK, h.service.Robots())
+}
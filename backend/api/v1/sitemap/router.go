@@ -0,0 +1,24 @@
+package sitemap
+
+import (
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/sitemap"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes ต้องผูกกับ root ของ server เพราะ crawler หา robots.txt และ sitemap.xml ที่ root เสมอ
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	sitemapService := sitemap.NewService(
+		sitemap.NewRepository(container.DB),
+		container.CacheService,
+		container.Env.AppUrl,
+		container.Env.CoreUrl,
+	)
+	handler := NewSitemapHandler(sitemapService)
+
+	router.GET("/robots.txt", handler.Robots)
+	router.GET("/sitemap.xml", handler.Index)
+	router.GET("/sitemaps/posts/:page", handler.Posts)
+	router.GET("/sitemaps/authors/:page", handler.Authors)
+}
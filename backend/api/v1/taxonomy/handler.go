@@ -0,0 +1,156 @@
+package taxonomy
+
+import (
+	"errors"
+	"net/http"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/taxonomy"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxonomyHandler struct {
+	service     taxonomy.ServiceInterface
+	postService *post.PostService
+}
+
+func NewTaxonomyHandler(service taxonomy.ServiceInterface, postService *post.PostService) *TaxonomyHandler {
+	return &TaxonomyHandler{service: service, postService: postService}
+}
+
+// respondTaxonomyError แปลง error ของ tag / category เป็น HTTP response
+func respondTaxonomyError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrTagNotFound):
+		response.JSONError(c, http.StatusNotFound, "Tag not found", err.Error())
+	case errors.Is(err, errs.ErrCategoryNotFound):
+		response.JSONError(c, http.StatusNotFound, "Category not found", err.Error())
+	case errors.Is(err, errs.ErrCategoryExists):
+		response.JSONError(c, http.StatusConflict, "Category already exists", err.Error())
+	case errors.Is(err, errs.ErrInvalidPayload):
+		response.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	default:
+		response.JSONError(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+func parsePagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	return page, limit
+}
+
+// SuggestTags autocomplete tag ตามคำที่พิมพ์ (?q=&limit=)
+func (h *TaxonomyHandler) SuggestTags(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	tags, err := h.service.SuggestTags(c.Query("q"), limit)
+	if err != nil {
+		respondTaxonomyError(c, "Failed to fetch tags", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get tags successfully", tags)
+}
+
+// GetPostsByTag ดึงโพสต์ที่ติด tag นี้
+func (h *TaxonomyHandler) GetPostsByTag(c *gin.Context) {
+	tag, err := h.service.GetTagByName(c.Param("name"))
+	if err != nil {
+		respondTaxonomyError(c, "Failed to fetch tag", err)
+		return
+	}
+
+	page, limit := parsePagination(c)
+	posts, err := h.postService.GetPostsByTag(tag.Name, page, limit)
+	if err != nil {
+		respondTaxonomyError(c, "Failed to fetch posts", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get posts by tag successfully", posts)
+}
+
+func (h *TaxonomyHandler) ListCategories(c *gin.Context) {
+	categories, err := h.service.ListCategories()
+	if err != nil {
+		respondTaxonomyError(c, "Failed to fetch categories", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get categories successfully", categories)
+}
+
+// GetPostsByCategory ดึงโพสต์ใน category นี้
+func (h *TaxonomyHandler) GetPostsByCategory(c *gin.Context) {
+	category, err := h.service.GetCategoryByName(c.Param("name"))
+	if err != nil {
+		respondTaxonomyError(c, "Failed to fetch category", err)
+		return
+	}
+
+	page, limit := parsePagination(c)
+	posts, err := h.postService.GetPostsByCategory(category.Name, page, limit)
+	if err != nil {
+		respondTaxonomyError(c, "Failed to fetch posts", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Get posts by category successfully", posts)
+}
+
+func (h *TaxonomyHandler) CreateCategory(c *gin.Context) {
+	var req taxonomy.CategoryRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	category, err := h.service.CreateCategory(req)
+	if err != nil {
+		respondTaxonomyError(c, "Failed to create category", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusCreated, "Category created successfully", category)
+}
+
+func (h *TaxonomyHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid category ID", err.Error())
+		return
+	}
+
+	var req taxonomy.CategoryRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	category, err := h.service.UpdateCategory(uint(id), req)
+	if err != nil {
+		respondTaxonomyError(c, "Failed to update category", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Category updated successfully", category)
+}
+
+func (h *TaxonomyHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.JSONError(c, http.StatusBadRequest, "Invalid category ID", err.Error())
+		return
+	}
+
+	if err := h.service.DeleteCategory(uint(id)); err != nil {
+		respondTaxonomyError(c, "Failed to delete category", err)
+		return
+	}
+
+	response.JSONSuccess(c, http.StatusOK, "Category deleted successfully", nil)
+}
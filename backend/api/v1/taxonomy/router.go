@@ -0,0 +1,46 @@
+package taxonomy
+
+import (
+	"log"
+	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/internal/middleware"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/taxonomy"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, container *container.Container) {
+	authMiddleware := middleware.NewAuthMiddleware(
+		container.UserService,
+		container.CryptoService,
+		container.CacheService,
+		container.Log,
+	)
+
+	postService, ok := container.PostService.(*post.PostService)
+	if !ok {
+		log.Fatal("[FATAL] Failed to cast postService to *post.PostService")
+	}
+	taxonomyService := taxonomy.NewService(taxonomy.NewRepository(container.DB))
+	handler := NewTaxonomyHandler(taxonomyService, postService)
+
+	// Tags (public)
+	tagRoutes := router.Group("/tags")
+	tagRoutes.GET("", handler.SuggestTags)
+	tagRoutes.GET("/:name/posts", handler.GetPostsByTag)
+
+	// Categories
+	categoryRoutes := router.Group("/categories")
+	categoryRoutes.GET("", handler.ListCategories)
+	categoryRoutes.GET("/:name/posts", handler.GetPostsByCategory)
+
+	// Admin only
+	categoryRoutes.Use(authMiddleware.Handler(), middleware.RequireRole(models.AdminUser))
+	{
+		categoryRoutes.POST("", handler.CreateCategory)
+		categoryRoutes.PUT("/:id", handler.UpdateCategory)
+		categoryRoutes.DELETE("/:id", handler.DeleteCategory)
+	}
+}
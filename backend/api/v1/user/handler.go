@@ -1,10 +1,12 @@
 package user
 
 import (
+	"errors"
 	"strings"
 
 	"rag-searchbot-backend/internal/post"
 	"rag-searchbot-backend/internal/user"
+	"rag-searchbot-backend/pkg/errs"
 	"rag-searchbot-backend/pkg/ginctx"
 	"rag-searchbot-backend/pkg/response"
 
@@ -168,7 +170,11 @@ func (h *UserHandler) GetUserProfileWithPosts(c *gin.Context) {
 	}
 
 	// Get user posts
-	posts, err := h.postService.GetPostsByAuthor(username, 1, 10)
+	posts, err := h.postService.GetPostsByAuthor(username, post.ParsePostListOptions(c))
+	if errors.Is(err, errs.ErrInvalidCursor) {
+		response.JSONError(c, 400, "Invalid cursor", err.Error())
+		return
+	}
 	if err != nil {
 		response.JSONError(c, 500, "Internal Server Error", "Failed to get user posts")
 		return
@@ -4,14 +4,27 @@ import (
 	"log"
 	"os"
 	"rag-searchbot-backend/api/v1/ai"
+	"rag-searchbot-backend/api/v1/analytics"
 	"rag-searchbot-backend/api/v1/auth"
+	"rag-searchbot-backend/api/v1/bookmark"
+	"rag-searchbot-backend/api/v1/collaborator"
+	"rag-searchbot-backend/api/v1/comment"
+	"rag-searchbot-backend/api/v1/feed"
+	"rag-searchbot-backend/api/v1/follow"
+	"rag-searchbot-backend/api/v1/history"
 	"rag-searchbot-backend/api/v1/media"
 	"rag-searchbot-backend/api/v1/notification"
 	"rag-searchbot-backend/api/v1/post"
+	"rag-searchbot-backend/api/v1/reaction"
+	"rag-searchbot-backend/api/v1/search"
+	"rag-searchbot-backend/api/v1/series"
+	"rag-searchbot-backend/api/v1/sitemap"
+	"rag-searchbot-backend/api/v1/taxonomy"
 	"rag-searchbot-backend/api/v1/user"
 	"rag-searchbot-backend/api/v1/ws"
 	"rag-searchbot-backend/config"
 	"rag-searchbot-backend/internal/container"
+	"rag-searchbot-backend/pkg/cursor"
 	"rag-searchbot-backend/pkg/logger"
 	"strings"
 	"time"
@@ -31,6 +44,13 @@ func main() {
 	defer logger.Log.Sync()
 	logger.Log.Info("Application started")
 
+	configureContentPolicy(cfg)
+
+	cursor.Configure(cfg.CursorSecret)
+	if cfg.CursorSecret == "" {
+		logger.Log.Warn("CURSOR_SECRET is not set, pagination cursors will not survive a restart")
+	}
+
 	// กำหนด Mode การทำงาน
 	if cfg.AppEnv == "release" {
 
@@ -75,6 +95,16 @@ func main() {
 		}
 	}()
 
+	// Scheduler สำหรับ background job ที่ต้องรันเป็นรอบ
+	scheduler := asynq.NewScheduler(asynq.RedisClientOpt{Addr: cfg.RedisAddr}, nil)
+	registerPeriodicTasks(scheduler)
+
+	go func() {
+		if err := scheduler.Run(); err != nil {
+			logger.Log.Fatal("Scheduler error", zap.Error(err))
+		}
+	}()
+
 	logger.Log.Info("Cache service initialized successfully")
 
 	containerDI, err := container.InitializeContainer(&cfg, db, logger.Log, redisClient, 24*time.Hour, asynqClient)
@@ -119,6 +149,20 @@ func main() {
 	user.RegisterRoutes(apiGroup, containerDI)
 	ai.RegisterRoutes(apiGroup, containerDI, mux)
 	notification.RegisterRoutes(apiGroup, containerDI)
+	taxonomy.RegisterRoutes(apiGroup, containerDI)
+	comment.RegisterRoutes(apiGroup, containerDI)
+	collaborator.RegisterRoutes(apiGroup, containerDI)
+	reaction.RegisterRoutes(apiGroup, containerDI, mux)
+	search.RegisterRoutes(apiGroup, containerDI)
+	feed.RegisterRoutes(apiGroup, containerDI)
+	series.RegisterRoutes(apiGroup, containerDI)
+	analytics.RegisterRoutes(apiGroup, containerDI, mux)
+	bookmark.RegisterRoutes(apiGroup, containerDI)
+	follow.RegisterRoutes(apiGroup, containerDI)
+	history.RegisterRoutes(apiGroup, containerDI)
+
+	// robots.txt และ sitemap อยู่ที่ root ไม่ใช่ใต้ /api/v1
+	sitemap.RegisterRoutes(&r.RouterGroup, containerDI)
 
 	r.Run(":8088")
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/url"
+	"rag-searchbot-backend/config"
+	"rag-searchbot-backend/pkg/logger"
+	"rag-searchbot-backend/pkg/sanitize"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// configureContentPolicy ตั้ง policy ของ HTML ที่ publish จาก env
+// ถ้าไม่ได้ตั้ง HTML_IMAGE_HOSTS จะอนุญาตเฉพาะรูปจาก host ของ Chibisafe
+func configureContentPolicy(cfg config.Config) {
+	imageHosts := splitList(cfg.HTMLImageHosts)
+	if len(imageHosts) == 0 && cfg.ChibisafeURL != "" {
+		if u, err := url.Parse(cfg.ChibisafeURL); err == nil && u.Host != "" {
+			imageHosts = []string{u.Host}
+		}
+	}
+
+	opts := sanitize.Options{
+		IframeHosts: splitList(cfg.HTMLIframeHosts),
+		ImageHosts:  imageHosts,
+		LinkRel:     cfg.HTMLLinkRel,
+	}
+	sanitize.Configure(opts)
+
+	logger.Log.Info("Configured HTML content policy",
+		zap.Strings("iframe_hosts", opts.IframeHosts),
+		zap.Strings("image_hosts", opts.ImageHosts))
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
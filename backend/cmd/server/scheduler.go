@@ -0,0 +1,34 @@
+package main
+
+import (
+	internalAnalytics "rag-searchbot-backend/internal/analytics"
+	internalPost "rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// periodicTasks คือ background job ที่ scheduler จะ enqueue ตามรอบเวลา (cron spec)
+var periodicTasks = []struct {
+	CronSpec string
+	TaskType string
+}{
+	{CronSpec: "@daily", TaskType: internalPost.TaskTypePruneRevisions},
+	{CronSpec: "@daily", TaskType: internalPost.TaskTypePurgeTrash},
+	{CronSpec: "@every 15m", TaskType: internalPost.TaskTypeRecomputeTrending},
+	{CronSpec: "@hourly", TaskType: internalAnalytics.TaskTypeAggregateDaily},
+}
+
+func registerPeriodicTasks(scheduler *asynq.Scheduler) {
+	for _, pt := range periodicTasks {
+		entryID, err := scheduler.Register(pt.CronSpec, asynq.NewTask(pt.TaskType, nil))
+		if err != nil {
+			logger.Log.Fatal("Failed to register periodic task", zap.String("task_type", pt.TaskType), zap.Error(err))
+		}
+		logger.Log.Info("Registered periodic task",
+			zap.String("task_type", pt.TaskType),
+			zap.String("cron", pt.CronSpec),
+			zap.String("entry_id", entryID))
+	}
+}
@@ -45,6 +45,17 @@ type Config struct {
 	AWSSecretAccessKey string
 	AWSBedrockLLMModel string
 	AWSBedrockEmbeddingModel string
+
+	// HTML sanitization policy (comma-separated lists)
+	HTMLIframeHosts string
+	HTMLImageHosts  string
+	HTMLLinkRel     string
+
+	// Secret สำหรับเซ็น cookie ที่ปลดล็อกโพสต์ protected
+	PostAccessSecret string
+
+	// Secret สำหรับเซ็น cursor ของการแบ่งหน้า (ทุก instance ต้องใช้ค่าเดียวกัน)
+	CursorSecret string
 }
 
 func LoadConfig() Config {
@@ -115,5 +126,10 @@ func LoadConfig() Config {
 		AWSSecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
 		AWSBedrockLLMModel: os.Getenv("AWS_BEDROCK_LLM_MODEL"),
 		AWSBedrockEmbeddingModel: os.Getenv("AWS_BEDROCK_EMBEDDING_MODEL"),
+		HTMLIframeHosts:    os.Getenv("HTML_IFRAME_HOSTS"),
+		HTMLImageHosts:     os.Getenv("HTML_IMAGE_HOSTS"),
+		HTMLLinkRel:        os.Getenv("HTML_LINK_REL"),
+		PostAccessSecret:   os.Getenv("POST_ACCESS_SECRET"),
+		CursorSecret:       os.Getenv("CURSOR_SECRET"),
 	}
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"log"
 	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/tiptap"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -37,6 +39,9 @@ func ConnectDatabase() *gorm.DB {
 	// 	log.Fatalf("Failed to alter table ai_responses: %v", err)
 	// }
 
+	// ประวัติการอ่านเติมจาก post_views เฉพาะตอนสร้างตารางครั้งแรก ไม่งั้นรายการที่ผู้ใช้ลบไปจะกลับมา
+	backfillHistory := !db.Migrator().HasTable(&models.ReadingHistory{})
+
 	// ทำ Migration ให้กับทุกตาราง
 	err = db.Set("gorm:foreign_key_constraints", true).AutoMigrate(
 		&models.User{},
@@ -51,12 +56,40 @@ func ConnectDatabase() *gorm.DB {
 		&models.ImageUpload{},
 		&models.QueueTaskLog{},
 		&models.PostView{},
+		&models.PostRevision{},
+		&models.PostReaction{},
+		&models.PostCentroid{},
+		&models.Series{},
+		&models.SeriesPost{},
+		&models.PostCollaborator{},
+		&models.PostDailyStat{},
+		&models.PostReferrerDailyStat{},
+		&models.PostScore{},
+		&models.BookmarkFolder{},
+		&models.Bookmark{},
+		&models.Follow{},
+		&models.ReadingHistory{},
+		&models.ReadingHistorySetting{},
 	)
 
 	if err != nil {
 		log.Fatal("[ERROR] Migration failed:", err)
 	}
 
+	if err := migrateSearchIndex(db); err != nil {
+		log.Fatal("[ERROR] Search index migration failed:", err)
+	}
+
+	if err := migratePostVisibility(db); err != nil {
+		log.Fatal("[ERROR] Post visibility migration failed:", err)
+	}
+
+	if backfillHistory {
+		if err := backfillReadingHistory(db); err != nil {
+			log.Fatal("[ERROR] Reading history backfill failed:", err)
+		}
+	}
+
 	// เก็บ instance ของ DB ไว้ในตัวแปร DB
 	DB = db
 	log.Println("[INFO] Database connected & migration completed successfully!")
@@ -83,3 +116,126 @@ func ConnectRedis() *redis.Client {
 	}
 	return client
 }
+
+// migrateSearchIndex สร้าง search_vector (generated column) พร้อม GIN index สำหรับ full-text search
+// และ trigram index สำหรับภาษาไทยซึ่งไม่มีการเว้นวรรคระหว่างคำ ทำให้ tsvector ตัดคำได้ไม่ดี
+// expression ของ trigram index ต้องตรงกับ post.SearchDocument
+// รวมถึง HNSW index ของ embeddings (semantic search ข้ามทุกโพสต์) และของ centroid (related posts)
+func migrateSearchIndex(db *gorm.DB) error {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		`ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+			setweight(to_tsvector('simple', coalesce(search_text, '')), 'C')
+		) STORED`,
+		"CREATE INDEX IF NOT EXISTS idx_posts_search_vector ON posts USING GIN (search_vector)",
+		`CREATE INDEX IF NOT EXISTS idx_posts_search_document_trgm ON posts USING GIN (
+			(coalesce(title, '') || ' ' || coalesce(description, '') || ' ' || coalesce(search_text, '')) gin_trgm_ops
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_embeddings_vector_cosine ON embeddings USING hnsw (vector vector_cosine_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_post_centroids_vector_cosine ON post_centroids USING hnsw (vector vector_cosine_ops)",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := backfillSearchText(db); err != nil {
+		return err
+	}
+	return backfillPostCentroids(db)
+}
+
+// migratePostVisibility โพสต์เก่าที่มี key เคยถูกซ่อนทั้งหมด ให้เป็น protected แทน
+// key เดิมไม่ได้ hash ไว้จึงปลดล็อกไม่ได้จนกว่าเจ้าของจะตั้งรหัสใหม่ (เหมือนพฤติกรรมเดิม)
+func migratePostVisibility(db *gorm.DB) error {
+	return db.Model(&models.Post{}).
+		Where("key IS NOT NULL AND key <> '' AND visibility = ?", models.VisibilityPublic).
+		Update("visibility", models.VisibilityProtected).Error
+}
+
+// backfillReadingHistory สร้างประวัติการอ่านจาก view เดิมของผู้ใช้ที่ login (เวลาอ่านล่าสุดต่อโพสต์)
+func backfillReadingHistory(db *gorm.DB) error {
+	return db.Exec(`INSERT INTO reading_histories (user_id, post_id, last_viewed_at, created_at)
+		SELECT user_id, post_id, MAX(viewed_at), MIN(viewed_at)
+		FROM post_views
+		WHERE user_id IS NOT NULL AND deleted_at IS NULL
+		GROUP BY user_id, post_id
+		ON CONFLICT (user_id, post_id) DO NOTHING`).Error
+}
+
+// backfillSearchText เติม search_text ให้โพสต์เก่าที่สร้างก่อนมี column นี้
+func backfillSearchText(db *gorm.DB) error {
+	const batchSize = 200
+	total := 0
+
+	for {
+		var posts []models.Post
+		err := db.Unscoped().
+			Select("id", "content").
+			Where("search_text IS NULL").
+			Limit(batchSize).
+			Find(&posts).Error
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			break
+		}
+
+		for _, post := range posts {
+			err := db.Unscoped().Model(&models.Post{}).
+				Where("id = ?", post.ID).
+				Update("search_text", tiptap.ExtractTextFromTiptap(post.Content)).Error
+			if err != nil {
+				return err
+			}
+		}
+		total += len(posts)
+	}
+
+	if total > 0 {
+		log.Printf("[INFO] Backfilled search_text for %d posts", total)
+	}
+	return nil
+}
+
+// backfillPostCentroids สร้าง centroid ให้โพสต์ที่ embed ไว้ก่อนมีตาราง post_centroids
+// ใช้ AVG ของ pgvector ซึ่งเท่ากับค่าเฉลี่ยที่ related.Service คำนวณตอน embed เสร็จ
+func backfillPostCentroids(db *gorm.DB) error {
+	const batchSize = 200
+	total := 0
+
+	for {
+		var postIDs []uuid.UUID
+		err := db.Model(&models.Embedding{}).
+			Distinct("post_id").
+			Where("NOT EXISTS (SELECT 1 FROM post_centroids c WHERE c.post_id = embeddings.post_id)").
+			Limit(batchSize).
+			Pluck("post_id", &postIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			break
+		}
+
+		err = db.Exec(`INSERT INTO post_centroids (post_id, vector, updated_at)
+			SELECT post_id, AVG(vector), NOW()
+			FROM embeddings
+			WHERE post_id IN ? AND deleted_at IS NULL
+			GROUP BY post_id
+			ON CONFLICT (post_id) DO NOTHING`, postIDs).Error
+		if err != nil {
+			return err
+		}
+		total += len(postIDs)
+	}
+
+	if total > 0 {
+		log.Printf("[INFO] Backfilled post_centroids for %d posts", total)
+	}
+	return nil
+}
@@ -37,27 +37,27 @@ func NewAIService(posRepo post.PostRepositoryInterface, enqueuer *TaskEnqueuer,
 }
 
 func (s *AIService) OpenAIMode(postID string, userData *models.User) (bool, error) {
-	post, err := s.PosRepo.GetByID(postID)
+	p, err := s.PosRepo.GetByID(postID)
 	if err != nil {
 		return false, err
 	}
-	if post == nil {
+	if p == nil {
 		return false, nil
 	}
 
-	if post.AIChatOpen {
+	if p.AIChatOpen {
 		return false, nil // AI chat already open
 	}
 
-	if post.AuthorID != userData.ID {
-		return false, nil // User is not the author of the post
+	if err := post.Authorize(s.PosRepo, p, userData, post.ActionPublish); err != nil {
+		return false, nil // User is neither the author nor an editor of the post
 	}
 
 	// Reader AI now runs locally in the browser, so enabling it does not need
 	// server-side embeddings or an LLM worker.
-	post.AIChatOpen = true
-	post.AIReady = true
-	if err := s.PosRepo.Update(post); err != nil {
+	p.AIChatOpen = true
+	p.AIReady = true
+	if err := s.PosRepo.Update(p); err != nil {
 		return false, err
 	}
 
@@ -66,25 +66,25 @@ func (s *AIService) OpenAIMode(postID string, userData *models.User) (bool, erro
 
 // DisableOpenAIMode disables the OpenAI mode for a post
 func (s *AIService) DisableOpenAIMode(postID string, userData *models.User) (bool, error) {
-	post, err := s.PosRepo.GetByID(postID)
+	p, err := s.PosRepo.GetByID(postID)
 	if err != nil {
 		return false, err
 	}
-	if post == nil {
+	if p == nil {
 		return false, nil
 	}
 
-	if !post.AIChatOpen {
+	if !p.AIChatOpen {
 		return false, nil // AI chat already disabled
 	}
 
-	if post.AuthorID != userData.ID {
-		return false, nil // User is not the author of the post
+	if err := post.Authorize(s.PosRepo, p, userData, post.ActionPublish); err != nil {
+		return false, nil // User is neither the author nor an editor of the post
 	}
 
-	post.AIChatOpen = false
-	post.AIReady = false
-	err = s.PosRepo.Update(post)
+	p.AIChatOpen = false
+	p.AIReady = false
+	err = s.PosRepo.Update(p)
 	if err != nil {
 		return false, err
 	}
@@ -12,6 +12,7 @@ import (
 	"rag-searchbot-backend/internal/models"
 	"rag-searchbot-backend/internal/notification"
 	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/related"
 	"strings"
 
 	"rag-searchbot-backend/pkg/tiptap"
@@ -22,9 +23,10 @@ import (
 )
 
 type EmbedPostWorker struct {
-	Logger      *zap.Logger
-	PostRepo    post.PostRepositoryInterface
-	NotiService *notification.NotificationService
+	Logger         *zap.Logger
+	PostRepo       post.PostRepositoryInterface
+	NotiService    *notification.NotificationService
+	RelatedService related.ServiceInterface
 }
 
 const (
@@ -96,8 +98,15 @@ func NewEmbedPostWorkerHandler(deps EmbedPostWorker) asynq.HandlerFunc {
 		}
 		deps.Logger.Info("Inserted all chunk embeddings", zap.String("post_id", postID), zap.Int("chunks", len(embeddings)))
 
-		// Update post status
-		updatedPost := payload.Post
+		// centroid ใช้หา related posts ถ้าพลาดก็ยังเปิด AI mode ได้ตามปกติ
+		if deps.RelatedService != nil {
+			if err := deps.RelatedService.RefreshCentroid(payload.Post.ID, embeddings); err != nil {
+				deps.Logger.Warn("Failed to refresh post centroid", zap.Error(err), zap.String("post_id", postID))
+			}
+		}
+
+		// Update post status จากโพสต์ที่เพิ่งโหลด ไม่ใช้ title/html ใน payload ที่อาจเก่ากว่า
+		updatedPost := *existingPost
 		updatedPost.AIChatOpen = true
 		updatedPost.AIReady = true
 		updatedPost.Published = true
@@ -0,0 +1,85 @@
+package analytics
+
+// TotalsDTO ผลรวมของช่วงเวลา unique_visitors / logged_in_viewers เป็นผลรวมรายวัน (คนเดียวกันต่างวันนับแยก)
+type TotalsDTO struct {
+	Views           int64 `json:"views"`
+	UniqueVisitors  int64 `json:"unique_visitors"`
+	LoggedInViewers int64 `json:"logged_in_viewers"`
+	AIChats         int64 `json:"ai_chats"`
+	Likes           int64 `json:"likes"`
+}
+
+// DailyPointDTO หนึ่งจุดของกราฟรายวัน (วันที่ไม่มีข้อมูลเป็น 0)
+type DailyPointDTO struct {
+	Date string `json:"date"` // YYYY-MM-DD (UTC)
+	TotalsDTO
+}
+
+type TopPostDTO struct {
+	PostID string `json:"post_id"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+	Author string `json:"author"`
+	TotalsDTO
+}
+
+type ReferrerDTO struct {
+	Referrer string `json:"referrer"` // host หรือ "direct"
+	Views    int64  `json:"views"`
+}
+
+// OverviewDTO ภาพรวมของโพสต์ทั้งหมดของผู้เขียน หรือทั้งเว็บสำหรับ admin
+type OverviewDTO struct {
+	Range     string          `json:"range"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Totals    TotalsDTO       `json:"totals"`
+	Series    []DailyPointDTO `json:"series"`
+	TopPosts  []TopPostDTO    `json:"top_posts"`
+	Referrers []ReferrerDTO   `json:"referrers"`
+}
+
+// PostAnalyticsDTO สถิติของโพสต์เดียว
+type PostAnalyticsDTO struct {
+	PostID    string          `json:"post_id"`
+	Title     string          `json:"title"`
+	Slug      string          `json:"slug"`
+	Range     string          `json:"range"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Totals    TotalsDTO       `json:"totals"`
+	Series    []DailyPointDTO `json:"series"`
+	Referrers []ReferrerDTO   `json:"referrers"`
+}
+
+func mapTotals(row TotalsRow) TotalsDTO {
+	return TotalsDTO{
+		Views:           row.Views,
+		UniqueVisitors:  row.UniqueVisitors,
+		LoggedInViewers: row.LoggedInViewers,
+		AIChats:         row.AIChats,
+		Likes:           row.Likes,
+	}
+}
+
+func mapTopPosts(rows []TopPostRow) []TopPostDTO {
+	result := make([]TopPostDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, TopPostDTO{
+			PostID:    row.PostID.String(),
+			Title:     row.Title,
+			Slug:      row.Slug,
+			Author:    row.Username,
+			TotalsDTO: mapTotals(row.TotalsRow),
+		})
+	}
+	return result
+}
+
+func mapReferrers(rows []ReferrerRow) []ReferrerDTO {
+	result := make([]ReferrerDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, ReferrerDTO{Referrer: row.Referrer, Views: row.Views})
+	}
+	return result
+}
@@ -0,0 +1,205 @@
+package analytics
+
+import (
+	"database/sql"
+	"rag-searchbot-backend/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const dateLayout = "2006-01-02"
+
+type RepositoryInterface interface {
+	AggregateDaily(since time.Time) error
+	LatestAggregatedDate() (*time.Time, error)
+	GetDailySeries(scope Scope, from, to time.Time) ([]DailyRow, error)
+	GetTotals(scope Scope, from, to time.Time) (*TotalsRow, error)
+	GetTopPosts(scope Scope, from, to time.Time, limit int) ([]TopPostRow, error)
+	GetReferrers(scope Scope, from, to time.Time, limit int) ([]ReferrerRow, error)
+}
+
+// Scope ขอบเขตของสถิติ: โพสต์เดียว, โพสต์ทั้งหมดของผู้เขียน หรือทั้งเว็บ (ไม่กำหนดอะไรเลย)
+type Scope struct {
+	AuthorID *uuid.UUID
+	PostID   *uuid.UUID
+}
+
+// AuthorScope เฉพาะโพสต์ที่ผู้ใช้เป็นเจ้าของ (ไม่รวมโพสต์ในถังขยะ)
+// โพสต์ที่ร่วมเขียนไม่ถูกนับในภาพรวมของผู้ร่วมเขียน เพื่อไม่ให้ยอดของโพสต์เดียวกันไปโผล่ในภาพรวมของหลายคน
+// ผู้ร่วมเขียนดูสถิติของโพสต์นั้นได้ทาง PostAnalytics (PostScope) แทน
+func AuthorScope(user *models.User) Scope {
+	return Scope{AuthorID: &user.ID}
+}
+
+func SiteScope() Scope {
+	return Scope{}
+}
+
+func PostScope(postID uuid.UUID) Scope {
+	return Scope{PostID: &postID}
+}
+
+type TotalsRow struct {
+	Views           int64
+	UniqueVisitors  int64
+	LoggedInViewers int64
+	AIChats         int64
+	Likes           int64
+}
+
+type DailyRow struct {
+	Date time.Time
+	TotalsRow
+}
+
+type TopPostRow struct {
+	PostID   uuid.UUID
+	Title    string
+	Slug     string
+	Username string
+	TotalsRow
+}
+
+type ReferrerRow struct {
+	Referrer string
+	Views    int64
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+// aggregateDailyStatsSQL รวมข้อมูลดิบตั้งแต่ since เป็นรายวัน (UTC)
+// view นับจาก post_views, AI chat นับผู้ใช้ไม่ซ้ำจาก ai_responses, like นับจาก post_reactions
+const aggregateDailyStatsSQL = `
+INSERT INTO post_daily_stats (post_id, date, views, unique_visitors, logged_in_viewers, ai_chats, likes, created_at, updated_at)
+SELECT post_id, date, SUM(views), SUM(unique_visitors), SUM(logged_in_viewers), SUM(ai_chats), SUM(likes), NOW(), NOW()
+FROM (
+	SELECT post_id, (viewed_at AT TIME ZONE 'UTC')::date AS date,
+		COUNT(*) AS views, COUNT(DISTINCT fingerprint) AS unique_visitors, COUNT(DISTINCT user_id) AS logged_in_viewers,
+		0 AS ai_chats, 0 AS likes
+	FROM post_views
+	WHERE deleted_at IS NULL AND viewed_at >= ?
+	GROUP BY 1, 2
+	UNION ALL
+	SELECT post_id, (used_at AT TIME ZONE 'UTC')::date, 0, 0, 0, COUNT(DISTINCT user_id), 0
+	FROM ai_responses
+	WHERE deleted_at IS NULL AND used_at >= ?
+	GROUP BY 1, 2
+	UNION ALL
+	SELECT post_id, (created_at AT TIME ZONE 'UTC')::date, 0, 0, 0, 0, COUNT(*)
+	FROM post_reactions
+	WHERE type = ? AND created_at >= ?
+	GROUP BY 1, 2
+) raw
+GROUP BY post_id, date`
+
+const aggregateReferrerStatsSQL = `
+INSERT INTO post_referrer_daily_stats (post_id, date, referrer, views)
+SELECT post_id, (viewed_at AT TIME ZONE 'UTC')::date, COALESCE(NULLIF(referrer, ''), ?), COUNT(*)
+FROM post_views
+WHERE deleted_at IS NULL AND viewed_at >= ?
+GROUP BY 1, 2, 3`
+
+// AggregateDaily คำนวณสถิติของทุกวันตั้งแต่ since ใหม่ทั้งหมด (ลบของเดิมแล้วสร้างใหม่ใน transaction เดียว)
+// ทำซ้ำได้โดยไม่นับซ้ำ และแก้ตัวเลขที่ลดลงได้ เช่น like ที่ถูกกดยกเลิก
+func (r *Repository) AggregateDaily(since time.Time) error {
+	since = since.UTC()
+	sinceDate := since.Format(dateLayout)
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("date >= ?", sinceDate).Delete(&models.PostDailyStat{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(aggregateDailyStatsSQL, since, since, models.ReactionLike, since).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("date >= ?", sinceDate).Delete(&models.PostReferrerDailyStat{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(aggregateReferrerStatsSQL, DirectReferrer, since).Error
+	})
+}
+
+// LatestAggregatedDate วันล่าสุดที่มีสถิติแล้ว (nil ถ้ายังไม่เคยรวม)
+func (r *Repository) LatestAggregatedDate() (*time.Time, error) {
+	var latest sql.NullTime
+	if err := r.DB.Model(&models.PostDailyStat{}).Select("MAX(date)").Row().Scan(&latest); err != nil {
+		return nil, err
+	}
+	if !latest.Valid {
+		return nil, nil
+	}
+	return &latest.Time, nil
+}
+
+// scoped query ของตารางสถิติรายวันในช่วง from - to (รวมทั้งสองวัน) ตามขอบเขต
+func (r *Repository) scoped(model interface{}, table string, scope Scope, from, to time.Time) *gorm.DB {
+	db := r.DB.Model(model).Where(table+".date BETWEEN ? AND ?", from.Format(dateLayout), to.Format(dateLayout))
+	if scope.PostID != nil {
+		db = db.Where(table+".post_id = ?", *scope.PostID)
+	}
+	if scope.AuthorID != nil {
+		db = db.Where(table+".post_id IN (SELECT id FROM posts WHERE author_id = ? AND deleted_at IS NULL)", *scope.AuthorID)
+	}
+	return db
+}
+
+const totalsSelect = "COALESCE(SUM(post_daily_stats.views), 0) AS views, " +
+	"COALESCE(SUM(post_daily_stats.unique_visitors), 0) AS unique_visitors, " +
+	"COALESCE(SUM(post_daily_stats.logged_in_viewers), 0) AS logged_in_viewers, " +
+	"COALESCE(SUM(post_daily_stats.ai_chats), 0) AS ai_chats, " +
+	"COALESCE(SUM(post_daily_stats.likes), 0) AS likes"
+
+func (r *Repository) GetDailySeries(scope Scope, from, to time.Time) ([]DailyRow, error) {
+	var rows []DailyRow
+	err := r.scoped(&models.PostDailyStat{}, "post_daily_stats", scope, from, to).
+		Select("post_daily_stats.date AS date, " + totalsSelect).
+		Group("post_daily_stats.date").
+		Order("post_daily_stats.date ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *Repository) GetTotals(scope Scope, from, to time.Time) (*TotalsRow, error) {
+	var totals TotalsRow
+	err := r.scoped(&models.PostDailyStat{}, "post_daily_stats", scope, from, to).
+		Select(totalsSelect).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// GetTopPosts โพสต์ที่มี view มากที่สุดในช่วงเวลา (ไม่รวมโพสต์ที่ถูกลบ)
+func (r *Repository) GetTopPosts(scope Scope, from, to time.Time, limit int) ([]TopPostRow, error) {
+	var rows []TopPostRow
+	err := r.scoped(&models.PostDailyStat{}, "post_daily_stats", scope, from, to).
+		Select("post_daily_stats.post_id AS post_id, posts.title AS title, posts.slug AS slug, users.username AS username, " + totalsSelect).
+		Joins("JOIN posts ON posts.id = post_daily_stats.post_id AND posts.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = posts.author_id").
+		Group("post_daily_stats.post_id, posts.title, posts.slug, users.username").
+		Order("views DESC, likes DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *Repository) GetReferrers(scope Scope, from, to time.Time, limit int) ([]ReferrerRow, error) {
+	var rows []ReferrerRow
+	err := r.scoped(&models.PostReferrerDailyStat{}, "post_referrer_daily_stats", scope, from, to).
+		Select("post_referrer_daily_stats.referrer AS referrer, SUM(post_referrer_daily_stats.views) AS views").
+		Group("post_referrer_daily_stats.referrer").
+		Order("views DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
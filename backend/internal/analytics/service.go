@@ -0,0 +1,173 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultRange   = "30d"
+	DirectReferrer = "direct"
+
+	topPostsLimit  = 10
+	referrersLimit = 10
+)
+
+// ranges ช่วงเวลาที่เลือกได้ (จำนวนวันรวมวันนี้)
+var ranges = map[string]int{
+	"7d":   7,
+	"30d":  30,
+	"90d":  90,
+	"365d": 365,
+}
+
+type ServiceInterface interface {
+	Overview(scope Scope, rangeKey string) (*OverviewDTO, error)
+	PostAnalytics(postID string, user *models.User, rangeKey string) (*PostAnalyticsDTO, error)
+}
+
+type Service struct {
+	Repo     RepositoryInterface
+	PostRepo post.PostRepositoryInterface
+}
+
+func NewService(repo RepositoryInterface, postRepo post.PostRepositoryInterface) ServiceInterface {
+	return &Service{Repo: repo, PostRepo: postRepo}
+}
+
+// resolveRange แปลง range เป็นช่วงวันที่ (UTC) ค่าว่างใช้ DefaultRange
+func resolveRange(rangeKey string, now time.Time) (string, time.Time, time.Time, error) {
+	if rangeKey == "" {
+		rangeKey = DefaultRange
+	}
+	days, ok := ranges[rangeKey]
+	if !ok {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("%w: range must be one of 7d, 30d, 90d or 365d", errs.ErrInvalidPayload)
+	}
+
+	to := truncateDay(now)
+	from := to.AddDate(0, 0, -(days - 1))
+	return rangeKey, from, to, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// fillSeries เติมวันที่ไม่มีข้อมูลเป็น 0 ให้กราฟมีครบทุกวัน
+func fillSeries(rows []DailyRow, from, to time.Time) []DailyPointDTO {
+	byDate := make(map[string]TotalsRow, len(rows))
+	for _, row := range rows {
+		byDate[row.Date.Format(dateLayout)] = row.TotalsRow
+	}
+
+	series := make([]DailyPointDTO, 0, int(to.Sub(from).Hours()/24)+1)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		series = append(series, DailyPointDTO{Date: date, TotalsDTO: mapTotals(byDate[date])})
+	}
+	return series
+}
+
+// Overview ผลรวม กราฟรายวัน โพสต์ยอดนิยม และแหล่งที่มาของ view ตามขอบเขต
+func (s *Service) Overview(scope Scope, rangeKey string) (*OverviewDTO, error) {
+	rangeKey, from, to, err := resolveRange(rangeKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.Repo.GetTotals(scope, from, to)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := s.Repo.GetDailySeries(scope, from, to)
+	if err != nil {
+		return nil, err
+	}
+	topPosts, err := s.Repo.GetTopPosts(scope, from, to, topPostsLimit)
+	if err != nil {
+		return nil, err
+	}
+	referrers, err := s.Repo.GetReferrers(scope, from, to, referrersLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OverviewDTO{
+		Range:     rangeKey,
+		From:      from.Format(dateLayout),
+		To:        to.Format(dateLayout),
+		Totals:    mapTotals(*totals),
+		Series:    fillSeries(daily, from, to),
+		TopPosts:  mapTopPosts(topPosts),
+		Referrers: mapReferrers(referrers),
+	}, nil
+}
+
+// PostAnalytics สถิติของโพสต์เดียว เจ้าของและผู้ร่วมเขียนดูได้ admin ดูได้ทุกโพสต์
+func (s *Service) PostAnalytics(postID string, user *models.User, rangeKey string) (*PostAnalyticsDTO, error) {
+	rangeKey, from, to, err := resolveRange(rangeKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, errs.ErrPostNotFound
+	}
+	p, err := s.PostRepo.GetByID(id.String())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != models.AdminUser {
+		if err := post.Authorize(s.PostRepo, p, user, post.ActionView); err != nil {
+			return nil, err
+		}
+	}
+
+	scope := PostScope(p.ID)
+	totals, err := s.Repo.GetTotals(scope, from, to)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := s.Repo.GetDailySeries(scope, from, to)
+	if err != nil {
+		return nil, err
+	}
+	referrers, err := s.Repo.GetReferrers(scope, from, to, referrersLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostAnalyticsDTO{
+		PostID:    p.ID.String(),
+		Title:     p.Title,
+		Slug:      p.Slug,
+		Range:     rangeKey,
+		From:      from.Format(dateLayout),
+		To:        to.Format(dateLayout),
+		Totals:    mapTotals(*totals),
+		Series:    fillSeries(daily, from, to),
+		Referrers: mapReferrers(referrers),
+	}, nil
+}
+
+// AggregationStart วันแรกที่ต้องคำนวณใหม่: ครั้งแรกรวมย้อนหลังทั้งหมด
+// หลังจากนั้นเริ่มจากวันก่อนวันล่าสุดที่มีสถิติ เพื่อเก็บข้อมูลที่เข้ามาช่วงข้ามวันและช่วงที่ job ไม่ได้รัน
+func AggregationStart(latest *time.Time) time.Time {
+	if latest == nil {
+		return time.Time{}
+	}
+	return truncateDay(*latest).AddDate(0, 0, -1)
+}
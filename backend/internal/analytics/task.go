@@ -0,0 +1,4 @@
+package analytics
+
+// TaskTypeAggregateDaily periodic task รวมข้อมูลดิบเป็นสถิติรายวัน (ลงทะเบียนใน scheduler)
+const TaskTypeAggregateDaily = "analytics:aggregate_daily"
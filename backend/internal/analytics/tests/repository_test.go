@@ -0,0 +1,293 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/analytics"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var (
+	utcDate = regexp.MustCompile(`\((\w+) AT TIME ZONE 'UTC'\)::date`)
+	sqlNow  = regexp.MustCompile(`\bNOW\(\)`)
+)
+
+// newAnalyticsDB SQLite ที่แปลง syntax วันที่ของ postgres ใน SQL รวมสถิติให้ SQLite รันได้
+// (DATE() ของ SQLite แปลงเวลาเป็น UTC ก่อนตัดวันเหมือน AT TIME ZONE 'UTC')
+func newAnalyticsDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.Callback().Raw().Before("gorm:raw").Register("test:postgres_dates", func(tx *gorm.DB) {
+		sql := tx.Statement.SQL.String()
+		sql = utcDate.ReplaceAllString(sql, "DATE($1)")
+		sql = sqlNow.ReplaceAllString(sql, "CURRENT_TIMESTAMP")
+		tx.Statement.SQL.Reset()
+		tx.Statement.SQL.WriteString(sql)
+	}))
+
+	for _, ddl := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE posts (id TEXT PRIMARY KEY, title TEXT, slug TEXT, author_id TEXT, views INTEGER DEFAULT 0,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_views (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, user_id TEXT, fingerprint TEXT NOT NULL,
+			ip_address TEXT, user_agent TEXT, referrer TEXT, viewed_at DATETIME,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE ai_responses (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, user_id TEXT NOT NULL, post_id TEXT NOT NULL, used_at DATETIME,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_reactions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, user_id TEXT NOT NULL, type TEXT NOT NULL, created_at DATETIME)`,
+		// unique index เดียวกับ production ถ้ารวมซ้ำโดยไม่ลบของเดิมจะ insert ไม่ผ่าน
+		`CREATE TABLE post_daily_stats (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, date DATE NOT NULL,
+			views INTEGER NOT NULL DEFAULT 0, unique_visitors INTEGER NOT NULL DEFAULT 0, logged_in_viewers INTEGER NOT NULL DEFAULT 0,
+			ai_chats INTEGER NOT NULL DEFAULT 0, likes INTEGER NOT NULL DEFAULT 0, created_at DATETIME, updated_at DATETIME,
+			UNIQUE (post_id, date))`,
+		`CREATE TABLE post_referrer_daily_stats (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, date DATE NOT NULL, referrer TEXT NOT NULL,
+			views INTEGER NOT NULL DEFAULT 0, UNIQUE (post_id, date, referrer))`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db
+}
+
+var (
+	day0 = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day1 = day0.AddDate(0, 0, 1)
+	day2 = day0.AddDate(0, 0, 2)
+)
+
+func insertAuthor(t *testing.T, db *gorm.DB, username string) *models.User {
+	user := &models.User{ID: uuid.New(), UserName: username}
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, user.ID.String(), username).Error)
+	return user
+}
+
+func insertPost(t *testing.T, db *gorm.DB, author *models.User, slug string) uuid.UUID {
+	id := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, title, slug, author_id) VALUES (?, ?, ?, ?)`,
+		id.String(), slug, slug, author.ID.String()).Error)
+	return id
+}
+
+func insertView(t *testing.T, db *gorm.DB, postID uuid.UUID, userID *uuid.UUID, fingerprint, referrer string, at time.Time) {
+	var user interface{}
+	if userID != nil {
+		user = userID.String()
+	}
+	require.NoError(t, db.Exec(`INSERT INTO post_views (post_id, user_id, fingerprint, referrer, viewed_at) VALUES (?, ?, ?, ?, ?)`,
+		postID.String(), user, fingerprint, referrer, at).Error)
+}
+
+func insertAIChat(t *testing.T, db *gorm.DB, postID, userID uuid.UUID, at time.Time) {
+	require.NoError(t, db.Exec(`INSERT INTO ai_responses (post_id, user_id, used_at) VALUES (?, ?, ?)`,
+		postID.String(), userID.String(), at).Error)
+}
+
+func insertReaction(t *testing.T, db *gorm.DB, postID, userID uuid.UUID, reaction models.ReactionType, at time.Time) {
+	require.NoError(t, db.Exec(`INSERT INTO post_reactions (post_id, user_id, type, created_at) VALUES (?, ?, ?, ?)`,
+		postID.String(), userID.String(), reaction, at).Error)
+}
+
+func totals(views, unique, loggedIn, aiChats, likes int64) analytics.TotalsRow {
+	return analytics.TotalsRow{Views: views, UniqueVisitors: unique, LoggedInViewers: loggedIn, AIChats: aiChats, Likes: likes}
+}
+
+func seriesByDate(t *testing.T, repo analytics.RepositoryInterface, scope analytics.Scope) map[string]analytics.TotalsRow {
+	rows, err := repo.GetDailySeries(scope, day0, day2)
+	require.NoError(t, err)
+	result := make(map[string]analytics.TotalsRow, len(rows))
+	for _, row := range rows {
+		result[row.Date.Format("2006-01-02")] = row.TotalsRow
+	}
+	return result
+}
+
+// Test case: view นับทุกครั้ง unique นับ fingerprint ไม่ซ้ำ logged-in นับผู้ใช้ไม่ซ้ำ (ไม่นับคนที่ไม่ได้ login)
+// AI chat นับผู้ใช้ไม่ซ้ำต่อวัน like นับเฉพาะ reaction แบบ like
+func TestAggregateDaily_Columns(t *testing.T) {
+	db := newAnalyticsDB(t)
+	repo := analytics.NewRepository(db)
+	author := insertAuthor(t, db, "writer")
+	postID := insertPost(t, db, author, "hello")
+	reader, other := uuid.New(), uuid.New()
+
+	morning, night := day1.Add(9*time.Hour), day1.Add(23*time.Hour+59*time.Minute)
+	insertView(t, db, postID, &reader, "laptop", "", morning)
+	insertView(t, db, postID, &reader, "phone", "", night) // คนเดียวกันสองเครื่อง
+	insertView(t, db, postID, &other, "tablet", "", morning)
+	insertView(t, db, postID, nil, "guest", "", morning)
+	require.NoError(t, db.Exec(`INSERT INTO post_views (post_id, fingerprint, viewed_at, deleted_at) VALUES (?, 'deleted', ?, ?)`,
+		postID.String(), morning, morning).Error)
+
+	insertAIChat(t, db, postID, reader, morning)
+	insertAIChat(t, db, postID, reader, night)
+	insertAIChat(t, db, postID, other, morning)
+
+	insertReaction(t, db, postID, reader, models.ReactionLike, morning)
+	insertReaction(t, db, postID, other, models.ReactionLike, night)
+	insertReaction(t, db, postID, other, models.ReactionFire, morning)
+
+	// เวลาไทยเช้าวันถัดไปยังเป็นวันเดียวกันใน UTC
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	insertView(t, db, postID, nil, "late", "", time.Date(2026, 3, 3, 6, 0, 0, 0, bangkok))
+
+	require.NoError(t, repo.AggregateDaily(time.Time{}))
+
+	series := seriesByDate(t, repo, analytics.PostScope(postID))
+	assert.Equal(t, map[string]analytics.TotalsRow{
+		"2026-03-02": totals(5, 5, 2, 2, 2),
+	}, series)
+}
+
+// Test case: รวมซ้ำตั้งแต่วันก่อนวันล่าสุดไม่นับซ้ำ แก้ตัวเลขที่เปลี่ยนในช่วงนั้น และไม่แตะวันที่อยู่ก่อน since
+func TestAggregateDaily_RerunIsIdempotent(t *testing.T) {
+	db := newAnalyticsDB(t)
+	repo := analytics.NewRepository(db)
+	author := insertAuthor(t, db, "writer")
+	postID := insertPost(t, db, author, "hello")
+	reader, other := uuid.New(), uuid.New()
+
+	insertView(t, db, postID, nil, "d0", "", day0.Add(time.Hour))
+	insertView(t, db, postID, &reader, "d1-a", "google.com", day1.Add(time.Hour))
+	insertView(t, db, postID, nil, "d1-b", "", day1.Add(2*time.Hour))
+	insertView(t, db, postID, &other, "d2-a", "google.com", day2.Add(time.Hour))
+	insertReaction(t, db, postID, reader, models.ReactionLike, day1.Add(time.Hour))
+	insertReaction(t, db, postID, other, models.ReactionLike, day1.Add(3*time.Hour))
+
+	require.NoError(t, repo.AggregateDaily(time.Time{}))
+	first := seriesByDate(t, repo, analytics.PostScope(postID))
+	assert.Equal(t, map[string]analytics.TotalsRow{
+		"2026-03-01": totals(1, 1, 0, 0, 0),
+		"2026-03-02": totals(2, 2, 1, 0, 2),
+		"2026-03-03": totals(1, 1, 1, 0, 0),
+	}, first)
+
+	// job ถัดไปเริ่มจากวันก่อนวันล่าสุด และรันซ้ำได้หลายครั้ง
+	since := analytics.AggregationStart(&day2)
+	assert.Equal(t, day1, since)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.AggregateDaily(since))
+		assert.Equal(t, first, seriesByDate(t, repo, analytics.PostScope(postID)))
+	}
+	require.NoError(t, repo.AggregateDaily(time.Time{}))
+	assert.Equal(t, first, seriesByDate(t, repo, analytics.PostScope(postID)))
+
+	// view ที่มาช้าของเมื่อวาน like ที่ถูกยกเลิก และข้อมูลดิบเก่าที่หายไปก่อน since
+	insertView(t, db, postID, nil, "d1-late", "", day1.Add(23*time.Hour))
+	require.NoError(t, db.Exec(`DELETE FROM post_reactions WHERE user_id = ?`, other.String()).Error)
+	require.NoError(t, db.Exec(`UPDATE post_views SET deleted_at = ? WHERE fingerprint = 'd0'`, day2).Error)
+
+	require.NoError(t, repo.AggregateDaily(since))
+	assert.Equal(t, map[string]analytics.TotalsRow{
+		"2026-03-01": totals(1, 1, 0, 0, 0),
+		"2026-03-02": totals(3, 3, 1, 0, 1),
+		"2026-03-03": totals(1, 1, 1, 0, 0),
+	}, seriesByDate(t, repo, analytics.PostScope(postID)))
+
+	referrers, err := repo.GetReferrers(analytics.PostScope(postID), day0, day2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []analytics.ReferrerRow{
+		{Referrer: analytics.DirectReferrer, Views: 3},
+		{Referrer: "google.com", Views: 2},
+	}, referrers)
+}
+
+// Test case: ภาพรวมของผู้เขียนนับเฉพาะโพสต์ที่เป็นเจ้าของและยังไม่อยู่ในถังขยะ ของ admin นับทั้งเว็บ
+func TestScopes(t *testing.T) {
+	db := newAnalyticsDB(t)
+	repo := analytics.NewRepository(db)
+	alice := insertAuthor(t, db, "alice")
+	bob := insertAuthor(t, db, "bob")
+	alicePost := insertPost(t, db, alice, "alice-post")
+	coAuthored := insertPost(t, db, alice, "co-authored") // bob ร่วมเขียน แต่ alice เป็นเจ้าของ
+	bobPost := insertPost(t, db, bob, "bob-post")
+	trashed := insertPost(t, db, alice, "trashed")
+	require.NoError(t, db.Exec(`UPDATE posts SET deleted_at = ? WHERE id = ?`, day2, trashed.String()).Error)
+
+	for i, postID := range []uuid.UUID{alicePost, alicePost, alicePost, coAuthored, coAuthored, bobPost, trashed} {
+		insertView(t, db, postID, nil, "fp-"+string(rune('a'+i)), "", day1.Add(time.Hour))
+	}
+	require.NoError(t, repo.AggregateDaily(time.Time{}))
+
+	viewsOf := func(scope analytics.Scope) int64 {
+		got, err := repo.GetTotals(scope, day0, day2)
+		require.NoError(t, err)
+		return got.Views
+	}
+	assert.Equal(t, int64(5), viewsOf(analytics.AuthorScope(alice)))
+	assert.Equal(t, int64(1), viewsOf(analytics.AuthorScope(bob)), "co-authored posts stay in the owner's overview")
+	assert.Equal(t, int64(2), viewsOf(analytics.PostScope(coAuthored)))
+	assert.Equal(t, int64(0), viewsOf(analytics.AuthorScope(&models.User{ID: uuid.New()})))
+	assert.Equal(t, int64(7), viewsOf(analytics.SiteScope()))
+
+	top, err := repo.GetTopPosts(analytics.SiteScope(), day0, day2, 10)
+	require.NoError(t, err)
+	require.Len(t, top, 3, "trashed posts are left out of the ranking")
+	assert.Equal(t, alicePost, top[0].PostID)
+	assert.Equal(t, "alice", top[0].Username)
+	assert.Equal(t, int64(3), top[0].Views)
+
+	top, err = repo.GetTopPosts(analytics.AuthorScope(bob), day0, day2, 10)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, bobPost, top[0].PostID)
+
+	// ช่วงเวลานับรวมทั้งวันแรกและวันสุดท้าย
+	got, err := repo.GetTotals(analytics.SiteScope(), day1, day1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), got.Views)
+	got, err = repo.GetTotals(analytics.SiteScope(), day2, day2)
+	require.NoError(t, err)
+	assert.Zero(t, got.Views)
+}
+
+// Test case: referrer ที่บันทึกผ่าน RecordPostView (body หรือ header Referer) ถูกเก็บเป็น host แล้วรวมตามแหล่งที่มา
+func TestReferrerBreakdown_FromRecordedViews(t *testing.T) {
+	db := newAnalyticsDB(t)
+	repo := analytics.NewRepository(db)
+	postRepo := post.NewPostRepository(db)
+	author := insertAuthor(t, db, "writer")
+	postID := insertPost(t, db, author, "hello")
+
+	for i, referer := range []string{
+		"https://www.Google.com/search?q=go",
+		"https://google.com/",
+		"http://news.ycombinator.com/item?id=1",
+		"",
+		"android-app://com.slack",
+		"not a url",
+	} {
+		fingerprint := "fp-" + string(rune('a'+i))
+		require.NoError(t, postRepo.RecordPostView(postID.String(), nil, fingerprint, "127.0.0.1", "test", post.NormalizeReferrer(referer)))
+	}
+
+	require.NoError(t, repo.AggregateDaily(time.Now().AddDate(0, 0, -1)))
+	today := time.Now().UTC()
+	referrers, err := repo.GetReferrers(analytics.AuthorScope(author), today.AddDate(0, 0, -1), today.AddDate(0, 0, 1), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []analytics.ReferrerRow{
+		{Referrer: analytics.DirectReferrer, Views: 3},
+		{Referrer: "google.com", Views: 2},
+		{Referrer: "news.ycombinator.com", Views: 1},
+	}, referrers)
+
+	limited, err := repo.GetReferrers(analytics.SiteScope(), today.AddDate(0, 0, -1), today.AddDate(0, 0, 1), 1)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
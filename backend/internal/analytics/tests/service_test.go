@@ -0,0 +1,253 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/analytics"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MockAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsRepository) AggregateDaily(since time.Time) error {
+	return m.Called(since).Error(0)
+}
+
+func (m *MockAnalyticsRepository) LatestAggregatedDate() (*time.Time, error) {
+	args := m.Called()
+	latest, _ := args.Get(0).(*time.Time)
+	return latest, args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetDailySeries(scope analytics.Scope, from, to time.Time) ([]analytics.DailyRow, error) {
+	args := m.Called(scope, from, to)
+	return args.Get(0).([]analytics.DailyRow), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetTotals(scope analytics.Scope, from, to time.Time) (*analytics.TotalsRow, error) {
+	args := m.Called(scope, from, to)
+	return args.Get(0).(*analytics.TotalsRow), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetTopPosts(scope analytics.Scope, from, to time.Time, limit int) ([]analytics.TopPostRow, error) {
+	args := m.Called(scope, from, to, limit)
+	return args.Get(0).([]analytics.TopPostRow), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetReferrers(scope analytics.Scope, from, to time.Time, limit int) ([]analytics.ReferrerRow, error) {
+	args := m.Called(scope, from, to, limit)
+	return args.Get(0).([]analytics.ReferrerRow), args.Error(1)
+}
+
+// MockPostRepository mock เฉพาะการโหลดโพสต์และ role ของผู้ร่วมเขียน
+type MockPostRepository struct {
+	post.PostRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetByID(id string) (*models.Post, error) {
+	args := m.Called(id)
+	p, _ := args.Get(0).(*models.Post)
+	return p, args.Error(1)
+}
+
+func (m *MockPostRepository) GetCollaboratorRole(postID, userID string) (models.CollaboratorRole, error) {
+	args := m.Called(postID, userID)
+	return args.Get(0).(models.CollaboratorRole), args.Error(1)
+}
+
+// stubEmptyRange ให้ repository คืนผลว่างสำหรับทุกช่วงเวลา
+func stubEmptyRange(repo *MockAnalyticsRepository) {
+	repo.On("GetTotals", mock.Anything, mock.Anything, mock.Anything).Return(&analytics.TotalsRow{}, nil)
+	repo.On("GetDailySeries", mock.Anything, mock.Anything, mock.Anything).Return([]analytics.DailyRow{}, nil)
+	repo.On("GetTopPosts", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]analytics.TopPostRow{}, nil)
+	repo.On("GetReferrers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]analytics.ReferrerRow{}, nil)
+}
+
+// Test case: ช่วงเวลานับรวมวันนี้ (UTC) ค่าว่างใช้ 30 วัน และกราฟมีครบทุกวัน
+func TestOverview_Ranges(t *testing.T) {
+	cases := []struct {
+		rangeKey string
+		want     string
+		days     int
+	}{
+		{"7d", "7d", 7},
+		{"30d", "30d", 30},
+		{"", analytics.DefaultRange, 30},
+		{"90d", "90d", 90},
+		{"365d", "365d", 365},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.want, func(t *testing.T) {
+			repo := new(MockAnalyticsRepository)
+			stubEmptyRange(repo)
+
+			result, err := analytics.NewService(repo, nil).Overview(analytics.SiteScope(), tc.rangeKey)
+			require.NoError(t, err)
+
+			from, err := time.Parse("2006-01-02", result.From)
+			require.NoError(t, err)
+			to, err := time.Parse("2006-01-02", result.To)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, result.Range)
+			assert.Equal(t, time.Now().UTC().Format("2006-01-02"), result.To)
+			assert.Equal(t, tc.days-1, int(to.Sub(from).Hours()/24))
+			require.Len(t, result.Series, tc.days)
+			assert.Equal(t, result.From, result.Series[0].Date)
+			assert.Equal(t, result.To, result.Series[tc.days-1].Date)
+			repo.AssertCalled(t, "GetTotals", analytics.SiteScope(), from, to)
+		})
+	}
+}
+
+func TestOverview_InvalidRange(t *testing.T) {
+	for _, rangeKey := range []string{"1d", "30", "all", "7D"} {
+		repo := new(MockAnalyticsRepository)
+		_, err := analytics.NewService(repo, nil).Overview(analytics.SiteScope(), rangeKey)
+		assert.ErrorIs(t, err, errs.ErrInvalidPayload, rangeKey)
+		repo.AssertNotCalled(t, "GetTotals", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+// Test case: วันที่ไม่มีข้อมูลเป็น 0 ทุกคอลัมน์ วันที่มีข้อมูลอยู่ตรงตำแหน่ง
+func TestOverview_ZeroFillsEmptyDays(t *testing.T) {
+	repo := new(MockAnalyticsRepository)
+	author := &models.User{ID: uuid.New()}
+	scope := analytics.AuthorScope(author)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	row := analytics.TotalsRow{Views: 4, UniqueVisitors: 3, LoggedInViewers: 2, AIChats: 1, Likes: 5}
+
+	repo.On("GetTotals", scope, mock.Anything, mock.Anything).Return(&row, nil)
+	repo.On("GetDailySeries", scope, mock.Anything, mock.Anything).Return([]analytics.DailyRow{
+		{Date: today.AddDate(0, 0, -5), TotalsRow: row},
+		{Date: today, TotalsRow: analytics.TotalsRow{Views: 1}},
+	}, nil)
+	repo.On("GetTopPosts", scope, mock.Anything, mock.Anything, mock.Anything).Return([]analytics.TopPostRow{
+		{PostID: uuid.New(), Title: "Hello", Slug: "hello", Username: "writer", TotalsRow: row},
+	}, nil)
+	repo.On("GetReferrers", scope, mock.Anything, mock.Anything, mock.Anything).Return([]analytics.ReferrerRow{
+		{Referrer: analytics.DirectReferrer, Views: 3},
+	}, nil)
+
+	result, err := analytics.NewService(repo, nil).Overview(scope, "7d")
+	require.NoError(t, err)
+	require.Len(t, result.Series, 7)
+
+	for i, point := range result.Series {
+		switch i {
+		case 1:
+			assert.Equal(t, analytics.TotalsDTO{Views: 4, UniqueVisitors: 3, LoggedInViewers: 2, AIChats: 1, Likes: 5}, point.TotalsDTO)
+		case 6:
+			assert.Equal(t, analytics.TotalsDTO{Views: 1}, point.TotalsDTO)
+		default:
+			assert.Zero(t, point.TotalsDTO, point.Date)
+		}
+	}
+	assert.Equal(t, int64(5), result.Totals.Likes)
+	require.Len(t, result.TopPosts, 1)
+	assert.Equal(t, "writer", result.TopPosts[0].Author)
+	assert.Equal(t, []analytics.ReferrerDTO{{Referrer: "direct", Views: 3}}, result.Referrers)
+}
+
+// Test case: สถิติของโพสต์เดียว เจ้าของ ผู้ร่วมเขียน และ admin ดูได้ คนอื่นไม่ได้
+func TestPostAnalytics_Access(t *testing.T) {
+	owner := &models.User{ID: uuid.New()}
+	reviewer := &models.User{ID: uuid.New()}
+	admin := &models.User{ID: uuid.New(), Role: models.AdminUser}
+	stranger := &models.User{ID: uuid.New()}
+	p := &models.Post{ID: uuid.New(), AuthorID: owner.ID, Title: "Hello", Slug: "hello"}
+
+	cases := []struct {
+		name string
+		user *models.User
+		err  error
+	}{
+		{"owner", owner, nil},
+		{"collaborator", reviewer, nil},
+		{"admin", admin, nil},
+		{"stranger", stranger, errs.ErrUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockAnalyticsRepository)
+			postRepo := new(MockPostRepository)
+			stubEmptyRange(repo)
+			postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+			postRepo.On("GetCollaboratorRole", p.ID.String(), reviewer.ID.String()).Return(models.CollaboratorReviewer, nil)
+			postRepo.On("GetCollaboratorRole", p.ID.String(), mock.Anything).Return(models.CollaboratorRole(""), gorm.ErrRecordNotFound)
+
+			result, err := analytics.NewService(repo, postRepo).PostAnalytics(p.ID.String(), tc.user, "7d")
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				repo.AssertNotCalled(t, "GetTotals", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, p.ID.String(), result.PostID)
+			assert.Len(t, result.Series, 7)
+			repo.AssertCalled(t, "GetTotals", analytics.PostScope(p.ID), mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("missing post", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		missing := uuid.NewString()
+		postRepo.On("GetByID", missing).Return(nil, gorm.ErrRecordNotFound)
+
+		service := analytics.NewService(new(MockAnalyticsRepository), postRepo)
+		_, err := service.PostAnalytics(missing, admin, "7d")
+		assert.ErrorIs(t, err, errs.ErrPostNotFound)
+		_, err = service.PostAnalytics("not-a-uuid", admin, "7d")
+		assert.ErrorIs(t, err, errs.ErrPostNotFound)
+	})
+}
+
+// Test case: job รวมสถิติทั้งหมดในครั้งแรก หลังจากนั้นเริ่มจากวันก่อนวันล่าสุดที่มีสถิติ
+func TestAggregateDailyWorker_Window(t *testing.T) {
+	latest := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	failure := errors.New("db down")
+
+	cases := []struct {
+		name   string
+		latest *time.Time
+		since  time.Time
+	}{
+		{"first run", nil, time.Time{}},
+		{"after the first run", &latest, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockAnalyticsRepository)
+			repo.On("LatestAggregatedDate").Return(tc.latest, nil)
+			repo.On("AggregateDaily", tc.since).Return(nil).Once()
+
+			handler := analytics.AggregateDailyWorkerHandler(analytics.AggregateDailyWorker{Logger: zap.NewNop(), Repo: repo})
+			require.NoError(t, handler(context.Background(), asynq.NewTask(analytics.TaskTypeAggregateDaily, nil)))
+			repo.AssertExpectations(t)
+		})
+	}
+
+	repo := new(MockAnalyticsRepository)
+	repo.On("LatestAggregatedDate").Return(&latest, nil)
+	repo.On("AggregateDaily", mock.Anything).Return(failure)
+	handler := analytics.AggregateDailyWorkerHandler(analytics.AggregateDailyWorker{Logger: zap.NewNop(), Repo: repo})
+	assert.ErrorIs(t, handler(context.Background(), asynq.NewTask(analytics.TaskTypeAggregateDaily, nil)), failure, "failures are retried")
+}
@@ -0,0 +1,33 @@
+package analytics
+
+import (
+	"context"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+type AggregateDailyWorker struct {
+	Logger *zap.Logger
+	Repo   RepositoryInterface
+}
+
+// AggregateDailyWorkerHandler คำนวณสถิติรายวันใหม่ตั้งแต่ AggregationStart
+func AggregateDailyWorkerHandler(deps AggregateDailyWorker) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		latest, err := deps.Repo.LatestAggregatedDate()
+		if err != nil {
+			deps.Logger.Error("Failed to get latest aggregated date", zap.Error(err))
+			return err
+		}
+
+		since := AggregationStart(latest)
+		if err := deps.Repo.AggregateDaily(since); err != nil {
+			deps.Logger.Error("Failed to aggregate daily post stats", zap.Time("since", since), zap.Error(err))
+			return err
+		}
+
+		deps.Logger.Info("Aggregated daily post stats", zap.Time("since", since))
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package bookmark
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"time"
+)
+
+// ToggleBookmarkRequestDTO ใช้ตอนเพิ่ม bookmark เท่านั้น (body ไม่บังคับ)
+type ToggleBookmarkRequestDTO struct {
+	FolderID *uint  `json:"folder_id"`
+	Note     string `json:"note"`
+}
+
+// UpdateBookmarkRequestDTO ย้ายโฟลเดอร์ (null = เอาออกจากโฟลเดอร์) และแก้ note
+type UpdateBookmarkRequestDTO struct {
+	FolderID *uint  `json:"folder_id"`
+	Note     string `json:"note"`
+}
+
+type FolderRequestDTO struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type BookmarkDTO struct {
+	ID        uint                `json:"id"`
+	FolderID  *uint               `json:"folder_id,omitempty"`
+	Note      string              `json:"note,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	Post      post.PostSummaryDTO `json:"post"`
+}
+
+type ToggleBookmarkResponseDTO struct {
+	Bookmarked bool         `json:"bookmarked"`
+	Bookmark   *BookmarkDTO `json:"bookmark,omitempty"`
+}
+
+// BookmarkListDTO หนึ่งหน้าของรายการ ส่ง NextCursor กลับมาเป็น ?cursor= เพื่อดึงหน้าถัดไป
+type BookmarkListDTO struct {
+	Bookmarks  []BookmarkDTO `json:"bookmarks"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	HasMore    bool          `json:"has_more"`
+}
+
+type FolderDTO struct {
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	BookmarkCount int64     `json:"bookmark_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func MapBookmarkToDTO(bookmark models.Bookmark) BookmarkDTO {
+	summary := post.MapPostToSummaryDTO(bookmark.Post)
+	bookmarked := true
+	summary.Bookmarked = &bookmarked
+
+	return BookmarkDTO{
+		ID:        bookmark.ID,
+		FolderID:  bookmark.FolderID,
+		Note:      bookmark.Note,
+		CreatedAt: bookmark.CreatedAt,
+		Post:      summary,
+	}
+}
+
+func MapFolderToDTO(folder models.BookmarkFolder, count int64) FolderDTO {
+	return FolderDTO{
+		ID:            folder.ID,
+		Name:          folder.Name,
+		BookmarkCount: count,
+		CreatedAt:     folder.CreatedAt,
+	}
+}
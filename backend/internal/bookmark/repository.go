@@ -0,0 +1,170 @@
+package bookmark
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryInterface interface {
+	Get(userID, postID uuid.UUID) (*models.Bookmark, error)
+	Toggle(bookmark *models.Bookmark) (bool, error)
+	Update(bookmark *models.Bookmark) error
+	List(query ListQuery) ([]models.Bookmark, error)
+	GetFolder(userID uuid.UUID, id uint) (*models.BookmarkFolder, error)
+	GetFolderByName(userID uuid.UUID, name string) (*models.BookmarkFolder, error)
+	ListFolders(userID uuid.UUID) ([]FolderCount, error)
+	CreateFolder(folder *models.BookmarkFolder) error
+	UpdateFolder(folder *models.BookmarkFolder) error
+	DeleteFolder(folder *models.BookmarkFolder) error
+}
+
+// ListQuery bookmark ของผู้ใช้ เรียงจากที่บันทึกล่าสุด ต่อจาก After (nil = หน้าแรก)
+type ListQuery struct {
+	UserID   uuid.UUID
+	FolderID *uint // nil = ทุกโฟลเดอร์
+	After    *Position
+	Limit    int
+}
+
+// Position ตำแหน่งของ bookmark สุดท้ายในหน้าก่อนหน้า (ถอดมาจาก cursor)
+type Position struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// FolderCount โฟลเดอร์พร้อมจำนวน bookmark
+type FolderCount struct {
+	models.BookmarkFolder
+	BookmarkCount int64
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) Get(userID, postID uuid.UUID) (*models.Bookmark, error) {
+	var bookmark models.Bookmark
+	if err := r.DB.Where("user_id = ? AND post_id = ?", userID, postID).First(&bookmark).Error; err != nil {
+		return nil, err
+	}
+	return &bookmark, nil
+}
+
+// Toggle ลบ bookmark ของ (user, post) ถ้ามีอยู่แล้ว ไม่อย่างนั้นสร้างใหม่ คืน true เมื่อเพิ่ม
+// กดพร้อมกันสองครั้ง: แถวที่ชนกับ unique index ถือว่า bookmark ไว้แล้ว และโหลดแถวนั้นกลับมาแทน
+func (r *Repository) Toggle(bookmark *models.Bookmark) (bool, error) {
+	added := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		removed := tx.
+			Where("user_id = ? AND post_id = ?", bookmark.UserID, bookmark.PostID).
+			Delete(&models.Bookmark{})
+		if removed.Error != nil {
+			return removed.Error
+		}
+		if removed.RowsAffected > 0 {
+			return nil
+		}
+
+		added = true
+		inserted := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(bookmark)
+		if inserted.Error != nil {
+			return inserted.Error
+		}
+		if inserted.RowsAffected == 0 {
+			return tx.Where("user_id = ? AND post_id = ?", bookmark.UserID, bookmark.PostID).First(bookmark).Error
+		}
+		return nil
+	})
+	return added, err
+}
+
+func (r *Repository) Update(bookmark *models.Bookmark) error {
+	return r.DB.Model(bookmark).Select("folder_id", "note").Updates(bookmark).Error
+}
+
+// List ซ่อน bookmark ของโพสต์ที่ถูกลบหรือเลิก publish ไปแล้ว (แถวยังอยู่ กลับมาเมื่อ publish อีกครั้ง)
+func (r *Repository) List(query ListQuery) ([]models.Bookmark, error) {
+	condition, published, status := post.PublishedPostCondition("posts")
+
+	db := r.DB.Model(&models.Bookmark{}).
+		Joins("JOIN posts ON posts.id = bookmarks.post_id AND "+condition, published, status).
+		Where("bookmarks.user_id = ?", query.UserID).
+		Preload("Post", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "slug", "title", "description", "thumbnail",
+				"published", "status", "published_at", "author_id", "likes", "views",
+				"read_time", "ai_chat_open", "ai_ready")
+		}).
+		Preload("Post.Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Preload("Post.Tags")
+	if query.FolderID != nil {
+		db = db.Where("bookmarks.folder_id = ?", *query.FolderID)
+	}
+	if query.After != nil {
+		db = db.Where("(bookmarks.created_at, bookmarks.id) < (?, ?)", query.After.CreatedAt, query.After.ID)
+	}
+
+	var bookmarks []models.Bookmark
+	err := db.Order("bookmarks.created_at DESC, bookmarks.id DESC").
+		Limit(query.Limit).
+		Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+func (r *Repository) GetFolder(userID uuid.UUID, id uint) (*models.BookmarkFolder, error) {
+	var folder models.BookmarkFolder
+	if err := r.DB.Where("user_id = ? AND id = ?", userID, id).First(&folder).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// GetFolderByName ใช้ตรวจชื่อซ้ำก่อนสร้าง / เปลี่ยนชื่อ
+func (r *Repository) GetFolderByName(userID uuid.UUID, name string) (*models.BookmarkFolder, error) {
+	var folder models.BookmarkFolder
+	if err := r.DB.Where("user_id = ? AND name = ?", userID, name).First(&folder).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *Repository) ListFolders(userID uuid.UUID) ([]FolderCount, error) {
+	var folders []FolderCount
+	err := r.DB.Model(&models.BookmarkFolder{}).
+		Select("bookmark_folders.*, COUNT(bookmarks.id) AS bookmark_count").
+		Joins("LEFT JOIN bookmarks ON bookmarks.folder_id = bookmark_folders.id").
+		Where("bookmark_folders.user_id = ?", userID).
+		Group("bookmark_folders.id").
+		Order("bookmark_folders.name ASC").
+		Scan(&folders).Error
+	return folders, err
+}
+
+func (r *Repository) CreateFolder(folder *models.BookmarkFolder) error {
+	return r.DB.Create(folder).Error
+}
+
+func (r *Repository) UpdateFolder(folder *models.BookmarkFolder) error {
+	return r.DB.Model(folder).Select("name").Updates(folder).Error
+}
+
+// DeleteFolder ลบโฟลเดอร์ bookmark ในโฟลเดอร์ยังอยู่แต่ไม่อยู่ในโฟลเดอร์ใดแล้ว
+func (r *Repository) DeleteFolder(folder *models.BookmarkFolder) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Bookmark{}).Where("folder_id = ?", folder.ID).Update("folder_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(folder).Error
+	})
+}
@@ -0,0 +1,291 @@
+package bookmark
+
+import (
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/cursor"
+	"rag-searchbot-backend/pkg/errs"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+
+	maxFolderNameLength = 100
+	maxNoteLength       = 2000
+)
+
+type ServiceInterface interface {
+	Toggle(postID string, req ToggleBookmarkRequestDTO, user *models.User) (*ToggleBookmarkResponseDTO, error)
+	Update(postID string, req UpdateBookmarkRequestDTO, user *models.User) (*BookmarkDTO, error)
+	List(user *models.User, folderID string, after string, limit int) (*BookmarkListDTO, error)
+	ListFolders(user *models.User) ([]FolderDTO, error)
+	CreateFolder(req FolderRequestDTO, user *models.User) (*FolderDTO, error)
+	RenameFolder(id uint, req FolderRequestDTO, user *models.User) (*FolderDTO, error)
+	DeleteFolder(id uint, user *models.User) error
+}
+
+type Service struct {
+	Repo     RepositoryInterface
+	PostRepo post.PostRepositoryInterface
+}
+
+func NewService(repo RepositoryInterface, postRepo post.PostRepositoryInterface) ServiceInterface {
+	return &Service{Repo: repo, PostRepo: postRepo}
+}
+
+func (s *Service) getPublishedPost(postID string) (*models.Post, error) {
+	if _, err := uuid.Parse(postID); err != nil {
+		return nil, errs.ErrPostNotFound
+	}
+	p, err := s.PostRepo.GetByID(postID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !p.Published || p.Status != models.PostPublished {
+		return nil, errs.ErrPostNotFound
+	}
+	return p, nil
+}
+
+// validateFolder ตรวจว่าโฟลเดอร์เป็นของผู้ใช้ (nil = ไม่อยู่ในโฟลเดอร์)
+func (s *Service) validateFolder(folderID *uint, user *models.User) error {
+	if folderID == nil {
+		return nil
+	}
+	_, err := s.Repo.GetFolder(user.ID, *folderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.ErrFolderNotFound
+	}
+	return err
+}
+
+func validateNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return "", fmt.Errorf("%w: note must be at most %d characters", errs.ErrInvalidPayload, maxNoteLength)
+	}
+	return note, nil
+}
+
+// Toggle bookmark โพสต์ หรือเอาออกถ้า bookmark ไว้แล้ว folder_id / note ใช้เฉพาะตอนเพิ่ม
+// (ตรวจก่อนเสมอ เพราะการเพิ่ม / ลบตัดสินใน transaction ของ repository)
+func (s *Service) Toggle(postID string, req ToggleBookmarkRequestDTO, user *models.User) (*ToggleBookmarkResponseDTO, error) {
+	p, err := s.getPublishedPost(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateFolder(req.FolderID, user); err != nil {
+		return nil, err
+	}
+	note, err := validateNote(req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark := &models.Bookmark{
+		UserID:   user.ID,
+		PostID:   p.ID,
+		FolderID: req.FolderID,
+		Note:     note,
+	}
+	added, err := s.Repo.Toggle(bookmark)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return &ToggleBookmarkResponseDTO{Bookmarked: false}, nil
+	}
+	bookmark.Post = *p
+
+	dto := MapBookmarkToDTO(*bookmark)
+	return &ToggleBookmarkResponseDTO{Bookmarked: true, Bookmark: &dto}, nil
+}
+
+// Update ย้ายโฟลเดอร์และแก้ note ของ bookmark ที่มีอยู่
+func (s *Service) Update(postID string, req UpdateBookmarkRequestDTO, user *models.User) (*BookmarkDTO, error) {
+	id, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, errs.ErrBookmarkNotFound
+	}
+	bookmark, err := s.Repo.Get(user.ID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrBookmarkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateFolder(req.FolderID, user); err != nil {
+		return nil, err
+	}
+	note, err := validateNote(req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark.FolderID = req.FolderID
+	bookmark.Note = note
+	if err := s.Repo.Update(bookmark); err != nil {
+		return nil, err
+	}
+
+	p, err := s.PostRepo.GetByID(postID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if p != nil {
+		bookmark.Post = *p
+	}
+
+	dto := MapBookmarkToDTO(*bookmark)
+	return &dto, nil
+}
+
+// List bookmark ของผู้ใช้แบบ cursor pagination (folderID ว่าง = ทุกโฟลเดอร์)
+func (s *Service) List(user *models.User, folderID string, after string, limit int) (*BookmarkListDTO, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	query := ListQuery{UserID: user.ID, Limit: limit + 1}
+
+	if folderID != "" {
+		id, err := strconv.ParseUint(folderID, 10, 64)
+		if err != nil {
+			return nil, errs.ErrFolderNotFound
+		}
+		folder := uint(id)
+		if err := s.validateFolder(&folder, user); err != nil {
+			return nil, err
+		}
+		query.FolderID = &folder
+	}
+
+	position, err := cursor.Decode(after)
+	if err != nil {
+		return nil, err
+	}
+	if position != nil {
+		id, err := strconv.ParseUint(position.ID, 10, 64)
+		if err != nil {
+			return nil, errs.ErrInvalidCursor
+		}
+		query.After = &Position{CreatedAt: position.Time, ID: uint(id)}
+	}
+
+	bookmarks, err := s.Repo.List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// ดึงเกินมาหนึ่งรายการเพื่อรู้ว่ายังมีหน้าถัดไปหรือไม่
+	result := &BookmarkListDTO{Bookmarks: make([]BookmarkDTO, 0, limit)}
+	if len(bookmarks) > limit {
+		bookmarks = bookmarks[:limit]
+		result.HasMore = true
+	}
+	for _, b := range bookmarks {
+		result.Bookmarks = append(result.Bookmarks, MapBookmarkToDTO(b))
+	}
+	if result.HasMore {
+		last := bookmarks[len(bookmarks)-1]
+		result.NextCursor = cursor.Encode(last.CreatedAt, strconv.FormatUint(uint64(last.ID), 10))
+	}
+	return result, nil
+}
+
+func (s *Service) ListFolders(user *models.User) ([]FolderDTO, error) {
+	folders, err := s.Repo.ListFolders(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]FolderDTO, 0, len(folders))
+	for _, f := range folders {
+		result = append(result, MapFolderToDTO(f.BookmarkFolder, f.BookmarkCount))
+	}
+	return result, nil
+}
+
+// validateFolderName ชื่อโฟลเดอร์ต้องไม่ว่างและไม่ซ้ำกับโฟลเดอร์อื่นของผู้ใช้ (excludeID = โฟลเดอร์ที่กำลังแก้)
+func (s *Service) validateFolderName(name string, user *models.User, excludeID uint) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxFolderNameLength {
+		return "", fmt.Errorf("%w: folder name must be 1-%d characters", errs.ErrInvalidPayload, maxFolderNameLength)
+	}
+
+	existing, err := s.Repo.GetFolderByName(user.ID, name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if existing != nil && existing.ID != excludeID {
+		return "", errs.ErrBookmarkFolderExists
+	}
+	return name, nil
+}
+
+func (s *Service) CreateFolder(req FolderRequestDTO, user *models.User) (*FolderDTO, error) {
+	name, err := s.validateFolderName(req.Name, user, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &models.BookmarkFolder{UserID: user.ID, Name: name}
+	if err := s.Repo.CreateFolder(folder); err != nil {
+		return nil, err
+	}
+
+	dto := MapFolderToDTO(*folder, 0)
+	return &dto, nil
+}
+
+func (s *Service) RenameFolder(id uint, req FolderRequestDTO, user *models.User) (*FolderDTO, error) {
+	folder, err := s.Repo.GetFolder(user.ID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrFolderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.validateFolderName(req.Name, user, folder.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	folder.Name = name
+	if err := s.Repo.UpdateFolder(folder); err != nil {
+		return nil, err
+	}
+
+	dto := MapFolderToDTO(*folder, 0)
+	return &dto, nil
+}
+
+// DeleteFolder ลบโฟลเดอร์ bookmark ข้างในยังอยู่
+func (s *Service) DeleteFolder(id uint, user *models.User) error {
+	folder, err := s.Repo.GetFolder(user.ID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.ErrFolderNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.Repo.DeleteFolder(folder)
+}
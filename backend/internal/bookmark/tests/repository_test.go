@@ -0,0 +1,246 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/bookmark"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newBookmarkDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, ddl := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT, avatar TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE posts (
+			id TEXT PRIMARY KEY, slug TEXT, title TEXT, description TEXT, thumbnail TEXT,
+			published BOOLEAN DEFAULT false, status TEXT DEFAULT 'DRAFT', published_at DATETIME,
+			likes INTEGER DEFAULT 0, views INTEGER DEFAULT 0, read_time REAL DEFAULT 0,
+			ai_chat_open BOOLEAN DEFAULT false, ai_ready BOOLEAN DEFAULT false, author_id TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_tags (post_id TEXT, tag_id INTEGER)`,
+		`CREATE TABLE bookmark_folders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, user_id TEXT NOT NULL, name TEXT NOT NULL,
+			created_at DATETIME, updated_at DATETIME, UNIQUE (user_id, name))`,
+		`CREATE TABLE bookmarks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, user_id TEXT NOT NULL, post_id TEXT NOT NULL, folder_id INTEGER,
+			note TEXT, created_at DATETIME, updated_at DATETIME, UNIQUE (user_id, post_id))`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db
+}
+
+func insertUser(t *testing.T, db *gorm.DB, username string) uuid.UUID {
+	id := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, id.String(), username).Error)
+	return id
+}
+
+func insertPost(t *testing.T, db *gorm.DB, authorID uuid.UUID, slug string, published bool) uuid.UUID {
+	id := uuid.New()
+	status := models.PostDraft
+	var publishedAt *time.Time
+	if published {
+		now := time.Now().UTC()
+		status, publishedAt = models.PostPublished, &now
+	}
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, slug, title, published, status, published_at, author_id) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id.String(), slug, slug, published, status, publishedAt, authorID.String()).Error)
+	return id
+}
+
+func insertBookmark(t *testing.T, db *gorm.DB, userID, postID uuid.UUID, folderID *uint, at time.Time) uint {
+	require.NoError(t, db.Exec(`INSERT INTO bookmarks (user_id, post_id, folder_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		userID.String(), postID.String(), folderID, at, at).Error)
+	var id uint
+	require.NoError(t, db.Raw(`SELECT id FROM bookmarks WHERE user_id = ? AND post_id = ?`, userID.String(), postID.String()).Scan(&id).Error)
+	return id
+}
+
+func countBookmarks(t *testing.T, db *gorm.DB, userID uuid.UUID) int64 {
+	var count int64
+	require.NoError(t, db.Model(&models.Bookmark{}).Where("user_id = ?", userID).Count(&count).Error)
+	return count
+}
+
+func bookmarkSlugs(bookmarks []models.Bookmark) []string {
+	slugs := make([]string, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		slugs = append(slugs, b.Post.Slug)
+	}
+	return slugs
+}
+
+// Test case: กดครั้งแรกเพิ่ม กดซ้ำลบ และ folder / note ถูกบันทึกตอนเพิ่ม
+func TestRepositoryToggle_AddsThenRemoves(t *testing.T) {
+	db := newBookmarkDB(t)
+	repo := bookmark.NewRepository(db)
+	reader := insertUser(t, db, "reader")
+	postID := insertPost(t, db, insertUser(t, db, "writer"), "hello", true)
+	folder := &models.BookmarkFolder{UserID: reader, Name: "later"}
+	require.NoError(t, repo.CreateFolder(folder))
+
+	added, err := repo.Toggle(&models.Bookmark{UserID: reader, PostID: postID, FolderID: &folder.ID, Note: "read on the train"})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	saved, err := repo.Get(reader, postID)
+	require.NoError(t, err)
+	require.NotNil(t, saved.FolderID)
+	assert.Equal(t, folder.ID, *saved.FolderID)
+	assert.Equal(t, "read on the train", saved.Note)
+
+	added, err = repo.Toggle(&models.Bookmark{UserID: reader, PostID: postID})
+	require.NoError(t, err)
+	assert.False(t, added)
+	assert.Zero(t, countBookmarks(t, db, reader))
+}
+
+// Test case: อีก request สร้างแถวเดียวกันไปก่อนระหว่างลบกับเพิ่ม ต้องไม่ error และคืนแถวที่มีอยู่
+func TestRepositoryToggle_ConcurrentInsertKeepsOneRow(t *testing.T) {
+	db := newBookmarkDB(t)
+	repo := bookmark.NewRepository(db)
+	reader := insertUser(t, db, "reader")
+	postID := insertPost(t, db, insertUser(t, db, "writer"), "hello", true)
+
+	var racedID uint
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:concurrent_bookmark", func(tx *gorm.DB) {
+		if _, ok := tx.Statement.Dest.(*models.Bookmark); !ok || racedID != 0 {
+			return
+		}
+		racedID = insertBookmark(t, tx.Session(&gorm.Session{NewDB: true}), reader, postID, nil, time.Now().UTC())
+	}))
+
+	created := &models.Bookmark{UserID: reader, PostID: postID, Note: "second click"}
+	added, err := repo.Toggle(created)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, racedID, created.ID)
+	assert.Equal(t, int64(1), countBookmarks(t, db, reader))
+}
+
+// Test case: เรียงจากบันทึกล่าสุด แบ่งหน้าด้วย (created_at, id) และซ่อนโพสต์ที่ไม่ได้ publish แล้ว
+func TestRepositoryList_PagesAndHidesUnpublished(t *testing.T) {
+	db := newBookmarkDB(t)
+	repo := bookmark.NewRepository(db)
+	reader := insertUser(t, db, "reader")
+	other := insertUser(t, db, "other")
+	writer := insertUser(t, db, "writer")
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// สองแถวแรกมีเวลาเท่ากัน ต้องใช้ id ตัดสินลำดับ
+	first := insertPost(t, db, writer, "first", true)
+	second := insertPost(t, db, writer, "second", true)
+	third := insertPost(t, db, writer, "third", true)
+	draft := insertPost(t, db, writer, "draft", false)
+	insertBookmark(t, db, reader, first, nil, base)
+	insertBookmark(t, db, reader, second, nil, base)
+	insertBookmark(t, db, reader, third, nil, base.Add(time.Hour))
+	insertBookmark(t, db, reader, draft, nil, base.Add(2*time.Hour))
+	insertBookmark(t, db, other, first, nil, base.Add(3*time.Hour))
+
+	page, err := repo.List(bookmark.ListQuery{UserID: reader, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"third", "second"}, bookmarkSlugs(page))
+	assert.Equal(t, "writer", page[0].Post.Author.UserName)
+
+	last := page[len(page)-1]
+	page, err = repo.List(bookmark.ListQuery{UserID: reader, Limit: 2, After: &bookmark.Position{CreatedAt: last.CreatedAt, ID: last.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, bookmarkSlugs(page))
+
+	// แถวของโพสต์ที่เลิก publish ยังอยู่ และกลับมาเมื่อ publish อีกครั้ง
+	require.NoError(t, db.Exec(`UPDATE posts SET published = true, status = ?, published_at = ? WHERE id = ?`,
+		models.PostPublished, base, draft.String()).Error)
+	page, err = repo.List(bookmark.ListQuery{UserID: reader, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"draft", "third", "second", "first"}, bookmarkSlugs(page))
+}
+
+// Test case: กรองตามโฟลเดอร์ นับจำนวนต่อโฟลเดอร์ และลบโฟลเดอร์แล้ว bookmark ยังอยู่
+func TestRepositoryFolders(t *testing.T) {
+	db := newBookmarkDB(t)
+	repo := bookmark.NewRepository(db)
+	reader := insertUser(t, db, "reader")
+	writer := insertUser(t, db, "writer")
+	now := time.Now().UTC()
+
+	work := &models.BookmarkFolder{UserID: reader, Name: "work"}
+	empty := &models.BookmarkFolder{UserID: reader, Name: "archive"}
+	require.NoError(t, repo.CreateFolder(work))
+	require.NoError(t, repo.CreateFolder(empty))
+	require.NoError(t, repo.CreateFolder(&models.BookmarkFolder{UserID: insertUser(t, db, "other"), Name: "work"}), "names are unique per user only")
+
+	insertBookmark(t, db, reader, insertPost(t, db, writer, "in-work", true), &work.ID, now)
+	insertBookmark(t, db, reader, insertPost(t, db, writer, "also-in-work", true), &work.ID, now.Add(time.Minute))
+	insertBookmark(t, db, reader, insertPost(t, db, writer, "loose", true), nil, now.Add(2*time.Minute))
+
+	folders, err := repo.ListFolders(reader)
+	require.NoError(t, err)
+	require.Len(t, folders, 2)
+	assert.Equal(t, "archive", folders[0].Name)
+	assert.Zero(t, folders[0].BookmarkCount)
+	assert.Equal(t, "work", folders[1].Name)
+	assert.Equal(t, int64(2), folders[1].BookmarkCount)
+
+	inWork, err := repo.List(bookmark.ListQuery{UserID: reader, FolderID: &work.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"also-in-work", "in-work"}, bookmarkSlugs(inWork))
+
+	byName, err := repo.GetFolderByName(reader, "work")
+	require.NoError(t, err)
+	assert.Equal(t, work.ID, byName.ID)
+	_, err = repo.GetFolder(insertUser(t, db, "stranger"), work.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	require.NoError(t, repo.DeleteFolder(work))
+	assert.Equal(t, int64(3), countBookmarks(t, db, reader))
+	all, err := repo.List(bookmark.ListQuery{UserID: reader, Limit: 10})
+	require.NoError(t, err)
+	for _, b := range all {
+		assert.Nil(t, b.FolderID, b.Post.Slug)
+	}
+}
+
+// Test case: flag bookmarked ของผู้อ่าน และจำนวน bookmark ที่ผู้เขียนเห็นในโพสต์ของตัวเอง
+func TestPostRepository_BookmarkedFlagAndCounts(t *testing.T) {
+	db := newBookmarkDB(t)
+	postRepo := post.NewPostRepository(db)
+	writer := insertUser(t, db, "writer")
+	reader := insertUser(t, db, "reader")
+	other := insertUser(t, db, "other")
+	popular := insertPost(t, db, writer, "popular", true)
+	niche := insertPost(t, db, writer, "niche", true)
+	unsaved := insertPost(t, db, writer, "unsaved", true)
+	now := time.Now().UTC()
+
+	insertBookmark(t, db, reader, popular, nil, now)
+	insertBookmark(t, db, other, popular, nil, now)
+	insertBookmark(t, db, other, niche, nil, now)
+
+	ids, err := postRepo.GetBookmarkedPostIDs(reader.String(), []uuid.UUID{popular, niche, unsaved})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{popular}, ids)
+
+	ids, err = postRepo.GetBookmarkedPostIDs(reader.String(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	counts, err := postRepo.CountBookmarks([]uuid.UUID{popular, niche, unsaved})
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int64{popular: 2, niche: 1}, counts)
+}
@@ -0,0 +1,348 @@
+package tests
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/bookmark"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/cursor"
+	"rag-searchbot-backend/pkg/errs"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type MockBookmarkRepository struct {
+	mock.Mock
+}
+
+func (m *MockBookmarkRepository) Get(userID, postID uuid.UUID) (*models.Bookmark, error) {
+	args := m.Called(userID, postID)
+	b, _ := args.Get(0).(*models.Bookmark)
+	return b, args.Error(1)
+}
+
+func (m *MockBookmarkRepository) Toggle(b *models.Bookmark) (bool, error) {
+	args := m.Called(b)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBookmarkRepository) Update(b *models.Bookmark) error {
+	return m.Called(b).Error(0)
+}
+
+func (m *MockBookmarkRepository) List(query bookmark.ListQuery) ([]models.Bookmark, error) {
+	args := m.Called(query)
+	return args.Get(0).([]models.Bookmark), args.Error(1)
+}
+
+func (m *MockBookmarkRepository) GetFolder(userID uuid.UUID, id uint) (*models.BookmarkFolder, error) {
+	args := m.Called(userID, id)
+	f, _ := args.Get(0).(*models.BookmarkFolder)
+	return f, args.Error(1)
+}
+
+func (m *MockBookmarkRepository) GetFolderByName(userID uuid.UUID, name string) (*models.BookmarkFolder, error) {
+	args := m.Called(userID, name)
+	f, _ := args.Get(0).(*models.BookmarkFolder)
+	return f, args.Error(1)
+}
+
+func (m *MockBookmarkRepository) ListFolders(userID uuid.UUID) ([]bookmark.FolderCount, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]bookmark.FolderCount), args.Error(1)
+}
+
+func (m *MockBookmarkRepository) CreateFolder(folder *models.BookmarkFolder) error {
+	return m.Called(folder).Error(0)
+}
+
+func (m *MockBookmarkRepository) UpdateFolder(folder *models.BookmarkFolder) error {
+	return m.Called(folder).Error(0)
+}
+
+func (m *MockBookmarkRepository) DeleteFolder(folder *models.BookmarkFolder) error {
+	return m.Called(folder).Error(0)
+}
+
+// MockPostRepository mock เฉพาะการโหลดโพสต์
+type MockPostRepository struct {
+	post.PostRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetByID(id string) (*models.Post, error) {
+	args := m.Called(id)
+	p, _ := args.Get(0).(*models.Post)
+	return p, args.Error(1)
+}
+
+func publishedPost() *models.Post {
+	now := time.Now()
+	return &models.Post{ID: uuid.New(), Slug: "hello", Title: "Hello", Published: true, Status: models.PostPublished, PublishedAt: &now}
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+// Test case: เพิ่ม bookmark พร้อมโฟลเดอร์และ note (ตัดช่องว่าง) และคืน flag bookmarked
+func TestToggle_Adds(t *testing.T) {
+	repo := new(MockBookmarkRepository)
+	postRepo := new(MockPostRepository)
+	user := &models.User{ID: uuid.New()}
+	p := publishedPost()
+	postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+	repo.On("GetFolder", user.ID, uint(7)).Return(&models.BookmarkFolder{ID: 7, UserID: user.ID}, nil)
+	repo.On("Toggle", mock.MatchedBy(func(b *models.Bookmark) bool {
+		return b.UserID == user.ID && b.PostID == p.ID && *b.FolderID == 7 && b.Note == "later"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Bookmark).ID = 42
+	}).Return(true, nil)
+
+	result, err := bookmark.NewService(repo, postRepo).Toggle(p.ID.String(), bookmark.ToggleBookmarkRequestDTO{FolderID: uintPtr(7), Note: "  later  "}, user)
+	require.NoError(t, err)
+	assert.True(t, result.Bookmarked)
+	require.NotNil(t, result.Bookmark)
+	assert.Equal(t, uint(42), result.Bookmark.ID)
+	assert.Equal(t, "hello", result.Bookmark.Post.Slug)
+	require.NotNil(t, result.Bookmark.Post.Bookmarked)
+	assert.True(t, *result.Bookmark.Post.Bookmarked)
+}
+
+func TestToggle_Removes(t *testing.T) {
+	repo := new(MockBookmarkRepository)
+	postRepo := new(MockPostRepository)
+	user := &models.User{ID: uuid.New()}
+	p := publishedPost()
+	postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+	repo.On("Toggle", mock.Anything).Return(false, nil)
+
+	result, err := bookmark.NewService(repo, postRepo).Toggle(p.ID.String(), bookmark.ToggleBookmarkRequestDTO{}, user)
+	require.NoError(t, err)
+	assert.False(t, result.Bookmarked)
+	assert.Nil(t, result.Bookmark)
+}
+
+// Test case: bookmark ได้เฉพาะโพสต์ที่ publish แล้ว โฟลเดอร์ต้องเป็นของผู้ใช้ และ note ยาวไม่เกินกำหนด
+func TestToggle_Rejects(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	p := publishedPost()
+	draft := &models.Post{ID: uuid.New(), Status: models.PostDraft}
+	missing := uuid.NewString()
+
+	cases := []struct {
+		name   string
+		postID string
+		req    bookmark.ToggleBookmarkRequestDTO
+		err    error
+	}{
+		{"invalid id", "not-a-uuid", bookmark.ToggleBookmarkRequestDTO{}, errs.ErrPostNotFound},
+		{"missing post", missing, bookmark.ToggleBookmarkRequestDTO{}, errs.ErrPostNotFound},
+		{"draft", draft.ID.String(), bookmark.ToggleBookmarkRequestDTO{}, errs.ErrPostNotFound},
+		{"someone else's folder", p.ID.String(), bookmark.ToggleBookmarkRequestDTO{FolderID: uintPtr(9)}, errs.ErrFolderNotFound},
+		{"note too long", p.ID.String(), bookmark.ToggleBookmarkRequestDTO{Note: strings.Repeat("ก", 2001)}, errs.ErrInvalidPayload},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockBookmarkRepository)
+			postRepo := new(MockPostRepository)
+			postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+			postRepo.On("GetByID", draft.ID.String()).Return(draft, nil)
+			postRepo.On("GetByID", missing).Return(nil, gorm.ErrRecordNotFound)
+			repo.On("GetFolder", user.ID, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+			_, err := bookmark.NewService(repo, postRepo).Toggle(tc.postID, tc.req, user)
+			assert.ErrorIs(t, err, tc.err)
+			repo.AssertNotCalled(t, "Toggle", mock.Anything)
+		})
+	}
+}
+
+// Test case: ย้ายโฟลเดอร์ / เอาออกจากโฟลเดอร์ และแก้ note ของ bookmark ที่มีอยู่
+func TestUpdate(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	p := publishedPost()
+
+	t.Run("moves out of folder and edits note", func(t *testing.T) {
+		repo := new(MockBookmarkRepository)
+		postRepo := new(MockPostRepository)
+		repo.On("Get", user.ID, p.ID).Return(&models.Bookmark{ID: 3, UserID: user.ID, PostID: p.ID, FolderID: uintPtr(7), Note: "old"}, nil)
+		repo.On("Update", mock.MatchedBy(func(b *models.Bookmark) bool {
+			return b.ID == 3 && b.FolderID == nil && b.Note == "new"
+		})).Return(nil)
+		postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+
+		result, err := bookmark.NewService(repo, postRepo).Update(p.ID.String(), bookmark.UpdateBookmarkRequestDTO{Note: " new "}, user)
+		require.NoError(t, err)
+		assert.Nil(t, result.FolderID)
+		assert.Equal(t, "new", result.Note)
+		assert.Equal(t, "hello", result.Post.Slug)
+	})
+
+	t.Run("not bookmarked", func(t *testing.T) {
+		repo := new(MockBookmarkRepository)
+		repo.On("Get", user.ID, p.ID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := bookmark.NewService(repo, new(MockPostRepository)).Update(p.ID.String(), bookmark.UpdateBookmarkRequestDTO{}, user)
+		assert.ErrorIs(t, err, errs.ErrBookmarkNotFound)
+		_, err = bookmark.NewService(repo, new(MockPostRepository)).Update("not-a-uuid", bookmark.UpdateBookmarkRequestDTO{}, user)
+		assert.ErrorIs(t, err, errs.ErrBookmarkNotFound)
+		repo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func bookmarkEntries(n int, newest time.Time) []models.Bookmark {
+	entries := make([]models.Bookmark, n)
+	for i := range entries {
+		entries[i] = models.Bookmark{
+			ID:        uint(100 - i),
+			CreatedAt: newest.Add(-time.Duration(i) * time.Minute),
+			Post:      models.Post{ID: uuid.New(), Slug: "post-" + strconv.Itoa(i)},
+		}
+	}
+	return entries
+}
+
+func TestList_ClampsLimit(t *testing.T) {
+	cases := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"default", 0, bookmark.DefaultLimit},
+		{"negative", -1, bookmark.DefaultLimit},
+		{"within range", 5, 5},
+		{"over max", 500, bookmark.MaxLimit},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockBookmarkRepository)
+			user := &models.User{ID: uuid.New()}
+			// ขอเกินหนึ่งรายการเพื่อดูว่ามีหน้าถัดไป
+			repo.On("List", bookmark.ListQuery{UserID: user.ID, Limit: tc.want + 1}).Return([]models.Bookmark{}, nil)
+
+			result, err := bookmark.NewService(repo, nil).List(user, "", "", tc.limit)
+			require.NoError(t, err)
+			assert.NotNil(t, result.Bookmarks)
+			assert.False(t, result.HasMore)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+// Test case: cursor ของหน้าถัดไปชี้ไปที่รายการสุดท้าย และถอดกลับเป็น Position เดิม
+func TestList_CursorRoundTrip(t *testing.T) {
+	repo := new(MockBookmarkRepository)
+	user := &models.User{ID: uuid.New()}
+	newest := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := bookmarkEntries(3, newest)
+	repo.On("List", bookmark.ListQuery{UserID: user.ID, Limit: 3}).Return(entries, nil)
+
+	service := bookmark.NewService(repo, nil)
+	first, err := service.List(user, "", "", 2)
+	require.NoError(t, err)
+	assert.True(t, first.HasMore)
+	require.Len(t, first.Bookmarks, 2)
+	for _, b := range first.Bookmarks {
+		require.NotNil(t, b.Post.Bookmarked)
+		assert.True(t, *b.Post.Bookmarked)
+	}
+	assert.Equal(t, cursor.Encode(entries[1].CreatedAt, "99"), first.NextCursor)
+
+	repo.On("List", bookmark.ListQuery{UserID: user.ID, Limit: 3, After: &bookmark.Position{CreatedAt: entries[1].CreatedAt, ID: 99}}).
+		Return(entries[2:], nil)
+	second, err := service.List(user, "", first.NextCursor, 2)
+	require.NoError(t, err)
+	assert.False(t, second.HasMore)
+	assert.Empty(t, second.NextCursor)
+	require.Len(t, second.Bookmarks, 1)
+	assert.Equal(t, "post-2", second.Bookmarks[0].Post.Slug)
+
+	_, err = service.List(user, "", "garbage", 2)
+	assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+	_, err = service.List(user, "", cursor.Encode(newest, "not-a-number"), 2)
+	assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+}
+
+func TestList_FolderFilter(t *testing.T) {
+	repo := new(MockBookmarkRepository)
+	user := &models.User{ID: uuid.New()}
+	folder := uint(7)
+	repo.On("GetFolder", user.ID, folder).Return(&models.BookmarkFolder{ID: folder, UserID: user.ID}, nil)
+	repo.On("GetFolder", user.ID, uint(8)).Return(nil, gorm.ErrRecordNotFound)
+	repo.On("List", bookmark.ListQuery{UserID: user.ID, FolderID: &folder, Limit: bookmark.DefaultLimit + 1}).Return([]models.Bookmark{}, nil)
+
+	service := bookmark.NewService(repo, nil)
+	_, err := service.List(user, "7", "", 0)
+	require.NoError(t, err)
+	repo.AssertCalled(t, "List", bookmark.ListQuery{UserID: user.ID, FolderID: &folder, Limit: bookmark.DefaultLimit + 1})
+
+	_, err = service.List(user, "8", "", 0)
+	assert.ErrorIs(t, err, errs.ErrFolderNotFound)
+	_, err = service.List(user, "abc", "", 0)
+	assert.ErrorIs(t, err, errs.ErrFolderNotFound)
+}
+
+// Test case: ชื่อโฟลเดอร์ต้องไม่ว่าง ไม่ยาวเกิน และไม่ซ้ำ (เปลี่ยนชื่อเป็นชื่อเดิมของตัวเองได้)
+func TestFolders_Names(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	work := &models.BookmarkFolder{ID: 1, UserID: user.ID, Name: "work"}
+
+	newRepo := func() *MockBookmarkRepository {
+		repo := new(MockBookmarkRepository)
+		repo.On("GetFolder", user.ID, uint(1)).Return(work, nil)
+		repo.On("GetFolder", user.ID, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		repo.On("GetFolderByName", user.ID, "work").Return(work, nil)
+		repo.On("GetFolderByName", user.ID, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		repo.On("CreateFolder", mock.Anything).Return(nil)
+		repo.On("UpdateFolder", mock.Anything).Return(nil)
+		repo.On("DeleteFolder", mock.Anything).Return(nil)
+		return repo
+	}
+
+	created, err := bookmark.NewService(newRepo(), nil).CreateFolder(bookmark.FolderRequestDTO{Name: "  reading  "}, user)
+	require.NoError(t, err)
+	assert.Equal(t, "reading", created.Name)
+
+	for _, name := range []string{"   ", strings.Repeat("a", 101)} {
+		_, err := bookmark.NewService(newRepo(), nil).CreateFolder(bookmark.FolderRequestDTO{Name: name}, user)
+		assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+	}
+	_, err = bookmark.NewService(newRepo(), nil).CreateFolder(bookmark.FolderRequestDTO{Name: "work"}, user)
+	assert.ErrorIs(t, err, errs.ErrBookmarkFolderExists)
+
+	renamed, err := bookmark.NewService(newRepo(), nil).RenameFolder(1, bookmark.FolderRequestDTO{Name: "work"}, user)
+	require.NoError(t, err)
+	assert.Equal(t, "work", renamed.Name)
+
+	_, err = bookmark.NewService(newRepo(), nil).RenameFolder(2, bookmark.FolderRequestDTO{Name: "x"}, user)
+	assert.ErrorIs(t, err, errs.ErrFolderNotFound)
+	assert.ErrorIs(t, bookmark.NewService(newRepo(), nil).DeleteFolder(2, user), errs.ErrFolderNotFound)
+
+	repo := newRepo()
+	require.NoError(t, bookmark.NewService(repo, nil).DeleteFolder(1, user))
+	repo.AssertCalled(t, "DeleteFolder", work)
+}
+
+func TestListFolders_MapsCounts(t *testing.T) {
+	repo := new(MockBookmarkRepository)
+	user := &models.User{ID: uuid.New()}
+	repo.On("ListFolders", user.ID).Return([]bookmark.FolderCount{
+		{BookmarkFolder: models.BookmarkFolder{ID: 1, Name: "archive"}},
+		{BookmarkFolder: models.BookmarkFolder{ID: 2, Name: "work"}, BookmarkCount: 3},
+	}, nil)
+
+	folders, err := bookmark.NewService(repo, nil).ListFolders(user)
+	require.NoError(t, err)
+	require.Len(t, folders, 2)
+	assert.Zero(t, folders[0].BookmarkCount)
+	assert.Equal(t, int64(3), folders[1].BookmarkCount)
+}
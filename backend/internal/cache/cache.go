@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"rag-searchbot-backend/internal/models"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,6 +13,7 @@ import (
 
 type ServiceInterface interface {
 	Delete(key string)
+	DeletePrefix(prefix string)
 	ClearUserCache(email string)
 	SetUserCache(email string, user interface{}) error
 	GetUserCache(email string) (*models.User, error)
@@ -23,20 +26,67 @@ type ServiceInterface interface {
 	ClearWarpKey(email string)
 }
 
+// MaxLocalTTL อายุสูงสุดของค่าใน memory เมื่อมี Redis (memory เป็นแค่ชั้นอ่านเร็ว
+// instance อื่นที่ลบ key ใน Redis จะเห็นผลภายในเวลานี้)
+const MaxLocalTTL = time.Minute
+
+type memoryEntry struct {
+	value     interface{}
+	expiresAt time.Time // zero = ไม่หมดอายุ
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Service cache สองชั้น memory + Redis ถูกเรียกพร้อมกันจากหลาย request และ worker จึงต้องล็อก map
 type Service struct {
-	Cache       map[string]interface{}
+	mu          sync.RWMutex
+	cache       map[string]memoryEntry
+	lastSweep   time.Time
 	RedisClient *redis.Client
 	RedisTTL    time.Duration
 }
 
 func NewService(redisClient *redis.Client, redisTTL time.Duration) ServiceInterface {
 	return &Service{
-		Cache:       make(map[string]interface{}),
+		cache:       make(map[string]memoryEntry),
+		lastSweep:   time.Now(),
 		RedisClient: redisClient,
 		RedisTTL:    redisTTL,
 	}
 }
 
+// localTTL อายุของค่าใน memory: ไม่มี Redis ใช้ RedisTTL (0 = ไม่หมดอายุ) มี Redis ไม่เกิน MaxLocalTTL
+func (s *Service) localTTL() time.Duration {
+	if s.RedisClient != nil && (s.RedisTTL <= 0 || s.RedisTTL > MaxLocalTTL) {
+		return MaxLocalTTL
+	}
+	return s.RedisTTL
+}
+
+// setLocal ต้องถือ s.mu (write) อยู่แล้ว และล้าง key ที่หมดอายุทิ้งเป็นระยะ
+func (s *Service) setLocal(key string, value interface{}, now time.Time) {
+	if s.cache == nil {
+		s.cache = make(map[string]memoryEntry)
+	}
+
+	ttl := s.localTTL()
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+		if now.Sub(s.lastSweep) >= ttl {
+			for k, e := range s.cache {
+				if e.expired(now) {
+					delete(s.cache, k)
+				}
+			}
+			s.lastSweep = now
+		}
+	}
+	s.cache[key] = entry
+}
+
 // ใช้ prefix เพื่อแยก key ของ user cache
 func getUserKey(email string) string {
 	return "cache:user:" + email
@@ -44,14 +94,32 @@ func getUserKey(email string) string {
 
 // ลบ key ออกจาก memory และ Redis
 func (s *Service) Delete(key string) {
-	if s.Cache != nil {
-		delete(s.Cache, key)
-	}
+	s.mu.Lock()
+	delete(s.cache, key)
+	s.mu.Unlock()
 	if s.RedisClient != nil {
 		s.RedisClient.Del(context.Background(), key)
 	}
 }
 
+// ลบทุก key ที่ขึ้นต้นด้วย prefix ออกจาก memory และ Redis (ใช้ล้าง cache ทั้งกลุ่ม)
+func (s *Service) DeletePrefix(prefix string) {
+	s.mu.Lock()
+	for key := range s.cache {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.cache, key)
+		}
+	}
+	s.mu.Unlock()
+	if s.RedisClient != nil {
+		ctx := context.Background()
+		iter := s.RedisClient.Scan(ctx, 0, prefix+"*", 100).Iterator()
+		for iter.Next(ctx) {
+			s.RedisClient.Del(ctx, iter.Val())
+		}
+	}
+}
+
 // ใช้กับ user โดยระบุ email
 func (s *Service) ClearUserCache(email string) {
 	s.Delete(getUserKey(email))
@@ -82,7 +150,9 @@ func (s *Service) GetUserCache(email string) (*models.User, error) {
 
 // รองรับเก็บข้อมูลประเภท string หรือ []byte ได้
 func (s *Service) Set(ctx context.Context, key string, value interface{}) error {
-	s.Cache[key] = value
+	s.mu.Lock()
+	s.setLocal(key, value, time.Now())
+	s.mu.Unlock()
 
 	if s.RedisClient != nil {
 		var toStore string
@@ -122,13 +192,18 @@ func (s *Service) GetString(ctx context.Context, key string) (string, bool) {
 
 // ดึงค่าจาก memory ก่อน → Redis fallback
 func (s *Service) Get(ctx context.Context, key string) (interface{}, bool) {
-	if val, ok := s.Cache[key]; ok {
-		return val, true
+	s.mu.RLock()
+	entry, ok := s.cache[key]
+	s.mu.RUnlock()
+	if ok && !entry.expired(time.Now()) {
+		return entry.value, true
 	}
 	if s.RedisClient != nil {
 		val, err := s.RedisClient.Get(ctx, key).Result()
 		if err == nil {
-			s.Cache[key] = val // cache locally
+			s.mu.Lock()
+			s.setLocal(key, val, time.Now()) // cache locally
+			s.mu.Unlock()
 			return val, true
 		}
 	}
@@ -137,7 +212,9 @@ func (s *Service) Get(ctx context.Context, key string) (interface{}, bool) {
 
 // Clear ทั้ง cache memory (global)
 func (s *Service) Clear() {
-	s.Cache = make(map[string]interface{})
+	s.mu.Lock()
+	s.cache = make(map[string]memoryEntry)
+	s.mu.Unlock()
 }
 
 func (s *Service) SetWarpKey(email string, warpKey string) error {
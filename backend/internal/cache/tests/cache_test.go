@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_MemoryEntriesExpire(t *testing.T) {
+	service := cache.NewService(nil, 20*time.Millisecond)
+	ctx := context.Background()
+
+	assert.NoError(t, service.Set(ctx, "related:a", "value"))
+	got, ok := service.GetString(ctx, "related:a")
+	assert.True(t, ok)
+	assert.Equal(t, "value", got)
+
+	time.Sleep(30 * time.Millisecond)
+	_, ok = service.Get(ctx, "related:a")
+	assert.False(t, ok, "memory entry should expire with the configured TTL")
+}
+
+func TestCache_DeletePrefix(t *testing.T) {
+	service := cache.NewService(nil, time.Minute)
+	ctx := context.Background()
+
+	for _, key := range []string{"related:1", "related:2", "sitemap:posts:1"} {
+		assert.NoError(t, service.Set(ctx, key, key))
+	}
+	service.DeletePrefix("related:")
+
+	_, ok := service.Get(ctx, "related:1")
+	assert.False(t, ok)
+	_, ok = service.Get(ctx, "related:2")
+	assert.False(t, ok)
+	_, ok = service.Get(ctx, "sitemap:posts:1")
+	assert.True(t, ok)
+}
+
+// request path (Set / Get) กับ AI worker (DeletePrefix) ทำงานพร้อมกัน ต้องไม่ panic "concurrent map writes"
+// (รันด้วย -race เพื่อให้ race detector ตรวจด้วย)
+func TestCache_ConcurrentAccess(t *testing.T) {
+	service := cache.NewService(nil, time.Minute)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("related:%d:%d", w, i%10)
+				_ = service.Set(ctx, key, "v")
+				service.Get(ctx, key)
+				if i%50 == 0 {
+					service.DeletePrefix("related:")
+				}
+				if i%100 == 0 {
+					service.Delete(key)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,75 @@
+package collaborator
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"time"
+)
+
+// InviteRequestDTO เชิญผู้ใช้ร่วมเขียนด้วย username และ role (editor / reviewer)
+type InviteRequestDTO struct {
+	Username string                  `json:"username" binding:"required"`
+	Role     models.CollaboratorRole `json:"role" binding:"required"`
+}
+
+type UpdateRoleRequestDTO struct {
+	Role models.CollaboratorRole `json:"role" binding:"required"`
+}
+
+type UserDTO struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Avatar    string `json:"avatar"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type CollaboratorDTO struct {
+	ID         uint       `json:"id"`
+	User       UserDTO    `json:"user"`
+	Role       string     `json:"role"`
+	Status     string     `json:"status"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// InvitationDTO คำเชิญที่รอผู้ใช้ตอบ
+type InvitationDTO struct {
+	ID        uint      `json:"id"`
+	PostID    string    `json:"post_id"`
+	PostTitle string    `json:"post_title"`
+	Role      string    `json:"role"`
+	InvitedBy UserDTO   `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func mapUser(user models.User) UserDTO {
+	return UserDTO{
+		ID:        user.ID.String(),
+		Username:  user.UserName,
+		Avatar:    user.Avatar,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}
+}
+
+func MapCollaboratorToDTO(collaborator models.PostCollaborator) CollaboratorDTO {
+	return CollaboratorDTO{
+		ID:         collaborator.ID,
+		User:       mapUser(collaborator.User),
+		Role:       string(collaborator.Role),
+		Status:     string(collaborator.Status),
+		AcceptedAt: collaborator.AcceptedAt,
+		CreatedAt:  collaborator.CreatedAt,
+	}
+}
+
+func MapInvitationToDTO(collaborator models.PostCollaborator) InvitationDTO {
+	return InvitationDTO{
+		ID:        collaborator.ID,
+		PostID:    collaborator.PostID.String(),
+		PostTitle: collaborator.Post.Title,
+		Role:      string(collaborator.Role),
+		InvitedBy: mapUser(collaborator.InvitedBy),
+		CreatedAt: collaborator.CreatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package collaborator
+
+import (
+	"rag-searchbot-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RepositoryInterface interface {
+	Create(collaborator *models.PostCollaborator) error
+	Update(collaborator *models.PostCollaborator) error
+	Delete(collaborator *models.PostCollaborator) error
+	GetByID(id uint) (*models.PostCollaborator, error)
+	GetByPostAndUser(postID uuid.UUID, userID uuid.UUID) (*models.PostCollaborator, error)
+	ListByPost(postID uuid.UUID) ([]models.PostCollaborator, error)
+	ListPendingByUser(userID uuid.UUID) ([]models.PostCollaborator, error)
+	GetUserByUsername(username string) (*models.User, error)
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+func selectUser(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "email", "username", "avatar", "first_name", "last_name")
+}
+
+func selectPost(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "slug", "short_slug", "title", "author_id")
+}
+
+func (r *Repository) Create(collaborator *models.PostCollaborator) error {
+	return r.DB.Create(collaborator).Error
+}
+
+func (r *Repository) Update(collaborator *models.PostCollaborator) error {
+	return r.DB.Model(collaborator).Select("role", "status", "invited_by_id", "accepted_at").Updates(collaborator).Error
+}
+
+func (r *Repository) Delete(collaborator *models.PostCollaborator) error {
+	return r.DB.Delete(collaborator).Error
+}
+
+func (r *Repository) GetByID(id uint) (*models.PostCollaborator, error) {
+	var collaborator models.PostCollaborator
+	err := r.DB.
+		Preload("Post", selectPost).
+		Preload("User", selectUser).
+		Preload("InvitedBy", selectUser).
+		First(&collaborator, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+func (r *Repository) GetByPostAndUser(postID uuid.UUID, userID uuid.UUID) (*models.PostCollaborator, error) {
+	var collaborator models.PostCollaborator
+	err := r.DB.
+		Preload("User", selectUser).
+		Where("post_id = ? AND user_id = ?", postID, userID).
+		First(&collaborator).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+func (r *Repository) ListByPost(postID uuid.UUID) ([]models.PostCollaborator, error) {
+	var collaborators []models.PostCollaborator
+	err := r.DB.
+		Preload("User", selectUser).
+		Where("post_id = ?", postID).
+		Order("created_at ASC").
+		Find(&collaborators).Error
+	return collaborators, err
+}
+
+// ListPendingByUser คำเชิญที่ยังไม่ได้ตอบ ไม่รวมโพสต์ที่ถูกลบไปแล้ว
+func (r *Repository) ListPendingByUser(userID uuid.UUID) ([]models.PostCollaborator, error) {
+	var collaborators []models.PostCollaborator
+	err := r.DB.
+		Preload("Post", selectPost).
+		Preload("InvitedBy", selectUser).
+		Where("user_id = ? AND status = ?", userID, models.CollaboratorPending).
+		Where("post_id IN (SELECT id FROM posts WHERE deleted_at IS NULL)").
+		Order("created_at DESC").
+		Find(&collaborators).Error
+	return collaborators, err
+}
+
+func (r *Repository) GetUserByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := selectUser(r.DB).Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
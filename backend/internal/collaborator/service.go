@@ -0,0 +1,287 @@
+package collaborator
+
+import (
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/notification"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	EventCollaborationInvited  = "notification:collaboration_invited"
+	EventCollaborationAccepted = "notification:collaboration_accepted"
+	EventCollaborationDeclined = "notification:collaboration_declined"
+
+	invitationsLink = "/collaborations/invitations"
+	myPostsLink     = "/my-posts"
+)
+
+type ServiceInterface interface {
+	List(postID string, user *models.User) ([]CollaboratorDTO, error)
+	Invite(postID string, req InviteRequestDTO, user *models.User) (*CollaboratorDTO, error)
+	UpdateRole(postID string, userID string, req UpdateRoleRequestDTO, user *models.User) (*CollaboratorDTO, error)
+	Remove(postID string, userID string, user *models.User) error
+	ListInvitations(user *models.User) ([]InvitationDTO, error)
+	Accept(invitationID uint, user *models.User) (*CollaboratorDTO, error)
+	Decline(invitationID uint, user *models.User) error
+}
+
+type Service struct {
+	Repo        RepositoryInterface
+	PostRepo    post.PostRepositoryInterface
+	NotiService notification.NotificationServiceInterface
+}
+
+func NewService(repo RepositoryInterface, postRepo post.PostRepositoryInterface, notiService notification.NotificationServiceInterface) ServiceInterface {
+	return &Service{Repo: repo, PostRepo: postRepo, NotiService: notiService}
+}
+
+// getAuthorizedPost ดึงโพสต์และตรวจสิทธิ์ตาม action
+func (s *Service) getAuthorizedPost(postID string, user *models.User, action post.PostAction) (*models.Post, error) {
+	if _, err := uuid.Parse(postID); err != nil {
+		return nil, errs.ErrPostNotFound
+	}
+	p, err := s.PostRepo.GetByID(postID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := post.Authorize(s.PostRepo, p, user, action); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func validateRole(role models.CollaboratorRole) (models.CollaboratorRole, error) {
+	role = models.CollaboratorRole(strings.ToLower(strings.TrimSpace(string(role))))
+	if !post.ValidCollaboratorRole(role) {
+		return "", fmt.Errorf("%w: role must be %q or %q", errs.ErrInvalidPayload, models.CollaboratorEditor, models.CollaboratorReviewer)
+	}
+	return role, nil
+}
+
+// List ผู้ร่วมเขียนทั้งหมดของโพสต์ (รวมคำเชิญที่ยังไม่ตอบ) เจ้าของและผู้ร่วมเขียนดูได้
+func (s *Service) List(postID string, user *models.User) ([]CollaboratorDTO, error) {
+	p, err := s.getAuthorizedPost(postID, user, post.ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators, err := s.Repo.ListByPost(p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CollaboratorDTO, 0, len(collaborators))
+	for _, c := range collaborators {
+		result = append(result, MapCollaboratorToDTO(c))
+	}
+	return result, nil
+}
+
+// Invite เชิญผู้ใช้ร่วมเขียน สิทธิ์จะมีผลเมื่อผู้ถูกเชิญตอบรับแล้ว
+// เชิญซ้ำขณะที่คำเชิญเดิมยังไม่ได้ตอบจะเปลี่ยน role และแจ้งเตือนอีกครั้ง
+func (s *Service) Invite(postID string, req InviteRequestDTO, user *models.User) (*CollaboratorDTO, error) {
+	p, err := s.getAuthorizedPost(postID, user, post.ActionManage)
+	if err != nil {
+		return nil, err
+	}
+	role, err := validateRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.Repo.GetUserByUsername(strings.TrimSpace(req.Username))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitee.ID == p.AuthorID {
+		return nil, fmt.Errorf("%w: the author cannot be invited to their own post", errs.ErrInvalidPayload)
+	}
+
+	collaborator, err := s.Repo.GetByPostAndUser(p.ID, invitee.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	switch {
+	case collaborator == nil:
+		collaborator = &models.PostCollaborator{
+			PostID:      p.ID,
+			UserID:      invitee.ID,
+			Role:        role,
+			Status:      models.CollaboratorPending,
+			InvitedByID: user.ID,
+		}
+		if err := s.Repo.Create(collaborator); err != nil {
+			return nil, err
+		}
+	case collaborator.Status == models.CollaboratorAccepted:
+		return nil, errs.ErrCollaboratorExists
+	default:
+		collaborator.Role = role
+		collaborator.InvitedByID = user.ID
+		if err := s.Repo.Update(collaborator); err != nil {
+			return nil, err
+		}
+	}
+	collaborator.User = *invitee
+
+	link := invitationsLink
+	message := fmt.Sprintf("%s invited you to collaborate on %s as %s", user.UserName, p.Title, role)
+	if err := s.NotiService.Notify(invitee, "Collaboration invitation", EventCollaborationInvited, message, &link); err != nil {
+		logger.Log.Warn("Failed to notify invited collaborator",
+			zap.String("post_id", p.ID.String()),
+			zap.Error(err))
+	}
+
+	dto := MapCollaboratorToDTO(*collaborator)
+	return &dto, nil
+}
+
+// getCollaborator ผู้ร่วมเขียนของโพสต์ตาม user id
+func (s *Service) getCollaborator(p *models.Post, userID string) (*models.PostCollaborator, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errs.ErrUserNotFound
+	}
+	collaborator, err := s.Repo.GetByPostAndUser(p.ID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrUserNotFound
+	}
+	return collaborator, err
+}
+
+func (s *Service) UpdateRole(postID string, userID string, req UpdateRoleRequestDTO, user *models.User) (*CollaboratorDTO, error) {
+	p, err := s.getAuthorizedPost(postID, user, post.ActionManage)
+	if err != nil {
+		return nil, err
+	}
+	role, err := validateRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborator, err := s.getCollaborator(p, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborator.Role = role
+	if err := s.Repo.Update(collaborator); err != nil {
+		return nil, err
+	}
+
+	dto := MapCollaboratorToDTO(*collaborator)
+	return &dto, nil
+}
+
+// Remove เจ้าของถอดผู้ร่วมเขียน (หรือยกเลิกคำเชิญ) และผู้ร่วมเขียนออกจากโพสต์เองได้
+func (s *Service) Remove(postID string, userID string, user *models.User) error {
+	if userID == user.ID.String() {
+		p, err := s.getAuthorizedPost(postID, user, post.ActionView)
+		if err != nil {
+			return err
+		}
+		collaborator, err := s.getCollaborator(p, userID)
+		if err != nil {
+			return err
+		}
+		return s.Repo.Delete(collaborator)
+	}
+
+	p, err := s.getAuthorizedPost(postID, user, post.ActionManage)
+	if err != nil {
+		return err
+	}
+	collaborator, err := s.getCollaborator(p, userID)
+	if err != nil {
+		return err
+	}
+	return s.Repo.Delete(collaborator)
+}
+
+func (s *Service) ListInvitations(user *models.User) ([]InvitationDTO, error) {
+	invitations, err := s.Repo.ListPendingByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]InvitationDTO, 0, len(invitations))
+	for _, invitation := range invitations {
+		result = append(result, MapInvitationToDTO(invitation))
+	}
+	return result, nil
+}
+
+// getInvitation คำเชิญที่ยังไม่ได้ตอบของผู้ใช้คนนี้ (ของคนอื่นถือว่าไม่พบ)
+func (s *Service) getInvitation(invitationID uint, user *models.User) (*models.PostCollaborator, error) {
+	invitation, err := s.Repo.GetByID(invitationID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitation.UserID != user.ID || invitation.Status != models.CollaboratorPending || invitation.Post.ID == uuid.Nil {
+		return nil, errs.ErrInvitationNotFound
+	}
+	return invitation, nil
+}
+
+func (s *Service) Accept(invitationID uint, user *models.User) (*CollaboratorDTO, error) {
+	invitation, err := s.getInvitation(invitationID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.Status = models.CollaboratorAccepted
+	invitation.AcceptedAt = &now
+	if err := s.Repo.Update(invitation); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%s accepted your invitation to collaborate on %s", user.UserName, invitation.Post.Title)
+	s.notifyInviter(invitation, "Collaboration accepted", EventCollaborationAccepted, message)
+
+	dto := MapCollaboratorToDTO(*invitation)
+	return &dto, nil
+}
+
+func (s *Service) Decline(invitationID uint, user *models.User) error {
+	invitation, err := s.getInvitation(invitationID, user)
+	if err != nil {
+		return err
+	}
+	if err := s.Repo.Delete(invitation); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%s declined your invitation to collaborate on %s", user.UserName, invitation.Post.Title)
+	s.notifyInviter(invitation, "Collaboration declined", EventCollaborationDeclined, message)
+	return nil
+}
+
+func (s *Service) notifyInviter(invitation *models.PostCollaborator, title, event, message string) {
+	link := myPostsLink
+	if err := s.NotiService.Notify(&invitation.InvitedBy, title, event, message, &link); err != nil {
+		logger.Log.Warn("Failed to notify collaboration inviter",
+			zap.Uint("invitation_id", invitation.ID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,370 @@
+package tests
+
+import (
+	"testing"
+
+	"rag-searchbot-backend/internal/collaborator"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MockCollaboratorRepository struct {
+	mock.Mock
+}
+
+func (m *MockCollaboratorRepository) Create(c *models.PostCollaborator) error {
+	return m.Called(c).Error(0)
+}
+
+func (m *MockCollaboratorRepository) Update(c *models.PostCollaborator) error {
+	return m.Called(c).Error(0)
+}
+
+func (m *MockCollaboratorRepository) Delete(c *models.PostCollaborator) error {
+	return m.Called(c).Error(0)
+}
+
+func (m *MockCollaboratorRepository) GetByID(id uint) (*models.PostCollaborator, error) {
+	args := m.Called(id)
+	if c, ok := args.Get(0).(*models.PostCollaborator); ok {
+		return c, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) GetByPostAndUser(postID, userID uuid.UUID) (*models.PostCollaborator, error) {
+	args := m.Called(postID, userID)
+	if c, ok := args.Get(0).(*models.PostCollaborator); ok {
+		return c, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) ListByPost(postID uuid.UUID) ([]models.PostCollaborator, error) {
+	args := m.Called(postID)
+	return args.Get(0).([]models.PostCollaborator), args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) ListPendingByUser(userID uuid.UUID) ([]models.PostCollaborator, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.PostCollaborator), args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) GetUserByUsername(username string) (*models.User, error) {
+	args := m.Called(username)
+	if u, ok := args.Get(0).(*models.User); ok {
+		return u, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// MockPostRepository mock เฉพาะเมธอดที่ใช้หาโพสต์และตรวจ role
+type MockPostRepository struct {
+	post.PostRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetByID(id string) (*models.Post, error) {
+	args := m.Called(id)
+	if p, ok := args.Get(0).(*models.Post); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostRepository) GetByShortSlug(shortSlug string) (*models.Post, error) {
+	args := m.Called(shortSlug)
+	if p, ok := args.Get(0).(*models.Post); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostRepository) GetCollaboratedPostByShortSlug(shortSlug, userID string) (*models.Post, error) {
+	args := m.Called(shortSlug, userID)
+	if p, ok := args.Get(0).(*models.Post); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostRepository) GetCollaboratorRole(postID, userID string) (models.CollaboratorRole, error) {
+	args := m.Called(postID, userID)
+	return args.Get(0).(models.CollaboratorRole), args.Error(1)
+}
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) Notify(user *models.User, title, event, data string, link *string) error {
+	return m.Called(user, title, event, data, link).Error(0)
+}
+
+// fixture โพสต์หนึ่งโพสต์พร้อมเจ้าของ editor และ reviewer ที่ตอบรับแล้ว
+type fixture struct {
+	service  collaborator.ServiceInterface
+	repo     *MockCollaboratorRepository
+	postRepo *MockPostRepository
+	noti     *MockNotificationService
+	post     *models.Post
+	owner    *models.User
+	editor   *models.User
+	reviewer *models.User
+	stranger *models.User
+}
+
+func newFixture() *fixture {
+	logger.Log = zap.NewNop()
+	f := &fixture{
+		repo:     new(MockCollaboratorRepository),
+		postRepo: new(MockPostRepository),
+		noti:     new(MockNotificationService),
+		owner:    &models.User{ID: uuid.New(), UserName: "owner"},
+		editor:   &models.User{ID: uuid.New(), UserName: "editor"},
+		reviewer: &models.User{ID: uuid.New(), UserName: "reviewer"},
+		stranger: &models.User{ID: uuid.New(), UserName: "stranger"},
+	}
+	f.post = &models.Post{ID: uuid.New(), AuthorID: f.owner.ID, Title: "Shared draft"}
+	f.post.ShortSlug = "shared-" + f.owner.ID.String()
+	f.service = collaborator.NewService(f.repo, f.postRepo, f.noti)
+
+	postID := f.post.ID.String()
+	f.postRepo.On("GetByID", postID).Return(f.post, nil)
+	f.postRepo.On("GetCollaboratorRole", postID, f.editor.ID.String()).Return(models.CollaboratorEditor, nil)
+	f.postRepo.On("GetCollaboratorRole", postID, f.reviewer.ID.String()).Return(models.CollaboratorReviewer, nil)
+	f.postRepo.On("GetCollaboratorRole", postID, f.stranger.ID.String()).Return(models.CollaboratorRole(""), gorm.ErrRecordNotFound)
+	return f
+}
+
+func TestInvite_CreatesPendingInvitation(t *testing.T) {
+	f := newFixture()
+	f.repo.On("GetUserByUsername", "stranger").Return(f.stranger, nil)
+	f.repo.On("GetByPostAndUser", f.post.ID, f.stranger.ID).Return(nil, gorm.ErrRecordNotFound)
+	f.repo.On("Create", mock.MatchedBy(func(c *models.PostCollaborator) bool {
+		return c.UserID == f.stranger.ID && c.Status == models.CollaboratorPending &&
+			c.Role == models.CollaboratorReviewer && c.InvitedByID == f.owner.ID
+	})).Return(nil).Once()
+	f.noti.On("Notify", f.stranger, "Collaboration invitation", collaborator.EventCollaborationInvited, mock.Anything, mock.Anything).Return(nil).Once()
+
+	dto, err := f.service.Invite(f.post.ID.String(), collaborator.InviteRequestDTO{Username: " stranger ", Role: " Reviewer "}, f.owner)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", dto.Status)
+	assert.Equal(t, "reviewer", dto.Role)
+	f.repo.AssertExpectations(t)
+	f.noti.AssertExpectations(t)
+}
+
+func TestInvite_Rejections(t *testing.T) {
+	t.Run("the author", func(t *testing.T) {
+		f := newFixture()
+		f.repo.On("GetUserByUsername", "owner").Return(f.owner, nil)
+
+		_, err := f.service.Invite(f.post.ID.String(), collaborator.InviteRequestDTO{Username: "owner", Role: models.CollaboratorEditor}, f.owner)
+		assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+		f.repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("already accepted", func(t *testing.T) {
+		f := newFixture()
+		existing := &models.PostCollaborator{PostID: f.post.ID, UserID: f.editor.ID, Role: models.CollaboratorEditor, Status: models.CollaboratorAccepted}
+		f.repo.On("GetUserByUsername", "editor").Return(f.editor, nil)
+		f.repo.On("GetByPostAndUser", f.post.ID, f.editor.ID).Return(existing, nil)
+
+		_, err := f.service.Invite(f.post.ID.String(), collaborator.InviteRequestDTO{Username: "editor", Role: models.CollaboratorReviewer}, f.owner)
+		assert.ErrorIs(t, err, errs.ErrCollaboratorExists)
+		assert.Equal(t, models.CollaboratorEditor, existing.Role, "an accepted role is only changed through UpdateRole")
+		f.repo.AssertNotCalled(t, "Update", mock.Anything)
+		f.noti.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		f := newFixture()
+		f.repo.On("GetUserByUsername", "ghost").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := f.service.Invite(f.post.ID.String(), collaborator.InviteRequestDTO{Username: "ghost", Role: models.CollaboratorEditor}, f.owner)
+		assert.ErrorIs(t, err, errs.ErrUserNotFound)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		f := newFixture()
+		_, err := f.service.Invite(f.post.ID.String(), collaborator.InviteRequestDTO{Username: "stranger", Role: "owner"}, f.owner)
+		assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+	})
+}
+
+// Test case: เชิญซ้ำขณะที่คำเชิญเดิมยังไม่ตอบ ใช้แถวเดิม เปลี่ยน role และแจ้งเตือนอีกครั้ง
+func TestInvite_TwiceUpdatesPendingInvitation(t *testing.T) {
+	f := newFixture()
+	pending := &models.PostCollaborator{ID: 7, PostID: f.post.ID, UserID: f.stranger.ID, Role: models.CollaboratorReviewer, Status: models.CollaboratorPending}
+	f.repo.On("GetUserByUsername", "stranger").Return(f.stranger, nil)
+	f.repo.On("GetByPostAndUser", f.post.ID, f.stranger.ID).Return(pending, nil)
+	f.repo.On("Update", pending).Return(nil).Once()
+	f.noti.On("Notify", f.stranger, "Collaboration invitation", collaborator.EventCollaborationInvited, mock.Anything, mock.Anything).Return(nil).Once()
+
+	dto, err := f.service.Invite(f.post.ID.String(), collaborator.InviteRequestDTO{Username: "stranger", Role: models.CollaboratorEditor}, f.owner)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), dto.ID)
+	assert.Equal(t, "editor", dto.Role)
+	assert.Equal(t, "pending", dto.Status)
+	f.repo.AssertNotCalled(t, "Create", mock.Anything)
+	f.repo.AssertExpectations(t)
+	f.noti.AssertExpectations(t)
+}
+
+// Test case: ตอบรับหรือปฏิเสธคำเชิญของคนอื่น หรือคำเชิญที่ตอบไปแล้ว ต้องได้ not found
+func TestRespond_OnlyOwnPendingInvitation(t *testing.T) {
+	f := newFixture()
+	livePost := models.Post{ID: f.post.ID, Title: f.post.Title}
+	invitations := map[uint]*models.PostCollaborator{
+		1: {ID: 1, UserID: f.stranger.ID, Status: models.CollaboratorPending, Post: livePost},
+		2: {ID: 2, UserID: f.editor.ID, Status: models.CollaboratorAccepted, Post: livePost},
+		3: {ID: 3, UserID: f.reviewer.ID, Status: models.CollaboratorPending}, // โพสต์ถูกลบไปแล้ว
+	}
+	for id, invitation := range invitations {
+		f.repo.On("GetByID", id).Return(invitation, nil)
+	}
+	f.repo.On("GetByID", uint(99)).Return(nil, gorm.ErrRecordNotFound)
+
+	cases := []struct {
+		name string
+		id   uint
+		user *models.User
+	}{
+		{"someone else's invitation", 1, f.reviewer},
+		{"the inviter", 1, f.owner},
+		{"already accepted", 2, f.editor},
+		{"post deleted", 3, f.reviewer},
+		{"missing", 99, f.stranger},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := f.service.Accept(tc.id, tc.user)
+			assert.ErrorIs(t, err, errs.ErrInvitationNotFound)
+			assert.ErrorIs(t, f.service.Decline(tc.id, tc.user), errs.ErrInvitationNotFound)
+		})
+	}
+	f.repo.AssertNotCalled(t, "Update", mock.Anything)
+	f.repo.AssertNotCalled(t, "Delete", mock.Anything)
+	assert.Equal(t, models.CollaboratorPending, invitations[1].Status)
+}
+
+func TestRespond_AcceptAndDeclineNotifyInviter(t *testing.T) {
+	f := newFixture()
+	f.repo.On("GetByID", uint(1)).Return(&models.PostCollaborator{
+		ID: 1, UserID: f.stranger.ID, Status: models.CollaboratorPending, InvitedBy: *f.owner, Post: *f.post,
+	}, nil)
+	f.repo.On("Update", mock.MatchedBy(func(c *models.PostCollaborator) bool {
+		return c.Status == models.CollaboratorAccepted && c.AcceptedAt != nil
+	})).Return(nil).Once()
+	f.noti.On("Notify", f.owner, "Collaboration accepted", collaborator.EventCollaborationAccepted, mock.Anything, mock.Anything).Return(nil).Once()
+
+	dto, err := f.service.Accept(1, f.stranger)
+	require.NoError(t, err)
+	assert.Equal(t, "accepted", dto.Status)
+
+	g := newFixture()
+	invitation := &models.PostCollaborator{ID: 2, UserID: g.stranger.ID, Status: models.CollaboratorPending, InvitedBy: *g.owner, Post: *g.post}
+	g.repo.On("GetByID", uint(2)).Return(invitation, nil)
+	g.repo.On("Delete", invitation).Return(nil).Once()
+	g.noti.On("Notify", g.owner, "Collaboration declined", collaborator.EventCollaborationDeclined, mock.Anything, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, g.service.Decline(2, g.stranger))
+	f.repo.AssertExpectations(t)
+	g.repo.AssertExpectations(t)
+	f.noti.AssertExpectations(t)
+	g.noti.AssertExpectations(t)
+}
+
+// Test case: จัดการผู้ร่วมเขียนได้เฉพาะเจ้าของ editor และ reviewer ทำไม่ได้
+func TestManage_OnlyOwner(t *testing.T) {
+	for _, role := range []string{"editor", "reviewer", "stranger"} {
+		t.Run(role, func(t *testing.T) {
+			f := newFixture()
+			user := map[string]*models.User{"editor": f.editor, "reviewer": f.reviewer, "stranger": f.stranger}[role]
+			postID := f.post.ID.String()
+
+			_, err := f.service.Invite(postID, collaborator.InviteRequestDTO{Username: "someone", Role: models.CollaboratorEditor}, user)
+			assert.ErrorIs(t, err, errs.ErrUnauthorized)
+			_, err = f.service.UpdateRole(postID, f.reviewer.ID.String(), collaborator.UpdateRoleRequestDTO{Role: models.CollaboratorEditor}, user)
+			assert.ErrorIs(t, err, errs.ErrUnauthorized)
+			assert.ErrorIs(t, f.service.Remove(postID, f.owner.ID.String(), user), errs.ErrUnauthorized)
+
+			f.repo.AssertNotCalled(t, "GetUserByUsername", mock.Anything)
+			f.repo.AssertNotCalled(t, "Update", mock.Anything)
+			f.repo.AssertNotCalled(t, "Delete", mock.Anything)
+		})
+	}
+}
+
+// Test case: reviewer แก้หรือ publish ไม่ได้ editor ลบโพสต์ไม่ได้ ผ่านการหาโพสต์ด้วย short slug แบบเดียวกับ handler
+func TestFindPostForUser_CollaboratorRoles(t *testing.T) {
+	f := newFixture()
+	service := post.NewPostService(f.postRepo, nil, &post.TaskEnqueuer{}).(*post.PostService)
+	for _, user := range []*models.User{f.editor, f.reviewer} {
+		f.postRepo.On("GetByShortSlug", "shared-"+user.ID.String()).Return(nil, gorm.ErrRecordNotFound)
+		f.postRepo.On("GetCollaboratedPostByShortSlug", "shared", user.ID.String()).Return(f.post, nil)
+	}
+
+	cases := []struct {
+		user    *models.User
+		action  post.PostAction
+		allowed bool
+	}{
+		{f.reviewer, post.ActionView, true},
+		{f.reviewer, post.ActionEdit, false},
+		{f.reviewer, post.ActionPublish, false},
+		{f.editor, post.ActionEdit, true},
+		{f.editor, post.ActionPublish, true},
+		{f.editor, post.ActionDelete, false},
+		{f.editor, post.ActionManage, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.user.UserName+" "+string(tc.action), func(t *testing.T) {
+			found, err := service.FindPostForUser("shared", tc.user, tc.action)
+			if tc.allowed {
+				require.NoError(t, err)
+				assert.Same(t, f.post, found)
+				return
+			}
+			assert.ErrorIs(t, err, errs.ErrUnauthorized)
+			assert.Nil(t, found)
+		})
+	}
+}
+
+// Test case: ผู้ร่วมเขียนทุก role ออกจากโพสต์เองได้ คนนอกทำไม่ได้
+func TestRemove_CollaboratorLeavesPost(t *testing.T) {
+	for _, role := range []string{"editor", "reviewer"} {
+		t.Run(role, func(t *testing.T) {
+			f := newFixture()
+			user := map[string]*models.User{"editor": f.editor, "reviewer": f.reviewer}[role]
+			membership := &models.PostCollaborator{ID: 5, PostID: f.post.ID, UserID: user.ID, Status: models.CollaboratorAccepted}
+			f.repo.On("GetByPostAndUser", f.post.ID, user.ID).Return(membership, nil)
+			f.repo.On("Delete", membership).Return(nil).Once()
+
+			require.NoError(t, f.service.Remove(f.post.ID.String(), user.ID.String(), user))
+			f.repo.AssertExpectations(t)
+		})
+	}
+
+	f := newFixture()
+	assert.ErrorIs(t, f.service.Remove(f.post.ID.String(), f.stranger.ID.String(), f.stranger), errs.ErrUnauthorized)
+	f.repo.AssertNotCalled(t, "Delete", mock.Anything)
+
+	// เจ้าของถอดผู้ร่วมเขียนได้
+	membership := &models.PostCollaborator{ID: 6, PostID: f.post.ID, UserID: f.editor.ID, Status: models.CollaboratorAccepted}
+	f.repo.On("GetByPostAndUser", f.post.ID, f.editor.ID).Return(membership, nil)
+	f.repo.On("Delete", membership).Return(nil).Once()
+	require.NoError(t, f.service.Remove(f.post.ID.String(), f.editor.ID.String(), f.owner))
+	f.repo.AssertExpectations(t)
+}
@@ -0,0 +1,71 @@
+package comment
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CommentAuthorDTO struct {
+	ID       uuid.UUID `json:"id"`
+	UserName string    `json:"username"`
+	Avatar   string    `json:"avatar"`
+}
+
+// CommentDTO comment ที่ถูกลบจะมี Deleted = true และไม่แสดงเนื้อหา / ผู้เขียน
+type CommentDTO struct {
+	ID        uint              `json:"id"`
+	ParentID  *uint             `json:"parent_id,omitempty"`
+	Content   string            `json:"content"`
+	Deleted   bool              `json:"deleted"`
+	EditedAt  *time.Time        `json:"edited_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Author    *CommentAuthorDTO `json:"author,omitempty"`
+	Replies   []CommentDTO      `json:"replies,omitempty"`
+}
+
+type CommentListResponse struct {
+	Comments []CommentDTO `json:"comments"`
+	Locked   bool         `json:"locked"`
+	Meta     post.Meta    `json:"meta"`
+}
+
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type LockCommentsRequest struct {
+	Locked *bool `json:"locked" binding:"required"`
+}
+
+func MapCommentToDTO(comment models.Comment) CommentDTO {
+	dto := CommentDTO{
+		ID:        comment.ID,
+		ParentID:  comment.ParentID,
+		CreatedAt: comment.CreatedAt,
+	}
+
+	if comment.DeletedAt.Valid {
+		dto.Deleted = true
+	} else {
+		dto.Content = comment.Content
+		dto.EditedAt = comment.EditedAt
+		dto.Author = &CommentAuthorDTO{
+			ID:       comment.Author.ID,
+			UserName: comment.Author.UserName,
+			Avatar:   comment.Author.Avatar,
+		}
+	}
+
+	for _, reply := range comment.Replies {
+		dto.Replies = append(dto.Replies, MapCommentToDTO(reply))
+	}
+	return dto
+}
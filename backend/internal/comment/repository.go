@@ -0,0 +1,100 @@
+package comment
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RepositoryInterface interface {
+	Create(comment *models.Comment) error
+	GetByID(id uint) (*models.Comment, error)
+	UpdateContent(comment *models.Comment) error
+	Delete(comment *models.Comment) error
+	GetThreads(postID string, limit, offset int) ([]models.Comment, int64, error)
+	SetCommentsLocked(postID string, locked bool) error
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+func selectAuthor(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "username", "avatar")
+}
+
+func (r *Repository) Create(comment *models.Comment) error {
+	if err := r.DB.Create(comment).Error; err != nil {
+		return err
+	}
+	return r.DB.Preload("Author", selectAuthor).First(comment, "id = ?", comment.ID).Error
+}
+
+func (r *Repository) GetByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.DB.
+		Preload("Author", selectAuthor).
+		First(&comment, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *Repository) UpdateContent(comment *models.Comment) error {
+	now := time.Now()
+	comment.EditedAt = &now
+	return r.DB.Model(comment).Updates(map[string]interface{}{
+		"content":   comment.Content,
+		"edited_at": comment.EditedAt,
+	}).Error
+}
+
+func (r *Repository) Delete(comment *models.Comment) error {
+	return r.DB.Delete(comment).Error
+}
+
+// visibleThreads คือ comment ระดับบนสุดที่ยังไม่ถูกลบ หรือถูกลบแล้วแต่ยังมี reply อยู่
+// (เก็บไว้เป็น placeholder เพื่อไม่ให้ thread แตก)
+func (r *Repository) visibleThreads(postID string) *gorm.DB {
+	return r.DB.Unscoped().Model(&models.Comment{}).
+		Where("comments.post_id = ? AND comments.parent_id IS NULL", postID).
+		Where(`(comments.deleted_at IS NULL OR EXISTS (
+			SELECT 1 FROM comments replies
+			WHERE replies.parent_id = comments.id AND replies.deleted_at IS NULL))`)
+}
+
+func (r *Repository) GetThreads(postID string, limit, offset int) ([]models.Comment, int64, error) {
+	var total int64
+	if err := r.visibleThreads(postID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []models.Comment
+	err := r.visibleThreads(postID).
+		Preload("Author", selectAuthor).
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Where("deleted_at IS NULL").Order("created_at ASC")
+		}).
+		Preload("Replies.Author", selectAuthor).
+		Order("comments.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+func (r *Repository) SetCommentsLocked(postID string, locked bool) error {
+	return r.DB.Model(&models.Post{}).
+		Where("id = ?", postID).
+		Update("comments_locked", locked).Error
+}
@@ -0,0 +1,294 @@
+package comment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/notification"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	MaxCommentLength = 5000 // ความยาวสูงสุดของ comment (นับเป็นตัวอักษร)
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+
+	EventCommentCreated = "notification:comment_created"
+	EventCommentReply   = "notification:comment_reply"
+)
+
+type ServiceInterface interface {
+	GetComments(postID string, page, limit int, user *models.User, accessToken string) (*CommentListResponse, error)
+	CreateComment(postID string, req CreateCommentRequest, user *models.User, accessToken string) (*CommentDTO, error)
+	UpdateComment(commentID uint, req UpdateCommentRequest, user *models.User) (*CommentDTO, error)
+	DeleteComment(commentID uint, user *models.User) error
+	SetCommentsLocked(postID string, locked bool, user *models.User) error
+}
+
+type Service struct {
+	Repo        RepositoryInterface
+	PostRepo    post.PostRepositoryInterface
+	NotiService notification.NotificationServiceInterface
+	Access      *post.PostAccess
+}
+
+func NewService(repo RepositoryInterface, postRepo post.PostRepositoryInterface, notiService notification.NotificationServiceInterface, access *post.PostAccess) ServiceInterface {
+	return &Service{Repo: repo, PostRepo: postRepo, NotiService: notiService, Access: access}
+}
+
+// getPublishedPost comment ได้เฉพาะโพสต์ที่ publish แล้ว โพสต์อื่นถือว่าไม่พบ
+func (s *Service) getPublishedPost(postID string) (*models.Post, error) {
+	p, err := s.PostRepo.GetByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrPostNotFound
+		}
+		return nil, err
+	}
+	if !p.Published || p.Status != models.PostPublished {
+		return nil, errs.ErrPostNotFound
+	}
+	return p, nil
+}
+
+// getReadablePost โพสต์ที่ publish แล้วและผู้ขออ่านได้ตาม visibility (protected ต้องเป็นเจ้าของ / ผู้ร่วมเขียน หรือปลดล็อกแล้ว)
+func (s *Service) getReadablePost(postID string, user *models.User, accessToken string) (*models.Post, error) {
+	p, err := s.getPublishedPost(postID)
+	if err != nil {
+		return nil, err
+	}
+	if s.Access == nil {
+		if p.Visibility == models.VisibilityProtected {
+			return nil, errs.ErrPostProtected
+		}
+		return p, nil
+	}
+	if !s.Access.CanRead(p, user, accessToken) {
+		return nil, errs.ErrPostProtected
+	}
+	return p, nil
+}
+
+func (s *Service) getComment(commentID uint) (*models.Comment, error) {
+	comment, err := s.Repo.GetByID(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+func validateContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", fmt.Errorf("%w: comment content is required", errs.ErrInvalidPayload)
+	}
+	if utf8.RuneCountInString(content) > MaxCommentLength {
+		return "", fmt.Errorf("%w: comment is longer than %d characters", errs.ErrInvalidPayload, MaxCommentLength)
+	}
+	return content, nil
+}
+
+// GetComments ดึง comment ระดับบนสุดแบบแบ่งหน้า (ใหม่สุดก่อน) พร้อม reply ทั้งหมดของแต่ละ thread
+// user เป็น nil ได้ accessToken คือ token ปลดล็อกโพสต์ protected จาก cookie
+func (s *Service) GetComments(postID string, page, limit int, user *models.User, accessToken string) (*CommentListResponse, error) {
+	p, err := s.getReadablePost(postID, user, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	comments, total, err := s.Repo.GetThreads(postID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]CommentDTO, 0, len(comments))
+	for _, c := range comments {
+		dtos = append(dtos, MapCommentToDTO(c))
+	}
+
+	return &CommentListResponse{
+		Comments: dtos,
+		Locked:   p.CommentsLocked,
+		Meta: post.Meta{
+			Total:       total,
+			HasNextPage: total > int64(offset+limit),
+			Page:        page,
+			Limit:       limit,
+			TotalPage:   int(math.Ceil(float64(total) / float64(limit))),
+		},
+	}, nil
+}
+
+/**
+* CreateComment adds a comment or a reply to a published post.
+* @param postID string - The ID of the post
+* @param req CreateCommentRequest - The comment content and optional parent comment
+* @param user *models.User - The commenter
+* @param accessToken string - The unlock token of a protected post (empty when not unlocked)
+* @return *CommentDTO - The created comment
+* @return error - An error if occurred
+* Replies are one level deep: replying to a reply attaches the new comment to the thread root
+* while still notifying the person being replied to.
+**/
+
+func (s *Service) CreateComment(postID string, req CreateCommentRequest, user *models.User, accessToken string) (*CommentDTO, error) {
+	p, err := s.getReadablePost(postID, user, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if p.CommentsLocked {
+		return nil, errs.ErrCommentsLocked
+	}
+
+	content, err := validateContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		Content:  content,
+		PostID:   p.ID,
+		AuthorID: user.ID,
+	}
+
+	var parent *models.Comment
+	if req.ParentID != nil {
+		parent, err = s.getComment(*req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.PostID != p.ID {
+			return nil, errs.ErrCommentNotFound
+		}
+
+		rootID := parent.ID
+		if parent.ParentID != nil {
+			rootID = *parent.ParentID
+		}
+		comment.ParentID = &rootID
+	}
+
+	if err := s.Repo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	s.notifyNewComment(p, comment, parent, user)
+
+	dto := MapCommentToDTO(*comment)
+	return &dto, nil
+}
+
+// notifyNewComment แจ้งผู้เขียนโพสต์ และเจ้าของ comment ที่ถูก reply (ไม่แจ้งตัวเอง และไม่แจ้งซ้ำคนเดิม)
+func (s *Service) notifyNewComment(p *models.Post, comment *models.Comment, parent *models.Comment, commenter *models.User) {
+	link := fmt.Sprintf("/posts/%s/%s#comment-%d", p.Author.UserName, p.Slug, comment.ID)
+	message := fmt.Sprintf("%s commented on %s\n\n%s", commenter.UserName, p.Title, comment.Content)
+
+	if parent != nil && parent.AuthorID != commenter.ID {
+		replyMessage := fmt.Sprintf("%s replied to your comment on %s\n\n%s", commenter.UserName, p.Title, comment.Content)
+		if err := s.NotiService.Notify(&parent.Author, "New reply to your comment", EventCommentReply, replyMessage, &link); err != nil {
+			logger.Log.Warn("Failed to notify parent commenter",
+				zap.Uint("comment_id", comment.ID),
+				zap.Error(err))
+		}
+	}
+
+	if p.AuthorID == commenter.ID || (parent != nil && parent.AuthorID == p.AuthorID) {
+		return
+	}
+	if err := s.NotiService.Notify(&p.Author, "New comment on your post", EventCommentCreated, message, &link); err != nil {
+		logger.Log.Warn("Failed to notify post author about new comment",
+			zap.String("post_id", p.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// UpdateComment แก้ไขได้เฉพาะเจ้าของ comment และโพสต์ต้องยังไม่ถูกล็อก
+func (s *Service) UpdateComment(commentID uint, req UpdateCommentRequest, user *models.User) (*CommentDTO, error) {
+	comment, err := s.getComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != user.ID {
+		return nil, errs.ErrUnauthorized
+	}
+
+	// ไม่ต้องเช็ก visibility ซ้ำ: แก้ได้แค่ comment ของตัวเองซึ่งผ่านการเช็กตอนสร้างแล้ว
+	p, err := s.getPublishedPost(comment.PostID.String())
+	if err != nil {
+		return nil, err
+	}
+	if p.CommentsLocked {
+		return nil, errs.ErrCommentsLocked
+	}
+
+	content, err := validateContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Content = content
+	if err := s.Repo.UpdateContent(comment); err != nil {
+		return nil, err
+	}
+
+	dto := MapCommentToDTO(*comment)
+	return &dto, nil
+}
+
+// DeleteComment ลบได้โดยเจ้าของ comment หรือผู้เขียนโพสต์ (soft delete เพื่อคงโครง thread)
+func (s *Service) DeleteComment(commentID uint, user *models.User) error {
+	comment, err := s.getComment(commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.AuthorID != user.ID {
+		p, err := s.PostRepo.GetByID(comment.PostID.String())
+		if err != nil {
+			return err
+		}
+		if p.AuthorID != user.ID {
+			return errs.ErrUnauthorized
+		}
+	}
+
+	return s.Repo.Delete(comment)
+}
+
+// SetCommentsLocked ผู้เขียนโพสต์ปิด / เปิดการรับ comment
+func (s *Service) SetCommentsLocked(postID string, locked bool, user *models.User) error {
+	p, err := s.PostRepo.GetByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrPostNotFound
+		}
+		return err
+	}
+	if p.AuthorID != user.ID {
+		return errs.ErrUnauthorized
+	}
+
+	return s.Repo.SetCommentsLocked(postID, locked)
+}
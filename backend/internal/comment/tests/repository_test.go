@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/comment"
+	"rag-searchbot-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newCommentRepository(t *testing.T) (*gorm.DB, comment.RepositoryInterface) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	for _, ddl := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT, avatar TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE comments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, content TEXT NOT NULL, parent_id INTEGER, edited_at DATETIME,
+			post_id TEXT NOT NULL, author_id TEXT NOT NULL,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db, comment.NewRepository(db)
+}
+
+type commentRow struct {
+	parent  *uint
+	deleted bool
+}
+
+// insertComment ใส่ comment ตรง ๆ ด้วย SQL (deleted = soft delete ณ เวลาเดียวกับที่สร้าง)
+func insertComment(t *testing.T, db *gorm.DB, postID, authorID uuid.UUID, content string, at time.Time, row commentRow) uint {
+	var deletedAt *time.Time
+	if row.deleted {
+		deletedAt = &at
+	}
+	require.NoError(t, db.Exec(
+		`INSERT INTO comments (content, parent_id, post_id, author_id, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		content, row.parent, postID.String(), authorID.String(), at, at, deletedAt).Error)
+
+	var id uint
+	require.NoError(t, db.Raw(`SELECT MAX(id) FROM comments`).Scan(&id).Error)
+	return id
+}
+
+// Test case: thread ที่ root ถูกลบแต่ยังมี reply อยู่ต้องคงไว้เป็น placeholder ส่วน thread ที่ลบหมดต้องหายไป
+func TestCommentRepository_GetThreads(t *testing.T) {
+	db, repo := newCommentRepository(t)
+	postID, otherPost := uuid.New(), uuid.New()
+	author := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, author.String(), "alice").Error)
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	live := insertComment(t, db, postID, author, "live root", at(0), commentRow{})
+	insertComment(t, db, postID, author, "reply 1", at(1), commentRow{parent: &live})
+	insertComment(t, db, postID, author, "deleted reply", at(2), commentRow{parent: &live, deleted: true})
+	insertComment(t, db, postID, author, "reply 2", at(3), commentRow{parent: &live})
+
+	placeholder := insertComment(t, db, postID, author, "deleted root", at(4), commentRow{deleted: true})
+	insertComment(t, db, postID, author, "orphan reply", at(5), commentRow{parent: &placeholder})
+
+	gone := insertComment(t, db, postID, author, "fully deleted", at(6), commentRow{deleted: true})
+	insertComment(t, db, postID, author, "deleted child", at(7), commentRow{parent: &gone, deleted: true})
+
+	insertComment(t, db, otherPost, author, "other post", at(8), commentRow{})
+
+	threads, total, err := repo.GetThreads(postID.String(), 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, threads, 2)
+
+	// ใหม่สุดก่อน
+	assert.Equal(t, placeholder, threads[0].ID)
+	assert.Equal(t, live, threads[1].ID)
+
+	dto := comment.MapCommentToDTO(threads[0])
+	assert.True(t, dto.Deleted)
+	assert.Empty(t, dto.Content)
+	assert.Nil(t, dto.Author)
+	require.Len(t, dto.Replies, 1)
+	assert.Equal(t, "orphan reply", dto.Replies[0].Content)
+
+	var replies []string
+	for _, r := range threads[1].Replies {
+		replies = append(replies, r.Content)
+	}
+	assert.Equal(t, []string{"reply 1", "reply 2"}, replies, "replies are oldest first without deleted ones")
+	assert.Equal(t, "alice", threads[1].Author.UserName)
+
+	page, total, err := repo.GetThreads(postID.String(), 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, page, 1)
+	assert.Equal(t, live, page[0].ID)
+}
+
+func TestCommentRepository_DeleteIsSoft(t *testing.T) {
+	db, repo := newCommentRepository(t)
+	postID, author := uuid.New(), uuid.New()
+	id := insertComment(t, db, postID, author, "bye", time.Now(), commentRow{})
+
+	require.NoError(t, repo.Delete(&models.Comment{ID: id}))
+
+	_, err := repo.GetByID(id)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	var count int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM comments WHERE id = ? AND deleted_at IS NOT NULL`, id).Scan(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,330 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/comment"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/crypto"
+	"rag-searchbot-backend/pkg/errs"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(c *models.Comment) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetByID(id uint) (*models.Comment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) UpdateContent(c *models.Comment) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Delete(c *models.Comment) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetThreads(postID string, limit, offset int) ([]models.Comment, int64, error) {
+	args := m.Called(postID, limit, offset)
+	return args.Get(0).([]models.Comment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCommentRepository) SetCommentsLocked(postID string, locked bool) error {
+	args := m.Called(postID, locked)
+	return args.Error(0)
+}
+
+// MockPostRepository mock เฉพาะ method ที่ comment ใช้ method อื่นจะ panic ถ้าถูกเรียก
+type MockPostRepository struct {
+	post.PostRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetByID(id string) (*models.Post, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) GetCollaboratorRole(postID, userID string) (models.CollaboratorRole, error) {
+	args := m.Called(postID, userID)
+	return args.Get(0).(models.CollaboratorRole), args.Error(1)
+}
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) Notify(user *models.User, title string, event string, data string, link *string) error {
+	args := m.Called(user, title, event, data, link)
+	return args.Error(0)
+}
+
+func newCommentService(t *testing.T) (comment.ServiceInterface, *MockCommentRepository, *MockPostRepository, *MockNotificationService, *crypto.CryptoService) {
+	t.Setenv("POST_ACCESS_SECRET", "test-secret")
+	repo := new(MockCommentRepository)
+	postRepo := new(MockPostRepository)
+	noti := new(MockNotificationService)
+	cryptoService := crypto.NewCryptoService()
+	service := comment.NewService(repo, postRepo, noti, post.NewPostAccess(postRepo, cryptoService))
+	return service, repo, postRepo, noti, cryptoService
+}
+
+func publishedPost(author *models.User) *models.Post {
+	now := time.Now()
+	return &models.Post{
+		ID:          uuid.New(),
+		Title:       "Post",
+		Slug:        "post",
+		AuthorID:    author.ID,
+		Author:      *author,
+		Published:   true,
+		PublishedAt: &now,
+		Status:      models.PostPublished,
+		Visibility:  models.VisibilityPublic,
+	}
+}
+
+func TestComments_ProtectedPostRequiresAccess(t *testing.T) {
+	service, repo, postRepo, noti, cryptoService := newCommentService(t)
+
+	owner := &models.User{ID: uuid.New(), UserName: "owner"}
+	reader := &models.User{ID: uuid.New(), UserName: "reader"}
+	p := publishedPost(owner)
+	p.Visibility = models.VisibilityProtected
+	postID := p.ID.String()
+
+	postRepo.On("GetByID", postID).Return(p, nil)
+	postRepo.On("GetCollaboratorRole", postID, reader.ID.String()).Return(models.CollaboratorRole(""), gorm.ErrRecordNotFound)
+	repo.On("GetThreads", postID, comment.DefaultPageLimit, 0).Return([]models.Comment{}, int64(0), nil)
+	repo.On("Create", mock.AnythingOfType("*models.Comment")).Return(nil)
+	noti.On("Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// ยังไม่ได้ปลดล็อก: อ่านและเขียน comment ไม่ได้
+	_, err := service.GetComments(postID, 1, 0, nil, "")
+	assert.ErrorIs(t, err, errs.ErrPostProtected)
+	_, err = service.GetComments(postID, 1, 0, reader, "")
+	assert.ErrorIs(t, err, errs.ErrPostProtected)
+	_, err = service.CreateComment(postID, comment.CreateCommentRequest{Content: "hi"}, reader, "")
+	assert.ErrorIs(t, err, errs.ErrPostProtected)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+
+	// token ของโพสต์อื่นใช้ไม่ได้
+	otherToken, err := cryptoService.GeneratePostAccessToken(uuid.NewString(), time.Minute)
+	require.NoError(t, err)
+	_, err = service.CreateComment(postID, comment.CreateCommentRequest{Content: "hi"}, reader, otherToken)
+	assert.ErrorIs(t, err, errs.ErrPostProtected)
+
+	// เจ้าของอ่านได้โดยไม่ต้องปลดล็อก ผู้อ่านที่ปลดล็อกแล้ว comment ได้
+	_, err = service.GetComments(postID, 1, 0, owner, "")
+	assert.NoError(t, err)
+	token, err := cryptoService.GeneratePostAccessToken(postID, time.Minute)
+	require.NoError(t, err)
+	_, err = service.GetComments(postID, 1, 0, nil, token)
+	assert.NoError(t, err)
+	_, err = service.CreateComment(postID, comment.CreateCommentRequest{Content: "hi"}, reader, token)
+	assert.NoError(t, err)
+}
+
+// Test case: reply ของ reply ต้องเกาะกับ root ของ thread แต่ยังแจ้งคนที่ถูกตอบ และแจ้งผู้เขียนโพสต์
+func TestCreateComment_ReplyAttachesToThreadRoot(t *testing.T) {
+	service, repo, postRepo, noti, _ := newCommentService(t)
+
+	owner := &models.User{ID: uuid.New(), UserName: "owner"}
+	second := &models.User{ID: uuid.New(), UserName: "second"}
+	replier := &models.User{ID: uuid.New(), UserName: "replier"}
+	p := publishedPost(owner)
+	postID := p.ID.String()
+
+	rootID := uint(10)
+	reply := &models.Comment{ID: 11, PostID: p.ID, AuthorID: second.ID, Author: *second, ParentID: &rootID}
+
+	postRepo.On("GetByID", postID).Return(p, nil)
+	repo.On("GetByID", uint(11)).Return(reply, nil)
+	repo.On("Create", mock.MatchedBy(func(c *models.Comment) bool {
+		return c.ParentID != nil && *c.ParentID == rootID && c.Content == "thanks" && c.AuthorID == replier.ID
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Comment).ID = 12
+	}).Return(nil)
+	noti.On("Notify", second, "New reply to your comment", comment.EventCommentReply, mock.Anything, mock.Anything).Return(nil)
+	noti.On("Notify", &p.Author, "New comment on your post", comment.EventCommentCreated, mock.Anything, mock.Anything).Return(nil)
+
+	parentID := uint(11)
+	created, err := service.CreateComment(postID, comment.CreateCommentRequest{Content: "  thanks  ", ParentID: &parentID}, replier, "")
+	require.NoError(t, err)
+	assert.Equal(t, uint(12), created.ID)
+	require.NotNil(t, created.ParentID)
+	assert.Equal(t, rootID, *created.ParentID)
+	assert.Equal(t, "thanks", created.Content)
+	repo.AssertExpectations(t)
+	noti.AssertExpectations(t)
+}
+
+func TestCreateComment_Validation(t *testing.T) {
+	owner := &models.User{ID: uuid.New(), UserName: "owner"}
+	reader := &models.User{ID: uuid.New(), UserName: "reader"}
+	otherPostComment := &models.Comment{ID: 5, PostID: uuid.New()}
+	parentID := uint(5)
+
+	cases := []struct {
+		name    string
+		setup   func(p *models.Post)
+		req     comment.CreateCommentRequest
+		wantErr error
+	}{
+		{"empty content", nil, comment.CreateCommentRequest{Content: "   "}, errs.ErrInvalidPayload},
+		{"too long", nil, comment.CreateCommentRequest{Content: strings.Repeat("ก", comment.MaxCommentLength+1)}, errs.ErrInvalidPayload},
+		{"locked post", func(p *models.Post) { p.CommentsLocked = true }, comment.CreateCommentRequest{Content: "hi"}, errs.ErrCommentsLocked},
+		{"draft post", func(p *models.Post) { p.Published = false; p.Status = models.PostDraft }, comment.CreateCommentRequest{Content: "hi"}, errs.ErrPostNotFound},
+		{"parent on another post", nil, comment.CreateCommentRequest{Content: "hi", ParentID: &parentID}, errs.ErrCommentNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo, postRepo, _, _ := newCommentService(t)
+			p := publishedPost(owner)
+			if tc.setup != nil {
+				tc.setup(p)
+			}
+			postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+			repo.On("GetByID", parentID).Return(otherPostComment, nil)
+
+			_, err := service.CreateComment(p.ID.String(), tc.req, reader, "")
+			assert.ErrorIs(t, err, tc.wantErr)
+			repo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestUpdateComment_OwnerOnlyAndRespectsLock(t *testing.T) {
+	owner := &models.User{ID: uuid.New(), UserName: "owner"}
+	commenter := &models.User{ID: uuid.New(), UserName: "commenter"}
+
+	cases := []struct {
+		name    string
+		user    *models.User
+		locked  bool
+		wantErr error
+	}{
+		{"commenter edits", commenter, false, nil},
+		{"post owner cannot edit someone else's comment", owner, false, errs.ErrUnauthorized},
+		{"locked post", commenter, true, errs.ErrCommentsLocked},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo, postRepo, _, _ := newCommentService(t)
+			p := publishedPost(owner)
+			p.CommentsLocked = tc.locked
+			c := &models.Comment{ID: 1, PostID: p.ID, AuthorID: commenter.ID, Content: "old"}
+
+			repo.On("GetByID", uint(1)).Return(c, nil)
+			postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+			repo.On("UpdateContent", c).Return(nil)
+
+			updated, err := service.UpdateComment(1, comment.UpdateCommentRequest{Content: "new"}, tc.user)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				repo.AssertNotCalled(t, "UpdateContent", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "new", updated.Content)
+		})
+	}
+}
+
+func TestDeleteComment_CommenterOrPostOwner(t *testing.T) {
+	owner := &models.User{ID: uuid.New(), UserName: "owner"}
+	commenter := &models.User{ID: uuid.New(), UserName: "commenter"}
+	stranger := &models.User{ID: uuid.New(), UserName: "stranger"}
+
+	cases := []struct {
+		name    string
+		user    *models.User
+		wantErr error
+	}{
+		{"commenter", commenter, nil},
+		{"post owner moderates", owner, nil},
+		{"stranger", stranger, errs.ErrUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo, postRepo, _, _ := newCommentService(t)
+			p := publishedPost(owner)
+			c := &models.Comment{ID: 1, PostID: p.ID, AuthorID: commenter.ID}
+
+			repo.On("GetByID", uint(1)).Return(c, nil)
+			postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+			repo.On("Delete", c).Return(nil)
+
+			err := service.DeleteComment(1, tc.user)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				repo.AssertNotCalled(t, "Delete", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			repo.AssertCalled(t, "Delete", c)
+		})
+	}
+}
+
+func TestSetCommentsLocked_PostOwnerOnly(t *testing.T) {
+	service, repo, postRepo, _, _ := newCommentService(t)
+	owner := &models.User{ID: uuid.New(), UserName: "owner"}
+	stranger := &models.User{ID: uuid.New(), UserName: "stranger"}
+	p := publishedPost(owner)
+
+	postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+	postRepo.On("GetByID", "missing").Return(nil, gorm.ErrRecordNotFound)
+	repo.On("SetCommentsLocked", p.ID.String(), true).Return(nil)
+
+	assert.ErrorIs(t, service.SetCommentsLocked(p.ID.String(), true, stranger), errs.ErrUnauthorized)
+	assert.ErrorIs(t, service.SetCommentsLocked("missing", true, owner), errs.ErrPostNotFound)
+	assert.NoError(t, service.SetCommentsLocked(p.ID.String(), true, owner))
+	repo.AssertNumberOfCalls(t, "SetCommentsLocked", 1)
+}
+
+func TestGetComments_ClampsPaging(t *testing.T) {
+	service, repo, postRepo, _, _ := newCommentService(t)
+	owner := &models.User{ID: uuid.New(), UserName: "owner"}
+	p := publishedPost(owner)
+	p.CommentsLocked = true
+	postID := p.ID.String()
+
+	postRepo.On("GetByID", postID).Return(p, nil)
+	repo.On("GetThreads", postID, comment.MaxPageLimit, comment.MaxPageLimit).Return([]models.Comment{{ID: 1, Content: "hi"}}, int64(150), nil)
+
+	result, err := service.GetComments(postID, 2, 1000, nil, "")
+	require.NoError(t, err)
+	assert.True(t, result.Locked)
+	assert.Equal(t, comment.MaxPageLimit, result.Meta.Limit)
+	assert.Equal(t, 2, result.Meta.TotalPage)
+	assert.False(t, result.Meta.HasNextPage)
+	require.Len(t, result.Comments, 1)
+}
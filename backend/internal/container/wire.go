@@ -31,7 +31,7 @@ import (
 var postSet = wire.NewSet(
 	post.NewPostRepository,
 	post.NewTaskEnqueuer,
-	post.NewPostService,
+	NewPostService,
 )
 
 var userSet = wire.NewSet(
@@ -80,6 +80,21 @@ func NewCacheService(redisClient *redis.Client, redisTTL time.Duration) cache.Se
 	return cache.NewService(redisClient, redisTTL)
 }
 
+// NewPostService PostService ตัวเดียวที่ทุก router ใช้ร่วมกัน ตั้ง crypto (ตรวจ visibility) และ embedding enqueuer ไว้ที่นี่
+// publication listener ลงทะเบียนเพิ่มใน post router
+func NewPostService(
+	repo post.PostRepositoryInterface,
+	mediaService media.MediaServiceInterface,
+	enqueuer *post.TaskEnqueuer,
+	cryptoService *crypto.CryptoService,
+	asynqClient *asynq.Client,
+) post.PostServiceInterface {
+	service := post.NewPostService(repo, mediaService, enqueuer).(*post.PostService)
+	service.SetCryptoService(cryptoService)
+	service.SetEmbeddingEnqueuer(ai.NewTaskEnqueuer(asynqClient))
+	return service
+}
+
 func NewAsynqMux() *asynq.ServeMux {
 	return asynq.NewServeMux()
 }
@@ -39,11 +39,11 @@ func InitializeContainer(env *config.Config, db *gorm.DB, log *zap.Logger, redis
 	userServiceInterface := user.NewService(repositoryInterface, serviceInterface, mediaServiceInterface)
 	queueRepositoryInterface := queue.NewRepository(db)
 	taskEnqueuer := post.NewTaskEnqueuer(asynqClient, queueRepositoryInterface)
-	postServiceInterface := post.NewPostService(postRepositoryInterface, mediaServiceInterface, taskEnqueuer)
+	cryptoService := crypto.NewCryptoService()
+	postServiceInterface := NewPostService(postRepositoryInterface, mediaServiceInterface, taskEnqueuer, cryptoService, asynqClient)
 	manager := ws.NewManager()
 	notificationServiceInterface := notification.NewService(notificationRepositoryInterface, manager)
 	serveMux := NewAsynqMux()
-	cryptoService := crypto.NewCryptoService()
 	authServiceInterface := auth.NewAuthService(userServiceInterface, cryptoService, env)
 	container := NewContainer(env, db, log, repositoryInterface, postRepositoryInterface, notificationRepositoryInterface, mediaRepositoryInterface, userServiceInterface, postServiceInterface, notificationServiceInterface, mediaServiceInterface, serviceInterface, manager, queueRepositoryInterface, asynqClient, serveMux, cryptoService, authServiceInterface)
 	return container, nil
@@ -51,7 +51,7 @@ func InitializeContainer(env *config.Config, db *gorm.DB, log *zap.Logger, redis
 
 // wire.go:
 
-var postSet = wire.NewSet(post.NewPostRepository, post.NewTaskEnqueuer, post.NewPostService)
+var postSet = wire.NewSet(post.NewPostRepository, post.NewTaskEnqueuer, NewPostService)
 
 var userSet = wire.NewSet(user.NewRepository, user.NewService)
 
@@ -77,6 +77,21 @@ func NewCacheService(redisClient *redis.Client, redisTTL time.Duration) cache.Se
 	return cache.NewService(redisClient, redisTTL)
 }
 
+// NewPostService PostService ตัวเดียวที่ทุก router ใช้ร่วมกัน ตั้ง crypto (ตรวจ visibility) และ embedding enqueuer ไว้ที่นี่
+// publication listener ลงทะเบียนเพิ่มใน post router
+func NewPostService(
+	repo post.PostRepositoryInterface,
+	mediaService media.MediaServiceInterface,
+	enqueuer *post.TaskEnqueuer,
+	cryptoService *crypto.CryptoService,
+	asynqClient *asynq.Client,
+) post.PostServiceInterface {
+	service := post.NewPostService(repo, mediaService, enqueuer).(*post.PostService)
+	service.SetCryptoService(cryptoService)
+	service.SetEmbeddingEnqueuer(ai.NewTaskEnqueuer(asynqClient))
+	return service
+}
+
 func NewAsynqMux() *asynq.ServeMux {
 	return asynq.NewServeMux()
 }
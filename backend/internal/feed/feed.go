@@ -0,0 +1,64 @@
+package feed
+
+import (
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+	FormatJSON Format = "json"
+)
+
+// Feed ข้อมูลกลางที่ใช้ render ได้ทั้ง RSS, Atom และ JSON Feed
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // หน้าเว็บที่ feed นี้แทน
+	FeedURL     string // URL ของ feed เอง (self link)
+	Updated     time.Time
+	Items       []Item
+}
+
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	ContentHTML string // มีค่าเฉพาะ feed แบบเต็ม
+	AuthorName  string
+	AuthorURL   string
+	Image       string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+	Tags        []string
+}
+
+// imageType เดา MIME type ของ thumbnail จากนามสกุลไฟล์ (Chibisafe URL มีนามสกุลเสมอ)
+func imageType(url string) string {
+	ext := strings.ToLower(path.Ext(strings.SplitN(url, "?", 2)[0]))
+	if t := mime.TypeByExtension(ext); strings.HasPrefix(t, "image/") {
+		return t
+	}
+	return "image/jpeg"
+}
+
+// Render แปลง feed เป็น body ตาม format พร้อม Content-Type ที่ต้องส่งกลับ
+func Render(feed *Feed, format Format) ([]byte, string, error) {
+	switch format {
+	case FormatAtom:
+		body, err := renderAtom(feed)
+		return body, "application/atom+xml; charset=utf-8", err
+	case FormatJSON:
+		body, err := renderJSON(feed)
+		return body, "application/feed+json; charset=utf-8", err
+	default:
+		body, err := renderRSS(feed)
+		return body, "application/rss+xml; charset=utf-8", err
+	}
+}
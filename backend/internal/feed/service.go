@@ -0,0 +1,182 @@
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/series"
+	"rag-searchbot-backend/internal/taxonomy"
+	"rag-searchbot-backend/internal/user"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/sanitize"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const ItemLimit = 20 // จำนวนโพสต์ล่าสุดในแต่ละ feed
+
+// Request ตัวเลือกของ feed ที่ผู้ใช้ขอ
+type Request struct {
+	Format  Format
+	Full    bool   // ใส่ HTML ของบทความทั้งหมด
+	FeedURL string // URL ที่ใช้เรียก feed นี้ (self link)
+}
+
+// Result body ที่ render แล้วพร้อมค่าที่ใช้ตอบ conditional request
+type Result struct {
+	Body         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time // zero เมื่อ feed ว่าง
+}
+
+type ServiceInterface interface {
+	SiteFeed(req Request) (*Result, error)
+	AuthorFeed(username string, req Request) (*Result, error)
+	TagFeed(name string, req Request) (*Result, error)
+	SeriesFeed(username string, slug string, req Request) (*Result, error)
+}
+
+type Service struct {
+	PostRepo     post.PostRepositoryInterface
+	TaxonomyRepo taxonomy.RepositoryInterface
+	SeriesRepo   series.RepositoryInterface
+	UserService  user.ServiceInterface
+	SiteURL      string // URL ของหน้าเว็บ ใช้สร้างลิงก์ไปยังโพสต์
+}
+
+func NewService(postRepo post.PostRepositoryInterface, taxonomyRepo taxonomy.RepositoryInterface, seriesRepo series.RepositoryInterface, userService user.ServiceInterface, siteURL string) ServiceInterface {
+	return &Service{
+		PostRepo:     postRepo,
+		TaxonomyRepo: taxonomyRepo,
+		SeriesRepo:   seriesRepo,
+		UserService:  userService,
+		SiteURL:      strings.TrimRight(siteURL, "/"),
+	}
+}
+
+func (s *Service) SiteFeed(req Request) (*Result, error) {
+	return s.build(post.FeedQuery{}, &Feed{
+		Title:       "Latest posts",
+		Description: "Latest published posts",
+		Link:        s.SiteURL + "/",
+	}, req)
+}
+
+func (s *Service) AuthorFeed(username string, req Request) (*Result, error) {
+	exists, err := s.UserService.GetExistingUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	return s.build(post.FeedQuery{AuthorUsername: username}, &Feed{
+		Title:       fmt.Sprintf("Posts by %s", username),
+		Description: fmt.Sprintf("Latest posts published by %s", username),
+		Link:        s.SiteURL + "/" + username,
+	}, req)
+}
+
+func (s *Service) TagFeed(name string, req Request) (*Result, error) {
+	tag, err := s.TaxonomyRepo.GetTagByName(taxonomy.NormalizeTagName(name))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	return s.build(post.FeedQuery{Tag: tag.Name}, &Feed{
+		Title:       fmt.Sprintf("Posts tagged #%s", tag.Name),
+		Description: fmt.Sprintf("Latest posts tagged #%s", tag.Name),
+		Link:        s.SiteURL + "/",
+	}, req)
+}
+
+// SeriesFeed ตอนใหม่ของ series เรียงตามเวลา publish เหมือน feed อื่น
+func (s *Service) SeriesFeed(username string, slug string, req Request) (*Result, error) {
+	sr, err := s.SeriesRepo.GetByAuthorAndSlug(username, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrSeriesNotFound
+		}
+		return nil, err
+	}
+
+	description := sr.Description
+	if description == "" {
+		description = fmt.Sprintf("Latest parts of %s by %s", sr.Title, username)
+	}
+	return s.build(post.FeedQuery{SeriesID: sr.ID.String()}, &Feed{
+		Title:       sr.Title,
+		Description: description,
+		Link:        fmt.Sprintf("%s/series/%s/%s", s.SiteURL, username, sr.Slug),
+	}, req)
+}
+
+func authorName(author models.User) string {
+	if name := strings.TrimSpace(author.FirstName + " " + author.LastName); name != "" {
+		return name
+	}
+	return author.UserName
+}
+
+// build โหลดโพสต์ตาม query แล้ว render ตาม format ที่ขอ
+func (s *Service) build(query post.FeedQuery, feed *Feed, req Request) (*Result, error) {
+	query.Limit = ItemLimit
+	query.WithContent = req.Full
+
+	posts, err := s.PostRepo.GetFeedPosts(query)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.FeedURL = req.FeedURL
+	for _, p := range posts {
+		item := Item{
+			ID:          "urn:uuid:" + p.ID.String(),
+			Title:       p.Title,
+			Link:        fmt.Sprintf("%s/posts/%s/%s", s.SiteURL, p.Author.UserName, p.Slug),
+			Description: p.Description,
+			AuthorName:  authorName(p.Author),
+			AuthorURL:   s.SiteURL + "/" + p.Author.UserName,
+			Image:       p.Thumbnail,
+			UpdatedAt:   p.UpdatedAt,
+		}
+		if p.PublishedAt != nil {
+			item.PublishedAt = *p.PublishedAt
+		}
+		if req.Full && p.HTMLContent != nil {
+			// โพสต์ที่ publish ก่อนมี sanitize policy ยังเก็บ HTML เดิมไว้ จึงกรองอีกครั้งตอนออก feed
+			item.ContentHTML = sanitize.Content(*p.HTMLContent).HTML
+		}
+		for _, tag := range p.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+
+		if item.UpdatedAt.After(feed.Updated) {
+			feed.Updated = item.UpdatedAt
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	body, contentType, err := Render(feed, req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	return &Result{
+		Body:         body,
+		ContentType:  contentType,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: feed.Updated,
+	}, nil
+}
@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/feed"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPostRepository mock เฉพาะ method ที่ feed ใช้ method อื่นจะ panic ถ้าถูกเรียก
+type MockPostRepository struct {
+	post.PostRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetFeedPosts(query post.FeedQuery) ([]models.Post, error) {
+	args := m.Called(query)
+	return args.Get(0).([]models.Post), args.Error(1)
+}
+
+var (
+	published = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	updated   = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+)
+
+func sampleFeed() *feed.Feed {
+	return &feed.Feed{
+		Title:       "Posts tagged #go & <web>",
+		Description: "Latest posts",
+		Link:        "https://blog.example.com/",
+		FeedURL:     "https://api.example.com/feed.xml",
+		Updated:     updated,
+		Items: []feed.Item{{
+			ID:          "urn:uuid:1",
+			Title:       "Tips & Tricks",
+			Link:        "https://blog.example.com/posts/alice/tips",
+			Description: "Short summary",
+			ContentHTML: "<p>Hello <b>world</b> ]]> end</p>",
+			AuthorName:  "Alice",
+			AuthorURL:   "https://blog.example.com/alice",
+			Image:       "https://cdn.example.com/thumb.png?size=large",
+			PublishedAt: published,
+			UpdatedAt:   updated,
+			Tags:        []string{"go", "web"},
+		}},
+	}
+}
+
+type rssOutput struct {
+	Channel struct {
+		Title         string `xml:"title"`
+		LastBuildDate string `xml:"lastBuildDate"`
+		Items         []struct {
+			Title     string   `xml:"title"`
+			Link      string   `xml:"link"`
+			GUID      string   `xml:"guid"`
+			PubDate   string   `xml:"pubDate"`
+			Content   string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			Creator   string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			Category  []string `xml:"category"`
+			Enclosure struct {
+				URL  string `xml:"url,attr"`
+				Type string `xml:"type,attr"`
+			} `xml:"enclosure"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomOutput struct {
+	Title   string `xml:"title"`
+	Updated string `xml:"updated"`
+	Entries []struct {
+		Title   string `xml:"title"`
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Content struct {
+			Type  string `xml:"type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"content"`
+		Author struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+type jsonOutput struct {
+	Version string `json:"version"`
+	Title   string `json:"title"`
+	FeedURL string `json:"feed_url"`
+	Items   []struct {
+		ID            string   `json:"id"`
+		URL           string   `json:"url"`
+		ContentHTML   string   `json:"content_html"`
+		ContentText   string   `json:"content_text"`
+		DatePublished string   `json:"date_published"`
+		Tags          []string `json:"tags"`
+	} `json:"items"`
+}
+
+func TestRender_Formats(t *testing.T) {
+	cases := []struct {
+		format      feed.Format
+		contentType string
+		check       func(t *testing.T, body []byte)
+	}{
+		{feed.FormatRSS, "application/rss+xml; charset=utf-8", func(t *testing.T, body []byte) {
+			var out rssOutput
+			require.NoError(t, xml.Unmarshal(body, &out))
+			assert.Equal(t, "Posts tagged #go & <web>", out.Channel.Title)
+			assert.Equal(t, updated.Format(time.RFC1123Z), out.Channel.LastBuildDate)
+			require.Len(t, out.Channel.Items, 1)
+			item := out.Channel.Items[0]
+			assert.Equal(t, "Tips & Tricks", item.Title)
+			assert.Equal(t, "urn:uuid:1", item.GUID)
+			assert.Equal(t, published.Format(time.RFC1123Z), item.PubDate)
+			assert.Equal(t, "<p>Hello <b>world</b> ]]> end</p>", item.Content)
+			assert.Equal(t, "Alice", item.Creator)
+			assert.Equal(t, []string{"go", "web"}, item.Category)
+			assert.Equal(t, "image/png", item.Enclosure.Type)
+		}},
+		{feed.FormatAtom, "application/atom+xml; charset=utf-8", func(t *testing.T, body []byte) {
+			var out atomOutput
+			require.NoError(t, xml.Unmarshal(body, &out))
+			assert.Equal(t, updated.Format(time.RFC3339), out.Updated)
+			require.Len(t, out.Entries, 1)
+			entry := out.Entries[0]
+			assert.Equal(t, "Tips & Tricks", entry.Title)
+			assert.Equal(t, "html", entry.Content.Type)
+			assert.Equal(t, "<p>Hello <b>world</b> ]]> end</p>", entry.Content.Value)
+			assert.Equal(t, "Alice", entry.Author.Name)
+			assert.Len(t, entry.Categories, 2)
+		}},
+		{feed.FormatJSON, "application/feed+json; charset=utf-8", func(t *testing.T, body []byte) {
+			var out jsonOutput
+			require.NoError(t, json.Unmarshal(body, &out))
+			assert.Equal(t, "https://jsonfeed.org/version/1.1", out.Version)
+			assert.Equal(t, "https://api.example.com/feed.xml", out.FeedURL)
+			require.Len(t, out.Items, 1)
+			assert.Equal(t, "<p>Hello <b>world</b> ]]> end</p>", out.Items[0].ContentHTML)
+			assert.Empty(t, out.Items[0].ContentText)
+			assert.Equal(t, published.Format(time.RFC3339), out.Items[0].DatePublished)
+			assert.Equal(t, []string{"go", "web"}, out.Items[0].Tags)
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.format), func(t *testing.T) {
+			body, contentType, err := feed.Render(sampleFeed(), tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.contentType, contentType)
+			tc.check(t, body)
+		})
+	}
+}
+
+func TestRender_JSONFeedFallsBackToContentText(t *testing.T) {
+	f := sampleFeed()
+	f.Items[0].ContentHTML = ""
+
+	body, _, err := feed.Render(f, feed.FormatJSON)
+	require.NoError(t, err)
+	var out jsonOutput
+	require.NoError(t, json.Unmarshal(body, &out))
+	assert.Equal(t, "Short summary", out.Items[0].ContentText)
+}
+
+func newFeedService(posts []models.Post) (feed.ServiceInterface, *MockPostRepository) {
+	repo := new(MockPostRepository)
+	repo.On("GetFeedPosts", mock.AnythingOfType("post.FeedQuery")).Return(posts, nil)
+	return feed.NewService(repo, nil, nil, nil, "https://blog.example.com/"), repo
+}
+
+func TestSiteFeed_EmptyFeedIsStable(t *testing.T) {
+	formats := []feed.Format{feed.FormatRSS, feed.FormatAtom, feed.FormatJSON}
+	service, _ := newFeedService([]models.Post{})
+	req := func(format feed.Format) feed.Request {
+		return feed.Request{Format: format, FeedURL: "https://api.example.com/feed"}
+	}
+
+	first := make(map[feed.Format]*feed.Result)
+	for _, format := range formats {
+		result, err := service.SiteFeed(req(format))
+		require.NoError(t, err)
+		first[format] = result
+	}
+	// timestamp ใน feed ละเอียดระดับวินาที
+	time.Sleep(1100 * time.Millisecond)
+
+	for _, format := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			second, err := service.SiteFeed(req(format))
+			require.NoError(t, err)
+
+			// ETag ต้องไม่เปลี่ยนตามเวลา ไม่อย่างนั้น reader จะไม่ได้ 304
+			assert.Equal(t, first[format].ETag, second.ETag)
+			assert.Equal(t, string(first[format].Body), string(second.Body))
+			assert.True(t, second.LastModified.IsZero())
+			if format == feed.FormatAtom {
+				var out atomOutput
+				require.NoError(t, xml.Unmarshal(second.Body, &out))
+				assert.Equal(t, "1970-01-01T00:00:00Z", out.Updated)
+			}
+		})
+	}
+}
+
+func TestSiteFeed_Items(t *testing.T) {
+	older := updated.Add(-48 * time.Hour)
+	html := `<p>Body</p><script>alert(1)</script>`
+	posts := []models.Post{
+		{
+			ID: uuid.New(), Title: "Newest", Slug: "newest", Description: "new",
+			Author:      models.User{UserName: "alice", FirstName: "Alice", LastName: "Smith"},
+			PublishedAt: &published, HTMLContent: &html,
+			Tags: []models.Tag{{Name: "go"}},
+		},
+		{
+			ID: uuid.New(), Title: "Older", Slug: "older",
+			Author:      models.User{UserName: "bob"},
+			PublishedAt: &older,
+		},
+	}
+	posts[0].UpdatedAt = updated
+	posts[1].UpdatedAt = older
+
+	service, repo := newFeedService(posts)
+	result, err := service.SiteFeed(feed.Request{Format: feed.FormatJSON, Full: true})
+	require.NoError(t, err)
+	repo.AssertCalled(t, "GetFeedPosts", post.FeedQuery{Limit: feed.ItemLimit, WithContent: true})
+
+	assert.Equal(t, updated, result.LastModified)
+	assert.True(t, strings.HasPrefix(result.ETag, `"`) && strings.HasSuffix(result.ETag, `"`))
+
+	var out jsonOutput
+	require.NoError(t, json.Unmarshal(result.Body, &out))
+	require.Len(t, out.Items, 2)
+	assert.Equal(t, "urn:uuid:"+posts[0].ID.String(), out.Items[0].ID)
+	assert.Equal(t, "https://blog.example.com/posts/alice/newest", out.Items[0].URL)
+	assert.Contains(t, out.Items[0].ContentHTML, "<p>Body</p>")
+	assert.NotContains(t, out.Items[0].ContentHTML, "script")
+	assert.Equal(t, []string{"go"}, out.Items[0].Tags)
+	assert.Equal(t, "https://blog.example.com/posts/bob/older", out.Items[1].URL)
+}
@@ -0,0 +1,45 @@
+package follow
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"time"
+)
+
+type FollowResponseDTO struct {
+	Following bool  `json:"following"`
+	Followers int64 `json:"followers"` // จำนวนผู้ติดตามล่าสุดของผู้เขียน
+}
+
+type FollowUserDTO struct {
+	Username   string    `json:"username"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Avatar     string    `json:"avatar,omitempty"`
+	Bio        string    `json:"bio,omitempty"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// FollowListDTO หนึ่งหน้าของรายชื่อ ส่ง NextCursor กลับมาเป็น ?cursor= เพื่อดึงหน้าถัดไป
+type FollowListDTO struct {
+	Users      []FollowUserDTO `json:"users"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+type FeedDTO struct {
+	Posts      []post.PostSummaryDTO `json:"posts"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	HasMore    bool                  `json:"has_more"`
+}
+
+func MapFollowUserToDTO(user models.User, followedAt time.Time) FollowUserDTO {
+	return FollowUserDTO{
+		Username:   user.UserName,
+		FirstName:  user.FirstName,
+		LastName:   user.LastName,
+		Avatar:     user.Avatar,
+		Bio:        user.Bio,
+		FollowedAt: followedAt,
+	}
+}
@@ -0,0 +1,136 @@
+package follow
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryInterface interface {
+	GetUserByUsername(username string) (*models.User, error)
+	Follow(followerID, followeeID uuid.UUID) (bool, error)
+	Unfollow(followerID, followeeID uuid.UUID) (bool, error)
+	CountFollowers(userID uuid.UUID) (int64, error)
+	ListFollowers(userID uuid.UUID, after *Position, limit int) ([]models.Follow, error)
+	ListFollowing(userID uuid.UUID, after *Position, limit int) ([]models.Follow, error)
+	GetFeed(followerID uuid.UUID, after *FeedPosition, limit int) ([]models.Post, error)
+}
+
+// Position ตำแหน่งของรายการสุดท้ายในหน้าก่อนหน้าของรายชื่อผู้ติดตาม (ถอดมาจาก cursor)
+type Position struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// FeedPosition ตำแหน่งของโพสต์สุดท้ายในหน้าก่อนหน้าของ feed
+type FeedPosition struct {
+	PublishedAt time.Time
+	ID          uuid.UUID
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) GetUserByUsername(username string) (*models.User, error) {
+	var user models.User
+	err := r.DB.
+		Where("username = ? AND deleted_at IS NULL", username).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Follow คืน true เมื่อเป็นการติดตามใหม่ (ติดตามอยู่แล้วไม่ถือว่าผิดพลาด)
+func (r *Repository) Follow(followerID, followeeID uuid.UUID) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Unfollow คืน true เมื่อมีการเลิกติดตามจริง
+func (r *Repository) Unfollow(followerID, followeeID uuid.UUID) (bool, error) {
+	result := r.DB.
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Delete(&models.Follow{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// activeFollows การติดตามที่ column เท่ากับ userID โดยอีกฝั่งเป็นบัญชีที่ยังไม่ถูกลบ
+// (column = followee_id คือผู้ติดตามของ userID, follower_id คือคนที่ userID ติดตาม)
+func activeFollows(db *gorm.DB, column string, userID uuid.UUID) *gorm.DB {
+	other := "follower_id"
+	if column == "follower_id" {
+		other = "followee_id"
+	}
+	return db.Model(&models.Follow{}).
+		Joins("JOIN users AS other_user ON other_user.id = follows."+other+" AND other_user.deleted_at IS NULL").
+		Where("follows."+column+" = ?", userID)
+}
+
+func (r *Repository) CountFollowers(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := activeFollows(r.DB, "followee_id", userID).Count(&count).Error
+	return count, err
+}
+
+func selectProfile(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "username", "first_name", "last_name", "avatar", "bio")
+}
+
+// listFollows รายการติดตามเรียงจากล่าสุด column คือฝั่งที่ใช้กรอง (follower_id / followee_id)
+func (r *Repository) listFollows(column string, preload string, userID uuid.UUID, after *Position, limit int) ([]models.Follow, error) {
+	db := activeFollows(r.DB, column, userID).Preload(preload, selectProfile)
+	if after != nil {
+		db = db.Where("(follows.created_at, follows.id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	var follows []models.Follow
+	err := db.Order("follows.created_at DESC, follows.id DESC").Limit(limit).Find(&follows).Error
+	return follows, err
+}
+
+// ListFollowers ผู้ที่ติดตาม userID
+func (r *Repository) ListFollowers(userID uuid.UUID, after *Position, limit int) ([]models.Follow, error) {
+	return r.listFollows("followee_id", "Follower", userID, after, limit)
+}
+
+// ListFollowing ผู้เขียนที่ userID ติดตาม
+func (r *Repository) ListFollowing(userID uuid.UUID, after *Position, limit int) ([]models.Follow, error) {
+	return r.listFollows("follower_id", "Followee", userID, after, limit)
+}
+
+// GetFeed โพสต์ที่ publish แล้วของผู้เขียนที่ติดตาม เรียงตามเวลา publish ล่าสุด
+func (r *Repository) GetFeed(followerID uuid.UUID, after *FeedPosition, limit int) ([]models.Post, error) {
+	db := r.DB.Model(&models.Post{}).
+		Select("posts.id", "posts.slug", "posts.title", "posts.description", "posts.thumbnail",
+			"posts.published", "posts.status", "posts.published_at", "posts.author_id", "posts.likes", "posts.views",
+			"posts.read_time", "posts.ai_chat_open", "posts.ai_ready").
+		Where(post.PublishedPostCondition("posts")).
+		Where(post.ListedPostCondition("posts")).
+		Where("posts.author_id IN (SELECT followee_id FROM follows WHERE follower_id = ?)", followerID).
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Preload("Tags").
+		Preload("Categories")
+	if after != nil {
+		db = db.Where("(posts.published_at, posts.id) < (?, ?)", after.PublishedAt, after.ID)
+	}
+
+	var posts []models.Post
+	err := db.Order("posts.published_at DESC, posts.id DESC").Limit(limit).Find(&posts).Error
+	return posts, err
+}
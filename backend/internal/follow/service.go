@@ -0,0 +1,206 @@
+package follow
+
+import (
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/notification"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/cursor"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	EventNewFollower = "notification:new_follower"
+
+	DefaultLimit = 20
+	MaxLimit     = 50
+)
+
+type ServiceInterface interface {
+	Follow(username string, user *models.User) (*FollowResponseDTO, error)
+	Unfollow(username string, user *models.User) (*FollowResponseDTO, error)
+	ListFollowers(username string, after string, limit int) (*FollowListDTO, error)
+	ListFollowing(username string, after string, limit int) (*FollowListDTO, error)
+	Feed(user *models.User, after string, limit int) (*FeedDTO, error)
+}
+
+type Service struct {
+	Repo        RepositoryInterface
+	NotiService notification.NotificationServiceInterface
+}
+
+func NewService(repo RepositoryInterface, notiService notification.NotificationServiceInterface) ServiceInterface {
+	return &Service{Repo: repo, NotiService: notiService}
+}
+
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+func (s *Service) getUser(username string) (*models.User, error) {
+	u, err := s.Repo.GetUserByUsername(username)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrUserNotFound
+	}
+	return u, err
+}
+
+// Follow ติดตามผู้เขียน ติดตามซ้ำไม่ถือว่าผิดพลาด แจ้งเตือนผู้เขียนเฉพาะการติดตามครั้งใหม่
+func (s *Service) Follow(username string, user *models.User) (*FollowResponseDTO, error) {
+	followee, err := s.getUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if followee.ID == user.ID {
+		return nil, fmt.Errorf("%w: you cannot follow yourself", errs.ErrInvalidPayload)
+	}
+
+	created, err := s.Repo.Follow(user.ID, followee.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		link := "/@" + user.UserName
+		message := fmt.Sprintf("%s started following you", user.UserName)
+		if err := s.NotiService.Notify(followee, "New follower", EventNewFollower, message, &link); err != nil {
+			logger.Log.Warn("Failed to notify new follower",
+				zap.String("followee_id", followee.ID.String()),
+				zap.Error(err))
+		}
+	}
+
+	return s.followResponse(followee, true)
+}
+
+func (s *Service) Unfollow(username string, user *models.User) (*FollowResponseDTO, error) {
+	followee, err := s.getUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Repo.Unfollow(user.ID, followee.ID); err != nil {
+		return nil, err
+	}
+
+	return s.followResponse(followee, false)
+}
+
+func (s *Service) followResponse(followee *models.User, following bool) (*FollowResponseDTO, error) {
+	followers, err := s.Repo.CountFollowers(followee.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &FollowResponseDTO{Following: following, Followers: followers}, nil
+}
+
+func decodePosition(after string) (*Position, error) {
+	c, err := cursor.Decode(after)
+	if err != nil || c == nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(c.ID, 10, 64)
+	if err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+	return &Position{CreatedAt: c.Time, ID: uint(id)}, nil
+}
+
+// ListFollowers ผู้ที่ติดตาม username เรียงจากล่าสุด
+func (s *Service) ListFollowers(username string, after string, limit int) (*FollowListDTO, error) {
+	return s.listFollows(username, after, limit, s.Repo.ListFollowers, func(f models.Follow) models.User { return f.Follower })
+}
+
+// ListFollowing ผู้เขียนที่ username ติดตาม เรียงจากล่าสุด
+func (s *Service) ListFollowing(username string, after string, limit int) (*FollowListDTO, error) {
+	return s.listFollows(username, after, limit, s.Repo.ListFollowing, func(f models.Follow) models.User { return f.Followee })
+}
+
+func (s *Service) listFollows(
+	username string,
+	after string,
+	limit int,
+	list func(userID uuid.UUID, after *Position, limit int) ([]models.Follow, error),
+	pick func(models.Follow) models.User,
+) (*FollowListDTO, error) {
+	u, err := s.getUser(username)
+	if err != nil {
+		return nil, err
+	}
+	position, err := decodePosition(after)
+	if err != nil {
+		return nil, err
+	}
+
+	limit = normalizeLimit(limit)
+	follows, err := list(u.ID, position, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// ดึงเกินมาหนึ่งรายการเพื่อรู้ว่ายังมีหน้าถัดไปหรือไม่
+	result := &FollowListDTO{Users: make([]FollowUserDTO, 0, limit)}
+	if len(follows) > limit {
+		follows = follows[:limit]
+		result.HasMore = true
+	}
+	for _, f := range follows {
+		result.Users = append(result.Users, MapFollowUserToDTO(pick(f), f.CreatedAt))
+	}
+	if result.HasMore {
+		last := follows[len(follows)-1]
+		result.NextCursor = cursor.Encode(last.CreatedAt, strconv.FormatUint(uint64(last.ID), 10))
+	}
+	return result, nil
+}
+
+// Feed โพสต์ล่าสุดจากผู้เขียนที่ติดตาม แบบ cursor pagination
+func (s *Service) Feed(user *models.User, after string, limit int) (*FeedDTO, error) {
+	c, err := cursor.Decode(after)
+	if err != nil {
+		return nil, err
+	}
+	var position *FeedPosition
+	if c != nil {
+		id, err := uuid.Parse(c.ID)
+		if err != nil {
+			return nil, errs.ErrInvalidCursor
+		}
+		position = &FeedPosition{PublishedAt: c.Time, ID: id}
+	}
+
+	limit = normalizeLimit(limit)
+	posts, err := s.Repo.GetFeed(user.ID, position, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FeedDTO{Posts: make([]post.PostSummaryDTO, 0, limit)}
+	if len(posts) > limit {
+		posts = posts[:limit]
+		result.HasMore = true
+	}
+	for _, p := range posts {
+		result.Posts = append(result.Posts, post.MapPostToSummaryDTO(p))
+	}
+	if result.HasMore {
+		last := posts[len(posts)-1]
+		if last.PublishedAt != nil {
+			result.NextCursor = cursor.Encode(*last.PublishedAt, last.ID.String())
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/follow"
+	"rag-searchbot-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newFollowDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, ddl := range []string{
+		`CREATE TABLE users (
+			id TEXT PRIMARY KEY, username TEXT, first_name TEXT, last_name TEXT, avatar TEXT, bio TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE follows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, follower_id TEXT NOT NULL, followee_id TEXT NOT NULL, created_at DATETIME,
+			UNIQUE (follower_id, followee_id))`,
+		`CREATE TABLE posts (
+			id TEXT PRIMARY KEY, slug TEXT, title TEXT, description TEXT, thumbnail TEXT,
+			published BOOLEAN DEFAULT false, status TEXT DEFAULT 'DRAFT', published_at DATETIME,
+			visibility TEXT DEFAULT 'public', likes INTEGER DEFAULT 0, views INTEGER DEFAULT 0, read_time REAL DEFAULT 0,
+			ai_chat_open BOOLEAN DEFAULT false, ai_ready BOOLEAN DEFAULT false, author_id TEXT NOT NULL,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_tags (post_id TEXT, tag_id INTEGER)`,
+		`CREATE TABLE categories (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_categories (post_id TEXT, category_id INTEGER)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db
+}
+
+func insertUser(t *testing.T, db *gorm.DB, username string) uuid.UUID {
+	id := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+		id.String(), username, time.Now(), time.Now()).Error)
+	return id
+}
+
+func TestFollowRepository_FollowAndUnfollowAreIdempotent(t *testing.T) {
+	db := newFollowDB(t)
+	repo := follow.NewRepository(db)
+	alice, bob, carol := insertUser(t, db, "alice"), insertUser(t, db, "bob"), insertUser(t, db, "carol")
+
+	steps := []struct {
+		name      string
+		run       func() (bool, error)
+		changed   bool
+		followers int64
+	}{
+		{"bob follows", func() (bool, error) { return repo.Follow(bob, alice) }, true, 1},
+		{"bob follows again", func() (bool, error) { return repo.Follow(bob, alice) }, false, 1},
+		{"carol follows", func() (bool, error) { return repo.Follow(carol, alice) }, true, 2},
+		{"bob unfollows", func() (bool, error) { return repo.Unfollow(bob, alice) }, true, 1},
+		{"bob unfollows again", func() (bool, error) { return repo.Unfollow(bob, alice) }, false, 1},
+		{"bob follows back", func() (bool, error) { return repo.Follow(bob, alice) }, true, 2},
+	}
+
+	for _, step := range steps {
+		changed, err := step.run()
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.changed, changed, step.name)
+
+		followers, err := repo.CountFollowers(alice)
+		require.NoError(t, err)
+		assert.Equal(t, step.followers, followers, step.name)
+	}
+}
+
+// Test case: บัญชีที่ถูกลบแล้วต้องไม่ถูกนับและไม่โผล่ในรายชื่อ
+func TestFollowRepository_DeletedUsersAreNotCounted(t *testing.T) {
+	db := newFollowDB(t)
+	repo := follow.NewRepository(db)
+	alice, bob, gone := insertUser(t, db, "alice"), insertUser(t, db, "bob"), insertUser(t, db, "gone")
+
+	for _, follower := range []uuid.UUID{bob, gone} {
+		_, err := repo.Follow(follower, alice)
+		require.NoError(t, err)
+	}
+	_, err := repo.Follow(alice, gone)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`UPDATE users SET deleted_at = ? WHERE id = ?`, time.Now(), gone.String()).Error)
+
+	followers, err := repo.CountFollowers(alice)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), followers)
+
+	list, err := repo.ListFollowers(alice, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "bob", list[0].Follower.UserName)
+
+	following, err := repo.ListFollowing(alice, nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, following)
+}
+
+func TestFollowRepository_ListFollowersPaging(t *testing.T) {
+	db := newFollowDB(t)
+	repo := follow.NewRepository(db)
+	alice := insertUser(t, db, "alice")
+
+	// ผู้ติดตามสองคนแรกติดตามในเวลาเดียวกัน ต้องใช้ id ตัดสินลำดับ
+	base := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"u1", "u2", "u3", "u4"} {
+		id := insertUser(t, db, name)
+		at := base.Add(time.Duration(max(i, 1)) * time.Minute)
+		require.NoError(t, db.Exec(`INSERT INTO follows (follower_id, followee_id, created_at) VALUES (?, ?, ?)`,
+			id.String(), alice.String(), at).Error)
+	}
+
+	var names []string
+	var after *follow.Position
+	for page := 0; page < 3; page++ {
+		list, err := repo.ListFollowers(alice, after, 2)
+		require.NoError(t, err)
+		if len(list) == 0 {
+			break
+		}
+		for _, f := range list {
+			names = append(names, f.Follower.UserName)
+		}
+		last := list[len(list)-1]
+		after = &follow.Position{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	assert.Equal(t, []string{"u4", "u3", "u2", "u1"}, names)
+}
+
+func TestFollowRepository_Feed(t *testing.T) {
+	db := newFollowDB(t)
+	repo := follow.NewRepository(db)
+	reader, followed, stranger := insertUser(t, db, "reader"), insertUser(t, db, "followed"), insertUser(t, db, "stranger")
+	_, err := repo.Follow(reader, followed)
+	require.NoError(t, err)
+
+	base := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	insert := func(author uuid.UUID, slug string, minutes int, published bool, visibility models.PostVisibility) {
+		status, at := models.PostDraft, (*time.Time)(nil)
+		if published {
+			t := base.Add(time.Duration(minutes) * time.Minute)
+			status, at = models.PostPublished, &t
+		}
+		require.NoError(t, db.Exec(
+			`INSERT INTO posts (id, slug, title, published, status, published_at, visibility, author_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			uuid.NewString(), slug, slug, published, status, at, visibility, author.String(), base, base).Error)
+	}
+	insert(followed, "old", 1, true, models.VisibilityPublic)
+	insert(followed, "new", 3, true, models.VisibilityPublic)
+	insert(followed, "middle", 2, true, models.VisibilityPublic)
+	insert(followed, "draft", 4, false, models.VisibilityPublic)
+	insert(followed, "unlisted", 5, true, models.VisibilityUnlisted)
+	insert(stranger, "stranger", 6, true, models.VisibilityPublic)
+
+	posts, err := repo.GetFeed(reader, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "new", posts[0].Slug)
+	assert.Equal(t, "followed", posts[0].Author.UserName)
+	assert.Equal(t, "middle", posts[1].Slug)
+
+	rest, err := repo.GetFeed(reader, &follow.FeedPosition{PublishedAt: *posts[1].PublishedAt, ID: posts[1].ID}, 2)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, "old", rest[0].Slug)
+}
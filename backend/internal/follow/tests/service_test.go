@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/follow"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/cursor"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MockFollowRepository struct {
+	mock.Mock
+}
+
+func (m *MockFollowRepository) GetUserByUsername(username string) (*models.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockFollowRepository) Follow(followerID, followeeID uuid.UUID) (bool, error) {
+	args := m.Called(followerID, followeeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFollowRepository) Unfollow(followerID, followeeID uuid.UUID) (bool, error) {
+	args := m.Called(followerID, followeeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFollowRepository) CountFollowers(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockFollowRepository) ListFollowers(userID uuid.UUID, after *follow.Position, limit int) ([]models.Follow, error) {
+	args := m.Called(userID, after, limit)
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
+
+func (m *MockFollowRepository) ListFollowing(userID uuid.UUID, after *follow.Position, limit int) ([]models.Follow, error) {
+	args := m.Called(userID, after, limit)
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
+
+func (m *MockFollowRepository) GetFeed(followerID uuid.UUID, after *follow.FeedPosition, limit int) ([]models.Post, error) {
+	args := m.Called(followerID, after, limit)
+	return args.Get(0).([]models.Post), args.Error(1)
+}
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) Notify(user *models.User, title string, event string, data string, link *string) error {
+	return m.Called(user, title, event, data, link).Error(0)
+}
+
+func newFollowService() (follow.ServiceInterface, *MockFollowRepository, *MockNotificationService) {
+	logger.Log = zap.NewNop()
+	repo := new(MockFollowRepository)
+	noti := new(MockNotificationService)
+	return follow.NewService(repo, noti), repo, noti
+}
+
+func TestFollow(t *testing.T) {
+	reader := &models.User{ID: uuid.New(), UserName: "reader"}
+	author := &models.User{ID: uuid.New(), UserName: "author"}
+
+	cases := []struct {
+		name      string
+		username  string
+		created   bool
+		notifyErr error
+		wantErr   error
+		notified  bool
+	}{
+		{"new follow notifies the author", "author", true, nil, nil, true},
+		{"following again is not an error and does not notify", "author", false, nil, nil, false},
+		{"notification failure does not fail the follow", "author", true, errors.New("ws down"), nil, true},
+		{"cannot follow yourself", "reader", false, nil, errs.ErrInvalidPayload, false},
+		{"unknown user", "nobody", false, nil, errs.ErrUserNotFound, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo, noti := newFollowService()
+			repo.On("GetUserByUsername", "author").Return(author, nil)
+			repo.On("GetUserByUsername", "reader").Return(reader, nil)
+			repo.On("GetUserByUsername", "nobody").Return(nil, gorm.ErrRecordNotFound)
+			repo.On("Follow", reader.ID, author.ID).Return(tc.created, nil)
+			repo.On("CountFollowers", author.ID).Return(int64(7), nil)
+			noti.On("Notify", author, "New follower", follow.EventNewFollower, "reader started following you", mock.Anything).Return(tc.notifyErr)
+
+			result, err := service.Follow(tc.username, reader)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				repo.AssertNotCalled(t, "Follow", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &follow.FollowResponseDTO{Following: true, Followers: 7}, result)
+			if tc.notified {
+				noti.AssertNumberOfCalls(t, "Notify", 1)
+			} else {
+				noti.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUnfollow_ReturnsCurrentCount(t *testing.T) {
+	reader := &models.User{ID: uuid.New()}
+	author := &models.User{ID: uuid.New(), UserName: "author"}
+
+	for _, removed := range []bool{true, false} {
+		service, repo, _ := newFollowService()
+		repo.On("GetUserByUsername", "author").Return(author, nil)
+		repo.On("Unfollow", reader.ID, author.ID).Return(removed, nil)
+		repo.On("CountFollowers", author.ID).Return(int64(3), nil)
+
+		result, err := service.Unfollow("author", reader)
+		require.NoError(t, err)
+		assert.Equal(t, &follow.FollowResponseDTO{Following: false, Followers: 3}, result)
+	}
+}
+
+func TestListFollowers_CursorPaging(t *testing.T) {
+	author := &models.User{ID: uuid.New(), UserName: "author"}
+	base := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	follows := []models.Follow{
+		{ID: 9, CreatedAt: base.Add(3 * time.Minute), Follower: models.User{UserName: "c"}},
+		{ID: 8, CreatedAt: base.Add(2 * time.Minute), Follower: models.User{UserName: "b"}},
+		{ID: 7, CreatedAt: base.Add(time.Minute), Follower: models.User{UserName: "a"}},
+	}
+
+	service, repo, _ := newFollowService()
+	repo.On("GetUserByUsername", "author").Return(author, nil)
+	// ขอ limit + 1 เพื่อรู้ว่ามีหน้าถัดไป
+	repo.On("ListFollowers", author.ID, (*follow.Position)(nil), 3).Return(follows, nil)
+	repo.On("ListFollowers", author.ID, &follow.Position{CreatedAt: follows[1].CreatedAt, ID: 8}, 3).Return(follows[2:], nil)
+
+	first, err := service.ListFollowers("author", "", 2)
+	require.NoError(t, err)
+	require.Len(t, first.Users, 2)
+	assert.Equal(t, "c", first.Users[0].Username)
+	assert.True(t, first.HasMore)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := service.ListFollowers("author", first.NextCursor, 2)
+	require.NoError(t, err)
+	require.Len(t, second.Users, 1)
+	assert.Equal(t, "a", second.Users[0].Username)
+	assert.False(t, second.HasMore)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestListFollowers_RejectsForeignCursors(t *testing.T) {
+	author := &models.User{ID: uuid.New(), UserName: "author"}
+	service, repo, _ := newFollowService()
+	repo.On("GetUserByUsername", "author").Return(author, nil)
+
+	cases := map[string]string{
+		"tampered":       "eyJ0IjoiMjAyNi0wMS0wMVQwMDowMDowMFoiLCJpZCI6IjEifQ.bad",
+		"feed cursor id": cursor.Encode(time.Now(), uuid.NewString()),
+	}
+	for name, after := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.ListFollowing("author", after, 10)
+			assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+		})
+	}
+	repo.AssertNotCalled(t, "ListFollowing", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFeed_LimitAndCursor(t *testing.T) {
+	reader := &models.User{ID: uuid.New()}
+	base := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	posts := make([]models.Post, follow.MaxLimit+1)
+	for i := range posts {
+		at := base.Add(-time.Duration(i) * time.Minute)
+		posts[i] = models.Post{ID: uuid.New(), Slug: "p", PublishedAt: &at}
+	}
+
+	service, repo, _ := newFollowService()
+	repo.On("GetFeed", reader.ID, (*follow.FeedPosition)(nil), follow.MaxLimit+1).Return(posts, nil)
+
+	feed, err := service.Feed(reader, "", 1000)
+	require.NoError(t, err)
+	assert.Len(t, feed.Posts, follow.MaxLimit)
+	assert.True(t, feed.HasMore)
+
+	last := posts[follow.MaxLimit-1]
+	repo.On("GetFeed", reader.ID, &follow.FeedPosition{PublishedAt: *last.PublishedAt, ID: last.ID}, follow.DefaultLimit+1).Return([]models.Post{}, nil)
+	next, err := service.Feed(reader, feed.NextCursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, next.Posts)
+	assert.False(t, next.HasMore)
+
+	_, err = service.Feed(reader, cursor.Encode(base, "12"), 10)
+	assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+}
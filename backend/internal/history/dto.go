@@ -0,0 +1,39 @@
+package history
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"time"
+)
+
+type HistoryEntryDTO struct {
+	LastViewedAt time.Time           `json:"last_viewed_at"`
+	Post         post.PostSummaryDTO `json:"post"`
+}
+
+// HistoryListDTO หนึ่งหน้าของประวัติ ส่ง NextCursor กลับมาเป็น ?cursor= เพื่อดึงหน้าถัดไป
+type HistoryListDTO struct {
+	Entries    []HistoryEntryDTO `json:"entries"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	HasMore    bool              `json:"has_more"`
+	Paused     bool              `json:"paused"`
+}
+
+type PauseRequestDTO struct {
+	Paused *bool `json:"paused" binding:"required"`
+}
+
+type SettingsDTO struct {
+	Paused bool `json:"paused"`
+}
+
+type ClearHistoryResponseDTO struct {
+	Removed int64 `json:"removed"`
+}
+
+func MapHistoryToDTO(entry models.ReadingHistory) HistoryEntryDTO {
+	return HistoryEntryDTO{
+		LastViewedAt: entry.LastViewedAt,
+		Post:         post.MapPostToSummaryDTO(entry.Post),
+	}
+}
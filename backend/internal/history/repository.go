@@ -0,0 +1,87 @@
+package history
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryInterface interface {
+	List(userID uuid.UUID, after *Position, limit int) ([]models.ReadingHistory, error)
+	Delete(userID, postID uuid.UUID) (bool, error)
+	Clear(userID uuid.UUID) (int64, error)
+	IsPaused(userID uuid.UUID) (bool, error)
+	SetPaused(userID uuid.UUID, paused bool) error
+}
+
+// Position ตำแหน่งของรายการสุดท้ายในหน้าก่อนหน้า (ถอดมาจาก cursor)
+type Position struct {
+	LastViewedAt time.Time
+	ID           uint
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+// List ประวัติการอ่านเรียงจากที่อ่านล่าสุด ซ่อนโพสต์ที่ถูกลบหรือเลิก publish ไปแล้ว
+func (r *Repository) List(userID uuid.UUID, after *Position, limit int) ([]models.ReadingHistory, error) {
+	condition, published, status := post.PublishedPostCondition("posts")
+
+	db := r.DB.Model(&models.ReadingHistory{}).
+		Joins("JOIN posts ON posts.id = reading_histories.post_id AND "+condition, published, status).
+		Where("reading_histories.user_id = ?", userID).
+		Preload("Post", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "slug", "title", "description", "thumbnail",
+				"published", "status", "published_at", "author_id", "likes", "views",
+				"read_time", "ai_chat_open", "ai_ready")
+		}).
+		Preload("Post.Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Preload("Post.Tags")
+	if after != nil {
+		db = db.Where("(reading_histories.last_viewed_at, reading_histories.id) < (?, ?)", after.LastViewedAt, after.ID)
+	}
+
+	var entries []models.ReadingHistory
+	err := db.Order("reading_histories.last_viewed_at DESC, reading_histories.id DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// Delete คืน true เมื่อมีรายการถูกลบจริง
+func (r *Repository) Delete(userID, postID uuid.UUID) (bool, error) {
+	result := r.DB.
+		Where("user_id = ? AND post_id = ?", userID, postID).
+		Delete(&models.ReadingHistory{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Clear ลบประวัติทั้งหมดของผู้ใช้ คืนจำนวนรายการที่ลบ (post_views ยังอยู่ ยอด view ไม่เปลี่ยน)
+func (r *Repository) Clear(userID uuid.UUID) (int64, error) {
+	result := r.DB.Where("user_id = ?", userID).Delete(&models.ReadingHistory{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *Repository) IsPaused(userID uuid.UUID) (bool, error) {
+	var setting models.ReadingHistorySetting
+	err := r.DB.Where("user_id = ?", userID).Limit(1).Find(&setting).Error
+	return setting.Paused, err
+}
+
+func (r *Repository) SetPaused(userID uuid.UUID, paused bool) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"paused", "updated_at"}),
+	}).Create(&models.ReadingHistorySetting{UserID: userID, Paused: paused}).Error
+}
@@ -0,0 +1,111 @@
+package history
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/cursor"
+	"rag-searchbot-backend/pkg/errs"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+)
+
+// ประวัติถูกเขียนโดย post.PostRepository.RecordPostView ตอนผู้ใช้ที่ login เปิดอ่านโพสต์
+// service นี้จึงมีแค่การอ่าน ลบ และหยุด / เริ่มบันทึกใหม่
+type ServiceInterface interface {
+	List(user *models.User, after string, limit int) (*HistoryListDTO, error)
+	Remove(postID string, user *models.User) error
+	Clear(user *models.User) (*ClearHistoryResponseDTO, error)
+	SetPaused(paused bool, user *models.User) (*SettingsDTO, error)
+}
+
+type Service struct {
+	Repo RepositoryInterface
+}
+
+func NewService(repo RepositoryInterface) ServiceInterface {
+	return &Service{Repo: repo}
+}
+
+// List โพสต์ที่อ่านล่าสุดแบบ cursor pagination พร้อมสถานะการหยุดบันทึก
+func (s *Service) List(user *models.User, after string, limit int) (*HistoryListDTO, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	c, err := cursor.Decode(after)
+	if err != nil {
+		return nil, err
+	}
+	var position *Position
+	if c != nil {
+		id, err := strconv.ParseUint(c.ID, 10, 64)
+		if err != nil {
+			return nil, errs.ErrInvalidCursor
+		}
+		position = &Position{LastViewedAt: c.Time, ID: uint(id)}
+	}
+
+	entries, err := s.Repo.List(user.ID, position, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	paused, err := s.Repo.IsPaused(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ดึงเกินมาหนึ่งรายการเพื่อรู้ว่ายังมีหน้าถัดไปหรือไม่
+	result := &HistoryListDTO{Entries: make([]HistoryEntryDTO, 0, limit), Paused: paused}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		result.HasMore = true
+	}
+	for _, e := range entries {
+		result.Entries = append(result.Entries, MapHistoryToDTO(e))
+	}
+	if result.HasMore {
+		last := entries[len(entries)-1]
+		result.NextCursor = cursor.Encode(last.LastViewedAt, strconv.FormatUint(uint64(last.ID), 10))
+	}
+	return result, nil
+}
+
+// Remove ลบโพสต์ออกจากประวัติ (อ่านอีกครั้งจะกลับเข้ามาใหม่ถ้ายังไม่ได้หยุดบันทึก)
+func (s *Service) Remove(postID string, user *models.User) error {
+	id, err := uuid.Parse(postID)
+	if err != nil {
+		return errs.ErrHistoryEntryNotFound
+	}
+	removed, err := s.Repo.Delete(user.ID, id)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errs.ErrHistoryEntryNotFound
+	}
+	return nil
+}
+
+func (s *Service) Clear(user *models.User) (*ClearHistoryResponseDTO, error) {
+	removed, err := s.Repo.Clear(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &ClearHistoryResponseDTO{Removed: removed}, nil
+}
+
+// SetPaused หยุด / เริ่มบันทึกประวัติการอ่าน ประวัติเดิมยังอยู่จนกว่าจะสั่ง Clear
+func (s *Service) SetPaused(paused bool, user *models.User) (*SettingsDTO, error) {
+	if err := s.Repo.SetPaused(user.ID, paused); err != nil {
+		return nil, err
+	}
+	return &SettingsDTO{Paused: paused}, nil
+}
@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/history"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newHistoryDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, ddl := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT, avatar TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE posts (
+			id TEXT PRIMARY KEY, slug TEXT, title TEXT, description TEXT, thumbnail TEXT,
+			published BOOLEAN DEFAULT false, status TEXT DEFAULT 'DRAFT', published_at DATETIME,
+			likes INTEGER DEFAULT 0, views INTEGER DEFAULT 0, read_time REAL DEFAULT 0,
+			ai_chat_open BOOLEAN DEFAULT false, ai_ready BOOLEAN DEFAULT false, author_id TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_tags (post_id TEXT, tag_id INTEGER)`,
+		`CREATE TABLE post_views (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, user_id TEXT, fingerprint TEXT NOT NULL,
+			ip_address TEXT, user_agent TEXT, referrer TEXT, viewed_at DATETIME,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE reading_histories (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, user_id TEXT NOT NULL, post_id TEXT NOT NULL,
+			last_viewed_at DATETIME NOT NULL, created_at DATETIME, UNIQUE (user_id, post_id))`,
+		`CREATE TABLE reading_history_settings (user_id TEXT PRIMARY KEY, paused BOOLEAN NOT NULL DEFAULT false, updated_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db
+}
+
+func insertUser(t *testing.T, db *gorm.DB, username string) uuid.UUID {
+	id := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, id.String(), username).Error)
+	return id
+}
+
+func insertPost(t *testing.T, db *gorm.DB, authorID uuid.UUID, slug string, published bool) uuid.UUID {
+	id := uuid.New()
+	status := models.PostDraft
+	var publishedAt *time.Time
+	if published {
+		now := time.Now().UTC()
+		status, publishedAt = models.PostPublished, &now
+	}
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, slug, title, published, status, published_at, author_id) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id.String(), slug, slug, published, status, publishedAt, authorID.String()).Error)
+	return id
+}
+
+func insertHistory(t *testing.T, db *gorm.DB, userID, postID uuid.UUID, at time.Time) {
+	require.NoError(t, db.Exec(`INSERT INTO reading_histories (user_id, post_id, last_viewed_at, created_at) VALUES (?, ?, ?, ?)`,
+		userID.String(), postID.String(), at, at).Error)
+}
+
+func historySlugs(entries []models.ReadingHistory) []string {
+	slugs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		slugs = append(slugs, e.Post.Slug)
+	}
+	return slugs
+}
+
+func TestHistoryRepository_ListNewestFirstAndPages(t *testing.T) {
+	db := newHistoryDB(t)
+	repo := history.NewRepository(db)
+	reader, author := insertUser(t, db, "reader"), insertUser(t, db, "author")
+	other := insertUser(t, db, "other")
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, slug := range []string{"first", "second", "third"} {
+		insertHistory(t, db, reader, insertPost(t, db, author, slug, true), base.Add(time.Duration(i)*time.Hour))
+	}
+	// เวลาเท่ากับ "third" ต้องเรียงต่อด้วย id
+	tie := insertPost(t, db, author, "tie", true)
+	insertHistory(t, db, reader, tie, base.Add(2*time.Hour))
+	// โพสต์ที่เลิก publish หรือถูกลบไม่แสดง แต่แถวยังอยู่
+	insertHistory(t, db, reader, insertPost(t, db, author, "draft", false), base.Add(5*time.Hour))
+	deleted := insertPost(t, db, author, "deleted", true)
+	require.NoError(t, db.Exec(`UPDATE posts SET deleted_at = ? WHERE id = ?`, time.Now(), deleted.String()).Error)
+	insertHistory(t, db, reader, deleted, base.Add(6*time.Hour))
+	// ประวัติของคนอื่นไม่ปนมา
+	insertHistory(t, db, other, tie, base.Add(7*time.Hour))
+
+	all, err := repo.List(reader, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tie", "third", "second", "first"}, historySlugs(all))
+	assert.Equal(t, "author", all[0].Post.Author.UserName)
+
+	firstPage, err := repo.List(reader, nil, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tie", "third"}, historySlugs(firstPage))
+
+	last := firstPage[len(firstPage)-1]
+	nextPage, err := repo.List(reader, &history.Position{LastViewedAt: last.LastViewedAt, ID: last.ID}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second", "first"}, historySlugs(nextPage))
+}
+
+func TestHistoryRepository_DeleteAndClear(t *testing.T) {
+	db := newHistoryDB(t)
+	repo := history.NewRepository(db)
+	reader, other, author := insertUser(t, db, "reader"), insertUser(t, db, "other"), insertUser(t, db, "author")
+	first, second := insertPost(t, db, author, "first", true), insertPost(t, db, author, "second", true)
+	now := time.Now().UTC()
+	insertHistory(t, db, reader, first, now)
+	insertHistory(t, db, reader, second, now)
+	insertHistory(t, db, other, first, now)
+
+	removed, err := repo.Delete(reader, first)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = repo.Delete(reader, first)
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	cleared, err := repo.Clear(reader)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), cleared)
+
+	remaining, err := repo.List(other, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, historySlugs(remaining))
+}
+
+func TestHistoryRepository_SetPausedUpserts(t *testing.T) {
+	db := newHistoryDB(t)
+	repo := history.NewRepository(db)
+	reader := insertUser(t, db, "reader")
+
+	paused, err := repo.IsPaused(reader)
+	require.NoError(t, err)
+	assert.False(t, paused, "no settings row means recording")
+
+	for _, want := range []bool{true, true, false, true} {
+		require.NoError(t, repo.SetPaused(reader, want))
+		paused, err := repo.IsPaused(reader)
+		require.NoError(t, err)
+		assert.Equal(t, want, paused)
+	}
+
+	var rows int64
+	require.NoError(t, db.Model(&models.ReadingHistorySetting{}).Count(&rows).Error)
+	assert.Equal(t, int64(1), rows)
+}
+
+// Test case: RecordPostView เขียนประวัติหนึ่งแถวต่อโพสต์ อ่านซ้ำแค่เลื่อนเวลา และข้ามเมื่อหยุดบันทึก
+func TestRecordPostView_TouchesReadingHistory(t *testing.T) {
+	db := newHistoryDB(t)
+	postRepo := post.NewPostRepository(db)
+	repo := history.NewRepository(db)
+	reader, author := insertUser(t, db, "reader"), insertUser(t, db, "author")
+	postID := insertPost(t, db, author, "article", true)
+	readerID := reader.String()
+
+	// อ่านแบบไม่ login ไม่มีประวัติ
+	require.NoError(t, postRepo.RecordPostView(postID.String(), nil, "browser", "", "", ""))
+	entries, err := repo.List(reader, nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, postRepo.RecordPostView(postID.String(), &readerID, "browser", "", "", ""))
+	entries, err = repo.List(reader, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	firstViewed := entries[0].LastViewedAt
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, postRepo.RecordPostView(postID.String(), &readerID, "other-browser", "", "", ""))
+	entries, err = repo.List(reader, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, entries[0].LastViewedAt.After(firstViewed))
+
+	// หยุดบันทึกแล้วอ่านโพสต์ใหม่ ไม่เข้าประวัติ แต่ยอด view ยังนับ
+	require.NoError(t, repo.SetPaused(reader, true))
+	pausedPost := insertPost(t, db, author, "while-paused", true)
+	require.NoError(t, postRepo.RecordPostView(pausedPost.String(), &readerID, "browser", "", "", ""))
+	entries, err = repo.List(reader, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"article"}, historySlugs(entries))
+
+	views, err := postRepo.GetPostViews(pausedPost.String())
+	require.NoError(t, err)
+	assert.Equal(t, 1, views)
+
+	// เริ่มบันทึกใหม่ อ่านซ้ำจึงเข้าประวัติ
+	require.NoError(t, repo.SetPaused(reader, false))
+	require.NoError(t, postRepo.RecordPostView(pausedPost.String(), &readerID, "browser", "", "", ""))
+	entries, err = repo.List(reader, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"while-paused", "article"}, historySlugs(entries))
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/history"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/cursor"
+	"rag-searchbot-backend/pkg/errs"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockHistoryRepository) List(userID uuid.UUID, after *history.Position, limit int) ([]models.ReadingHistory, error) {
+	args := m.Called(userID, after, limit)
+	return args.Get(0).([]models.ReadingHistory), args.Error(1)
+}
+
+func (m *MockHistoryRepository) Delete(userID, postID uuid.UUID) (bool, error) {
+	args := m.Called(userID, postID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockHistoryRepository) Clear(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockHistoryRepository) IsPaused(userID uuid.UUID) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockHistoryRepository) SetPaused(userID uuid.UUID, paused bool) error {
+	args := m.Called(userID, paused)
+	return args.Error(0)
+}
+
+func historyEntries(n int, newest time.Time) []models.ReadingHistory {
+	entries := make([]models.ReadingHistory, n)
+	for i := range entries {
+		entries[i] = models.ReadingHistory{
+			ID:           uint(100 - i),
+			LastViewedAt: newest.Add(-time.Duration(i) * time.Minute),
+			Post:         models.Post{ID: uuid.New(), Slug: "post-" + strconv.Itoa(i)},
+		}
+	}
+	return entries
+}
+
+func TestHistoryList_ClampsLimit(t *testing.T) {
+	cases := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"default", 0, history.DefaultLimit},
+		{"negative", -5, history.DefaultLimit},
+		{"within range", 5, 5},
+		{"over max", 500, history.MaxLimit},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockHistoryRepository)
+			user := &models.User{ID: uuid.New()}
+			// ขอเกินหนึ่งรายการเพื่อดูว่ามีหน้าถัดไป
+			repo.On("List", user.ID, (*history.Position)(nil), tc.want+1).Return([]models.ReadingHistory{}, nil)
+			repo.On("IsPaused", user.ID).Return(false, nil)
+
+			result, err := history.NewService(repo).List(user, "", tc.limit)
+			require.NoError(t, err)
+			assert.NotNil(t, result.Entries)
+			assert.False(t, result.HasMore)
+			assert.Empty(t, result.NextCursor)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHistoryList_CursorPaging(t *testing.T) {
+	repo := new(MockHistoryRepository)
+	service := history.NewService(repo)
+	user := &models.User{ID: uuid.New()}
+	entries := historyEntries(3, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	repo.On("List", user.ID, (*history.Position)(nil), 3).Return(entries, nil)
+	repo.On("IsPaused", user.ID).Return(true, nil)
+
+	page, err := service.List(user, "", 2)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	assert.True(t, page.HasMore)
+	assert.True(t, page.Paused)
+	assert.Equal(t, "post-0", page.Entries[0].Post.Slug)
+	require.NotEmpty(t, page.NextCursor)
+
+	// cursor ชี้ไปที่รายการสุดท้ายของหน้าแรก
+	last := entries[1]
+	repo.On("List", user.ID, &history.Position{LastViewedAt: last.LastViewedAt, ID: last.ID}, 3).
+		Return(entries[2:], nil)
+
+	next, err := service.List(user, page.NextCursor, 2)
+	require.NoError(t, err)
+	require.Len(t, next.Entries, 1)
+	assert.False(t, next.HasMore)
+	assert.Empty(t, next.NextCursor)
+	assert.Equal(t, "post-2", next.Entries[0].Post.Slug)
+}
+
+func TestHistoryList_InvalidCursor(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cases := map[string]string{
+		"garbage":         "not-a-cursor",
+		"non numeric id":  cursor.Encode(time.Now(), uuid.NewString()),
+		"negative number": cursor.Encode(time.Now(), "-1"),
+	}
+
+	for name, after := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockHistoryRepository)
+			_, err := history.NewService(repo).List(user, after, 10)
+			assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+			repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHistoryRemove(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	postID := uuid.New()
+	failure := errors.New("db down")
+
+	cases := []struct {
+		name    string
+		postID  string
+		removed bool
+		repoErr error
+		wantErr error
+	}{
+		{"removed", postID.String(), true, nil, nil},
+		{"not in history", postID.String(), false, nil, errs.ErrHistoryEntryNotFound},
+		{"invalid id", "not-a-uuid", false, nil, errs.ErrHistoryEntryNotFound},
+		{"repository error", postID.String(), false, failure, failure},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockHistoryRepository)
+			repo.On("Delete", user.ID, postID).Return(tc.removed, tc.repoErr)
+
+			err := history.NewService(repo).Remove(tc.postID, user)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestHistoryClearAndPause(t *testing.T) {
+	repo := new(MockHistoryRepository)
+	service := history.NewService(repo)
+	user := &models.User{ID: uuid.New()}
+
+	repo.On("Clear", user.ID).Return(int64(4), nil)
+	cleared, err := service.Clear(user)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), cleared.Removed)
+
+	repo.On("SetPaused", user.ID, true).Return(nil)
+	settings, err := service.SetPaused(true, user)
+	require.NoError(t, err)
+	assert.True(t, settings.Paused)
+
+	failure := errors.New("db down")
+	repo.On("SetPaused", user.ID, false).Return(failure)
+	_, err = service.SetPaused(false, user)
+	assert.ErrorIs(t, err, failure)
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole อนุญาตเฉพาะผู้ใช้ที่มี role ตามที่กำหนด ต้องใช้ต่อจาก AuthMiddleware
+func RequireRole(roles ...models.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user")
+		user, ok := value.(*models.User)
+		if !exists || !ok || user == nil {
+			response.JSONError(c, http.StatusUnauthorized, "Unauthorized", "User not found in context")
+			c.Abort()
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		response.JSONError(c, http.StatusForbidden, "Forbidden", "You do not have permission to access this resource")
+		c.Abort()
+	}
+}
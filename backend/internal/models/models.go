@@ -30,6 +30,16 @@ const (
 	PostProcessing PostStatus = "PROCESSING"
 	PostPublished  PostStatus = "PUBLISHED"
 	PostRejected   PostStatus = "REJECTED"
+	PostScheduled  PostStatus = "SCHEDULED"
+)
+
+// PostVisibility ใครเห็นโพสต์ที่ publish แล้วได้บ้าง
+type PostVisibility string
+
+const (
+	VisibilityPublic    PostVisibility = "public"    // แสดงทุกที่ตามปกติ
+	VisibilityUnlisted  PostVisibility = "unlisted"  // เปิดได้ด้วยลิงก์ แต่ไม่อยู่ในรายการ feed sitemap และการค้นหา
+	VisibilityProtected PostVisibility = "protected" // ต้องปลดล็อกด้วย share key / รหัสผ่านก่อนอ่าน
 )
 
 type User struct {
@@ -62,25 +72,31 @@ type User struct {
 }
 
 type Post struct {
-	ID          uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Slug        string         `gorm:"uniqueIndex;not null;index" json:"slug"`
-	ShortSlug   string         `gorm:"uniqueIndex;not null;index" json:"short_slug" binding:"required"`
-	Title       string         `gorm:"default:null" json:"title"`
-	Description string         `json:"description,omitempty"`
-	Thumbnail   string         `json:"thumbnail,omitempty"`
-	Example     string         `json:"example,omitempty"`
-	Content     string         `gorm:"type:text;not null" json:"content"`
-	HTMLContent *string        `gorm:"type:text" json:"html_content"`
-	Published   bool           `gorm:"default:false" json:"published"`
-	Status      PostStatus     `gorm:"type:varchar(20);default:'DRAFT'" json:"status"`
-	PublishedAt *time.Time     `json:"published_at,omitempty"`
-	Keywords    pq.StringArray `gorm:"type:text[]" json:"keywords,omitempty"`
-	Key         string         `json:"key,omitempty"`
-	Likes       int            `gorm:"default:0" json:"likes"`
-	Views       int            `gorm:"default:0" json:"views"`
-	ReadTime    float64        `gorm:"default:0" json:"read_time"`
-	AIChatOpen  bool           `gorm:"default:false" json:"ai_chat_open"` // เปิด AI chat หรือไม่
-	AIReady     bool           `gorm:"default:false" json:"ai_ready"`     // AI พร้อมใช้งานหรือไม่
+	ID             uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Slug           string         `gorm:"uniqueIndex;not null;index" json:"slug"`
+	ShortSlug      string         `gorm:"uniqueIndex;not null;index" json:"short_slug" binding:"required"`
+	Title          string         `gorm:"default:null" json:"title"`
+	Description    string         `json:"description,omitempty"`
+	Thumbnail      string         `json:"thumbnail,omitempty"`
+	Example        string         `json:"example,omitempty"`
+	Content        string         `gorm:"type:text;not null" json:"content"`
+	HTMLContent    *string        `gorm:"type:text" json:"html_content"`
+	SearchText     string         `gorm:"type:text" json:"-"` // plain text จาก content ใช้สร้าง search_vector (ดู config.migrateSearchIndex)
+	Published      bool           `gorm:"default:false" json:"published"`
+	Status         PostStatus     `gorm:"type:varchar(20);default:'DRAFT'" json:"status"`
+	PublishedAt    *time.Time     `json:"published_at,omitempty"`
+	PublishAt      *time.Time     `json:"publish_at,omitempty"`   // เวลาที่ตั้งให้ publish อัตโนมัติ (status = SCHEDULED)
+	UnpublishAt    *time.Time     `json:"unpublish_at,omitempty"` // เวลาที่ตั้งให้ unpublish อัตโนมัติ
+	Keywords       pq.StringArray `gorm:"type:text[]" json:"keywords,omitempty"`
+	Key            string         `json:"-"` // bcrypt hash ของ share key / รหัสผ่าน ใช้เมื่อ Visibility = protected
+	Visibility     PostVisibility `gorm:"type:varchar(20);default:'public';index" json:"visibility"`
+	Likes          int            `gorm:"default:0" json:"likes"`
+	Views          int            `gorm:"default:0" json:"views"`
+	ReadTime       float64        `gorm:"default:0" json:"read_time"`
+	AIChatOpen     bool           `gorm:"default:false" json:"ai_chat_open"`    // เปิด AI chat หรือไม่
+	AIReady        bool           `gorm:"default:false" json:"ai_ready"`        // AI พร้อมใช้งานหรือไม่
+	CommentsLocked bool           `gorm:"default:false" json:"comments_locked"` // ผู้เขียนปิดรับ comment ใหม่
+	Version        int            `gorm:"not null;default:1" json:"version"`    // เพิ่มทุกครั้งที่ title/content เปลี่ยน ใช้กัน autosave ทับกัน
 	BaseModel
 
 	AuthorID   uuid.UUID     `gorm:"not null" json:"author_id"`
@@ -90,17 +106,22 @@ type Post struct {
 	Comments   []Comment     `gorm:"foreignKey:PostID;references:ID" json:"comments,omitempty"`
 	Embeddings []Embedding   `gorm:"foreignKey:PostID;references:ID" json:"embeddings,omitempty"`
 	Images     []ImageUpload `gorm:"foreignKey:PostID;references:ID" json:"images,omitempty"`
+
+	Collaborators []PostCollaborator `gorm:"foreignKey:PostID;references:ID" json:"collaborators,omitempty"`
 }
 
 type Comment struct {
-	ID      uint   `gorm:"primaryKey;autoIncrement" json:"id"`
-	Content string `gorm:"type:text;not null" json:"content"`
+	ID       uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Content  string     `gorm:"type:text;not null" json:"content"`
+	ParentID *uint      `gorm:"index" json:"parent_id,omitempty"` // reply ได้ชั้นเดียว ชี้ไปที่ comment ระดับบนสุดเสมอ
+	EditedAt *time.Time `json:"edited_at,omitempty"`
 	BaseModel
 
-	PostID   uuid.UUID `gorm:"not null" json:"post_id"`
+	PostID   uuid.UUID `gorm:"not null;index" json:"post_id"`
 	AuthorID uuid.UUID `gorm:"not null" json:"author_id"`
 	Post     Post      `gorm:"foreignKey:PostID;references:ID" json:"post,omitempty"`
 	Author   User      `gorm:"foreignKey:AuthorID;references:ID" json:"author,omitempty"`
+	Replies  []Comment `gorm:"foreignKey:ParentID;references:ID" json:"replies,omitempty"`
 }
 
 type Tag struct {
@@ -129,6 +150,16 @@ type Embedding struct {
 	Post Post `gorm:"foreignKey:PostID;references:ID" json:"post,omitempty"`
 }
 
+// PostCentroid ค่าเฉลี่ยของ embedding ทุก chunk ในโพสต์ ใช้หาโพสต์ที่เกี่ยวข้อง
+// คำนวณใหม่ทุกครั้งที่ embed โพสต์เสร็จ และถูกลบพร้อม embedding
+type PostCentroid struct {
+	PostID    uuid.UUID       `gorm:"type:uuid;primaryKey" json:"post_id"`
+	Vector    pgvector.Vector `gorm:"type:vector(384)" json:"-"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Post Post `gorm:"foreignKey:PostID;references:ID" json:"-"`
+}
+
 type Notification struct {
 	ID      uint      `gorm:"primaryKey;autoIncrement" json:"id"`
 	Title   string    `gorm:"not null" json:"title"`
@@ -218,9 +249,204 @@ type PostView struct {
 	Fingerprint string     `gorm:"not null;index" json:"fingerprint"`            // unique fingerprint ของ browser
 	IPAddress   string     `gorm:"type:varchar(45)" json:"ip_address,omitempty"` // IPv4 หรือ IPv6
 	UserAgent   string     `gorm:"type:text" json:"user_agent,omitempty"`
+	Referrer    string     `gorm:"type:varchar(255);index" json:"referrer,omitempty"` // host ของหน้าที่ส่งมา ว่าง = เข้าตรง
 	ViewedAt    time.Time  `gorm:"autoCreateTime" json:"viewed_at"`
 	BaseModel
 
 	Post Post  `gorm:"foreignKey:PostID;references:ID" json:"post,omitempty"`
 	User *User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
 }
+
+type PostRevisionReason string
+
+const (
+	RevisionSave    PostRevisionReason = "SAVE"
+	RevisionPublish PostRevisionReason = "PUBLISH"
+	RevisionRestore PostRevisionReason = "RESTORE"
+)
+
+// PostRevision สำหรับเก็บ snapshot ของเนื้อหา post ทุกครั้งที่บันทึกหรือ publish
+type PostRevision struct {
+	ID          uint               `gorm:"primaryKey;autoIncrement" json:"id"`
+	PostID      uuid.UUID          `gorm:"type:uuid;not null;index" json:"post_id"`
+	UserID      uuid.UUID          `gorm:"type:uuid;not null;index" json:"user_id"` // ใครเป็นคนบันทึก revision นี้
+	Title       string             `json:"title"`
+	Content     string             `gorm:"type:text;not null" json:"content"`
+	HTMLContent *string            `gorm:"type:text" json:"html_content,omitempty"`
+	Reason      PostRevisionReason `gorm:"type:varchar(20);not null;index" json:"reason"` // SAVE, PUBLISH, RESTORE
+	BaseModel
+
+	Post Post `gorm:"foreignKey:PostID;references:ID" json:"post,omitempty"`
+	User User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+type ReactionType string
+
+const (
+	ReactionLike  ReactionType = "like"
+	ReactionLove  ReactionType = "love"
+	ReactionClap  ReactionType = "clap"
+	ReactionFire  ReactionType = "fire"
+	ReactionLaugh ReactionType = "laugh"
+	ReactionWow   ReactionType = "wow"
+)
+
+// PostReaction หนึ่งแถวต่อ (post, user, type) ผู้ใช้คนเดียวกดได้หลายประเภทแต่ประเภทละครั้ง
+// ลบแถวจริงเมื่อกดซ้ำ (toggle) จึงไม่ใช้ soft delete
+type PostReaction struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement" json:"id"`
+	PostID    uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_post_reaction" json:"post_id"`
+	UserID    uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_post_reaction" json:"user_id"`
+	Type      ReactionType `gorm:"type:varchar(20);not null;uniqueIndex:idx_post_reaction" json:"type"`
+	CreatedAt time.Time    `gorm:"autoCreateTime;index" json:"created_at"`
+
+	Post Post `gorm:"foreignKey:PostID;references:ID" json:"-"`
+	User User `gorm:"foreignKey:UserID;references:ID" json:"-"`
+}
+
+// Series ชุดบทความต่อเนื่องของผู้เขียนคนเดียว (เช่น tutorial หลายตอน)
+// slug ไม่ซ้ำภายในผู้เขียนเดียวกัน จึงลบแถวจริงแทน soft delete
+type Series struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Slug        string    `gorm:"not null;uniqueIndex:idx_series_author_slug" json:"slug"`
+	Title       string    `gorm:"not null" json:"title"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	AuthorID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_series_author_slug" json:"author_id"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Author User         `gorm:"foreignKey:AuthorID;references:ID" json:"author,omitempty"`
+	Posts  []SeriesPost `gorm:"foreignKey:SeriesID;references:ID" json:"posts,omitempty"`
+}
+
+// SeriesPost ลำดับของโพสต์ใน series (Position เริ่มที่ 1) โพสต์หนึ่งอยู่ได้เพียง series เดียว
+type SeriesPost struct {
+	ID       uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	SeriesID uuid.UUID `gorm:"type:uuid;not null;index" json:"series_id"`
+	PostID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"post_id"`
+	Position int       `gorm:"not null" json:"position"`
+
+	Post Post `gorm:"foreignKey:PostID;references:ID" json:"post,omitempty"`
+}
+
+type CollaboratorRole string
+
+const (
+	CollaboratorEditor   CollaboratorRole = "editor"   // แก้เนื้อหา publish / unpublish และเปิด AI chat ได้
+	CollaboratorReviewer CollaboratorRole = "reviewer" // อ่าน draft และ revision ได้อย่างเดียว
+)
+
+type CollaboratorStatus string
+
+const (
+	CollaboratorPending  CollaboratorStatus = "pending"
+	CollaboratorAccepted CollaboratorStatus = "accepted"
+)
+
+// PostCollaborator ผู้ร่วมเขียนโพสต์ มีสิทธิ์ตาม Role หลังตอบรับคำเชิญแล้วเท่านั้น
+// ลบแถวจริงเมื่อปฏิเสธหรือถูกถอดออก จึงเชิญใหม่ได้
+type PostCollaborator struct {
+	ID          uint               `gorm:"primaryKey;autoIncrement" json:"id"`
+	PostID      uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_post_collaborator" json:"post_id"`
+	UserID      uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_post_collaborator;index" json:"user_id"`
+	Role        CollaboratorRole   `gorm:"type:varchar(20);not null" json:"role"`
+	Status      CollaboratorStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	InvitedByID uuid.UUID          `gorm:"type:uuid;not null" json:"invited_by_id"`
+	AcceptedAt  *time.Time         `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Post      Post `gorm:"foreignKey:PostID;references:ID" json:"-"`
+	User      User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	InvitedBy User `gorm:"foreignKey:InvitedByID;references:ID" json:"-"`
+}
+
+// PostDailyStat สถิติรายวันของโพสต์ (วันตาม UTC) คำนวณใหม่จากข้อมูลดิบโดย analytics job
+type PostDailyStat struct {
+	ID              uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PostID          uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_post_daily_stat" json:"post_id"`
+	Date            time.Time `gorm:"type:date;not null;uniqueIndex:idx_post_daily_stat;index" json:"date"`
+	Views           int64     `gorm:"not null;default:0" json:"views"`
+	UniqueVisitors  int64     `gorm:"not null;default:0" json:"unique_visitors"`   // fingerprint ไม่ซ้ำ
+	LoggedInViewers int64     `gorm:"not null;default:0" json:"logged_in_viewers"` // ผู้ใช้ที่ login ไม่ซ้ำ
+	AIChats         int64     `gorm:"not null;default:0" json:"ai_chats"`          // ผู้ใช้ที่เริ่มคุยกับ AI ในวันนั้น
+	Likes           int64     `gorm:"not null;default:0" json:"likes"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PostReferrerDailyStat จำนวน view รายวันแยกตามแหล่งที่มา (host ของ referrer, "direct" เมื่อไม่มี)
+type PostReferrerDailyStat struct {
+	ID       uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PostID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_post_referrer_daily_stat" json:"post_id"`
+	Date     time.Time `gorm:"type:date;not null;uniqueIndex:idx_post_referrer_daily_stat;index" json:"date"`
+	Referrer string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_post_referrer_daily_stat" json:"referrer"`
+	Views    int64     `gorm:"not null;default:0" json:"views"`
+}
+
+// PostScore คะแนน trending ของโพสต์ในแต่ละช่วงเวลา (24h, 7d, 30d) คำนวณใหม่เป็นรอบโดย background job
+type PostScore struct {
+	PostID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"post_id"`
+	Window    string    `gorm:"column:time_window;type:varchar(10);primaryKey;index:idx_post_score_rank,priority:1" json:"window"`
+	Score     float64   `gorm:"not null;default:0;index:idx_post_score_rank,priority:2,sort:desc" json:"score"`
+	Views     int64     `gorm:"not null;default:0" json:"views"`
+	Likes     int64     `gorm:"not null;default:0" json:"likes"`
+	AIChats   int64     `gorm:"not null;default:0" json:"ai_chats"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// BookmarkFolder โฟลเดอร์ที่ผู้ใช้สร้างเองสำหรับจัดกลุ่ม bookmark (ชื่อไม่ซ้ำต่อผู้ใช้)
+type BookmarkFolder struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bookmark_folder_name" json:"user_id"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_bookmark_folder_name" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Bookmark โพสต์ที่ผู้ใช้บันทึกไว้อ่านภายหลัง หนึ่งแถวต่อ (user, post)
+// ลบแถวจริงเมื่อกดซ้ำ (toggle) จึงไม่ใช้ soft delete
+type Bookmark struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_bookmark" json:"user_id"`
+	PostID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_bookmark;index" json:"post_id"`
+	FolderID  *uint     `gorm:"index" json:"folder_id,omitempty"` // null = ไม่อยู่ในโฟลเดอร์
+	Note      string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Post   Post            `gorm:"foreignKey:PostID;references:ID" json:"-"`
+	User   User            `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	Folder *BookmarkFolder `gorm:"foreignKey:FolderID;references:ID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+// Follow ผู้ใช้ (Follower) ติดตามผู้เขียน (Followee) ลบแถวจริงเมื่อเลิกติดตาม
+type Follow struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	FollowerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_follow" json:"follower_id"`
+	FolloweeID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_follow;index" json:"followee_id"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+
+	Follower User `gorm:"foreignKey:FollowerID;references:ID" json:"-"`
+	Followee User `gorm:"foreignKey:FolloweeID;references:ID" json:"-"`
+}
+
+// ReadingHistory โพสต์ที่ผู้ใช้ที่ login อ่านแล้ว หนึ่งแถวต่อโพสต์ ลบแถวจริงเมื่อผู้ใช้ลบออกจากประวัติ
+// แยกจาก PostView เพื่อให้การลบประวัติไม่กระทบยอด view และสถิติ
+type ReadingHistory struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_reading_history;index:idx_reading_history_recent,priority:1" json:"user_id"`
+	PostID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_reading_history;index" json:"post_id"`
+	LastViewedAt time.Time `gorm:"not null;index:idx_reading_history_recent,priority:2" json:"last_viewed_at"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Post Post `gorm:"foreignKey:PostID;references:ID" json:"-"`
+}
+
+// ReadingHistorySetting ผู้ใช้ที่ยังไม่มีแถวนี้ถือว่าบันทึกประวัติการอ่านตามปกติ
+// แยกจาก User เพราะ User ถูก cache ไว้ใน auth middleware
+type ReadingHistorySetting struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Paused    bool      `gorm:"not null;default:false" json:"paused"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
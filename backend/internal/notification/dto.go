@@ -42,10 +42,12 @@ type NotiListResponse struct {
 	Meta         Meta                 `json:"meta"`
 }
 
+// Meta NextCursor ส่งกลับมาเป็น ?cursor= เพื่อดึงหน้าถัดไป Total เป็น 0 เมื่อขอ count=false
 type Meta struct {
-	Total       int64 `json:"total"`
-	HasNextPage bool  `json:"hasNextPage"`
-	Page        int   `json:"page"`
-	Limit       int   `json:"limit"`
-	TotalPage   int   `json:"totalPage"`
+	Total       int64  `json:"total"`
+	HasNextPage bool   `json:"hasNextPage"`
+	Page        int    `json:"page"`
+	Limit       int    `json:"limit"`
+	TotalPage   int    `json:"totalPage"`
+	NextCursor  string `json:"nextCursor,omitempty"`
 }
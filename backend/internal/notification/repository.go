@@ -3,13 +3,14 @@ package notification
 import (
 	"fmt"
 	"rag-searchbot-backend/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type RepositoryInterface interface {
 	Create(noti *models.Notification) (*models.Notification, error)
-	GetByUser(userID string, limit int, page int) ([]models.Notification, error)
+	GetByUser(userID string, limit int, offset int, after *Position) ([]models.Notification, error)
 	CountByUser(userID string) (int64, error)
 	MarkAsRead(notiID uint) error
 	MarkAllAsRead(userID string) error
@@ -17,6 +18,12 @@ type RepositoryInterface interface {
 	DeleteByID(notiID uint, user *models.User) error
 }
 
+// Position ตำแหน่งของการแจ้งเตือนสุดท้ายในหน้าก่อนหน้า (ถอดมาจาก cursor)
+type Position struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
 type Repository struct {
 	db *gorm.DB
 }
@@ -33,20 +40,18 @@ func (r *Repository) Create(noti *models.Notification) (*models.Notification, er
 	return noti, nil
 }
 
-// Get by user
-func (r *Repository) GetByUser(userID string, limit int, page int) ([]models.Notification, error) {
+// Get by user เรียงจากล่าสุด ต่อจาก after ถ้ามี (keyset) ไม่งั้นใช้ offset แบบเดิม
+func (r *Repository) GetByUser(userID string, limit int, offset int, after *Position) ([]models.Notification, error) {
 	var notifications []models.Notification
-	r.db = r.db.Debug()
 	query := r.db.Where("user_id = ?", userID)
 
-	offset := (page - 1) * limit
-	if offset < 0 {
-		offset = 0
+	if after != nil {
+		query = query.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
+	} else if offset > 0 {
+		query = query.Offset(offset)
 	}
 
-	fmt.Printf("Fetching notifications for user %s with limit %d and offset %d\n", userID, limit, offset)
-
-	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&notifications).Error; err != nil {
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error; err != nil {
 		return nil, err
 	}
 	return notifications, nil
@@ -5,7 +5,9 @@ import (
 	"math"
 	"rag-searchbot-backend/internal/models"
 	"rag-searchbot-backend/internal/ws"
+	"rag-searchbot-backend/pkg/cursor"
 	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/sanitize"
 	"strconv"
 )
 
@@ -23,9 +25,10 @@ func NewService(repo RepositoryInterface, socketManager *ws.Manager) Notificatio
 }
 
 func (s *NotificationService) Notify(user *models.User, title string, event string, data string, link *string) error {
+	// content อาจมีข้อความที่ผู้ใช้พิมพ์มา (เช่น comment) เก็บเป็น plain text เพราะ client แสดงแบบ text
 	noti := &models.Notification{
 		Title:   title,
-		Content: data,
+		Content: sanitize.Notification(data),
 		UserID:  user.ID,
 		Seen:    false,
 		Even:    event,
@@ -48,7 +51,8 @@ func (s *NotificationService) Notify(user *models.User, title string, event stri
 }
 
 // Get notifications for a user
-func (s *NotificationService) GetNotifications(user models.User, limitStr, pageStr string) (*NotiListResponse, error) {
+// cursorStr (จาก Meta.NextCursor) ใช้แทน page ได้ และ count=false จะข้ามการนับ total
+func (s *NotificationService) GetNotifications(user models.User, limitStr, pageStr, cursorStr string, count bool) (*NotiListResponse, error) {
 	// 1. แปลง limit/page string → int
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 {
@@ -60,20 +64,35 @@ func (s *NotificationService) GetNotifications(user models.User, limitStr, pageS
 	}
 	offset := (page - 1) * limit
 
-	// 2. ดึง notifications ตาม limit/offset
-	notifications, err := s.NotiRepo.GetByUser(user.ID.String(), limit, offset)
+	after, err := decodePosition(cursorStr)
 	if err != nil {
 		return nil, err
 	}
+	if after != nil {
+		offset = 0
+		page = 1
+	}
 
-	// 3. นับ total ทั้งหมดของ user นี้
-	total, err := s.NotiRepo.CountByUser(user.ID.String())
+	// 2. ดึง notifications เกินมาหนึ่งรายการเพื่อรู้ว่ามีหน้าถัดไปหรือไม่
+	notifications, err := s.NotiRepo.GetByUser(user.ID.String(), limit+1, offset, after)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. คำนวณ hasNext
-	hasNext := int64(offset+limit) < total
+	// 3. hasNext จากจำนวนที่ดึงได้
+	hasNext := len(notifications) > limit
+	if hasNext {
+		notifications = notifications[:limit]
+	}
+
+	// 4. นับ total ทั้งหมดของ user นี้ (ข้ามได้)
+	var total int64
+	if count {
+		total, err = s.NotiRepo.CountByUser(user.ID.String())
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// 5. สร้าง DTO
 	result := &NotificationRepositoryQuery{
@@ -91,6 +110,10 @@ func (s *NotificationService) GetNotifications(user models.User, limitStr, pageS
 	meta.Page = result.Page
 	meta.Limit = result.Limit
 	meta.TotalPage = int(math.Ceil(float64(result.Total) / float64(limit)))
+	if hasNext {
+		last := notifications[len(notifications)-1]
+		meta.NextCursor = cursor.Encode(last.CreatedAt, strconv.FormatUint(uint64(last.ID), 10))
+	}
 
 	// Convert []models.Notification to []GetNotificationDTO
 	var notificationDTOs []GetNotificationDTO
@@ -112,6 +135,18 @@ func (s *NotificationService) GetNotifications(user models.User, limitStr, pageS
 	}, nil
 }
 
+func decodePosition(value string) (*Position, error) {
+	c, err := cursor.Decode(value)
+	if err != nil || c == nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(c.ID, 10, 64)
+	if err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+	return &Position{CreatedAt: c.Time, ID: uint(id)}, nil
+}
+
 // Mark a notification as read
 func (s *NotificationService) MarkAsRead(notiID uint, user models.User) error {
 	// Validate notification ID
@@ -0,0 +1,37 @@
+package post
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MarkBookmarked ตั้งค่า Bookmarked ของแต่ละโพสต์ตามที่ผู้ใช้ bookmark ไว้ ไม่ทำอะไรเมื่อไม่ได้ login
+// ถ้าดึงข้อมูลไม่สำเร็จจะปล่อยรายการไว้ไม่มี flag (ไม่ทำให้ทั้งหน้าล้ม)
+func (s *PostService) MarkBookmarked(user *models.User, posts []PostSummaryDTO) {
+	if user == nil || len(posts) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	bookmarked, err := s.Repo.GetBookmarkedPostIDs(user.ID.String(), ids)
+	if err != nil {
+		logger.Log.Warn("Failed to load bookmarked posts", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return
+	}
+
+	set := make(map[uuid.UUID]bool, len(bookmarked))
+	for _, id := range bookmarked {
+		set[id] = true
+	}
+	for i := range posts {
+		flag := set[posts[i].ID]
+		posts[i].Bookmarked = &flag
+	}
+}
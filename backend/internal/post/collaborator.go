@@ -0,0 +1,102 @@
+package post
+
+import (
+	"errors"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+
+	"gorm.io/gorm"
+)
+
+// PostAction สิ่งที่ผู้ใช้ต้องการทำกับโพสต์ ใช้ตรวจสิทธิ์ของผู้ร่วมเขียน
+type PostAction string
+
+const (
+	ActionView    PostAction = "view"    // อ่าน draft, revision และ export
+	ActionEdit    PostAction = "edit"    // บันทึกเนื้อหาและ restore revision
+	ActionPublish PostAction = "publish" // publish / unpublish / ตั้งเวลา และเปิดปิด AI chat
+	ActionDelete  PostAction = "delete"  // ลบโพสต์ (เจ้าของเท่านั้น)
+	ActionManage  PostAction = "manage"  // เชิญ / ถอดผู้ร่วมเขียน (เจ้าของเท่านั้น)
+)
+
+// rolePermissions สิทธิ์ของผู้ร่วมเขียนแต่ละ role เจ้าของโพสต์ทำได้ทุกอย่าง
+var rolePermissions = map[models.CollaboratorRole]map[PostAction]bool{
+	models.CollaboratorEditor:   {ActionView: true, ActionEdit: true, ActionPublish: true},
+	models.CollaboratorReviewer: {ActionView: true},
+}
+
+// ValidCollaboratorRole บอกว่า role นี้เชิญได้หรือไม่
+func ValidCollaboratorRole(role models.CollaboratorRole) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Authorize ตรวจว่าผู้ใช้ทำ action กับโพสต์ได้หรือไม่ คืน errs.ErrUnauthorized ถ้าไม่มีสิทธิ์
+func Authorize(repo PostRepositoryInterface, post *models.Post, user *models.User, action PostAction) error {
+	if post.AuthorID == user.ID {
+		return nil
+	}
+
+	role, err := repo.GetCollaboratorRole(post.ID.String(), user.ID.String())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.ErrUnauthorized
+	}
+	if err != nil {
+		return err
+	}
+	if !rolePermissions[role][action] {
+		return errs.ErrUnauthorized
+	}
+	return nil
+}
+
+// lookupPost หาโพสต์จาก short slug ที่ frontend ส่งมา ลองโพสต์ของผู้ใช้เองก่อน แล้วจึงหาโพสต์ที่ร่วมเขียน
+// คืน nil, nil เมื่อไม่พบ
+func (s *PostService) lookupPost(shortSlug string, user *models.User) (*models.Post, error) {
+	post, err := s.Repo.GetByShortSlug(shortSlug + "-" + user.ID.String())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if post != nil {
+		return post, nil
+	}
+
+	post, err = s.Repo.GetCollaboratedPostByShortSlug(shortSlug, user.ID.String())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return post, err
+}
+
+// FindPostForUser ดึงโพสต์จาก short slug และตรวจสิทธิ์ตาม action ทั้งของเจ้าของและผู้ร่วมเขียน
+func (s *PostService) FindPostForUser(shortSlug string, user *models.User, action PostAction) (*models.Post, error) {
+	post, err := s.lookupPost(shortSlug, user)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, errs.ErrPostNotFound
+	}
+	if err := Authorize(s.Repo, post, user, action); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// GetEditablePost โพสต์สำหรับหน้า editor เจ้าของและผู้ร่วมเขียนทุก role เปิดอ่านได้
+func (s *PostService) GetEditablePost(shortSlug string, user *models.User) (*models.Post, error) {
+	return s.FindPostForUser(shortSlug, user, ActionView)
+}
+
+// collaboratorRole role ของผู้ใช้ในโพสต์จาก GetMyPosts ("owner" เมื่อเป็นเจ้าของ)
+func collaboratorRole(post *models.Post, user *models.User) string {
+	if post.AuthorID == user.ID {
+		return "owner"
+	}
+	for _, c := range post.Collaborators {
+		if c.UserID == user.ID {
+			return string(c.Role)
+		}
+	}
+	return ""
+}
@@ -1,7 +1,11 @@
 package post
 
 import (
+	"encoding/json"
 	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/sanitize"
+	"rag-searchbot-backend/pkg/tiptap"
 	"strings"
 	"time"
 
@@ -14,6 +18,7 @@ import (
 **/
 
 type PostSummaryDTO struct {
+	ID          uuid.UUID  `json:"id"`
 	Slug        string     `json:"slug"`
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
@@ -29,8 +34,10 @@ type PostSummaryDTO struct {
 		UserName string `json:"username"`
 		Avatar   string `json:"avatar"`
 	} `json:"author"`
-	Tags       []TagDTO      `json:"tags,omitempty"`
-	Categories []CategoryDTO `json:"categories,omitempty"`
+	Tags       []TagDTO            `json:"tags,omitempty"`
+	Categories []CategoryDTO       `json:"categories,omitempty"`
+	Highlight  *SearchHighlightDTO `json:"highlight,omitempty"`  // มีเฉพาะผลการค้นหา
+	Bookmarked *bool               `json:"bookmarked,omitempty"` // มีเมื่อรู้ตัวผู้ใช้ (ดู MarkBookmarked)
 }
 
 /**
@@ -42,12 +49,15 @@ type PostListResponse struct {
 	Meta  Meta             `json:"meta"`
 }
 
+// Meta ข้อมูลการแบ่งหน้า NextCursor ส่งกลับมาเป็น ?cursor= เพื่อดึงหน้าถัดไปแทน page
+// Total / TotalPage เป็น 0 เมื่อ client ขอ count=false
 type Meta struct {
-	Total       int64 `json:"total"`
-	HasNextPage bool  `json:"hasNextPage"`
-	Page        int   `json:"page"`
-	Limit       int   `json:"limit"`
-	TotalPage   int   `json:"totalPage"`
+	Total       int64  `json:"total"`
+	HasNextPage bool   `json:"hasNextPage"`
+	Page        int    `json:"page"`
+	Limit       int    `json:"limit"`
+	TotalPage   int    `json:"totalPage"`
+	NextCursor  string `json:"nextCursor,omitempty"`
 }
 
 /**
@@ -57,6 +67,7 @@ type Meta struct {
 
 func MapPostToSummaryDTO(post models.Post) PostSummaryDTO {
 	dto := PostSummaryDTO{
+		ID:          post.ID,
 		Slug:        post.Slug,
 		Title:       post.Title,
 		Description: post.Description,
@@ -169,6 +180,56 @@ type CreatePostRequest struct {
 	ShortSlug string               `json:"short_slug" binding:"required"`
 	Content   PostContentStructure `json:"content" binding:"required"`
 	Title     string               `json:"title" binding:"required"`
+	Version   int                  `json:"version"` // version ที่ editor โหลดมาแก้ ต้องส่งเมื่อบันทึกทับโพสต์เดิม
+}
+
+// CreatePostResponseDTO ผลการบันทึก editor ต้องใช้ Version นี้ในการบันทึกครั้งถัดไป
+type CreatePostResponseDTO struct {
+	PostID  string `json:"post_id"`
+	Version int    `json:"version"`
+}
+
+// VersionConflictDTO เนื้อหาล่าสุดบน server ส่งให้ editor ใช้ merge เมื่อบันทึกจาก version เก่า
+type VersionConflictDTO struct {
+	Version int             `json:"version"`
+	Title   string          `json:"title"`
+	Content json.RawMessage `json:"content"`
+}
+
+// VersionConflictError คืนจาก CreatePost เมื่อ version ไม่ตรง (errors.Is กับ errs.ErrPostVersionConflict ได้)
+type VersionConflictError struct {
+	Current VersionConflictDTO
+}
+
+func (e *VersionConflictError) Error() string {
+	return errs.ErrPostVersionConflict.Error()
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return errs.ErrPostVersionConflict
+}
+
+func newVersionConflict(post *models.Post) *VersionConflictError {
+	content := json.RawMessage(post.Content)
+	if !json.Valid(content) {
+		content = json.RawMessage("null")
+	}
+	return &VersionConflictError{Current: VersionConflictDTO{
+		Version: post.Version,
+		Title:   post.Title,
+		Content: content,
+	}}
+}
+
+// ImportMarkdownResponseDTO ผลการ import Markdown เป็น draft
+type ImportMarkdownResponseDTO struct {
+	PostID        string   `json:"post_id"`
+	ShortSlug     string   `json:"short_slug"`
+	Slug          string   `json:"slug"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Tags          []string `json:"tags"`
+	MissingImages []string `json:"missing_images"`
 }
 
 type MyPostsDTO struct {
@@ -179,6 +240,8 @@ type MyPostsDTO struct {
 	Description string     `json:"description"`
 	Thumbnail   string     `json:"thumbnail,omitempty"`
 	PublishedAt *time.Time `json:"published_at,omitempty"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
 	Published   bool       `json:"published"`
 	Status      string     `json:"status"`
 	Views       int        `json:"views"`
@@ -187,6 +250,10 @@ type MyPostsDTO struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	AIChatOpen  bool       `json:"ai_chat_open"`
 	AIReady     bool       `json:"ai_ready"`
+	Visibility  string     `json:"visibility"`
+	Role        string     `json:"role"`            // owner, editor หรือ reviewer
+	Owner       string     `json:"owner,omitempty"` // username ของเจ้าของ เมื่อเป็นโพสต์ที่ร่วมเขียน
+	Bookmarks   int64      `json:"bookmarks"`       // จำนวนผู้อ่านที่ bookmark โพสต์นี้
 }
 
 func MapMyPostToSummaryDTO(post models.Post) MyPostsDTO {
@@ -207,6 +274,8 @@ func MapMyPostToSummaryDTO(post models.Post) MyPostsDTO {
 		Description: post.Description,
 		Thumbnail:   post.Thumbnail,
 		PublishedAt: post.PublishedAt,
+		PublishAt:   post.PublishAt,
+		UnpublishAt: post.UnpublishAt,
 		Status:      string(post.Status),
 		Views:       post.Views,
 		Likes:       post.Likes,
@@ -214,6 +283,7 @@ func MapMyPostToSummaryDTO(post models.Post) MyPostsDTO {
 		CreatedAt:   post.CreatedAt,
 		AIChatOpen:  post.AIChatOpen,
 		AIReady:     post.AIReady,
+		Visibility:  string(post.Visibility),
 	}
 
 	return dto
@@ -227,7 +297,36 @@ type PublishPostRequestDTO struct {
 	Title       string   `json:"title" binding:"required"`
 	Description string   `json:"description"`
 	Thumbnail   string   `json:"thumbnail"`
-	HTMLContent *string  `json:"html_content"`
+	// Deprecated: HTML ถูก render จาก content ฝั่ง server ค่านี้ไม่ถูกใช้แล้ว
+	HTMLContent *string `json:"html_content"`
+
+	// ตั้งเวลา publish / unpublish ล่วงหน้า (ถ้าไม่ส่งหรือเป็นเวลาในอดีต จะ publish ทันที)
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// PublishPostResponseDTO ข้อมูลที่ publish พร้อมรายการ HTML ที่ถูกตัดออกโดย sanitize policy
+type PublishPostResponseDTO struct {
+	PublishPostRequestDTO
+	RejectedElements []sanitize.Rejection `json:"rejected_elements"`
+}
+
+// IsScheduled บอกว่า request นี้ต้องตั้งเวลา publish แทนการ publish ทันที
+func (p *PublishPostRequestDTO) IsScheduled(now time.Time) bool {
+	return p.PublishAt != nil && p.PublishAt.After(now)
+}
+
+// ScheduleRequestDTO สำหรับเลื่อนเวลา publish / unpublish ของโพสต์ที่ตั้งเวลาไว้แล้ว
+type ScheduleRequestDTO struct {
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// ScheduleResponseDTO สถานะการตั้งเวลาปัจจุบันของโพสต์
+type ScheduleResponseDTO struct {
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
 }
 
 // PostViewRequest สำหรับ API นับ view
@@ -235,6 +334,7 @@ type PostViewRequest struct {
 	Fingerprint string `json:"fingerprint" binding:"required"`
 	IPAddress   string `json:"ip_address,omitempty"`
 	UserAgent   string `json:"user_agent,omitempty"`
+	Referrer    string `json:"referrer,omitempty"` // document.referrer ของหน้าโพสต์ ถ้าไม่ส่งจะใช้ header Referer
 }
 
 // PostViewResponse สำหรับ response ของ view API
@@ -243,3 +343,68 @@ type PostViewResponse struct {
 	Message string `json:"message"`
 	Views   int    `json:"views"`
 }
+
+// PostRevisionDTO ข้อมูล revision สำหรับแสดงในรายการ (ไม่รวมเนื้อหา)
+type PostRevisionDTO struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct {
+		UserName string `json:"username"`
+		Avatar   string `json:"avatar"`
+	} `json:"user"`
+}
+
+// PostRevisionDetailDTO ข้อมูล revision พร้อมเนื้อหา
+type PostRevisionDetailDTO struct {
+	PostRevisionDTO
+	Content     string  `json:"content"`
+	HTMLContent *string `json:"html_content,omitempty"`
+}
+
+type PostRevisionListResponse struct {
+	Revisions []PostRevisionDTO `json:"revisions"`
+}
+
+// PostRevisionDiffResponse ผลต่างระหว่าง revision สองตัว (from/to เป็น revision id หรือ "current")
+type PostRevisionDiffResponse struct {
+	From string             `json:"from"`
+	To   string             `json:"to"`
+	Diff *tiptap.DiffResult `json:"diff"`
+}
+
+func MapPostRevisionToDTO(revision models.PostRevision) PostRevisionDTO {
+	dto := PostRevisionDTO{
+		ID:        revision.ID,
+		Title:     revision.Title,
+		Reason:    string(revision.Reason),
+		CreatedAt: revision.CreatedAt,
+	}
+	dto.User.UserName = revision.User.UserName
+	dto.User.Avatar = revision.User.Avatar
+	return dto
+}
+
+// UpdateVisibilityRequestDTO เปลี่ยน visibility ของโพสต์
+// protected: ส่ง password เพื่อตั้งรหัสเอง หรือเว้นว่างเพื่อใช้ key เดิม / ให้ระบบสร้าง share key
+type UpdateVisibilityRequestDTO struct {
+	Visibility    models.PostVisibility `json:"visibility" binding:"required"`
+	Password      string                `json:"password"`
+	RegenerateKey bool                  `json:"regenerate_key"` // สร้าง share key ใหม่แทน key เดิม
+}
+
+type VisibilityResponseDTO struct {
+	Visibility models.PostVisibility `json:"visibility"`
+	HasKey     bool                  `json:"has_key"`
+	ShareKey   string                `json:"share_key,omitempty"` // แสดงครั้งเดียวตอนสร้าง เก็บไว้เฉพาะ hash
+}
+
+type UnlockPostRequestDTO struct {
+	Key string `json:"key" binding:"required"`
+}
+
+type UnlockPostResponseDTO struct {
+	PostID    string     `json:"post_id"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,263 @@
+package post
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/sanitize"
+	"rag-searchbot-backend/pkg/tiptap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportFormatMarkdown = "md"
+	ExportFormatHTML     = "html"
+	ExportFormatJSON     = "json"
+)
+
+var exportContentTypes = map[string]string{
+	ExportFormatMarkdown: "text/markdown; charset=utf-8",
+	ExportFormatHTML:     "text/html; charset=utf-8",
+	ExportFormatJSON:     "application/json; charset=utf-8",
+}
+
+// ExportedFile คือไฟล์ที่ส่งกลับให้ดาวน์โหลด
+type ExportedFile struct {
+	FileName    string
+	ContentType string
+	Body        []byte
+}
+
+// PostExportDTO รูปแบบ export แบบ json: metadata ของโพสต์พร้อม TipTap JSON เดิม
+type PostExportDTO struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Slug        string          `json:"slug,omitempty"`
+	Tags        []string        `json:"tags"`
+	Status      string          `json:"status"`
+	PublishedAt *time.Time      `json:"published_at,omitempty"`
+	Content     json.RawMessage `json:"content"`
+}
+
+func validateExportFormat(format string) error {
+	if _, ok := exportContentTypes[format]; !ok {
+		return fmt.Errorf("%w: unsupported export format %q (md, html or json)", errs.ErrInvalidPayload, format)
+	}
+	return nil
+}
+
+// exportSlug slug ของ draft ที่ยังไม่เคยตั้งจะเท่ากับ short slug ภายใน จึงไม่ export ออกไป
+func exportSlug(post *models.Post) string {
+	if post.Slug == post.ShortSlug {
+		return ""
+	}
+	return post.Slug
+}
+
+func exportTags(post *models.Post) []string {
+	tags := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		tags = append(tags, tag.Name)
+	}
+	return tags
+}
+
+func exportDocument(post *models.Post) (*tiptap.Node, error) {
+	if strings.TrimSpace(post.Content) == "" {
+		return &tiptap.Node{Type: "doc"}, nil
+	}
+	doc, err := tiptap.ParseDocument(post.Content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: stored content is not valid TipTap JSON: %v", errs.ErrInvalidPayload, err)
+	}
+	return doc, nil
+}
+
+// RenderPostMarkdown แปลงโพสต์เป็น Markdown พร้อม front-matter ที่ ImportMarkdown อ่านกลับได้
+func RenderPostMarkdown(post *models.Post) (string, error) {
+	doc, err := exportDocument(post)
+	if err != nil {
+		return "", err
+	}
+	return tiptap.RenderMarkdown(&tiptap.MarkdownDocument{
+		FrontMatter: tiptap.FrontMatter{
+			Title:       post.Title,
+			Description: post.Description,
+			Slug:        exportSlug(post),
+			Tags:        exportTags(post),
+		},
+		Doc: *doc,
+	})
+}
+
+// RenderPostHTML แปลงโพสต์เป็นหน้า HTML ที่เปิดได้เอง
+func RenderPostHTML(post *models.Post) (string, error) {
+	doc, err := exportDocument(post)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>" + html.EscapeString(post.Title) + "</title>\n")
+	if post.Description != "" {
+		b.WriteString(`<meta name="description" content="` + html.EscapeString(post.Description) + "\">\n")
+	}
+	if tags := exportTags(post); len(tags) > 0 {
+		b.WriteString(`<meta name="keywords" content="` + html.EscapeString(strings.Join(tags, ", ")) + "\">\n")
+	}
+	b.WriteString("</head>\n<body>\n<article>\n")
+	b.WriteString("<h1>" + html.EscapeString(post.Title) + "</h1>\n")
+	b.WriteString(sanitize.Content(tiptap.RenderHTML(*doc)).HTML)
+	b.WriteString("\n</article>\n</body>\n</html>\n")
+	return b.String(), nil
+}
+
+func renderPostJSON(post *models.Post) ([]byte, error) {
+	content := json.RawMessage(`{"type":"doc"}`)
+	if strings.TrimSpace(post.Content) != "" {
+		if !json.Valid([]byte(post.Content)) {
+			return nil, fmt.Errorf("%w: stored content is not valid JSON", errs.ErrInvalidPayload)
+		}
+		content = json.RawMessage(post.Content)
+	}
+	return json.MarshalIndent(PostExportDTO{
+		Title:       post.Title,
+		Description: post.Description,
+		Slug:        exportSlug(post),
+		Tags:        exportTags(post),
+		Status:      string(post.Status),
+		PublishedAt: post.PublishedAt,
+		Content:     content,
+	}, "", "  ")
+}
+
+// exportFileBase ชื่อไฟล์จาก slug (หรือ short slug ของ draft) ตัดตัวอักษรที่ใช้เป็นชื่อไฟล์ไม่ได้
+func exportFileBase(post *models.Post) string {
+	name := exportSlug(post)
+	if name == "" {
+		name = strings.SplitN(post.ShortSlug, "-", 2)[0]
+	}
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return '-'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = post.ID.String()
+	}
+	return name
+}
+
+func exportPost(post *models.Post, format string) (*ExportedFile, error) {
+	var body []byte
+	switch format {
+	case ExportFormatMarkdown:
+		md, err := RenderPostMarkdown(post)
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(md)
+	case ExportFormatHTML:
+		page, err := RenderPostHTML(post)
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(page)
+	case ExportFormatJSON:
+		data, err := renderPostJSON(post)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	return &ExportedFile{
+		FileName:    exportFileBase(post) + "." + format,
+		ContentType: exportContentTypes[format],
+		Body:        body,
+	}, nil
+}
+
+/**
+* ExportPost exports one of the user's posts.
+* @param shortSlug string - The short slug of the post
+* @param format string - md (Markdown with front-matter), html (standalone page) or json (TipTap JSON with metadata)
+* @param user *models.User - The author
+* @return *ExportedFile - The file to download
+* @return error - An error if occurred
+**/
+
+func (s *PostService) ExportPost(shortSlug, format string, user *models.User) (*ExportedFile, error) {
+	if err := validateExportFormat(format); err != nil {
+		return nil, err
+	}
+
+	post, err := s.FindPostForUser(shortSlug, user, ActionView)
+	if err != nil {
+		return nil, err
+	}
+	return exportPost(post, format)
+}
+
+/**
+* ExportAllPosts exports every post of the user (drafts included) as a ZIP archive.
+* @param format string - md, html or json, applied to every file in the archive
+* @param user *models.User - The author
+* @return *ExportedFile - The ZIP archive
+* @return error - An error if occurred
+* File names come from the post slug; duplicates get a numeric suffix.
+**/
+
+func (s *PostService) ExportAllPosts(format string, user *models.User) (*ExportedFile, error) {
+	if err := validateExportFormat(format); err != nil {
+		return nil, err
+	}
+
+	posts, err := s.Repo.GetMyPosts(user)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	used := make(map[string]int, len(posts))
+
+	for _, post := range posts {
+		file, err := exportPost(post, format)
+		if err != nil {
+			return nil, fmt.Errorf("export post %s: %w", post.ID, err)
+		}
+
+		name := file.FileName
+		if n := used[name]; n > 0 {
+			name = strings.TrimSuffix(name, "."+format) + "-" + strconv.Itoa(n+1) + "." + format
+		}
+		used[file.FileName]++
+
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: post.UpdatedAt})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(file.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return &ExportedFile{
+		FileName:    "posts-" + time.Now().Format("20060102") + ".zip",
+		ContentType: "application/zip",
+		Body:        buf.Bytes(),
+	}, nil
+}
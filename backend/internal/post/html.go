@@ -0,0 +1,28 @@
+package post
+
+import (
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/sanitize"
+	"rag-searchbot-backend/pkg/tiptap"
+	"strings"
+)
+
+// renderHTMLContent สร้าง HTMLContent จาก TipTap JSON ของโพสต์ แทนการเชื่อ HTML ที่ client ส่งมา
+// ทั้ง read time, งาน moderation และ feed ใช้ค่านี้ จึงต้อง render ใหม่ทุกครั้งก่อน publish
+// HTML ที่ได้ผ่าน sanitize policy เสมอ และคืนรายการที่ถูกตัดออก (เช่น ลิงก์ javascript: หรือรูปจาก host อื่น) ให้ผู้เขียน
+func renderHTMLContent(post *models.Post) ([]sanitize.Rejection, error) {
+	doc := &tiptap.Node{Type: "doc"}
+	if strings.TrimSpace(post.Content) != "" {
+		parsed, err := tiptap.ParseDocument(post.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errs.ErrInvalidContent, err)
+		}
+		doc = parsed
+	}
+
+	result := sanitize.Content(tiptap.RenderHTML(*doc))
+	post.HTMLContent = &result.HTML
+	return result.Rejected, nil
+}
@@ -0,0 +1,265 @@
+package post
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/taxonomy"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"rag-searchbot-backend/pkg/tiptap"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	MaxMarkdownImportBytes = 1 << 20 // ขนาด Markdown สูงสุดที่ import ได้
+	shortSlugLength        = 8
+	shortSlugAlphabet      = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// newShortSlug สุ่ม short slug แบบเดียวกับที่ frontend สร้าง (ห้ามมี "-" เพราะใช้ต่อกับ user id)
+func newShortSlug() (string, error) {
+	b := make([]byte, shortSlugLength)
+	max := big.NewInt(int64(len(shortSlugAlphabet)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = shortSlugAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// isLocalImage รูปที่อ้างถึงไฟล์ข้างเคียง (ไม่ใช่ URL) ต้องถูกอัปโหลดพร้อมกับ Markdown
+func isLocalImage(src string) bool {
+	return src != "" && !strings.Contains(src, "://") && !strings.HasPrefix(src, "//") && !strings.HasPrefix(src, "data:")
+}
+
+// imageFileName หาชื่อไฟล์จาก src เช่น "./images/My%20Shot.png?raw=1" -> "My Shot.png"
+func imageFileName(src string) string {
+	if i := strings.IndexAny(src, "?#"); i >= 0 {
+		src = src[:i]
+	}
+	if unescaped, err := url.PathUnescape(src); err == nil {
+		src = unescaped
+	}
+	return path.Base(strings.ReplaceAll(src, "\\", "/"))
+}
+
+// takeLeadingTitle ใช้ heading แรกของเอกสารเป็นชื่อโพสต์ และตัดออกจากเนื้อหาเพื่อไม่ให้ชื่อซ้ำ
+func takeLeadingTitle(doc *tiptap.Node) string {
+	if len(doc.Content) == 0 || doc.Content[0].Type != "heading" {
+		return ""
+	}
+	title := strings.TrimSpace(doc.Content[0].PlainText())
+	if title != "" {
+		doc.Content = doc.Content[1:]
+		if len(doc.Content) == 0 {
+			doc.Content = []tiptap.Node{{Type: "paragraph"}}
+		}
+	}
+	return title
+}
+
+func (s *PostService) uniqueShortSlug(user *models.User) (string, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		shortSlug, err := newShortSlug()
+		if err != nil {
+			return "", err
+		}
+		existing, err := s.Repo.GetByShortSlug(shortSlug + "-" + user.ID.String())
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+		if existing == nil {
+			return shortSlug, nil
+		}
+	}
+	return "", errors.New("failed to generate a unique short slug")
+}
+
+// importSlug ใช้ slug จาก front-matter ถ้ามี ถ้าชนกับโพสต์อื่นจะต่อท้ายแบบเดียวกับตอน publish
+func (s *PostService) importSlug(slug, fallback string) (string, error) {
+	slug = strings.Join(strings.Fields(slug), "-")
+	if slug == "" {
+		return fallback, nil
+	}
+
+	existing, err := s.Repo.GetBySlug(slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if existing != nil {
+		slug = slug + "-" + uuid.New().String()[:8]
+	}
+	return slug, nil
+}
+
+// uploadLocalImages อัปโหลดรูป local ที่แนบมาผ่าน MediaService และเปลี่ยน src เป็น URL ที่ได้
+// รูปยังไม่ผูกกับโพสต์ (post_id ว่าง) จนกว่าจะสร้าง draft สำเร็จ
+// คืนชื่อไฟล์ที่ไม่ได้แนบมาหรืออัปโหลดไม่สำเร็จ
+func (s *PostService) uploadLocalImages(node *tiptap.Node, images map[string]*multipart.FileHeader, user *models.User, uploaded map[string]*models.ImageUpload) []string {
+	var missing []string
+
+	if node.Type == "image" {
+		src := node.AttrString("src")
+		if isLocalImage(src) {
+			name := imageFileName(src)
+			image, ok := uploaded[name]
+			if !ok {
+				if file, found := images[name]; found {
+					created, err := s.MediaService.CreateMedia(file, user, nil)
+					if err != nil {
+						logger.Log.Warn("Failed to upload imported image",
+							zap.String("user_id", user.ID.String()),
+							zap.String("file", name),
+							zap.Error(err))
+					} else {
+						image = created
+					}
+				}
+				uploaded[name] = image
+			}
+
+			if image != nil {
+				node.Attrs["src"] = image.ImageURL
+			} else {
+				missing = append(missing, src)
+			}
+		}
+	}
+
+	for i := range node.Content {
+		missing = append(missing, s.uploadLocalImages(&node.Content[i], images, user, uploaded)...)
+	}
+	return missing
+}
+
+// releaseImportedImages ปลดรูปที่อัปโหลดไว้เมื่อสร้าง draft ไม่สำเร็จ ให้ถูกเก็บกวาดแบบรูปที่ไม่ได้ใช้
+func (s *PostService) releaseImportedImages(uploaded map[string]*models.ImageUpload) {
+	for _, image := range uploaded {
+		if image == nil {
+			continue
+		}
+		image.IsUsed = false
+		if err := s.MediaService.UpdateImageUsage(image); err != nil {
+			logger.Log.Warn("Failed to release imported image", zap.String("image_url", image.ImageURL), zap.Error(err))
+		}
+	}
+}
+
+/**
+* ImportMarkdown creates a draft post from a Markdown document.
+* @param markdown string - CommonMark + GFM source, optionally starting with YAML front-matter
+* @param images map[string]*multipart.FileHeader - Local images referenced by the document, keyed by file name
+* @param user *models.User - The author of the draft
+* @return *ImportMarkdownResponseDTO - The created draft and any images that could not be resolved
+* @return error - An error if occurred
+* Front-matter title, description, slug and tags populate the post fields; without a title the
+* leading heading is used. Local images are uploaded first and rewritten to their URLs, then the
+* draft, its tags and the image links are created in one transaction and the first revision is recorded.
+**/
+
+func (s *PostService) ImportMarkdown(markdown string, images map[string]*multipart.FileHeader, user *models.User) (*ImportMarkdownResponseDTO, error) {
+	if len(markdown) > MaxMarkdownImportBytes {
+		return nil, fmt.Errorf("%w: markdown is larger than %d bytes", errs.ErrInvalidPayload, MaxMarkdownImportBytes)
+	}
+
+	parsed, err := tiptap.ParseMarkdown(markdown)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrInvalidPayload, err)
+	}
+	meta := parsed.FrontMatter
+	doc := parsed.Doc
+
+	title := meta.Title
+	if title == "" {
+		title = takeLeadingTitle(&doc)
+	}
+	if title == "" {
+		return nil, fmt.Errorf("%w: markdown needs a title in front matter or a leading heading", errs.ErrInvalidPayload)
+	}
+
+	tags, err := taxonomy.NormalizeTagNames(meta.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	shortSlug, err := s.uniqueShortSlug(user)
+	if err != nil {
+		return nil, err
+	}
+	fullShortSlug := shortSlug + "-" + user.ID.String()
+
+	slug, err := s.importSlug(meta.Slug, fullShortSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. อัปโหลดรูป local แล้วแทน src ด้วย URL จริง
+	uploaded := make(map[string]*models.ImageUpload)
+	missing := s.uploadLocalImages(&doc, images, user, uploaded)
+
+	// 2. แปลงเป็น PostContentStructure แบบเดียวกับที่ editor บันทึก
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var content PostContentStructure
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, err
+	}
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. สร้าง draft พร้อมเนื้อหา tag และผูกรูปใน transaction เดียว ถ้าพลาดจะไม่มี draft ว่างค้างอยู่
+	draft := &models.Post{
+		Slug:        slug,
+		ShortSlug:   fullShortSlug,
+		Title:       title,
+		Description: meta.Description,
+		Content:     string(contentJSON),
+		AuthorID:    user.ID,
+		Status:      models.PostDraft,
+		Version:     1,
+	}
+	imageIDs := make([]uuid.UUID, 0, len(uploaded))
+	for _, image := range uploaded {
+		if image != nil {
+			imageIDs = append(imageIDs, image.ID)
+		}
+	}
+	if err := s.Repo.CreateImportedPost(draft, tags, imageIDs); err != nil {
+		s.releaseImportedImages(uploaded)
+		return nil, err
+	}
+
+	// 4. revision แรกของ draft
+	s.recordRevision(draft, user.ID, models.RevisionSave)
+
+	if missing == nil {
+		missing = []string{}
+	}
+	return &ImportMarkdownResponseDTO{
+		PostID:        draft.ID.String(),
+		ShortSlug:     shortSlug,
+		Slug:          slug,
+		Title:         title,
+		Description:   meta.Description,
+		Tags:          tags,
+		MissingImages: missing,
+	}, nil
+}
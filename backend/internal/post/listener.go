@@ -0,0 +1,27 @@
+package post
+
+import "rag-searchbot-backend/internal/models"
+
+// PublicationListener รับแจ้งเมื่อโพสต์ขึ้นหน้า public หรือหายไปจากหน้า public
+// (unpublish, ถูกตั้งเวลา publish ใหม่ หรือถูกลบ) เช่น ใช้ล้าง cache ที่อ้างถึงโพสต์
+type PublicationListener interface {
+	OnPostPublished(post *models.Post)
+	OnPostUnpublished(post *models.Post)
+}
+
+// AddPublicationListener ลงทะเบียน listener ให้ PostService เรียกหลังเปลี่ยนสถานะสำเร็จ
+func (s *PostService) AddPublicationListener(listener PublicationListener) {
+	s.Listeners = append(s.Listeners, listener)
+}
+
+func (s *PostService) notifyPublished(post *models.Post) {
+	for _, listener := range s.Listeners {
+		listener.OnPostPublished(post)
+	}
+}
+
+func (s *PostService) notifyUnpublished(post *models.Post) {
+	for _, listener := range s.Listeners {
+		listener.OnPostUnpublished(post)
+	}
+}
@@ -0,0 +1,79 @@
+package post
+
+import (
+	"rag-searchbot-backend/pkg/cursor"
+	"rag-searchbot-backend/pkg/errs"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const DefaultListLimit = 10
+
+// PostListOptions การแบ่งหน้าที่ client ขอมา ใช้ได้ทั้ง page/limit แบบเดิมและ cursor
+type PostListOptions struct {
+	Page   int
+	Limit  int
+	Cursor string // ถ้ามี page จะถูกข้าม
+	Count  bool   // false = ไม่นับ total (Meta.Total / TotalPage เป็น 0)
+}
+
+// ParsePostListOptions อ่าน ?page=&limit=&cursor=&count= ค่าเริ่มต้นนับ total เหมือนเดิม
+// client ที่เลื่อนด้วย cursor อย่างเดียวส่ง count=false เพื่อข้าม COUNT(*)
+func ParsePostListOptions(c *gin.Context) PostListOptions {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(DefaultListLimit)))
+	if err != nil || limit <= 0 {
+		limit = DefaultListLimit
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	count, err := strconv.ParseBool(c.DefaultQuery("count", "true"))
+	if err != nil {
+		count = true
+	}
+
+	return PostListOptions{
+		Page:   page,
+		Limit:  limit,
+		Cursor: c.Query("cursor"),
+		Count:  count,
+	}
+}
+
+// listQuery แปลงตัวเลือกของ client เป็น PostListQuery ของ repository
+func (o PostListOptions) listQuery() (PostListQuery, error) {
+	query := PostListQuery{
+		Limit:  o.Limit,
+		Offset: (o.Page - 1) * o.Limit,
+		Count:  o.Count,
+	}
+
+	c, err := cursor.Decode(o.Cursor)
+	if err != nil || c == nil {
+		return query, err
+	}
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return query, errs.ErrInvalidCursor
+	}
+	query.After = &PostPosition{PublishedAt: c.Time, ID: id}
+	query.Offset = 0
+	return query, nil
+}
+
+// nextPostCursor cursor ของหน้าถัดไปจากโพสต์สุดท้ายของหน้านี้ ("" = หน้าสุดท้าย)
+func nextPostCursor(result *PostRepositoryQuery) string {
+	if !result.HasNext || len(result.Posts) == 0 {
+		return ""
+	}
+	last := result.Posts[len(result.Posts)-1]
+	if last.PublishedAt == nil {
+		return ""
+	}
+	return cursor.Encode(*last.PublishedAt, last.ID.String())
+}
@@ -1,38 +1,75 @@
 package post
 
 import (
+	"errors"
+	"fmt"
 	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/tiptap"
+	"rag-searchbot-backend/pkg/utils"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Constants for popular posts configuration
 const (
 	MinPopularPostViews = 0 // Minimum view count required for a post to be considered popular
+
+	RevisionRetentionKeepLast = 50 // Number of recent revisions kept per post (published snapshots are always kept)
+
+	TrashRetention = 30 * 24 * time.Hour // Deleted posts stay restorable in the trash for this long before being purged
 )
 
 type PostRepositoryInterface interface {
 	Create(post *models.Post) (string, error)
 	GetAll(limit, offset int, search string) (*PostRepositoryQuery, error)
+	ListPublished(query PostListQuery) (*PostRepositoryQuery, error)
 	GetByID(id string) (*models.Post, error)
 	GetBySlug(slug string) (*models.Post, error)
 	Update(post *models.Post) error
 	GetMyPosts(user *models.User) ([]*models.Post, error)
 	GetByShortSlug(shortSlug string) (*models.Post, error)
 	GetPublicPostBySlugAndUsername(slug string, username string) (*models.Post, error)
-	GetPublishedPostsByAuthor(username string, page, limit int) ([]models.Post, int64, error)
+	GetPublishedPostsByAuthor(username string, query PostListQuery) (*PostRepositoryQuery, error)
 	PublishPost(post *models.Post) error
 	UnpublishPost(post *models.Post) error
 	DeletePost(post *models.Post) error
+	GetTrashedPosts(authorID string) ([]models.Post, error)
+	GetTrashedPostByID(id string) (*models.Post, error)
+	RestorePost(post *models.Post) error
+	PurgeDeletedPosts(deletedBefore time.Time) (int64, error)
 	GetEmbeddingByPostID(postID string) ([]models.Embedding, error)
 	InsertEmbedding(post *models.Post, embedding models.Embedding) error
 	UpdateEmbedding(post *models.Post, embedding models.Embedding) error
 	DeleteEmbeddingsByPostID(postID string) error
 	BulkInsertEmbeddings(post *models.Post, embeddings []models.Embedding) error
-	RecordPostView(postID string, userID *string, fingerprint string, ipAddress, userAgent string) error
+	RecordPostView(postID string, userID *string, fingerprint string, ipAddress, userAgent, referrer string) error
 	GetPostViews(postID string) (int, error)
 	GetPopularPosts(limit int) ([]models.Post, error)
+	RecomputeTrendingScores(window string, since time.Time) (int64, error)
+	GetTrendingPosts(window string, limit int) ([]models.Post, error)
+	CreateRevision(revision *models.PostRevision) error
+	GetLatestRevision(postID string) (*models.PostRevision, error)
+	GetRevisionsByPostID(postID string) ([]models.PostRevision, error)
+	GetRevisionByID(postID string, revisionID uint) (*models.PostRevision, error)
+	PruneRevisions(keepLast int) (int64, error)
+	UpdateSchedule(postID string, publishAt, unpublishAt *time.Time) error
+	UpdateVisibility(postID string, visibility models.PostVisibility, keyHash string) error
+	ReplacePostTags(post *models.Post, names []string) error
+	CreateImportedPost(post *models.Post, tags []string, imageIDs []uuid.UUID) error
+	ReplacePostCategories(post *models.Post, names []string) error
+	GetPublishedPostsByTag(name string, limit, offset int) (*PostRepositoryQuery, error)
+	GetPublishedPostsByCategory(name string, limit, offset int) (*PostRepositoryQuery, error)
+	GetPublishedPostsByIDs(ids []uuid.UUID) ([]models.Post, error)
+	GetFeedPosts(query FeedQuery) ([]models.Post, error)
+	GetCollaboratorRole(postID string, userID string) (models.CollaboratorRole, error)
+	GetCollaboratedPostByShortSlug(shortSlug string, userID string) (*models.Post, error)
+	GetBookmarkedPostIDs(userID string, postIDs []uuid.UUID) ([]uuid.UUID, error)
+	CountBookmarks(postIDs []uuid.UUID) (map[uuid.UUID]int64, error)
 }
 
 type PostRepository struct {
@@ -44,6 +81,7 @@ func NewPostRepository(db *gorm.DB) PostRepositoryInterface {
 }
 
 func (r *PostRepository) Create(post *models.Post) (string, error) {
+	post.SearchText = tiptap.ExtractTextFromTiptap(post.Content)
 	err := r.DB.Create(post).Error
 	if err != nil {
 		return "", err
@@ -61,68 +99,204 @@ type PostRepositoryQuery struct {
 	Posts   []models.Post `json:"posts"`
 }
 
-func (r *PostRepository) GetAll(limit, offset int, search string) (*PostRepositoryQuery, error) {
-	var posts []models.Post
+// PostListQuery การแบ่งหน้าของรายการโพสต์ที่ publish แล้ว ถ้ามี After จะใช้ keyset แทน Offset
+type PostListQuery struct {
+	Limit  int
+	Offset int
+	Search string
+	After  *PostPosition
+	Count  bool // นับ total ด้วย COUNT(*) (ข้ามได้เมื่อ client ใช้ cursor อย่างเดียว)
+}
 
-	query := r.DB.
-		Select("id", "slug", "title", "description", "thumbnail",
-			"published", "published_at", "author_id", "likes",
-			"views", "read_time", "ai_chat_open", "ai_ready").
+// PostPosition ตำแหน่งของโพสต์สุดท้ายในหน้าก่อนหน้า เรียงตาม published_at, id (ถอดมาจาก cursor)
+type PostPosition struct {
+	PublishedAt time.Time
+	ID          uuid.UUID
+}
+
+// SearchDocument ข้อความที่ใช้ค้นแบบ trigram (ILIKE) ต้องตรงกับ expression ของ index ใน config.migrateSearchIndex
+const SearchDocument = "coalesce(title, '') || ' ' || coalesce(description, '') || ' ' || coalesce(search_text, '')"
+
+// PublishedPostCondition เงื่อนไขเดียวกับ publishedPostsQuery สำหรับ query ที่ join ตาราง posts ด้วย alias
+// เช่น db.Where(post.PublishedPostCondition("p"))
+func PublishedPostCondition(alias string) (string, bool, models.PostStatus) {
+	return alias + ".deleted_at IS NULL AND " +
+		alias + ".published = ? AND " +
+		alias + ".published_at IS NOT NULL AND " +
+		alias + ".status = ?", true, models.PostPublished
+}
+
+// ListedPostCondition เงื่อนไขของโพสต์ที่แสดงในรายการ feed sitemap และการค้นหา
+// โพสต์ unlisted / protected เปิดได้เฉพาะผ่านลิงก์ตรง ใช้คู่กับ PublishedPostCondition
+func ListedPostCondition(alias string) (string, models.PostVisibility) {
+	return alias + ".visibility = ?", models.VisibilityPublic
+}
+
+// publishedPostsQuery เงื่อนไขของรายการโพสต์ที่ publish แล้ว ใช้ร่วมกันทั้งตอนดึงข้อมูลและตอนนับ
+// คำค้นจะ match ได้ทั้ง full-text (tsvector) และ trigram ซึ่งช่วยกรณีข้อความภาษาไทย
+func (r *PostRepository) publishedPostsQuery(search string) *gorm.DB {
+	query := r.DB.Model(&models.Post{}).
 		Where("published = ?", true).
-		Where("deleted_at IS NULL").
 		Where("published_at IS NOT NULL").
 		Where("status = ?", models.PostPublished).
+		Where(ListedPostCondition("posts"))
+
+	if search != "" {
+		query = query.Where(
+			"(search_vector @@ plainto_tsquery('simple', ?) OR "+SearchDocument+" ILIKE ?)",
+			search, "%"+utils.EscapeLike(search)+"%")
+	}
+	return query
+}
+
+func (r *PostRepository) GetAll(limit, offset int, search string) (*PostRepositoryQuery, error) {
+	return r.ListPublished(PostListQuery{Limit: limit, Offset: offset, Search: search, Count: true})
+}
+
+// ListPublished รายการโพสต์ที่ publish แล้วแบบแบ่งหน้า ด้วย offset หรือ keyset (query.After)
+// เมื่อมีคำค้นจะเรียงตามความเกี่ยวข้องก่อน จึงใช้ได้เฉพาะ offset
+func (r *PostRepository) ListPublished(query PostListQuery) (*PostRepositoryQuery, error) {
+	search := strings.TrimSpace(query.Search)
+	query.Search = search
+
+	columns := []string{"id", "slug", "title", "description", "thumbnail",
+		"published", "published_at", "author_id", "likes",
+		"views", "read_time", "ai_chat_open", "ai_ready"}
+	if search != "" {
+		// ใช้สร้าง snippet ของผลการค้นหา
+		columns = append(columns, "search_text")
+		query.After = nil
+	}
+
+	db := r.publishedPostsQuery(search).
+		Select(columns).
 		Preload("Author", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "username", "avatar")
 		}).
 		Preload("Tags").
-		Preload("Categories").
-		Order("published_at DESC").
-		Limit(limit).
-		Offset(offset)
+		Preload("Categories")
 
 	if search != "" {
-		searchTerm := "%" + search + "%"
-		query = query.Where("title ILIKE ? OR content ILIKE ?", searchTerm, searchTerm)
+		// เรียงตามความเกี่ยวข้อง: rank ของ full-text ก่อน แล้วค่อย trigram similarity (สำหรับภาษาไทย)
+		// ต้องใส่ลำดับสำรองไว้ใน expression เดียวกัน เพราะ gorm ทิ้ง Expression เมื่อ merge กับ Order แบบ column
+		db = db.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(search_vector, plainto_tsquery('simple', ?)) DESC, similarity(" + SearchDocument + ", ?) DESC, posts.published_at DESC, posts.id DESC",
+			Vars:               []interface{}{search, search},
+			WithoutParentheses: true,
+		}})
 	}
 
-	err := query.Find(&posts).Error
-	if err != nil {
-		return nil, err
+	return findPostPage(db, query, func() (int64, error) {
+		return r.getCount(search)
+	})
+}
+
+// findPostPage ดึงเกิน limit หนึ่งรายการเพื่อรู้ว่ามีหน้าถัดไปโดยไม่ต้อง COUNT(*)
+// นับ total เฉพาะเมื่อ query.Count เรียงตาม published_at, id ให้ตรงกับ PostPosition
+func findPostPage(db *gorm.DB, query PostListQuery, count func() (int64, error)) (*PostRepositoryQuery, error) {
+	if query.After != nil {
+		db = db.Where("(posts.published_at, posts.id) < (?, ?)", query.After.PublishedAt, query.After.ID)
+		query.Offset = 0
+	} else {
+		db = db.Offset(query.Offset)
 	}
 
-	total, err := r.getCount(search)
+	// query ที่กำหนดลำดับเอง (เช่นผลค้นหา) ใส่ลำดับสำรองมาแล้ว
+	if _, ordered := db.Statement.Clauses["ORDER BY"]; !ordered {
+		db = db.Order("posts.published_at DESC, posts.id DESC")
+	}
+
+	var posts []models.Post
+	err := db.Limit(query.Limit + 1).Find(&posts).Error
 	if err != nil {
 		return nil, err
 	}
 
-	hasNext := total > int64(offset+limit)
-
 	result := &PostRepositoryQuery{
-		Limit:   limit,
-		Total:   total,
-		HasNext: hasNext,
-		Page:    offset/limit + 1,
-		Offset:  offset,
-		Posts:   posts,
+		Limit:  query.Limit,
+		Page:   query.Offset/query.Limit + 1,
+		Offset: query.Offset,
+		Search: query.Search,
+		Posts:  posts,
+	}
+	if len(posts) > query.Limit {
+		result.Posts = posts[:query.Limit]
+		result.HasNext = true
 	}
 
+	if query.Count {
+		total, err := count()
+		if err != nil {
+			return nil, err
+		}
+		result.Total = total
+	}
 	return result, nil
 }
 
-func (r *PostRepository) getCount(search string) (int64, error) {
-	var count int64
-	query := r.DB.Model(&models.Post{}).
-		Where("published = ?", true).
-		Where("deleted_at IS NULL").
-		Where("published_at IS NOT NULL")
+// GetPublishedPostsByIDs โหลดโพสต์ที่ publish แล้วตาม id ที่ได้จากการจัดอันดับภายนอก (ลำดับไม่ตรงกับ ids)
+func (r *PostRepository) GetPublishedPostsByIDs(ids []uuid.UUID) ([]models.Post, error) {
+	var posts []models.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
 
-	if search != "" {
-		searchTerm := "%" + search + "%"
-		query = query.Where("title ILIKE ? OR content ILIKE ?", searchTerm, searchTerm)
+	err := r.publishedPostsQuery("").
+		Select("id", "slug", "title", "description", "thumbnail",
+			"published", "published_at", "author_id", "likes",
+			"views", "read_time", "ai_chat_open", "ai_ready").
+		Where("id IN ?", ids).
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Preload("Tags").
+		Preload("Categories").
+		Find(&posts).Error
+	return posts, err
+}
+
+// FeedQuery เงื่อนไขของโพสต์สำหรับ RSS / Atom / JSON Feed (ว่างทั้งหมด = ทั้งเว็บ)
+type FeedQuery struct {
+	AuthorUsername string
+	Tag            string
+	SeriesID       string
+	Limit          int
+	WithContent    bool // ดึง html_content มาด้วยสำหรับ feed แบบเต็ม
+}
+
+// GetFeedPosts โพสต์ล่าสุดตามเงื่อนไขเดียวกับ GetAll เรียงตามเวลา publish
+func (r *PostRepository) GetFeedPosts(query FeedQuery) ([]models.Post, error) {
+	columns := []string{"id", "slug", "title", "description", "thumbnail",
+		"published", "published_at", "author_id", "created_at", "updated_at"}
+	if query.WithContent {
+		columns = append(columns, "html_content")
 	}
 
-	err := query.Count(&count).Error
+	db := r.publishedPostsQuery("").
+		Select(columns).
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "first_name", "last_name", "avatar")
+		}).
+		Preload("Tags")
+
+	if query.AuthorUsername != "" {
+		db = db.Where("author_id = (SELECT id FROM users WHERE username = ? AND deleted_at IS NULL)", query.AuthorUsername)
+	}
+	if query.Tag != "" {
+		db = db.Where("id IN (SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ?)", query.Tag)
+	}
+	if query.SeriesID != "" {
+		db = db.Where("id IN (SELECT post_id FROM series_posts WHERE series_id = ?)", query.SeriesID)
+	}
+
+	var posts []models.Post
+	err := db.Order("published_at DESC").Limit(query.Limit).Find(&posts).Error
+	return posts, err
+}
+
+func (r *PostRepository) getCount(search string) (int64, error) {
+	var count int64
+	err := r.publishedPostsQuery(search).Count(&count).Error
 	return count, err
 }
 
@@ -130,9 +304,10 @@ func (r *PostRepository) GetByID(id string) (*models.Post, error) {
 	var post models.Post
 
 	err := r.DB.
-		Select("id", "slug", "title", "content", "description",
-			"thumbnail", "published", "published_at", "author_id",
-			"likes", "views", "read_time", "ai_chat_open", "ai_ready").
+		Select("id", "slug", "title", "content", "html_content", "description",
+			"thumbnail", "published", "status", "published_at", "publish_at", "unpublish_at",
+			"author_id", "likes", "views", "read_time", "ai_chat_open", "ai_ready", "comments_locked",
+			"visibility", "key", "version").
 		Where("deleted_at IS NULL").
 		Preload("Author", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "username", "avatar")
@@ -152,7 +327,7 @@ func (r *PostRepository) GetBySlug(slug string) (*models.Post, error) {
 	var post models.Post
 
 	err := r.DB.
-		Select("id", "slug", "title", "content", "description", "thumbnail", "published", "published_at", "author_id", "likes", "views", "read_time").
+		Select("id", "slug", "title", "content", "description", "thumbnail", "published", "published_at", "author_id", "likes", "views", "read_time", "visibility", "key", "version").
 		Where("deleted_at IS NULL").
 		Preload("Author", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "username", "avatar")
@@ -203,6 +378,7 @@ func (r *PostRepository) Update(post *models.Post) error {
 	}
 	if post.Content != "" {
 		updates["content"] = post.Content
+		updates["search_text"] = tiptap.ExtractTextFromTiptap(post.Content)
 	}
 	if post.HTMLContent != nil && *post.HTMLContent != "" {
 		updates["html_content"] = *post.HTMLContent
@@ -233,28 +409,62 @@ func (r *PostRepository) Update(post *models.Post) error {
 		return nil // ไม่มีฟิลด์ไหนเปลี่ยน
 	}
 
-	return r.DB.Model(post).Updates(updates).Error
+	// title/content เปลี่ยน = เนื้อหาเวอร์ชันใหม่ ต้องแก้จาก version ล่าสุดเท่านั้น
+	// เช็คใน WHERE ของ UPDATE เดียวกัน การบันทึกจาก version เก่า (หรือไม่ได้โหลด version มา) จึงไม่ทับของคนอื่น
+	contentChanged := (post.Title != "" && post.Title != existing.Title) ||
+		(post.Content != "" && post.Content != existing.Content)
+	if !contentChanged {
+		return r.DB.Model(post).Updates(updates).Error
+	}
+
+	updates["version"] = gorm.Expr("version + 1")
+	result := r.DB.Model(&models.Post{}).
+		Where("id = ? AND version = ?", post.ID, post.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrPostVersionConflict
+	}
+	post.Version++
+	return nil
 }
 
+// acceptedCollaboration โพสต์ที่ผู้ใช้ตอบรับคำเชิญร่วมเขียนแล้ว
+const acceptedCollaboration = "id IN (SELECT post_id FROM post_collaborators WHERE user_id = ? AND status = ?)"
+
+// GetMyPosts โพสต์ที่ผู้ใช้เป็นเจ้าของหรือร่วมเขียน (Collaborators มีเฉพาะแถวของผู้ใช้คนนี้ ใช้บอก role)
 func (r *PostRepository) GetMyPosts(user *models.User) ([]*models.Post, error) {
 	var posts []*models.Post
 	err := r.DB.
-		Where("author_id = ? AND deleted_at IS NULL", user.ID).
+		Where("deleted_at IS NULL").
+		Where(r.DB.Where("author_id = ?", user.ID).Or(acceptedCollaboration, user.ID, models.CollaboratorAccepted)).
+		Preload("Tags").
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Preload("Collaborators", "user_id = ? AND status = ?", user.ID, models.CollaboratorAccepted).
 		Find(&posts).Error
 	return posts, err
 }
 
-func (r *PostRepository) GetByShortSlug(shortSlug string) (*models.Post, error) {
-	var post models.Post
-
-	err := r.DB.
-		Select("id", "slug", "short_slug", "title", "content", "description", "thumbnail", "published", "status", "published_at", "author_id", "likes", "views", "read_time").
+// editablePostQuery คอลัมน์ที่ editor ใช้ของโพสต์ที่ยังไม่ถูกลบ
+func (r *PostRepository) editablePostQuery() *gorm.DB {
+	return r.DB.
+		Select("id", "slug", "short_slug", "title", "content", "description", "thumbnail", "published", "status", "published_at", "publish_at", "unpublish_at", "author_id", "likes", "views", "read_time", "visibility", "key", "version").
 		Where("deleted_at IS NULL").
 		Preload("Author", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "username", "avatar")
 		}).
 		Preload("Tags").
-		Preload("Categories").
+		Preload("Categories")
+}
+
+func (r *PostRepository) GetByShortSlug(shortSlug string) (*models.Post, error) {
+	var post models.Post
+
+	err := r.editablePostQuery().
 		Where("short_slug = ?", shortSlug).
 		First(&post).Error
 	if err != nil {
@@ -264,6 +474,37 @@ func (r *PostRepository) GetByShortSlug(shortSlug string) (*models.Post, error)
 	return &post, err
 }
 
+// GetCollaboratedPostByShortSlug หาโพสต์ของผู้เขียนคนอื่นที่ผู้ใช้ร่วมเขียนอยู่
+// short slug ที่เก็บจริงคือ <short>-<user id ของเจ้าของ> จึงเทียบกับ author_id ของแต่ละโพสต์ให้ตรงทั้งค่า
+// (prefix จะไปโดน <short>-อื่น-<id> ด้วย) ถ้าเจ้าของหลายคนใช้ short เดียวกันเลือกโพสต์ที่แก้ล่าสุดเสมอ
+func (r *PostRepository) GetCollaboratedPostByShortSlug(shortSlug string, userID string) (*models.Post, error) {
+	var post models.Post
+
+	err := r.editablePostQuery().
+		Where("short_slug = ? || '-' || CAST(author_id AS TEXT)", shortSlug).
+		Where(acceptedCollaboration, userID, models.CollaboratorAccepted).
+		Order("updated_at DESC, id").
+		First(&post).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+// GetCollaboratorRole role ของผู้ร่วมเขียนที่ตอบรับแล้ว (ErrRecordNotFound ถ้าไม่ได้ร่วมเขียน)
+func (r *PostRepository) GetCollaboratorRole(postID string, userID string) (models.CollaboratorRole, error) {
+	var collaborator models.PostCollaborator
+	err := r.DB.
+		Select("role").
+		Where("post_id = ? AND user_id = ? AND status = ?", postID, userID, models.CollaboratorAccepted).
+		First(&collaborator).Error
+	if err != nil {
+		return "", err
+	}
+	return collaborator.Role, nil
+}
+
 func (r *PostRepository) GetPublicPostBySlugAndUsername(slug string, username string) (*models.Post, error) {
 	var post models.Post
 
@@ -271,13 +512,18 @@ func (r *PostRepository) GetPublicPostBySlugAndUsername(slug string, username st
 		Select("posts.id", "posts.slug", "posts.title", "posts.content",
 			"posts.description", "posts.thumbnail", "posts.published", "posts.published_at",
 			"posts.author_id", "posts.likes", "posts.views", "posts.read_time",
-			"posts.created_at", "posts.updated_at", "posts.ai_chat_open", "posts.ai_ready").
+			"posts.created_at", "posts.updated_at", "posts.ai_chat_open", "posts.ai_ready",
+			"posts.comments_locked", "posts.visibility", "posts.key").
 		Where("posts.deleted_at IS NULL").
 		Preload("Author", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "username", "avatar", "bio", "first_name", "last_name")
 		}).
 		Preload("Tags").
 		Preload("Categories").
+		Preload("Collaborators", "status = ?", models.CollaboratorAccepted).
+		Preload("Collaborators.User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar", "bio", "first_name", "last_name")
+		}).
 		Joins("JOIN users ON users.id = posts.author_id").
 		Where("posts.slug = ? AND users.username = ? AND posts.published = ?", slug, username, true).
 		First(&post).Error
@@ -306,14 +552,79 @@ func (r *PostRepository) UnpublishPost(post *models.Post) error {
 		"published":    post.Published,
 		"published_at": post.PublishedAt,
 		"status":       post.Status,
+		"publish_at":   post.PublishAt,
+		"unpublish_at": post.UnpublishAt,
 	}).Error
 
 }
 
+// DeletePost ย้ายโพสต์ไปถังขยะ (soft delete) ลบจริงโดย PurgeDeletedPosts เมื่อครบ TrashRetention
 func (r *PostRepository) DeletePost(post *models.Post) error {
 	return r.DB.Delete(&models.Post{}, "id = ?", post.ID).Error
 }
 
+// GetTrashedPosts โพสต์ในถังขยะของผู้เขียน เรียงจากที่ลบล่าสุด
+func (r *PostRepository) GetTrashedPosts(authorID string) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.DB.Unscoped().
+		Where("author_id = ? AND deleted_at IS NOT NULL", authorID).
+		Order("deleted_at DESC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *PostRepository) GetTrashedPostByID(id string) (*models.Post, error) {
+	var post models.Post
+	if err := r.DB.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&post).Error; err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *PostRepository) RestorePost(post *models.Post) error {
+	return r.DB.Unscoped().Model(&models.Post{}).Where("id = ?", post.ID).Update("deleted_at", nil).Error
+}
+
+// trashDependentTables ตารางที่อ้างถึง posts.id ต้องลบก่อนลบโพสต์จริง (image_uploads แยกจัดการ)
+var trashDependentTables = []string{
+	"comments", "embeddings", "post_centroids", "ai_responses", "post_views", "post_revisions",
+	"post_reactions", "series_posts", "post_collaborators", "post_daily_stats",
+	"post_referrer_daily_stats", "post_scores", "bookmarks", "reading_histories",
+	"post_tags", "post_categories",
+}
+
+// PurgeDeletedPosts ลบโพสต์ที่อยู่ในถังขยะก่อน deletedBefore ออกถาวรพร้อมข้อมูลที่อ้างถึง
+// รูปของโพสต์ถูกปลดออกและตั้ง is_used = false เพื่อให้ DeleteUnusedImages เก็บคืนได้
+func (r *PostRepository) PurgeDeletedPosts(deletedBefore time.Time) (int64, error) {
+	var ids []uuid.UUID
+	err := r.DB.Unscoped().Model(&models.Post{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, table := range trashDependentTables {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE post_id IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.ImageUpload{}).
+			Where("post_id IN ?", ids).
+			Updates(map[string]interface{}{"is_used": false, "used_at": nil, "post_id": nil}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&models.Post{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
 func (r *PostRepository) GetEmbeddingByPostID(postID string) ([]models.Embedding, error) {
 	var post models.Post
 	err := r.DB.Preload("Embeddings").Where("id = ?", postID).First(&post).Error
@@ -351,10 +662,16 @@ func (r *PostRepository) UpdateEmbedding(post *models.Post, embedding models.Emb
 // DeleteEmbeddingsByPostID
 
 func (r *PostRepository) DeleteEmbeddingsByPostID(postID string) error {
-	return r.DB.
+	err := r.DB.
 		Unscoped().
 		Where("post_id = ?", postID).
 		Delete(&models.Embedding{}).Error
+	if err != nil {
+		return err
+	}
+
+	// centroid คำนวณจาก embedding จึงต้องลบตามกัน
+	return r.DB.Where("post_id = ?", postID).Delete(&models.PostCentroid{}).Error
 }
 
 func (r *PostRepository) BulkInsertEmbeddings(post *models.Post, embeddings []models.Embedding) error {
@@ -364,8 +681,8 @@ func (r *PostRepository) BulkInsertEmbeddings(post *models.Post, embeddings []mo
 	return r.DB.Create(&embeddings).Error
 }
 
-// RecordPostView บันทึก view ของ post
-func (r *PostRepository) RecordPostView(postID string, userID *string, fingerprint string, ipAddress, userAgent string) error {
+// RecordPostView บันทึก view ของ post นับ view เฉพาะ fingerprint ใหม่ ส่วนผู้ใช้ที่ login อัปเดตประวัติการอ่านทุกครั้ง
+func (r *PostRepository) RecordPostView(postID string, userID *string, fingerprint string, ipAddress, userAgent, referrer string) error {
 	// แปลง string เป็น uuid.UUID
 	postUUID, err := uuid.Parse(postID)
 	if err != nil {
@@ -394,6 +711,7 @@ func (r *PostRepository) RecordPostView(postID string, userID *string, fingerpri
 				Fingerprint: fingerprint,
 				IPAddress:   ipAddress,
 				UserAgent:   userAgent,
+				Referrer:    referrer,
 			}
 
 			// สร้าง PostView ใหม่
@@ -408,11 +726,36 @@ func (r *PostRepository) RecordPostView(postID string, userID *string, fingerpri
 		} else {
 			return err
 		}
+	} else if userUUID != nil && existingView.UserID == nil {
+		// เคยอ่านก่อน login บน browser เดียวกัน ผูก view เดิมกับผู้ใช้ (ไม่นับ view ซ้ำ)
+		if err := r.DB.Model(&existingView).UpdateColumn("user_id", *userUUID).Error; err != nil {
+			return err
+		}
 	}
 
+	if userUUID != nil {
+		return r.touchReadingHistory(*userUUID, postUUID)
+	}
 	return nil
 }
 
+// touchReadingHistory เพิ่มโพสต์เข้าประวัติการอ่าน หรืออัปเดตเวลาอ่านล่าสุดถ้าเคยอ่านแล้ว
+// ข้ามเมื่อผู้ใช้หยุดบันทึกประวัติไว้ (ดู models.ReadingHistorySetting)
+func (r *PostRepository) touchReadingHistory(userID, postID uuid.UUID) error {
+	var setting models.ReadingHistorySetting
+	if err := r.DB.Where("user_id = ?", userID).Limit(1).Find(&setting).Error; err != nil {
+		return err
+	}
+	if setting.Paused {
+		return nil
+	}
+
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_viewed_at"}),
+	}).Create(&models.ReadingHistory{UserID: userID, PostID: postID, LastViewedAt: time.Now()}).Error
+}
+
 // GetPostViews ดึงจำนวน view ของ post
 func (r *PostRepository) GetPostViews(postID string) (int, error) {
 	var count int64
@@ -423,56 +766,42 @@ func (r *PostRepository) GetPostViews(postID string) (int, error) {
 	return int(count), nil
 }
 
-// GetPublishedPostsByAuthor ดึงบทความที่ published โดย author คนหนึ่ง
-func (r *PostRepository) GetPublishedPostsByAuthor(username string, page, limit int) ([]models.Post, int64, error) {
-	var posts []models.Post
-	offset := (page - 1) * limit
-
+// GetPublishedPostsByAuthor ดึงบทความที่ published โดย author คนหนึ่ง แบ่งหน้าแบบเดียวกับ ListPublished
+func (r *PostRepository) GetPublishedPostsByAuthor(username string, query PostListQuery) (*PostRepositoryQuery, error) {
 	// ดึง user ID จาก username
 	var user models.User
 	err := r.DB.Where("username = ? AND deleted_at IS NULL", username).First(&user).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+
+	authorPosts := func() *gorm.DB {
+		return r.DB.Model(&models.Post{}).
+			Where("author_id = ?", user.ID).
+			Where("published = ?", true).
+			Where("deleted_at IS NULL").
+			Where("published_at IS NOT NULL").
+			Where("status = ?", models.PostPublished).
+			Where(ListedPostCondition("posts"))
 	}
 
 	// ดึงบทความที่ published โดย author คนนี้
-	query := r.DB.
+	db := authorPosts().
 		Select("id", "slug", "title", "description", "thumbnail",
 			"published", "published_at", "author_id", "likes",
 			"views", "read_time", "ai_chat_open", "ai_ready").
-		Where("author_id = ?", user.ID).
-		Where("published = ?", true).
-		Where("deleted_at IS NULL").
-		Where("published_at IS NOT NULL").
-		Where("status = ?", models.PostPublished).
 		Preload("Author", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "username", "avatar")
 		}).
 		Preload("Tags").
-		Preload("Categories").
-		Order("published_at DESC").
-		Limit(limit).
-		Offset(offset)
-
-	err = query.Find(&posts).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// นับจำนวนบทความทั้งหมด
-	var total int64
-	err = r.DB.Model(&models.Post{}).
-		Where("author_id = ?", user.ID).
-		Where("published = ?", true).
-		Where("deleted_at IS NULL").
-		Where("published_at IS NOT NULL").
-		Where("status = ?", models.PostPublished).
-		Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return posts, total, nil
+		Preload("Categories")
+
+	return findPostPage(db, query, func() (int64, error) {
+		// นับจำนวนบทความทั้งหมด
+		var total int64
+		err := authorPosts().Count(&total).Error
+		return total, err
+	})
 }
 
 // GetPopularPosts ดึงบทความยอดนิยมตามจำนวน view
@@ -487,6 +816,7 @@ func (r *PostRepository) GetPopularPosts(limit int) ([]models.Post, error) {
 		Where("deleted_at IS NULL").
 		Where("published_at IS NOT NULL").
 		Where("status = ?", models.PostPublished).
+		Where(ListedPostCondition("posts")).
 		Where("views > ?", MinPopularPostViews). // เฉพาะบทความที่มี view มากกว่า 0
 		Preload("Author", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "username", "avatar")
@@ -503,3 +833,342 @@ func (r *PostRepository) GetPopularPosts(limit int) ([]models.Post, error) {
 
 	return posts, nil
 }
+
+// recomputeTrendingScoresSQL คะแนนแบบ Hacker News: กิจกรรมในช่วงเวลา (view, like, ผู้ใช้ที่คุยกับ AI) ถ่วงน้ำหนัก
+// หารด้วย (อายุโพสต์เป็นชั่วโมง + 2) ^ gravity โพสต์เก่าจึงค่อย ๆ หลุดจาก trending แม้ยอดรวมจะสูง
+const recomputeTrendingScoresSQL = `
+INSERT INTO post_scores (post_id, time_window, views, likes, ai_chats, score, updated_at)
+SELECT p.id, ?, COALESCE(v.views, 0), COALESCE(l.likes, 0), COALESCE(a.ai_chats, 0),
+	(COALESCE(v.views, 0) * ? + COALESCE(l.likes, 0) * ? + COALESCE(a.ai_chats, 0) * ?) /
+		POWER(GREATEST(EXTRACT(EPOCH FROM (NOW() - p.published_at)) / 3600, 0) + 2, ?),
+	NOW()
+FROM posts p
+LEFT JOIN (
+	SELECT post_id, COUNT(*) AS views FROM post_views
+	WHERE deleted_at IS NULL AND viewed_at >= ? GROUP BY post_id
+) v ON v.post_id = p.id
+LEFT JOIN (
+	SELECT post_id, COUNT(*) AS likes FROM post_reactions
+	WHERE type = ? AND created_at >= ? GROUP BY post_id
+) l ON l.post_id = p.id
+LEFT JOIN (
+	SELECT post_id, COUNT(DISTINCT user_id) AS ai_chats FROM ai_responses
+	WHERE deleted_at IS NULL AND used_at >= ? GROUP BY post_id
+) a ON a.post_id = p.id
+WHERE %s AND %s AND (v.views IS NOT NULL OR l.likes IS NOT NULL OR a.ai_chats IS NOT NULL)`
+
+// RecomputeTrendingScores คำนวณคะแนนของช่วงเวลาใหม่ทั้งหมดจากกิจกรรมตั้งแต่ since
+// โพสต์ที่ไม่มีกิจกรรมในช่วงนี้จะไม่มีแถว คืนจำนวนโพสต์ที่มีคะแนน
+func (r *PostRepository) RecomputeTrendingScores(window string, since time.Time) (int64, error) {
+	condition, published, status := PublishedPostCondition("p")
+	listed, visibility := ListedPostCondition("p")
+
+	var inserted int64
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("time_window = ?", window).Delete(&models.PostScore{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(fmt.Sprintf(recomputeTrendingScoresSQL, condition, listed),
+			window, TrendingViewWeight, TrendingLikeWeight, TrendingAIChatWeight, TrendingGravity,
+			since, models.ReactionLike, since, since,
+			published, status, visibility)
+		inserted = result.RowsAffected
+		return result.Error
+	})
+	return inserted, err
+}
+
+// GetTrendingPosts โพสต์ที่มีคะแนน trending สูงสุดในช่วงเวลา
+// ตรวจ publish / visibility ซ้ำ เพราะโพสต์อาจถูกซ่อนหลังจากคำนวณคะแนนรอบล่าสุด
+func (r *PostRepository) GetTrendingPosts(window string, limit int) ([]models.Post, error) {
+	var posts []models.Post
+
+	err := r.DB.
+		Select("posts.id", "posts.slug", "posts.title", "posts.description", "posts.thumbnail",
+			"posts.published", "posts.published_at", "posts.author_id", "posts.likes",
+			"posts.views", "posts.read_time", "posts.ai_chat_open", "posts.ai_ready").
+		Joins("JOIN post_scores ON post_scores.post_id = posts.id AND post_scores.time_window = ?", window).
+		Where(PublishedPostCondition("posts")).
+		Where(ListedPostCondition("posts")).
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Preload("Tags").
+		Preload("Categories").
+		Order("post_scores.score DESC, posts.published_at DESC").
+		Limit(limit).
+		Find(&posts).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// CreateRevision บันทึก snapshot ของเนื้อหา post
+func (r *PostRepository) CreateRevision(revision *models.PostRevision) error {
+	return r.DB.Create(revision).Error
+}
+
+// GetLatestRevision ดึง revision ล่าสุดของ post (คืน nil ถ้ายังไม่มี)
+func (r *PostRepository) GetLatestRevision(postID string) (*models.PostRevision, error) {
+	var revision models.PostRevision
+	err := r.DB.
+		Where("post_id = ?", postID).
+		Order("created_at DESC, id DESC").
+		First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetRevisionsByPostID ดึงรายการ revision ของ post เรียงจากใหม่ไปเก่า (ไม่รวมเนื้อหา)
+func (r *PostRepository) GetRevisionsByPostID(postID string) ([]models.PostRevision, error) {
+	var revisions []models.PostRevision
+	err := r.DB.
+		Select("id", "post_id", "user_id", "title", "reason", "created_at").
+		Where("post_id = ?", postID).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Order("created_at DESC, id DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevisionByID ดึง revision พร้อมเนื้อหา โดยต้องเป็นของ post ที่ระบุ
+func (r *PostRepository) GetRevisionByID(postID string, revisionID uint) (*models.PostRevision, error) {
+	var revision models.PostRevision
+	err := r.DB.
+		Where("post_id = ? AND id = ?", postID, revisionID).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// PruneRevisions ลบ revision เก่าที่เกิน keepLast ต่อ post ยกเว้น revision ที่มาจากการ publish
+func (r *PostRepository) PruneRevisions(keepLast int) (int64, error) {
+	result := r.DB.Exec(`
+		DELETE FROM post_revisions
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, reason,
+					ROW_NUMBER() OVER (PARTITION BY post_id ORDER BY created_at DESC, id DESC) AS rn
+				FROM post_revisions
+			) ranked
+			WHERE ranked.rn > ? AND ranked.reason <> ?
+		)`, keepLast, models.RevisionPublish)
+	return result.RowsAffected, result.Error
+}
+
+// UpdateSchedule เขียนเวลา publish_at / unpublish_at ตรง ๆ (รวมค่า nil เพื่อยกเลิกการตั้งเวลา)
+// แยกจาก Update เพราะ Update จะข้ามฟิลด์ที่เป็นค่า zero
+func (r *PostRepository) UpdateSchedule(postID string, publishAt, unpublishAt *time.Time) error {
+	return r.DB.Model(&models.Post{}).
+		Where("id = ?", postID).
+		Updates(map[string]interface{}{
+			"publish_at":   publishAt,
+			"unpublish_at": unpublishAt,
+		}).Error
+}
+
+// UpdateVisibility ตั้ง visibility และ hash ของ key (ว่าง = ไม่มี key) ในคำสั่งเดียว
+func (r *PostRepository) UpdateVisibility(postID string, visibility models.PostVisibility, keyHash string) error {
+	return r.DB.Model(&models.Post{}).
+		Where("id = ?", postID).
+		Updates(map[string]interface{}{
+			"visibility": visibility,
+			"key":        keyHash,
+		}).Error
+}
+
+// ReplacePostTags แทนที่ tag ของโพสต์ด้วยชื่อที่ normalize แล้ว สร้าง tag ใหม่ถ้ายังไม่มี
+func (r *PostRepository) ReplacePostTags(post *models.Post, names []string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return replacePostTags(tx, post.ID, names)
+	})
+}
+
+func replacePostTags(tx *gorm.DB, postID uuid.UUID, names []string) error {
+	tags := []models.Tag{}
+	if len(names) > 0 {
+		newTags := make([]models.Tag, 0, len(names))
+		for _, name := range names {
+			newTags = append(newTags, models.Tag{Name: name})
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoNothing: true,
+		}).Create(&newTags).Error; err != nil {
+			return err
+		}
+
+		// โหลดใหม่เพื่อให้ได้ ID ของ tag ที่มีอยู่แล้วด้วย
+		if err := tx.Where("name IN ?", names).Find(&tags).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&models.Post{ID: postID}).Association("Tags").Replace(&tags)
+}
+
+// CreateImportedPost สร้าง draft จากการ import พร้อม tag และผูกรูปที่อัปโหลดไว้ก่อนใน transaction เดียว
+func (r *PostRepository) CreateImportedPost(post *models.Post, tags []string, imageIDs []uuid.UUID) error {
+	post.SearchText = tiptap.ExtractTextFromTiptap(post.Content)
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		if len(tags) > 0 {
+			if err := replacePostTags(tx, post.ID, tags); err != nil {
+				return err
+			}
+		}
+		if len(imageIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&models.ImageUpload{}).
+			Where("id IN ?", imageIDs).
+			Updates(map[string]interface{}{"post_id": post.ID, "is_used": true, "used_at": time.Now()}).Error
+	})
+}
+
+// ReplacePostCategories แทนที่ category ของโพสต์ category ต้องถูกสร้างโดย admin ไว้ก่อนแล้ว
+func (r *PostRepository) ReplacePostCategories(post *models.Post, names []string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		categories := []models.Category{}
+		if len(names) > 0 {
+			lowered := make([]string, 0, len(names))
+			for _, name := range names {
+				lowered = append(lowered, strings.ToLower(name))
+			}
+			if err := tx.Where("LOWER(name) IN ?", lowered).Find(&categories).Error; err != nil {
+				return err
+			}
+			if len(categories) != len(names) {
+				return fmt.Errorf("%w: %s", errs.ErrCategoryNotFound, strings.Join(missingCategoryNames(names, categories), ", "))
+			}
+		}
+
+		return tx.Model(&models.Post{ID: post.ID}).Association("Categories").Replace(&categories)
+	})
+}
+
+func missingCategoryNames(names []string, found []models.Category) []string {
+	foundSet := make(map[string]bool, len(found))
+	for _, c := range found {
+		foundSet[strings.ToLower(c.Name)] = true
+	}
+
+	var missing []string
+	for _, name := range names {
+		if !foundSet[strings.ToLower(name)] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func (r *PostRepository) GetPublishedPostsByTag(name string, limit, offset int) (*PostRepositoryQuery, error) {
+	return r.getPublishedPostsByJoin(
+		"JOIN post_tags ON post_tags.post_id = posts.id JOIN tags ON tags.id = post_tags.tag_id",
+		"tags.name = ?", name, limit, offset)
+}
+
+func (r *PostRepository) GetPublishedPostsByCategory(name string, limit, offset int) (*PostRepositoryQuery, error) {
+	return r.getPublishedPostsByJoin(
+		"JOIN post_categories ON post_categories.post_id = posts.id JOIN categories ON categories.id = post_categories.category_id",
+		"LOWER(categories.name) = LOWER(?)", name, limit, offset)
+}
+
+// getPublishedPostsByJoin ดึงโพสต์ที่ publish แล้วผ่าน join table (tag / category) พร้อม pagination
+func (r *PostRepository) getPublishedPostsByJoin(join, condition string, value interface{}, limit, offset int) (*PostRepositoryQuery, error) {
+	base := func() *gorm.DB {
+		return r.DB.Model(&models.Post{}).
+			Joins(join).
+			Where(condition, value).
+			Where("posts.published = ?", true).
+			Where("posts.published_at IS NOT NULL").
+			Where("posts.status = ?", models.PostPublished).
+			Where(ListedPostCondition("posts"))
+	}
+
+	var total int64
+	if err := base().Distinct("posts.id").Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var posts []models.Post
+	err := base().
+		Select("posts.id", "posts.slug", "posts.title", "posts.description", "posts.thumbnail",
+			"posts.published", "posts.published_at", "posts.author_id", "posts.likes",
+			"posts.views", "posts.read_time", "posts.ai_chat_open", "posts.ai_ready").
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Preload("Tags").
+		Preload("Categories").
+		Order("posts.published_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostRepositoryQuery{
+		Limit:   limit,
+		Total:   total,
+		HasNext: total > int64(offset+limit),
+		Page:    offset/limit + 1,
+		Offset:  offset,
+		Posts:   posts,
+	}, nil
+}
+
+// GetBookmarkedPostIDs โพสต์ใน postIDs ที่ผู้ใช้ bookmark ไว้
+func (r *PostRepository) GetBookmarkedPostIDs(userID string, postIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if len(postIDs) == 0 {
+		return ids, nil
+	}
+	err := r.DB.Model(&models.Bookmark{}).
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).
+		Pluck("post_id", &ids).Error
+	return ids, err
+}
+
+type bookmarkCount struct {
+	PostID uuid.UUID
+	Count  int64
+}
+
+// CountBookmarks จำนวน bookmark ต่อโพสต์ (โพสต์ที่ไม่มีจะไม่อยู่ใน map)
+func (r *PostRepository) CountBookmarks(postIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []bookmarkCount
+	err := r.DB.Model(&models.Bookmark{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("post_id IN ?", postIDs).
+		Group("post_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.PostID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,185 @@
+package post
+
+import (
+	"encoding/json"
+	"errors"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"rag-searchbot-backend/pkg/tiptap"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CurrentRevision ใช้แทน revision id เพื่ออ้างถึงเนื้อหาปัจจุบันของ post
+const CurrentRevision = "current"
+
+// snapshotRevision บันทึก snapshot ของ post
+// ถ้าเป็นการ save ที่เนื้อหาไม่ต่างจาก revision ล่าสุด จะไม่บันทึกซ้ำ (autosave ส่งมาบ่อย)
+func (s *PostService) snapshotRevision(post *models.Post, userID uuid.UUID, reason models.PostRevisionReason) error {
+	if reason == models.RevisionSave {
+		latest, err := s.Repo.GetLatestRevision(post.ID.String())
+		if err != nil {
+			return err
+		}
+		if latest != nil && latest.Content == post.Content && latest.Title == post.Title {
+			return nil
+		}
+	}
+
+	return s.Repo.CreateRevision(&models.PostRevision{
+		PostID:      post.ID,
+		UserID:      userID,
+		Title:       post.Title,
+		Content:     post.Content,
+		HTMLContent: post.HTMLContent,
+		Reason:      reason,
+	})
+}
+
+// recordRevision เหมือน snapshotRevision แต่ไม่ทำให้การบันทึก post ล้มเหลว
+func (s *PostService) recordRevision(post *models.Post, userID uuid.UUID, reason models.PostRevisionReason) {
+	if err := s.snapshotRevision(post, userID, reason); err != nil {
+		logger.Log.Warn("Failed to record post revision",
+			zap.String("post_id", post.ID.String()),
+			zap.String("reason", string(reason)),
+			zap.Error(err))
+	}
+}
+
+func (s *PostService) getRevision(post *models.Post, revisionID string) (*models.PostRevision, error) {
+	id, err := strconv.ParseUint(revisionID, 10, 64)
+	if err != nil {
+		return nil, errs.ErrRevisionNotFound
+	}
+
+	revision, err := s.Repo.GetRevisionByID(post.ID.String(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// GetRevisions ดึงรายการ revision ของ post
+func (s *PostService) GetRevisions(shortSlug string, user *models.User) (*PostRevisionListResponse, error) {
+	post, err := s.FindPostForUser(shortSlug, user, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.Repo.GetRevisionsByPostID(post.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]PostRevisionDTO, 0, len(revisions))
+	for _, revision := range revisions {
+		dtos = append(dtos, MapPostRevisionToDTO(revision))
+	}
+
+	return &PostRevisionListResponse{Revisions: dtos}, nil
+}
+
+// GetRevision ดึง revision เดียวพร้อมเนื้อหา
+func (s *PostService) GetRevision(shortSlug string, revisionID string, user *models.User) (*PostRevisionDetailDTO, error) {
+	post, err := s.FindPostForUser(shortSlug, user, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.getRevision(post, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostRevisionDetailDTO{
+		PostRevisionDTO: MapPostRevisionToDTO(*revision),
+		Content:         revision.Content,
+		HTMLContent:     revision.HTMLContent,
+	}, nil
+}
+
+// DiffRevisions เปรียบเทียบโครงสร้าง TipTap ระหว่าง revision สองตัว
+// from/to เป็น revision id หรือ "current" สำหรับเนื้อหาปัจจุบัน
+func (s *PostService) DiffRevisions(shortSlug string, from string, to string, user *models.User) (*PostRevisionDiffResponse, error) {
+	post, err := s.FindPostForUser(shortSlug, user, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == "" {
+		to = CurrentRevision
+	}
+
+	contentOf := func(ref string) (string, error) {
+		if ref == CurrentRevision {
+			return post.Content, nil
+		}
+		revision, err := s.getRevision(post, ref)
+		if err != nil {
+			return "", err
+		}
+		return revision.Content, nil
+	}
+
+	fromContent, err := contentOf(from)
+	if err != nil {
+		return nil, err
+	}
+	toContent, err := contentOf(to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := tiptap.Diff(fromContent, toContent)
+	if err != nil {
+		return nil, errs.ErrInvalidPayload
+	}
+
+	return &PostRevisionDiffResponse{From: from, To: to, Diff: diff}, nil
+}
+
+// RestoreRevision นำเนื้อหาจาก revision กลับมาเป็น draft ปัจจุบัน
+// post ที่ publish อยู่จะยัง publish ต่อไป จนกว่าผู้เขียนจะ publish ใหม่
+func (s *PostService) RestoreRevision(shortSlug string, revisionID string, user *models.User) (string, error) {
+	post, err := s.FindPostForUser(shortSlug, user, ActionEdit)
+	if err != nil {
+		return "", err
+	}
+
+	revision, err := s.getRevision(post, revisionID)
+	if err != nil {
+		return "", err
+	}
+
+	var content PostContentStructure
+	if err := json.Unmarshal([]byte(revision.Content), &content); err != nil {
+		return "", errs.ErrInvalidPayload
+	}
+
+	// เก็บเนื้อหาปัจจุบันไว้ก่อน เผื่อยังไม่เคยมี revision
+	s.recordRevision(post, user.ID, models.RevisionSave)
+
+	post.Content = revision.Content
+	post.Title = revision.Title
+	post.AIChatOpen = false
+	post.AIReady = false
+
+	if err := s.UpdateImageUsageStatus(post, content, ""); err != nil {
+		return "", err
+	}
+
+	if err := s.Repo.Update(post); err != nil {
+		return "", err
+	}
+
+	s.recordRevision(post, user.ID, models.RevisionRestore)
+
+	return post.ID.String(), nil
+}
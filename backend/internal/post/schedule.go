@@ -0,0 +1,260 @@
+package post
+
+import (
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"rag-searchbot-backend/pkg/tiptap"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// validateSchedule ตรวจว่า unpublish_at ต้องอยู่หลังเวลาที่โพสต์จะถูก publish
+func validateSchedule(publishAt, unpublishAt *time.Time, now time.Time) error {
+	if unpublishAt == nil {
+		return nil
+	}
+
+	start := now
+	if publishAt != nil && publishAt.After(now) {
+		start = *publishAt
+	}
+	if !unpublishAt.After(start) {
+		return fmt.Errorf("%w: unpublish_at must be after the publish time", errs.ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// truncateSchedule ตัดเศษวินาทีออก เพื่อให้เทียบกับค่าที่อ่านกลับจาก DB ได้ตรงกัน
+func truncateSchedule(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	truncated := t.Truncate(time.Second)
+	return &truncated
+}
+
+// sameScheduleTime ใช้ตรวจว่า task ที่ถูกปลุกขึ้นมายังตรงกับเวลาที่ตั้งไว้ล่าสุดหรือไม่
+func sameScheduleTime(scheduled *time.Time, runAt time.Time) bool {
+	return scheduled != nil && scheduled.Unix() == runAt.Unix()
+}
+
+// markPublished ตั้งสถานะโพสต์เป็น PUBLISHED พร้อมคำนวณเวลาอ่านใหม่และล้าง embedding เดิม
+func (s *PostService) markPublished(post *models.Post) {
+	post.Published = true
+	post.Status = models.PostPublished
+	now := time.Now()
+	post.PublishedAt = &now
+	if post.HTMLContent != nil {
+		post.ReadTime = float64(tiptap.EstimateReadTimeFromHTML(*post.HTMLContent))
+	}
+
+	// delete existing embedding from vector db
+	s.Repo.DeleteEmbeddingsByPostID(post.ID.String())
+}
+
+// applySchedule บันทึกเวลา publish/unpublish ลง DB แล้ว enqueue task ตามเวลาที่ตั้งไว้
+// ส่ง nil เพื่อยกเลิกการตั้งเวลานั้น ๆ
+func (s *PostService) applySchedule(post *models.Post, publishAt, unpublishAt *time.Time) error {
+	publishAt = truncateSchedule(publishAt)
+	unpublishAt = truncateSchedule(unpublishAt)
+
+	if err := s.Repo.UpdateSchedule(post.ID.String(), publishAt, unpublishAt); err != nil {
+		return err
+	}
+	post.PublishAt = publishAt
+	post.UnpublishAt = unpublishAt
+
+	if publishAt != nil {
+		if err := s.TaskEnqueuer.EnqueueScheduledPublish(post, *publishAt); err != nil {
+			return err
+		}
+	}
+	if unpublishAt != nil {
+		if err := s.TaskEnqueuer.EnqueueScheduledUnpublish(post, *unpublishAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schedulePublish เก็บโพสต์ไว้ในสถานะ SCHEDULED จนถึงเวลา publishAt
+func (s *PostService) schedulePublish(post *models.Post, publishAt time.Time, unpublishAt *time.Time) error {
+	post.Published = false
+	post.Status = models.PostScheduled
+
+	// โพสต์ที่เคย publish แล้วถูกตั้งเวลาใหม่ต้องหายจากหน้า public และ vector db ก่อน
+	s.Repo.DeleteEmbeddingsByPostID(post.ID.String())
+
+	if err := s.Repo.Update(post); err != nil {
+		return err
+	}
+	s.notifyUnpublished(post)
+
+	logger.Log.Info("Scheduled post for publishing",
+		zap.String("post_id", post.ID.String()),
+		zap.Time("publish_at", publishAt))
+
+	return s.applySchedule(post, &publishAt, unpublishAt)
+}
+
+// getScheduledTaskPost โหลดโพสต์ให้ worker คืน nil ถ้าโพสต์ถูกลบไปแล้ว
+func (s *PostService) getScheduledTaskPost(postID string) (*models.Post, error) {
+	post, err := s.Repo.GetByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return post, nil
+}
+
+/**
+* PublishScheduledPost is called by the scheduled publish worker.
+* @param postID string - The ID of the post
+* @param runAt time.Time - The publish time carried in the task payload
+* @return *models.Post - The published post, or nil when the task is stale
+* @return error - An error if occurred
+* A task is stale when the post was deleted, cancelled or rescheduled after it was enqueued.
+* The post goes live exactly like PublishPost: read time is re-estimated and old embeddings are removed.
+**/
+
+func (s *PostService) PublishScheduledPost(postID string, runAt time.Time) (*models.Post, error) {
+	post, err := s.getScheduledTaskPost(postID)
+	if err != nil || post == nil {
+		return nil, err
+	}
+
+	if post.Status != models.PostScheduled || !sameScheduleTime(post.PublishAt, runAt) {
+		return nil, nil
+	}
+
+	// เนื้อหาอาจถูกแก้หลังตั้งเวลา จึง render HTML ใหม่จาก content ล่าสุด
+	rejected, err := renderHTMLContent(post)
+	if err != nil {
+		return nil, err
+	}
+	if len(rejected) > 0 {
+		logger.Log.Warn("Removed disallowed HTML from scheduled post",
+			zap.String("post_id", post.ID.String()),
+			zap.Any("rejected", rejected))
+	}
+	s.markPublished(post)
+
+	if err := s.Repo.Update(post); err != nil {
+		return nil, err
+	}
+
+	if err := s.applySchedule(post, nil, post.UnpublishAt); err != nil {
+		return nil, err
+	}
+
+	s.recordRevision(post, post.AuthorID, models.RevisionPublish)
+	s.notifyPublished(post)
+
+	return post, nil
+}
+
+/**
+* UnpublishScheduledPost is called by the scheduled unpublish worker.
+* @param postID string - The ID of the post
+* @param runAt time.Time - The unpublish time carried in the task payload
+* @return *models.Post - The unpublished post, or nil when the task is stale
+* @return error - An error if occurred
+**/
+
+func (s *PostService) UnpublishScheduledPost(postID string, runAt time.Time) (*models.Post, error) {
+	post, err := s.getScheduledTaskPost(postID)
+	if err != nil || post == nil {
+		return nil, err
+	}
+
+	if !post.Published || !sameScheduleTime(post.UnpublishAt, runAt) {
+		return nil, nil
+	}
+
+	post.Published = false
+	post.Status = models.PostDraft
+	post.PublishAt = nil
+	post.UnpublishAt = nil
+
+	// delete embedding from vector db
+	s.Repo.DeleteEmbeddingsByPostID(post.ID.String())
+
+	if err := s.Repo.UnpublishPost(post); err != nil {
+		return nil, err
+	}
+
+	s.notifyUnpublished(post)
+	return post, nil
+}
+
+// ReschedulePost เลื่อนเวลา publish (โพสต์ SCHEDULED) หรือเวลา unpublish (โพสต์ที่ publish แล้ว)
+func (s *PostService) ReschedulePost(shortSlug string, req ScheduleRequestDTO, user *models.User) (*ScheduleResponseDTO, error) {
+	post, err := s.FindPostForUser(shortSlug, user, ActionPublish)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case post.Status == models.PostScheduled:
+		if req.PublishAt == nil || !req.PublishAt.After(now) {
+			return nil, fmt.Errorf("%w: publish_at must be in the future", errs.ErrInvalidSchedule)
+		}
+		if err := validateSchedule(req.PublishAt, req.UnpublishAt, now); err != nil {
+			return nil, err
+		}
+		if err := s.applySchedule(post, req.PublishAt, req.UnpublishAt); err != nil {
+			return nil, err
+		}
+	case post.Published:
+		if req.PublishAt != nil {
+			return nil, fmt.Errorf("%w: post is already published", errs.ErrInvalidSchedule)
+		}
+		if err := validateSchedule(nil, req.UnpublishAt, now); err != nil {
+			return nil, err
+		}
+		if err := s.applySchedule(post, nil, req.UnpublishAt); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errs.ErrNotScheduled
+	}
+
+	return &ScheduleResponseDTO{
+		Status:      string(post.Status),
+		PublishAt:   post.PublishAt,
+		UnpublishAt: post.UnpublishAt,
+	}, nil
+}
+
+// CancelSchedule ยกเลิกการตั้งเวลา โพสต์ SCHEDULED จะกลับเป็น DRAFT ส่วนโพสต์ที่ publish แล้วจะไม่ถูก unpublish อัตโนมัติ
+func (s *PostService) CancelSchedule(shortSlug string, user *models.User) (*ScheduleResponseDTO, error) {
+	post, err := s.FindPostForUser(shortSlug, user, ActionPublish)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case post.Status == models.PostScheduled:
+		post.Status = models.PostDraft
+		if err := s.Repo.Update(post); err != nil {
+			return nil, err
+		}
+	case post.UnpublishAt == nil:
+		return nil, errs.ErrNotScheduled
+	}
+
+	// task ที่ค้างอยู่ใน queue จะถูกข้ามเองเพราะเวลาใน DB ไม่ตรงกับ payload แล้ว
+	if err := s.applySchedule(post, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return &ScheduleResponseDTO{Status: string(post.Status)}, nil
+}
@@ -0,0 +1,131 @@
+package post
+
+import (
+	"html"
+	"rag-searchbot-backend/internal/models"
+	"strings"
+	"unicode"
+)
+
+// snippetRadius จำนวนตัวอักษรที่แสดงรอบคำที่ค้นเจอในแต่ละด้าน
+const snippetRadius = 80
+
+// SearchHighlightDTO ข้อความที่ครอบคำค้นด้วย <mark> (ส่วนอื่น escape HTML แล้ว)
+type SearchHighlightDTO struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+}
+
+// searchTerms แยกคำค้นตามช่องว่าง ภาษาไทยที่ไม่เว้นวรรคจะถูกใช้ทั้งวลี
+func searchTerms(query string) [][]rune {
+	seen := map[string]bool{}
+	var terms [][]rune
+	for _, field := range strings.Fields(query) {
+		term := lowerRunes([]rune(field))
+		if seen[string(term)] {
+			continue
+		}
+		seen[string(term)] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// lowerRunes แปลงเป็นตัวพิมพ์เล็กทีละตัว เพื่อให้ตำแหน่งตรงกับข้อความเดิม
+func lowerRunes(runes []rune) []rune {
+	lowered := make([]rune, len(runes))
+	for i, r := range runes {
+		lowered[i] = unicode.ToLower(r)
+	}
+	return lowered
+}
+
+// matchAt คืนความยาวของคำค้นที่ยาวที่สุดที่ match ที่ตำแหน่ง i (0 ถ้าไม่ match)
+func matchAt(text []rune, i int, terms [][]rune) int {
+	best := 0
+	for _, term := range terms {
+		if len(term) <= best || i+len(term) > len(text) {
+			continue
+		}
+		if string(text[i:i+len(term)]) == string(term) {
+			best = len(term)
+		}
+	}
+	return best
+}
+
+// highlight escape ข้อความแล้วครอบส่วนที่ตรงกับคำค้นด้วย <mark>
+func highlight(text []rune, terms [][]rune) string {
+	lowered := lowerRunes(text)
+
+	var b strings.Builder
+	start := 0
+	for i := 0; i < len(text); {
+		n := matchAt(lowered, i, terms)
+		if n == 0 {
+			i++
+			continue
+		}
+		b.WriteString(html.EscapeString(string(text[start:i])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(text[i : i+n])))
+		b.WriteString("</mark>")
+		i += n
+		start = i
+	}
+	b.WriteString(html.EscapeString(string(text[start:])))
+	return b.String()
+}
+
+// snippetWindow ตัดข้อความรอบคำค้นที่เจอครั้งแรก ถ้าไม่เจอใช้ช่วงต้นของข้อความ
+func snippetWindow(text []rune, terms [][]rune) ([]rune, bool, bool) {
+	lowered := lowerRunes(text)
+
+	first, length := -1, 0
+	for i := range lowered {
+		if n := matchAt(lowered, i, terms); n > 0 {
+			first, length = i, n
+			break
+		}
+	}
+
+	start, end := 0, 2*snippetRadius
+	if first >= 0 {
+		start = first - snippetRadius
+		end = first + length + snippetRadius
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > len(text) {
+		end = len(text)
+	}
+	return text[start:end], start > 0, end < len(text)
+}
+
+// BuildSearchHighlight สร้าง title และ snippet ที่ไฮไลต์คำค้นสำหรับแสดงในผลการค้นหา
+func BuildSearchHighlight(post models.Post, query string) *SearchHighlightDTO {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	body := []rune(strings.Join(strings.Fields(post.SearchText), " "))
+	if len(body) == 0 {
+		body = []rune(post.Description)
+	}
+
+	window, truncatedStart, truncatedEnd := snippetWindow(body, terms)
+	snippet := highlight(window, terms)
+	if truncatedStart {
+		snippet = "…" + snippet
+	}
+	if truncatedEnd {
+		snippet += "…"
+	}
+
+	return &SearchHighlightDTO{
+		Title:   highlight([]rune(post.Title), terms),
+		Snippet: snippet,
+	}
+}
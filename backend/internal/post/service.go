@@ -5,12 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/url"
 	"rag-searchbot-backend/internal/media"
 	"rag-searchbot-backend/internal/models"
 	"rag-searchbot-backend/pkg/errs"
 	"rag-searchbot-backend/pkg/logger"
-	"rag-searchbot-backend/pkg/tiptap"
-	"strconv"
+	"rag-searchbot-backend/pkg/sanitize"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,13 +21,13 @@ import (
 )
 
 type PostServiceInterface interface {
-	CreatePost(post CreatePostRequest, user *models.User) (string, error)
+	CreatePost(post CreatePostRequest, user *models.User) (*CreatePostResponseDTO, error)
 	GetPostByID(id string) (*models.Post, error)
 	GetPostBySlug(slug string) (*PostByIdResponse, error)
 	UpdatePost(post *models.Post) error
 	MyPosts(user *models.User) (*MyPostsResponseDTO, error)
-	GetPostsByAuthor(username string, page, limit int) (*PostListResponse, error)
-	RecordPostView(postID string, user *models.User, fingerprint string, ipAddress, userAgent string) (*PostViewResponse, error)
+	GetPostsByAuthor(username string, opts PostListOptions) (*PostListResponse, error)
+	RecordPostView(postID string, user *models.User, accessToken string, fingerprint string, ipAddress, userAgent, referrer string) (*PostViewResponse, error)
 	GetPopularPosts(limit int) (*PostListResponse, error)
 }
 
@@ -34,6 +35,9 @@ type PostService struct {
 	Repo         PostRepositoryInterface
 	MediaService media.MediaServiceInterface
 	TaskEnqueuer *TaskEnqueuer
+	Listeners    []PublicationListener
+	Access       *PostAccess
+	Embeddings   EmbeddingEnqueuer
 }
 
 func NewPostService(repo PostRepositoryInterface, mediaRepo media.MediaServiceInterface, enqueuer *TaskEnqueuer) PostServiceInterface {
@@ -54,50 +58,73 @@ type CategoryDTO struct {
 * CreatePost creates a new post or updates an existing one if a post with the same short slug already exists.
 * @param post CreatePostRequest - The request containing post details
 * @param user *models.User - The user creating the post
-* @return string - The ID of the created or updated post
-* @return error - An error if occurred
+* @return *CreatePostResponseDTO - The ID and current version of the created or updated post
+* @return error - An error if occurred (*VersionConflictError when post.Version is stale)
 * This function marshals the content of the post, checks if a post with the same short slug exists,
 * updates the existing post if it does, or creates a new post if it doesn't.
 * It also updates the image usage status based on the content of the post.
+* Every save is snapshotted as a PostRevision so autosaves can be rolled back.
 * If an error occurs during any of these operations, it returns the error.
 * If the post is created successfully, it returns the ID of the post.
 * If an existing post is updated, it returns the ID of the updated post.
 * The short slug is combined with the user ID to ensure uniqueness.
+* Collaborators with the editor role save into the owner's post instead of creating their own.
+* Saving into an existing post requires the version the client edited; a stale version is rejected
+* with the current version and content so the editor can merge.
 **/
 
-func (s *PostService) CreatePost(post CreatePostRequest, user *models.User) (string, error) {
+func (s *PostService) CreatePost(post CreatePostRequest, user *models.User) (*CreatePostResponseDTO, error) {
 	slug := post.ShortSlug + "-" + user.ID.String()
 
 	// 1. Marshal content
 	contentJSON, err := json.Marshal(post.Content)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// 2. check if a post with the same short slug already exists
-	existingPost, err := s.Repo.GetByShortSlug(slug)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return "", err
+	// 2. check if a post with the same short slug already exists (own or collaborated)
+	existingPost, err := s.lookupPost(post.ShortSlug, user)
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. if a post with the same short slug exists, update it
 	if existingPost != nil {
+		if err := Authorize(s.Repo, existingPost, user, ActionEdit); err != nil {
+			return nil, err
+		}
+
+		// editor ต้องส่ง version ที่โหลดมาแก้ ถ้าไม่ตรงกับปัจจุบันให้ client merge เอง
+		if post.Version != existingPost.Version {
+			return nil, newVersionConflict(existingPost)
+		}
+
 		existingPost.Content = string(contentJSON)
 		existingPost.Title = post.Title
 		existingPost.AIChatOpen = false
 		existingPost.AIReady = false
 
-		err = s.UpdateImageUsageStatus(existingPost, post.Content, "")
+		// Repo.Update เช็ค version อีกครั้งใน UPDATE เผื่อมีคนบันทึกแทรกหลังอ่าน
+		err = s.Repo.Update(existingPost)
+		if errors.Is(err, errs.ErrPostVersionConflict) {
+			current, getErr := s.Repo.GetByID(existingPost.ID.String())
+			if getErr != nil {
+				return nil, getErr
+			}
+			return nil, newVersionConflict(current)
+		}
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		err = s.Repo.Update(existingPost)
+		err = s.UpdateImageUsageStatus(existingPost, post.Content, "")
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		return existingPost.ID.String(), nil
+		s.recordRevision(existingPost, user.ID, models.RevisionSave)
+
+		return &CreatePostResponseDTO{PostID: existingPost.ID.String(), Version: existingPost.Version}, nil
 	}
 
 	// 4. if no post with the same short slug exists, create a new one
@@ -111,25 +138,29 @@ func (s *PostService) CreatePost(post CreatePostRequest, user *models.User) (str
 		PublishedAt: nil,
 		AIChatOpen:  false,
 		AIReady:     false,
+		Version:     1,
 	}
 
 	postID, err := s.Repo.Create(newPost)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 5. Get the created post and update image usage status
 	createdPost, err := s.Repo.GetByID(postID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	err = s.UpdateImageUsageStatus(createdPost, post.Content, "")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return postID, nil
+	newPost.ID = createdPost.ID
+	s.recordRevision(newPost, user.ID, models.RevisionSave)
+
+	return &CreatePostResponseDTO{PostID: postID, Version: newPost.Version}, nil
 }
 
 /**
@@ -155,37 +186,44 @@ func (s *PostService) GetByShortSlug(shortSlug string) (*models.Post, error) {
 /*
 *
   - GetPosts retrieves a paginated list of posts.
+  - Pages by ?page= or by the signed ?cursor= from Meta.NextCursor; ?count=false skips the total count.
   - @param c *gin.Context - The Gin context
   - @return *PostListResponse - The response containing the list of posts
   - @return error - An error if occurred
 */
 
 func (s *PostService) GetPosts(c *gin.Context) (*PostListResponse, error) {
-	limitStr := c.DefaultQuery("limit", "10")
-	pageStr := c.DefaultQuery("page", "1")
-	search := c.Query("search")
+	search := strings.TrimSpace(c.Query("search"))
+	opts := ParsePostListOptions(c)
 
-	if search == "" {
-		search = " "
+	// ผลการค้นหาเรียงตามความเกี่ยวข้อง จึงแบ่งหน้าด้วย page เท่านั้น
+	if search != "" {
+		opts.Cursor = ""
 	}
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page <= 0 {
-		page = 1
+	query, err := opts.listQuery()
+	if err != nil {
+		return nil, err
 	}
+	query.Search = search
 
-	offset := (page - 1) * limit
-
-	result, err := s.Repo.GetAll(limit, offset, search)
+	result, err := s.Repo.ListPublished(query)
 	if err != nil {
 		return nil, err
 	}
 
+	response := buildPostListResponse(result, opts.Limit)
+	if search != "" {
+		for i, post := range result.Posts {
+			response.Posts[i].Highlight = BuildSearchHighlight(post, search)
+		}
+	} else {
+		response.Meta.NextCursor = nextPostCursor(result)
+	}
+	return response, nil
+}
+
+// buildPostListResponse แปลงผล query แบบแบ่งหน้าเป็น PostListResponse พร้อม Meta
+func buildPostListResponse(result *PostRepositoryQuery, limit int) *PostListResponse {
 	var meta Meta
 
 	meta.Total = result.Total
@@ -202,7 +240,7 @@ func (s *PostService) GetPosts(c *gin.Context) (*PostListResponse, error) {
 	return &PostListResponse{
 		Posts: postDTOs,
 		Meta:  meta,
-	}, nil
+	}
 }
 
 /**
@@ -225,7 +263,8 @@ func (s *PostService) GetPostByID(id string) (*models.Post, error) {
 		return nil, nil
 	}
 
-	if post.Key != "" {
+	// getter นี้ไม่มีข้อมูลผู้อ่าน โพสต์ protected จึงไม่คืนเนื้อหา
+	if post.Visibility == models.VisibilityProtected {
 		return nil, nil
 	}
 	return post, nil
@@ -246,7 +285,7 @@ func (s *PostService) GetPostBySlug(slug string) (*PostByIdResponse, error) {
 		return nil, nil
 	}
 
-	if post.Key != "" {
+	if post.Visibility == models.VisibilityProtected {
 		return nil, nil
 	}
 	postDTO := MapPostToSummaryDTOWithContent(*post)
@@ -280,7 +319,7 @@ type MyPostsResponseDTO struct {
 }
 
 /**
-* MyPosts retrieves the posts created by the specified user.
+* MyPosts retrieves the posts created by the specified user and the posts they collaborate on.
 * @param user *models.User - The user whose posts to retrieve
 * @return *MyPostsResponseDTO - The response containing the user's posts
 * @return error - An error if occurred
@@ -292,9 +331,24 @@ func (s *PostService) MyPosts(user *models.User) (*MyPostsResponseDTO, error) {
 		return nil, err
 	}
 
+	ids := make([]uuid.UUID, 0, len(rawPosts))
+	for _, post := range rawPosts {
+		ids = append(ids, post.ID)
+	}
+	bookmarks, err := s.Repo.CountBookmarks(ids)
+	if err != nil {
+		return nil, err
+	}
+
 	var postDTOs []MyPostsDTO
 	for _, post := range rawPosts {
-		postDTOs = append(postDTOs, MapMyPostToSummaryDTO(*post))
+		dto := MapMyPostToSummaryDTO(*post)
+		dto.Role = collaboratorRole(post, user)
+		if dto.Role != "owner" {
+			dto.Owner = post.Author.UserName
+		}
+		dto.Bookmarks = bookmarks[post.ID]
+		postDTOs = append(postDTOs, dto)
 	}
 
 	return &MyPostsResponseDTO{
@@ -309,31 +363,28 @@ func (s *PostService) MyPosts(user *models.User) (*MyPostsResponseDTO, error) {
 * @param shortSlug string - The short slug of the post
 * @return error - An error if occurred
 * This function checks if a post with the given short slug exists and is not already published.
+* When publish_at is in the future the post is kept as SCHEDULED and published later by a worker.
+* When unpublish_at is set the post is taken down automatically at that time.
+* The returned rejections list markup removed by the HTML sanitization policy.
 **/
 
-func (s *PostService) PublishPost(post *PublishPostRequestDTO, user *models.User, shortSlug string) error {
-
-	shortSlug = shortSlug + "-" + user.ID.String()
-
-	existingPost, err := s.Repo.GetByShortSlug(shortSlug)
+func (s *PostService) PublishPost(post *PublishPostRequestDTO, user *models.User, shortSlug string) ([]sanitize.Rejection, error) {
 
+	existingPost, err := s.FindPostForUser(shortSlug, user, ActionPublish)
 	if err != nil {
-		return err
-	}
-
-	if existingPost == nil {
-		return errors.New("post not found")
+		return nil, err
 	}
 
-	if existingPost.AuthorID != user.ID {
-		return errors.New("you are not the author of this post")
+	now := time.Now()
+	if err := validateSchedule(post.PublishAt, post.UnpublishAt, now); err != nil {
+		return nil, err
 	}
 
 	// Validate Slug is not duplicate
 	existingPostBySlug, err := s.Repo.GetBySlug(post.Slug)
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
+		return nil, err
 	}
 
 	// If a post with the same slug exists, append user ID and a random UUID to the slug
@@ -349,17 +400,24 @@ func (s *PostService) PublishPost(post *PublishPostRequestDTO, user *models.User
 	existingPost.Title = post.Title
 	existingPost.Description = post.Description
 	existingPost.Thumbnail = post.Thumbnail
-	existingPost.HTMLContent = post.HTMLContent
-	existingPost.Published = true
-	existingPost.Status = models.PostPublished
-	now := time.Now()
-	existingPost.PublishedAt = &now
-	if post.HTMLContent != nil {
-		existingPost.ReadTime = float64(tiptap.EstimateReadTimeFromHTML(*post.HTMLContent))
+	rejected, err := renderHTMLContent(existingPost)
+	if err != nil {
+		return nil, err
 	}
 
-	// delete existing embedding from vector db
-	s.Repo.DeleteEmbeddingsByPostID(existingPost.ID.String())
+	if err := s.attachTaxonomy(existingPost, post); err != nil {
+		return nil, err
+	}
+
+	// ตั้งเวลา publish ล่วงหน้า: worker จะ publish ให้เมื่อถึงเวลา
+	if post.IsScheduled(now) {
+		if err := s.schedulePublish(existingPost, *post.PublishAt, post.UnpublishAt); err != nil {
+			return nil, err
+		}
+		return rejected, nil
+	}
+
+	s.markPublished(existingPost)
 
 	logger.Log.Info("Publishing post without server-side AI moderation",
 		zap.String("post_id", existingPost.ID.String()),
@@ -367,34 +425,42 @@ func (s *PostService) PublishPost(post *PublishPostRequestDTO, user *models.User
 		zap.String("author_id", existingPost.AuthorID.String()),
 		zap.String("author_email", user.Email))
 
-	return s.Repo.Update(existingPost)
-}
+	if err := s.Repo.Update(existingPost); err != nil {
+		return nil, err
+	}
 
-func (s *PostService) UnpublishPost(user *models.User, shortSlug string) error {
+	// publish ทันทีจะล้าง publish_at ที่ค้างอยู่ และตั้ง unpublish_at ถ้ามี
+	if err := s.applySchedule(existingPost, nil, post.UnpublishAt); err != nil {
+		return nil, err
+	}
+
+	s.recordRevision(existingPost, user.ID, models.RevisionPublish)
+	s.notifyPublished(existingPost)
 
-	shortSlug = shortSlug + "-" + user.ID.String()
+	return rejected, nil
+}
 
-	existingPost, err := s.Repo.GetByShortSlug(shortSlug)
+func (s *PostService) UnpublishPost(user *models.User, shortSlug string) error {
 
+	existingPost, err := s.FindPostForUser(shortSlug, user, ActionPublish)
 	if err != nil {
 		return err
 	}
 
-	if existingPost == nil {
-		return errors.New("post not found")
-	}
-
-	if existingPost.AuthorID != user.ID {
-		return errors.New("you are not the author of this post")
-	}
-
 	existingPost.Published = false
 	existingPost.Status = models.PostDraft
+	existingPost.PublishAt = nil
+	existingPost.UnpublishAt = nil
 
 	// delete embedding from vector db
 	s.Repo.DeleteEmbeddingsByPostID(existingPost.ID.String())
 
-	return s.Repo.UnpublishPost(existingPost)
+	if err := s.Repo.UnpublishPost(existingPost); err != nil {
+		return err
+	}
+
+	s.notifyUnpublished(existingPost)
+	return nil
 }
 
 func (s *PostService) GetPublicPostBySlugAndUsername(slug string, username string) (*models.Post, error) {
@@ -406,9 +472,7 @@ func (s *PostService) GetPublicPostBySlugAndUsername(slug string, username strin
 		return nil, nil
 	}
 
-	if post.Key != "" {
-		return nil, nil
-	}
+	// visibility ตรวจที่ CanReadPost เพราะต้องใช้ผู้ใช้และ cookie ปลดล็อก
 	return post, nil
 }
 
@@ -421,24 +485,20 @@ func (s *PostService) DeletePostByID(id string, user *models.User) error {
 	if existingPost == nil || existingPost.ID == uuid.Nil {
 		return errs.ErrPostNotFound
 	}
-	if existingPost.AuthorID != user.ID {
-		return errs.ErrUnauthorized
+	if err := Authorize(s.Repo, existingPost, user, ActionDelete); err != nil {
+		return err
 	}
 
-	// Remove all images related to the post
-	if err := s.MakeAllNotUsedImageStatus(existingPost); err != nil {
-		return fmt.Errorf("failed to update image usage status: %w", err)
-	}
+	// รูปยังถูกนับว่าใช้อยู่ระหว่างอยู่ในถังขยะ จะถูกปลดตอน PurgeDeletedPosts
+	s.Repo.DeleteEmbeddingsByPostID(existingPost.ID.String())
 
-	// Make thunbnail not used
-	if err := s.UpdateThumbnailUsageStatus(existingPost, existingPost.Thumbnail); err != nil {
-		return fmt.Errorf("failed to update thumbnail usage status: %w", err)
+	// Move the post to the trash (restorable until TrashRetention passes)
+	if err := s.Repo.DeletePost(existingPost); err != nil {
+		return err
 	}
 
-	s.Repo.DeleteEmbeddingsByPostID(existingPost.ID.String())
-
-	// Delete the post
-	return s.Repo.DeletePost(existingPost)
+	s.notifyUnpublished(existingPost)
+	return nil
 }
 
 // updateImageUsageStatus checks which images in content or thumbnail are used
@@ -575,13 +635,16 @@ func (s *PostService) MakeAllNotUsedImageStatus(post *models.Post) error {
 	return nil
 }
 
-// RecordPostView บันทึก view ของ post
-func (s *PostService) RecordPostView(postID string, user *models.User, fingerprint string, ipAddress, userAgent string) (*PostViewResponse, error) {
+// RecordPostView บันทึก view ของ post โพสต์ protected นับเฉพาะผู้ที่อ่านได้ (ดู CanReadPost)
+func (s *PostService) RecordPostView(postID string, user *models.User, accessToken string, fingerprint string, ipAddress, userAgent, referrer string) (*PostViewResponse, error) {
 	// ตรวจสอบว่า post มีอยู่จริงหรือไม่
-	_, err := s.Repo.GetByID(postID)
+	post, err := s.Repo.GetByID(postID)
 	if err != nil {
 		return nil, err
 	}
+	if !s.CanReadPost(post, user, accessToken) {
+		return nil, errs.ErrPostProtected
+	}
 
 	var userID *string
 	if user != nil {
@@ -590,7 +653,7 @@ func (s *PostService) RecordPostView(postID string, user *models.User, fingerpri
 	}
 
 	// บันทึก view
-	err = s.Repo.RecordPostView(postID, userID, fingerprint, ipAddress, userAgent)
+	err = s.Repo.RecordPostView(postID, userID, fingerprint, ipAddress, userAgent, NormalizeReferrer(referrer))
 	if err != nil {
 		return nil, err
 	}
@@ -608,35 +671,36 @@ func (s *PostService) RecordPostView(postID string, user *models.User, fingerpri
 	}, nil
 }
 
+// NormalizeReferrer เก็บเฉพาะ host ของ referrer (ตัว www. ออก ตัวพิมพ์เล็ก) เพื่อรวมสถิติตามแหล่งที่มา
+// ค่าที่ไม่ใช่ URL http(s) ถือว่าเข้าตรง (คืนค่าว่าง)
+func NormalizeReferrer(referrer string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if len(host) > 255 {
+		return ""
+	}
+	return host
+}
+
 // GetPostsByAuthor ดึงบทความที่เขียนโดยผู้เขียนคนหนึ่ง
-func (s *PostService) GetPostsByAuthor(username string, page, limit int) (*PostListResponse, error) {
-	// ดึงบทความที่ published โดย author คนนี้
-	posts, total, err := s.Repo.GetPublishedPostsByAuthor(username, page, limit)
+func (s *PostService) GetPostsByAuthor(username string, opts PostListOptions) (*PostListResponse, error) {
+	query, err := opts.listQuery()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get posts by author: %w", err)
+		return nil, err
 	}
 
-	// แปลงเป็น DTO
-	var postDTOs []PostSummaryDTO
-	for _, post := range posts {
-		postDTO := MapPostToSummaryDTO(post)
-		postDTOs = append(postDTOs, postDTO)
+	// ดึงบทความที่ published โดย author คนนี้
+	result, err := s.Repo.GetPublishedPostsByAuthor(username, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts by author: %w", err)
 	}
 
-	// คำนวณ pagination
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
-	hasNextPage := page < totalPages
-
-	return &PostListResponse{
-		Posts: postDTOs,
-		Meta: Meta{
-			Total:       total,
-			HasNextPage: hasNextPage,
-			Page:        page,
-			Limit:       limit,
-			TotalPage:   totalPages,
-		},
-	}, nil
+	response := buildPostListResponse(result, opts.Limit)
+	response.Meta.NextCursor = nextPostCursor(result)
+	return response, nil
 }
 
 // GetPopularPosts ดึงบทความยอดนิยมตามจำนวน view
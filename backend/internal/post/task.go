@@ -4,17 +4,30 @@ import (
 	"encoding/json"
 	"rag-searchbot-backend/internal/models"
 	"rag-searchbot-backend/internal/queue"
+	"time"
 
 	"github.com/hibiken/asynq"
 )
 
 const TaskTypeFilterPostContentByAI = "ai:filter_post_content"
+const TaskTypePruneRevisions = "post:prune_revisions"
+const TaskTypePurgeTrash = "post:purge_trash"
+const TaskTypeRecomputeTrending = "post:recompute_trending"
+const TaskTypeScheduledPublish = "post:scheduled_publish"
+const TaskTypeScheduledUnpublish = "post:scheduled_unpublish"
 
 type FilterPostContentByAIPayload struct {
 	Post models.Post
 	User models.User
 }
 
+// ScheduledPostPayload ระบุโพสต์และเวลาที่ task ถูกตั้งไว้
+// worker จะเทียบ RunAt กับค่าใน DB เพื่อข้าม task ที่ถูกเลื่อนหรือยกเลิกไปแล้ว
+type ScheduledPostPayload struct {
+	PostID string    `json:"post_id"`
+	RunAt  time.Time `json:"run_at"`
+}
+
 type TaskEnqueuer struct {
 	QueueRepository queue.QueueRepositoryInterface
 	Client          *asynq.Client
@@ -68,3 +81,27 @@ func (t *TaskEnqueuer) EnqueueFilterPostContentByAI(post *models.Post, user *mod
 
 	return true, nil
 }
+
+// EnqueueScheduledPublish ตั้ง task ให้ publish โพสต์ ณ เวลา publishAt
+func (t *TaskEnqueuer) EnqueueScheduledPublish(post *models.Post, publishAt time.Time) error {
+	return t.enqueueScheduled(TaskTypeScheduledPublish, post, publishAt)
+}
+
+// EnqueueScheduledUnpublish ตั้ง task ให้ unpublish โพสต์ ณ เวลา unpublishAt
+func (t *TaskEnqueuer) EnqueueScheduledUnpublish(post *models.Post, unpublishAt time.Time) error {
+	return t.enqueueScheduled(TaskTypeScheduledUnpublish, post, unpublishAt)
+}
+
+func (t *TaskEnqueuer) enqueueScheduled(taskType string, post *models.Post, runAt time.Time) error {
+	payload, err := json.Marshal(ScheduledPostPayload{
+		PostID: post.ID.String(),
+		RunAt:  runAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	// ไม่ต้องลบ task เดิมเมื่อเลื่อนเวลา worker จะข้าม task ที่ RunAt ไม่ตรงกับ DB เอง
+	_, err = t.Client.Enqueue(asynq.NewTask(taskType, payload), asynq.ProcessAt(runAt))
+	return err
+}
@@ -0,0 +1,60 @@
+package post
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/taxonomy"
+)
+
+// attachTaxonomy แทนที่ tag / category ของโพสต์ตาม request
+// ถ้า request ไม่ส่ง field มา (nil) จะคงค่าเดิมไว้ ส่ง array ว่างเพื่อล้าง
+func (s *PostService) attachTaxonomy(post *models.Post, req *PublishPostRequestDTO) error {
+	if req.Tags != nil {
+		names, err := taxonomy.NormalizeTagNames(req.Tags)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.ReplacePostTags(post, names); err != nil {
+			return err
+		}
+	}
+
+	if req.Categories != nil {
+		if err := s.Repo.ReplacePostCategories(post, taxonomy.NormalizeCategoryNames(req.Categories)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetPostsByTag ดึงโพสต์ที่ publish แล้วซึ่งติด tag นี้ แบ่งหน้าแบบเดียวกับ GetPosts
+func (s *PostService) GetPostsByTag(name string, page, limit int) (*PostListResponse, error) {
+	page, limit = normalizePage(page, limit)
+
+	result, err := s.Repo.GetPublishedPostsByTag(taxonomy.NormalizeTagName(name), limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	return buildPostListResponse(result, limit), nil
+}
+
+// GetPostsByCategory ดึงโพสต์ที่ publish แล้วใน category นี้ แบ่งหน้าแบบเดียวกับ GetPosts
+func (s *PostService) GetPostsByCategory(name string, page, limit int) (*PostListResponse, error) {
+	page, limit = normalizePage(page, limit)
+
+	result, err := s.Repo.GetPublishedPostsByCategory(taxonomy.NormalizeCategoryName(name), limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	return buildPostListResponse(result, limit), nil
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	return page, limit
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exportContent = `{"type":"doc","content":[` +
+	`{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Intro"}]},` +
+	`{"type":"paragraph","content":[{"type":"text","text":"Hello "},{"type":"text","marks":[{"type":"bold"}],"text":"world"}]}]}`
+
+func newExportPost(author *models.User, slug, shortSlug string) *models.Post {
+	now := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	p := &models.Post{
+		ID:          uuid.New(),
+		Title:       "Tips & <Tricks>",
+		Description: "About \"quotes\"",
+		Slug:        slug,
+		ShortSlug:   shortSlug + "-" + author.ID.String(),
+		AuthorID:    author.ID,
+		Content:     exportContent,
+		Status:      models.PostPublished,
+		PublishedAt: &now,
+		Tags:        []models.Tag{{Name: "go"}},
+	}
+	p.UpdatedAt = now
+	return p
+}
+
+func TestExportPost_Formats(t *testing.T) {
+	author := &models.User{ID: uuid.New()}
+
+	cases := []struct {
+		format      string
+		fileName    string
+		contentType string
+		contains    []string
+	}{
+		{
+			format:      post.ExportFormatMarkdown,
+			fileName:    "tips.md",
+			contentType: "text/markdown; charset=utf-8",
+			contains:    []string{"title: Tips & <Tricks>\n", "slug: tips\n", "## Intro\n\nHello **world**\n"},
+		},
+		{
+			format:      post.ExportFormatHTML,
+			fileName:    "tips.html",
+			contentType: "text/html; charset=utf-8",
+			contains: []string{
+				"<title>Tips &amp; &lt;Tricks&gt;</title>",
+				`<meta name="description" content="About &#34;quotes&#34;">`,
+				`<meta name="keywords" content="go">`,
+				"<strong>world</strong>",
+			},
+		},
+		{
+			format:      post.ExportFormatJSON,
+			fileName:    "tips.json",
+			contentType: "application/json; charset=utf-8",
+			contains:    []string{`"slug": "tips"`, `"status": "` + string(models.PostPublished) + `"`, `"tags": [`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			repo := new(MockPostRepository)
+			p := newExportPost(author, "tips", "abc123")
+			repo.On("GetByShortSlug", p.ShortSlug).Return(p, nil)
+			service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+			file, err := service.ExportPost("abc123", tc.format, author)
+			require.NoError(t, err)
+			assert.Equal(t, tc.fileName, file.FileName)
+			assert.Equal(t, tc.contentType, file.ContentType)
+			for _, want := range tc.contains {
+				assert.Contains(t, string(file.Body), want)
+			}
+		})
+	}
+}
+
+func TestExportPost_JSONKeepsContent(t *testing.T) {
+	author := &models.User{ID: uuid.New()}
+	repo := new(MockPostRepository)
+	p := newExportPost(author, "tips", "abc123")
+	repo.On("GetByShortSlug", p.ShortSlug).Return(p, nil)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	file, err := service.ExportPost("abc123", post.ExportFormatJSON, author)
+	require.NoError(t, err)
+
+	var exported post.PostExportDTO
+	require.NoError(t, json.Unmarshal(file.Body, &exported))
+	assert.JSONEq(t, exportContent, string(exported.Content))
+	assert.Equal(t, []string{"go"}, exported.Tags)
+}
+
+func TestExportPost_Errors(t *testing.T) {
+	author := &models.User{ID: uuid.New()}
+	repo := new(MockPostRepository)
+	broken := newExportPost(author, "broken", "broken1")
+	broken.Content = "not json"
+	repo.On("GetByShortSlug", broken.ShortSlug).Return(broken, nil)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	_, err := service.ExportPost("broken1", "pdf", author)
+	assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+	repo.AssertNotCalled(t, "GetByShortSlug", broken.ShortSlug)
+
+	for _, format := range []string{post.ExportFormatMarkdown, post.ExportFormatHTML, post.ExportFormatJSON} {
+		_, err := service.ExportPost("broken1", format, author)
+		assert.ErrorIs(t, err, errs.ErrInvalidPayload, format)
+	}
+}
+
+func TestExportAllPosts_ZipNames(t *testing.T) {
+	author := &models.User{ID: uuid.New()}
+	repo := new(MockPostRepository)
+
+	// draft ที่ยังไม่ตั้ง slug ใช้ short slug ตัด user id ออก
+	draft := newExportPost(author, "", "draft1")
+	draft.Slug = draft.ShortSlug
+	draft.Content = ""
+	posts := []*models.Post{
+		newExportPost(author, "same", "a1"),
+		newExportPost(author, "same", "b2"),
+		newExportPost(author, "a/b:c", "c3"),
+		draft,
+	}
+	repo.On("GetMyPosts", author).Return(posts, nil)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	file, err := service.ExportAllPosts(post.ExportFormatMarkdown, author)
+	require.NoError(t, err)
+	assert.Equal(t, "application/zip", file.ContentType)
+	assert.True(t, strings.HasSuffix(file.FileName, ".zip"))
+
+	archive, err := zip.NewReader(bytes.NewReader(file.Body), int64(len(file.Body)))
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+		assert.True(t, f.Modified.Equal(posts[0].UpdatedAt), f.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"a-b-c.md", "draft1.md", "same-2.md", "same.md"}, names)
+
+	for _, f := range archive.File {
+		if f.Name != "draft1.md" {
+			continue
+		}
+		r, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), "slug:")
+	}
+}
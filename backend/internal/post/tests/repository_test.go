@@ -4,9 +4,13 @@ import (
 	"testing"
 	"time"
 
+	"rag-searchbot-backend/internal/models"
 	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -547,3 +551,142 @@ func (suite *PostRepositoryTestSuite) TestDeleteEmbeddingsByPostID() {
 func TestPostRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(PostRepositoryTestSuite))
 }
+
+// newEditorRepository PostRepository จริงบน SQLite ตาราง posts สร้างเองเพราะ models.Post ใช้ type ของ postgres
+func newEditorRepository(t *testing.T) (*gorm.DB, post.PostRepositoryInterface) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	for _, ddl := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT, avatar TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE posts (
+			id TEXT PRIMARY KEY, slug TEXT, short_slug TEXT, title TEXT, description TEXT, thumbnail TEXT,
+			example TEXT, content TEXT, html_content TEXT, search_text TEXT, published BOOLEAN DEFAULT false,
+			status TEXT DEFAULT 'DRAFT', published_at DATETIME, publish_at DATETIME, unpublish_at DATETIME,
+			key TEXT, visibility TEXT DEFAULT 'public', likes INTEGER DEFAULT 0, views INTEGER DEFAULT 0,
+			read_time REAL DEFAULT 0, ai_chat_open BOOLEAN DEFAULT false, ai_ready BOOLEAN DEFAULT false,
+			comments_locked BOOLEAN DEFAULT false, version INTEGER NOT NULL DEFAULT 1, author_id TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_tags (post_id TEXT, tag_id INTEGER)`,
+		`CREATE TABLE categories (id INTEGER PRIMARY KEY, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_categories (post_id TEXT, category_id INTEGER)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db, post.NewPostRepository(db)
+}
+
+func insertEditorPost(t *testing.T, db *gorm.DB, version int) (uuid.UUID, string) {
+	authorID := uuid.New()
+	postID := uuid.New()
+	shortSlug := "draft-" + authorID.String()
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, authorID.String(), "writer").Error)
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, slug, short_slug, title, content, author_id, version) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		postID.String(), shortSlug, shortSlug, "Title", `{"type":"doc"}`, authorID.String(), version).Error)
+	return postID, shortSlug
+}
+
+// Test case: ทุกช่องทางที่ editor โหลดโพสต์ต้องได้ version จริง ไม่ใช่ 0
+func TestPostRepository_EditorQueriesLoadVersion(t *testing.T) {
+	db, repo := newEditorRepository(t)
+	postID, shortSlug := insertEditorPost(t, db, 7)
+
+	byShortSlug, err := repo.GetByShortSlug(shortSlug)
+	require.NoError(t, err)
+	assert.Equal(t, 7, byShortSlug.Version)
+
+	byID, err := repo.GetByID(postID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 7, byID.Version)
+
+	bySlug, err := repo.GetBySlug(shortSlug)
+	require.NoError(t, err)
+	assert.Equal(t, 7, bySlug.Version)
+}
+
+// Test case: Update เช็ค version ใน UPDATE เดียวกัน และเพิ่ม version เมื่อเนื้อหาเปลี่ยน
+func TestPostRepository_UpdateChecksVersion(t *testing.T) {
+	db, repo := newEditorRepository(t)
+	_, shortSlug := insertEditorPost(t, db, 1)
+
+	first, err := repo.GetByShortSlug(shortSlug)
+	require.NoError(t, err)
+	second, err := repo.GetByShortSlug(shortSlug)
+	require.NoError(t, err)
+
+	first.Content = `{"type":"doc","content":[{"type":"paragraph"}]}`
+	require.NoError(t, repo.Update(first))
+	assert.Equal(t, 2, first.Version)
+
+	// แท็บที่สองยังถือ version 1 อยู่
+	second.Content = `{"type":"doc","content":[]}`
+	assert.ErrorIs(t, repo.Update(second), errs.ErrPostVersionConflict)
+
+	// โพสต์ที่ไม่ได้โหลด version มาก็แก้เนื้อหาไม่ได้
+	blind := &models.Post{ID: first.ID, Title: "Blind overwrite"}
+	assert.ErrorIs(t, repo.Update(blind), errs.ErrPostVersionConflict)
+
+	current, err := repo.GetByShortSlug(shortSlug)
+	require.NoError(t, err)
+	assert.Equal(t, 2, current.Version)
+	assert.Equal(t, first.Content, current.Content)
+	assert.Equal(t, "Title", current.Title)
+
+	// การเปลี่ยนที่ไม่ใช่เนื้อหา (เช่น AI mode) ไม่ต้องเช็คและไม่เพิ่ม version
+	require.NoError(t, repo.Update(&models.Post{ID: current.ID, AIChatOpen: true}))
+	reloaded, err := repo.GetByID(current.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 2, reloaded.Version)
+	assert.True(t, reloaded.AIChatOpen)
+}
+
+// Test case: โพสต์ที่ร่วมเขียนต้องตรงกับ <short>-<id เจ้าของ> ทั้งค่า ไม่ใช่แค่ prefix
+func TestPostRepository_GetCollaboratedPostByShortSlug(t *testing.T) {
+	db, repo := newEditorRepository(t)
+	require.NoError(t, db.Exec(`CREATE TABLE post_collaborators (
+		id INTEGER PRIMARY KEY, post_id TEXT, user_id TEXT, role TEXT, status TEXT, invited_by_id TEXT,
+		accepted_at DATETIME, created_at DATETIME, updated_at DATETIME)`).Error)
+
+	collaborator := uuid.New()
+	insert := func(shortSlug string, status models.CollaboratorStatus, updatedAt time.Time) uuid.UUID {
+		ownerID, postID := uuid.New(), uuid.New()
+		require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, ownerID.String(), "owner").Error)
+		require.NoError(t, db.Exec(`INSERT INTO posts (id, slug, short_slug, title, content, author_id, updated_at) VALUES (?, ?, ?, ?, '{}', ?, ?)`,
+			postID.String(), shortSlug, shortSlug+"-"+ownerID.String(), shortSlug, ownerID.String(), updatedAt).Error)
+		require.NoError(t, db.Exec(`INSERT INTO post_collaborators (post_id, user_id, role, status, invited_by_id) VALUES (?, ?, ?, ?, ?)`,
+			postID.String(), collaborator.String(), models.CollaboratorEditor, status, ownerID.String()).Error)
+		return postID
+	}
+
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	longer := insert("draft-copy", models.CollaboratorAccepted, base.Add(time.Hour))
+	pending := insert("pending", models.CollaboratorPending, base)
+
+	// "draft" เป็น prefix ของ "draft-copy-<id>" แต่ไม่ใช่โพสต์เดียวกัน
+	_, err := repo.GetCollaboratedPostByShortSlug("draft", collaborator.String())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	found, err := repo.GetCollaboratedPostByShortSlug("draft-copy", collaborator.String())
+	require.NoError(t, err)
+	assert.Equal(t, longer, found.ID)
+
+	// wildcard ของ LIKE ไม่มีผลแล้ว
+	_, err = repo.GetCollaboratedPostByShortSlug("draft%", collaborator.String())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = repo.GetCollaboratedPostByShortSlug("pending", collaborator.String())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "pending invitations grant no access (%s)", pending)
+
+	_, err = repo.GetCollaboratedPostByShortSlug("draft-copy", uuid.NewString())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// เจ้าของสองคนใช้ short เดียวกัน ได้โพสต์ที่แก้ล่าสุดทุกครั้ง
+	insert("shared", models.CollaboratorAccepted, base)
+	newest := insert("shared", models.CollaboratorAccepted, base.Add(2*time.Hour))
+	for i := 0; i < 3; i++ {
+		found, err = repo.GetCollaboratedPostByShortSlug("shared", collaborator.String())
+		require.NoError(t, err)
+		assert.Equal(t, newest, found.ID)
+	}
+}
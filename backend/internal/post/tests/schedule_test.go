@@ -0,0 +1,254 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// recordingListener เก็บโพสต์ที่ถูกแจ้งผ่าน PublicationListener
+type recordingListener struct {
+	published   []uuid.UUID
+	unpublished []uuid.UUID
+}
+
+func (l *recordingListener) OnPostPublished(p *models.Post) {
+	l.published = append(l.published, p.ID)
+}
+
+func (l *recordingListener) OnPostUnpublished(p *models.Post) {
+	l.unpublished = append(l.unpublished, p.ID)
+}
+
+func newScheduleService() (*post.PostService, *MockPostRepository, *recordingListener) {
+	logger.Log = zap.NewNop()
+	repo := new(MockPostRepository)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+	listener := &recordingListener{}
+	service.AddPublicationListener(listener)
+	return service, repo, listener
+}
+
+// Test case: task ที่ถูกปลุกขึ้นมาหลังโพสต์ถูกลบ ยกเลิก เลื่อนเวลา หรือ publish ไปแล้ว ต้องถูกข้ามโดยไม่แตะโพสต์
+func TestPublishScheduledPost_SkipsStaleTasks(t *testing.T) {
+	runAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	later := runAt.Add(time.Hour)
+
+	cases := []struct {
+		name string
+		post *models.Post
+	}{
+		{"deleted", nil},
+		{"cancelled", &models.Post{Status: models.PostDraft}},
+		{"rescheduled later", &models.Post{Status: models.PostScheduled, PublishAt: &later}},
+		{"schedule cleared", &models.Post{Status: models.PostScheduled}},
+		{"published manually", &models.Post{Status: models.PostPublished, Published: true, PublishAt: &runAt}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo, listener := newScheduleService()
+			postID := uuid.New()
+			if tc.post == nil {
+				repo.On("GetByID", postID.String()).Return(nil, gorm.ErrRecordNotFound)
+			} else {
+				tc.post.ID = postID
+				repo.On("GetByID", postID.String()).Return(tc.post, nil)
+			}
+
+			published, err := service.PublishScheduledPost(postID.String(), runAt)
+
+			assert.NoError(t, err, "stale tasks must not be retried")
+			assert.Nil(t, published)
+			repo.AssertNotCalled(t, "Update", mock.Anything)
+			repo.AssertNotCalled(t, "DeleteEmbeddingsByPostID", mock.Anything)
+			assert.Empty(t, listener.published)
+		})
+	}
+}
+
+// Test case: payload เก็บเวลาละเอียดกว่าวินาที แต่ DB เก็บแบบตัดวินาที ต้องถือว่าเป็น task เดียวกัน
+func TestPublishScheduledPost_MatchesRunAtToTheSecond(t *testing.T) {
+	service, repo, listener := newScheduleService()
+	publishAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	existing := &models.Post{
+		ID:        uuid.New(),
+		AuthorID:  uuid.New(),
+		Status:    models.PostScheduled,
+		PublishAt: &publishAt,
+		Content:   `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Hi"}]}]}`,
+	}
+
+	repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+	repo.On("DeleteEmbeddingsByPostID", existing.ID.String()).Return(nil)
+	repo.On("Update", existing).Return(nil)
+	repo.On("UpdateSchedule", existing.ID.String(), (*time.Time)(nil), (*time.Time)(nil)).Return(nil)
+	repo.On("CreateRevision", mock.Anything).Return(nil)
+
+	published, err := service.PublishScheduledPost(existing.ID.String(), publishAt.Add(400*time.Millisecond))
+
+	require.NoError(t, err)
+	assert.Same(t, existing, published)
+	assert.Equal(t, models.PostPublished, existing.Status)
+	assert.Equal(t, []uuid.UUID{existing.ID}, listener.published)
+	repo.AssertExpectations(t)
+}
+
+func TestPublishScheduledPost_ReturnsLoadErrors(t *testing.T) {
+	service, repo, _ := newScheduleService()
+	postID := uuid.NewString()
+	failure := errors.New("db down")
+	repo.On("GetByID", postID).Return(nil, failure)
+
+	published, err := service.PublishScheduledPost(postID, time.Now())
+
+	assert.ErrorIs(t, err, failure, "transient errors must be retried by asynq")
+	assert.Nil(t, published)
+}
+
+// Test case: ถึงเวลา unpublish_at โพสต์กลับเป็น DRAFT ล้างเวลาที่ตั้งไว้ ลบ embedding และแจ้ง listener
+func TestUnpublishScheduledPost_UnpublishesDuePost(t *testing.T) {
+	service, repo, listener := newScheduleService()
+	publishedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	unpublishAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	existing := &models.Post{
+		ID:          uuid.New(),
+		Status:      models.PostPublished,
+		Published:   true,
+		PublishedAt: &publishedAt,
+		UnpublishAt: &unpublishAt,
+	}
+
+	repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+	repo.On("DeleteEmbeddingsByPostID", existing.ID.String()).Return(nil)
+	repo.On("UnpublishPost", mock.MatchedBy(func(p *models.Post) bool {
+		return !p.Published && p.Status == models.PostDraft && p.PublishAt == nil && p.UnpublishAt == nil
+	})).Return(nil)
+
+	unpublished, err := service.UnpublishScheduledPost(existing.ID.String(), unpublishAt)
+
+	require.NoError(t, err)
+	assert.Same(t, existing, unpublished)
+	assert.Equal(t, []uuid.UUID{existing.ID}, listener.unpublished)
+	repo.AssertExpectations(t)
+}
+
+func TestUnpublishScheduledPost_SkipsStaleTasks(t *testing.T) {
+	runAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	later := runAt.Add(time.Hour)
+
+	cases := []struct {
+		name string
+		post *models.Post
+	}{
+		{"deleted", nil},
+		{"already unpublished", &models.Post{Status: models.PostDraft, UnpublishAt: &runAt}},
+		{"moved back to scheduled", &models.Post{Status: models.PostScheduled, UnpublishAt: &runAt}},
+		{"unpublish postponed", &models.Post{Status: models.PostPublished, Published: true, UnpublishAt: &later}},
+		{"unpublish cancelled", &models.Post{Status: models.PostPublished, Published: true}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo, listener := newScheduleService()
+			postID := uuid.New()
+			if tc.post == nil {
+				repo.On("GetByID", postID.String()).Return(nil, gorm.ErrRecordNotFound)
+			} else {
+				tc.post.ID = postID
+				repo.On("GetByID", postID.String()).Return(tc.post, nil)
+			}
+
+			unpublished, err := service.UnpublishScheduledPost(postID.String(), runAt)
+
+			assert.NoError(t, err)
+			assert.Nil(t, unpublished)
+			repo.AssertNotCalled(t, "UnpublishPost", mock.Anything)
+			repo.AssertNotCalled(t, "DeleteEmbeddingsByPostID", mock.Anything)
+			assert.Empty(t, listener.unpublished)
+		})
+	}
+}
+
+// Test case: unpublish เองก่อนเวลาต้องล้าง unpublish_at ด้วย task ที่ค้างอยู่จึงกลายเป็น stale
+func TestUnpublishPost_ClearsPendingSchedule(t *testing.T) {
+	service, repo, listener := newScheduleService()
+	user := &models.User{ID: uuid.New()}
+	unpublishAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	existing := &models.Post{
+		ID:          uuid.New(),
+		AuthorID:    user.ID,
+		ShortSlug:   "live-" + user.ID.String(),
+		Status:      models.PostPublished,
+		Published:   true,
+		UnpublishAt: &unpublishAt,
+	}
+
+	repo.On("GetByShortSlug", existing.ShortSlug).Return(existing, nil)
+	repo.On("DeleteEmbeddingsByPostID", existing.ID.String()).Return(nil)
+	repo.On("UnpublishPost", existing).Return(nil)
+
+	require.NoError(t, service.UnpublishPost(user, "live"))
+	assert.Nil(t, existing.UnpublishAt)
+	assert.Equal(t, models.PostDraft, existing.Status)
+	assert.Equal(t, []uuid.UUID{existing.ID}, listener.unpublished)
+
+	// task เดิมตื่นขึ้นมาทีหลังจะไม่ทำอะไร
+	repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+	stale, err := service.UnpublishScheduledPost(existing.ID.String(), unpublishAt)
+	assert.NoError(t, err)
+	assert.Nil(t, stale)
+	repo.AssertNumberOfCalls(t, "UnpublishPost", 1)
+}
+
+// Test case: UpdateSchedule และ UnpublishPost ต้องเขียนค่า NULL ลง DB จริง ไม่ข้ามเพราะเป็นค่า zero
+func TestPostRepository_ScheduleColumns(t *testing.T) {
+	db, repo := newEditorRepository(t)
+	postID, _ := insertEditorPost(t, db, 1)
+
+	publishAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	unpublishAt := publishAt.Add(24 * time.Hour)
+	require.NoError(t, repo.UpdateSchedule(postID.String(), &publishAt, &unpublishAt))
+
+	stored, err := repo.GetByID(postID.String())
+	require.NoError(t, err)
+	require.NotNil(t, stored.PublishAt)
+	require.NotNil(t, stored.UnpublishAt)
+	assert.True(t, publishAt.Equal(*stored.PublishAt))
+	assert.True(t, unpublishAt.Equal(*stored.UnpublishAt))
+
+	require.NoError(t, repo.UpdateSchedule(postID.String(), nil, &unpublishAt))
+	stored, err = repo.GetByID(postID.String())
+	require.NoError(t, err)
+	assert.Nil(t, stored.PublishAt)
+	assert.NotNil(t, stored.UnpublishAt)
+
+	publishedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Exec(`UPDATE posts SET published = ?, status = ?, published_at = ? WHERE id = ?`,
+		true, models.PostPublished, publishedAt, postID.String()).Error)
+
+	require.NoError(t, repo.UnpublishPost(&models.Post{ID: postID, Status: models.PostDraft}))
+
+	var row struct {
+		Published   bool
+		Status      string
+		PublishAt   *time.Time
+		UnpublishAt *time.Time
+	}
+	require.NoError(t, db.Raw(`SELECT published, status, publish_at, unpublish_at FROM posts WHERE id = ?`, postID.String()).Scan(&row).Error)
+	assert.False(t, row.Published)
+	assert.Equal(t, string(models.PostDraft), row.Status)
+	assert.Nil(t, row.PublishAt)
+	assert.Nil(t, row.UnpublishAt)
+}
@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type capturedQuery struct {
+	SQL  string
+	Vars []interface{}
+}
+
+// newDryRunRepository PostRepository บน dialect ของ postgres ที่ไม่ต่อ DB จริง ใช้ตรวจ SQL ของการค้นหา
+// ซึ่งใช้ tsvector / trigram ที่ SQLite ไม่รองรับ
+func newDryRunRepository(t *testing.T) (post.PostRepositoryInterface, *[]capturedQuery) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 dbname=dry"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+
+	var queries []capturedQuery
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, capturedQuery{
+			SQL:  tx.Statement.SQL.String(),
+			Vars: append([]interface{}(nil), tx.Statement.Vars...),
+		})
+	}))
+	return post.NewPostRepository(db), &queries
+}
+
+// whereClause ตัดเฉพาะส่วน WHERE ของ SQL (ก่อน ORDER BY / LIMIT)
+func whereClause(sql string) string {
+	_, where, ok := strings.Cut(sql, " WHERE ")
+	if !ok {
+		return ""
+	}
+	for _, end := range []string{" ORDER BY ", " LIMIT ", " OFFSET "} {
+		if i := strings.Index(where, end); i >= 0 {
+			where = where[:i]
+		}
+	}
+	return where
+}
+
+func findQuery(t *testing.T, queries []capturedQuery, prefix string) capturedQuery {
+	t.Helper()
+	for _, q := range queries {
+		if strings.HasPrefix(q.SQL, prefix) {
+			return q
+		}
+	}
+	require.Failf(t, "query not found", "no query starting with %q in %v", prefix, queries)
+	return capturedQuery{}
+}
+
+// Test case: total ต้องนับด้วยเงื่อนไขเดียวกับแถวที่ส่งกลับ ทั้ง published, listed, ถังขยะ และคำค้น
+func TestListPublished_SearchCountUsesSamePredicate(t *testing.T) {
+	repo, queries := newDryRunRepository(t)
+
+	_, err := repo.ListPublished(post.PostListQuery{Limit: 10, Search: "  golang  ", Count: true})
+	require.NoError(t, err)
+
+	rows := findQuery(t, *queries, `SELECT "id"`)
+	count := findQuery(t, *queries, "SELECT count(*)")
+
+	where := whereClause(rows.SQL)
+	require.NotEmpty(t, where)
+	assert.Equal(t, where, whereClause(count.SQL))
+	for _, condition := range []string{"published = ", "published_at IS NOT NULL", "status = ", "posts.visibility = ", "deleted_at", "ILIKE"} {
+		assert.Contains(t, where, condition)
+	}
+
+	// ค่าของเงื่อนไขมาก่อนค่าของ ORDER BY / LIMIT จึงต้องเป็น prefix เดียวกัน
+	require.GreaterOrEqual(t, len(rows.Vars), len(count.Vars))
+	assert.Equal(t, count.Vars, rows.Vars[:len(count.Vars)])
+	assert.Contains(t, count.Vars, "golang", "search is trimmed before it reaches SQL")
+}
+
+func TestListPublished_SearchEscapesLikeWildcards(t *testing.T) {
+	cases := []struct {
+		search  string
+		pattern string
+	}{
+		{"golang", "%golang%"},
+		{"100%", `%100\%%`},
+		{"snake_case", `%snake\_case%`},
+		{`C:\path`, `%C:\\path%`},
+		{"ภาษาไทย", "%ภาษาไทย%"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.search, func(t *testing.T) {
+			repo, queries := newDryRunRepository(t)
+			_, err := repo.ListPublished(post.PostListQuery{Limit: 10, Search: tc.search, Count: true})
+			require.NoError(t, err)
+
+			for _, prefix := range []string{`SELECT "id"`, "SELECT count(*)"} {
+				q := findQuery(t, *queries, prefix)
+				// คำค้นดิบใช้กับ full-text ส่วน ILIKE ได้ pattern ที่ escape แล้ว
+				assert.Contains(t, q.Vars, tc.search, prefix)
+				assert.Contains(t, q.Vars, tc.pattern, prefix)
+			}
+		})
+	}
+}
+
+// Test case: pattern ที่ escape แล้ว match เฉพาะข้อความตรงตัว ไม่ใช่ wildcard
+func TestEscapeLike_MatchesLiterally(t *testing.T) {
+	db, _ := newEditorRepository(t)
+
+	cases := []struct {
+		text   string
+		search string
+		match  bool
+	}{
+		{"save 100% today", "100%", true},
+		{"save 1000 today", "100%", false},
+		{"use snake_case", "snake_case", true},
+		{"use snakeXcase", "snake_case", false},
+		{`C:\path\to`, `C:\path`, true},
+	}
+
+	for _, tc := range cases {
+		var matched bool
+		// SQLite ไม่มี escape character เริ่มต้นเหมือน postgres จึงต้องระบุ ESCAPE
+		require.NoError(t, db.Raw(`SELECT ? LIKE ? ESCAPE '\'`, tc.text, "%"+utils.EscapeLike(tc.search)+"%").Scan(&matched).Error)
+		assert.Equal(t, tc.match, matched, "%q in %q", tc.search, tc.text)
+	}
+}
+
+// Test case: ผลการค้นหาเรียงตาม rank ของ full-text ก่อน trigram similarity และเวลา publish
+// และ cursor ถูกข้ามเพราะลำดับตามความเกี่ยวข้องใช้ keyset ไม่ได้
+func TestListPublished_SearchRanksByRelevance(t *testing.T) {
+	repo, queries := newDryRunRepository(t)
+	after := &post.PostPosition{PublishedAt: time.Now(), ID: uuid.New()}
+
+	result, err := repo.ListPublished(post.PostListQuery{Limit: 5, Offset: 10, Search: "go", After: after})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Page)
+
+	rows := findQuery(t, *queries, `SELECT "id"`)
+	assert.Contains(t, rows.SQL, `"search_text"`, "snippets are built from search_text")
+	assert.NotContains(t, whereClause(rows.SQL), "(posts.published_at, posts.id) <")
+
+	_, order, ok := strings.Cut(rows.SQL, " ORDER BY ")
+	require.True(t, ok)
+	rank := strings.Index(order, "ts_rank(")
+	similarity := strings.Index(order, "similarity(")
+	published := strings.Index(order, "posts.published_at DESC")
+	assert.True(t, rank >= 0 && rank < similarity && similarity < published, order)
+	assert.Equal(t, 1, strings.Count(order, "posts.published_at DESC"), order)
+
+	for _, q := range *queries {
+		assert.False(t, strings.HasPrefix(q.SQL, "SELECT count(*)"), "Count: false must skip COUNT(*)")
+	}
+}
+
+func insertListedPost(t *testing.T, db *gorm.DB, title string, publishedAt time.Time, mutate string) uuid.UUID {
+	authorID, postID := uuid.New(), uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, authorID.String(), "author-"+title).Error)
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, slug, short_slug, title, content, author_id, published, status, published_at, visibility)
+		VALUES (?, ?, ?, ?, '{}', ?, true, ?, ?, 'public')`,
+		postID.String(), title, title+"-"+authorID.String(), title, authorID.String(), models.PostPublished, publishedAt).Error)
+	if mutate != "" {
+		require.NoError(t, db.Exec(`UPDATE posts SET `+mutate+` WHERE id = ?`, postID.String()).Error)
+	}
+	return postID
+}
+
+// Test case: ไม่มีคำค้น total และแถวที่ได้ต้องกรองโพสต์ชุดเดียวกัน รวมถึงการแบ่งหน้าแบบ keyset
+func TestListPublished_CountMatchesRows(t *testing.T) {
+	db, repo := newEditorRepository(t)
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	insertListedPost(t, db, "oldest", base, "")
+	insertListedPost(t, db, "middle", base.Add(time.Hour), "")
+	insertListedPost(t, db, "newest", base.Add(2*time.Hour), "")
+	insertListedPost(t, db, "unlisted", base.Add(3*time.Hour), "visibility = 'unlisted'")
+	insertListedPost(t, db, "protected", base.Add(3*time.Hour), "visibility = 'protected'")
+	insertListedPost(t, db, "draft", base.Add(3*time.Hour), "published = false, status = 'DRAFT'")
+	insertListedPost(t, db, "scheduled", base.Add(3*time.Hour), "published = false, status = 'SCHEDULED'")
+	insertListedPost(t, db, "no-date", base.Add(3*time.Hour), "published_at = NULL")
+	insertListedPost(t, db, "trashed", base.Add(3*time.Hour), "deleted_at = '2026-03-02 00:00:00'")
+
+	all, err := repo.ListPublished(post.PostListQuery{Limit: 10, Count: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), all.Total)
+	require.Len(t, all.Posts, 3)
+	assert.Equal(t, "newest", all.Posts[0].Title)
+	assert.False(t, all.HasNext)
+
+	first, err := repo.ListPublished(post.PostListQuery{Limit: 2, Count: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), first.Total)
+	assert.True(t, first.HasNext)
+	assert.Equal(t, []string{"newest", "middle"}, []string{first.Posts[0].Title, first.Posts[1].Title})
+
+	last := first.Posts[1]
+	next, err := repo.ListPublished(post.PostListQuery{Limit: 2, After: &post.PostPosition{PublishedAt: *last.PublishedAt, ID: last.ID}})
+	require.NoError(t, err)
+	require.Len(t, next.Posts, 1)
+	assert.Equal(t, "oldest", next.Posts[0].Title)
+	assert.False(t, next.HasNext)
+	assert.Zero(t, next.Total, "Count: false leaves the total empty")
+}
@@ -1,18 +1,28 @@
 package tests
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"mime/multipart"
 	"rag-searchbot-backend/internal/media"
 	"rag-searchbot-backend/internal/models"
 	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/crypto"
+	"rag-searchbot-backend/pkg/errs"
 	"rag-searchbot-backend/pkg/logger"
+	"rag-searchbot-backend/pkg/sanitize"
+	"rag-searchbot-backend/pkg/tiptap"
 
 	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -34,6 +44,10 @@ func (m *MockPostRepository) GetAll(limit, offset int, search string) (*post.Pos
 	args := m.Called(limit, offset, search)
 	return args.Get(0).(*post.PostRepositoryQuery), args.Error(1)
 }
+func (m *MockPostRepository) ListPublished(query post.PostListQuery) (*post.PostRepositoryQuery, error) {
+	args := m.Called(query)
+	return args.Get(0).(*post.PostRepositoryQuery), args.Error(1)
+}
 func (m *MockPostRepository) GetByID(id string) (*models.Post, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -72,6 +86,25 @@ func (m *MockPostRepository) UnpublishPost(post *models.Post) error {
 	args := m.Called(post)
 	return args.Error(0)
 }
+func (m *MockPostRepository) GetTrashedPosts(authorID string) ([]models.Post, error) {
+	args := m.Called(authorID)
+	return args.Get(0).([]models.Post), args.Error(1)
+}
+func (m *MockPostRepository) GetTrashedPostByID(id string) (*models.Post, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Post), args.Error(1)
+}
+func (m *MockPostRepository) RestorePost(post *models.Post) error {
+	args := m.Called(post)
+	return args.Error(0)
+}
+func (m *MockPostRepository) PurgeDeletedPosts(deletedBefore time.Time) (int64, error) {
+	args := m.Called(deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
 func (m *MockPostRepository) DeletePost(post *models.Post) error {
 	args := m.Called(post)
 	return args.Error(0)
@@ -98,8 +131,8 @@ func (m *MockPostRepository) BulkInsertEmbeddings(post *models.Post, embeddings
 }
 
 // เพิ่ม method ใหม่ที่ขาดหายไป
-func (m *MockPostRepository) RecordPostView(postID string, userID *string, fingerprint string, ipAddress, userAgent string) error {
-	args := m.Called(postID, userID, fingerprint, ipAddress, userAgent)
+func (m *MockPostRepository) RecordPostView(postID string, userID *string, fingerprint string, ipAddress, userAgent, referrer string) error {
+	args := m.Called(postID, userID, fingerprint, ipAddress, userAgent, referrer)
 	return args.Error(0)
 }
 
@@ -114,10 +147,125 @@ func (m *MockPostRepository) GetPopularPosts(limit int) ([]models.Post, error) {
 	return args.Get(0).([]models.Post), args.Error(1)
 }
 
+func (m *MockPostRepository) RecomputeTrendingScores(window string, since time.Time) (int64, error) {
+	args := m.Called(window, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostRepository) GetTrendingPosts(window string, limit int) ([]models.Post, error) {
+	args := m.Called(window, limit)
+	return args.Get(0).([]models.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) GetBookmarkedPostIDs(userID string, postIDs []uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(userID, postIDs)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockPostRepository) CountBookmarks(postIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	args := m.Called(postIDs)
+	return args.Get(0).(map[uuid.UUID]int64), args.Error(1)
+}
+
 // Add missing method GetPublishedPostsByAuthor
-func (m *MockPostRepository) GetPublishedPostsByAuthor(username string, page, limit int) ([]models.Post, int64, error) {
-	args := m.Called(username, page, limit)
-	return args.Get(0).([]models.Post), args.Get(1).(int64), args.Error(2)
+func (m *MockPostRepository) GetPublishedPostsByAuthor(username string, query post.PostListQuery) (*post.PostRepositoryQuery, error) {
+	args := m.Called(username, query)
+	return args.Get(0).(*post.PostRepositoryQuery), args.Error(1)
+}
+
+func (m *MockPostRepository) CreateRevision(revision *models.PostRevision) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) GetLatestRevision(postID string) (*models.PostRevision, error) {
+	args := m.Called(postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRepository) GetRevisionsByPostID(postID string) ([]models.PostRevision, error) {
+	args := m.Called(postID)
+	return args.Get(0).([]models.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRepository) GetRevisionByID(postID string, revisionID uint) (*models.PostRevision, error) {
+	args := m.Called(postID, revisionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRepository) PruneRevisions(keepLast int) (int64, error) {
+	args := m.Called(keepLast)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostRepository) UpdateSchedule(postID string, publishAt, unpublishAt *time.Time) error {
+	args := m.Called(postID, publishAt, unpublishAt)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) UpdateVisibility(postID string, visibility models.PostVisibility, keyHash string) error {
+	args := m.Called(postID, visibility, keyHash)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) ReplacePostTags(p *models.Post, names []string) error {
+	args := m.Called(p, names)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) CreateImportedPost(p *models.Post, tags []string, imageIDs []uuid.UUID) error {
+	args := m.Called(p, tags, imageIDs)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) ReplacePostCategories(p *models.Post, names []string) error {
+	args := m.Called(p, names)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) GetPublishedPostsByTag(name string, limit, offset int) (*post.PostRepositoryQuery, error) {
+	args := m.Called(name, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*post.PostRepositoryQuery), args.Error(1)
+}
+
+func (m *MockPostRepository) GetPublishedPostsByCategory(name string, limit, offset int) (*post.PostRepositoryQuery, error) {
+	args := m.Called(name, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*post.PostRepositoryQuery), args.Error(1)
+}
+
+func (m *MockPostRepository) GetPublishedPostsByIDs(ids []uuid.UUID) ([]models.Post, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) GetFeedPosts(query post.FeedQuery) ([]models.Post, error) {
+	args := m.Called(query)
+	return args.Get(0).([]models.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) GetCollaboratorRole(postID, userID string) (models.CollaboratorRole, error) {
+	args := m.Called(postID, userID)
+	return args.Get(0).(models.CollaboratorRole), args.Error(1)
+}
+
+func (m *MockPostRepository) GetCollaboratedPostByShortSlug(shortSlug, userID string) (*models.Post, error) {
+	args := m.Called(shortSlug, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Post), args.Error(1)
 }
 
 // Mock for MediaServiceInterface (minimal for this test)
@@ -126,7 +274,11 @@ type MockMediaService struct {
 }
 
 func (m *MockMediaService) CreateMedia(fileHeader *multipart.FileHeader, user *models.User, postID *uuid.UUID) (*models.ImageUpload, error) {
-	return nil, nil
+	args := m.Called(fileHeader, user, postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImageUpload), args.Error(1)
 }
 func (m *MockMediaService) DeleteFromChibisafe(image *models.ImageUpload) error {
 	return nil
@@ -163,22 +315,31 @@ func TestPublishPostPublishesWithoutAIReview(t *testing.T) {
 		Slug:      shortSlug + "-" + user.ID.String(),
 		ShortSlug: shortSlug + "-" + user.ID.String(),
 		AuthorID:  user.ID,
+		Content:   `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Hello world"}]}]}`,
 	}
 	html := "<p>Hello world</p>"
+	// HTML ที่ client ส่งมาต้องไม่ถูกใช้ เพราะ server render จาก content เอง
+	clientHTML := "<p>Injected</p><script>alert(1)</script>"
 
 	repo.On("GetByShortSlug", existing.ShortSlug).Return(existing, nil)
 	repo.On("GetBySlug", "hello-world").Return(nil, gorm.ErrRecordNotFound)
 	repo.On("DeleteEmbeddingsByPostID", existing.ID.String()).Return(nil)
 	repo.On("Update", existing).Return(nil)
+	repo.On("UpdateSchedule", existing.ID.String(), (*time.Time)(nil), (*time.Time)(nil)).Return(nil)
+	repo.On("CreateRevision", mock.MatchedBy(func(rev *models.PostRevision) bool {
+		return rev.PostID == existing.ID && rev.Reason == models.RevisionPublish && rev.HTMLContent != nil && *rev.HTMLContent == html
+	})).Return(nil)
 
 	service := post.NewPostService(repo, media, &post.TaskEnqueuer{}).(*post.PostService)
-	err := service.PublishPost(&post.PublishPostRequestDTO{
+	rejected, err := service.PublishPost(&post.PublishPostRequestDTO{
 		Slug:        "hello-world",
 		Title:       "Hello world",
-		HTMLContent: &html,
+		HTMLContent: &clientHTML,
 	}, user, shortSlug)
 
 	assert.NoError(t, err)
+	assert.Empty(t, rejected)
+	assert.Equal(t, html, *existing.HTMLContent)
 	assert.True(t, existing.Published)
 	assert.Equal(t, models.PostPublished, existing.Status)
 	assert.NotNil(t, existing.PublishedAt)
@@ -199,10 +360,11 @@ func TestCreatePost_UpdateExisting(t *testing.T) {
 		ShortSlug: "testslug",
 		Title:     "Updated Title",
 		Content:   content,
+		Version:   1,
 	}
 
 	slug := postReq.ShortSlug + "-" + user.ID.String()
-	existing := &models.Post{ID: uuid.New(), Slug: slug, ShortSlug: slug}
+	existing := &models.Post{ID: uuid.New(), Slug: slug, ShortSlug: slug, AuthorID: user.ID, Version: 1}
 
 	repo.On("GetByShortSlug", slug).Return(existing, nil)
 	repo.On("Update", mock.AnythingOfType("*models.Post")).Return(nil)
@@ -212,9 +374,15 @@ func TestCreatePost_UpdateExisting(t *testing.T) {
 	// Mock media service calls for UpdateImageUsageStatus
 	media.On("GetImagesByPostID", existing.ID).Return([]models.ImageUpload{}, nil)
 
-	id, err := service.CreatePost(postReq, user)
+	// ทุกการบันทึกต้องเก็บ revision
+	repo.On("GetLatestRevision", existing.ID.String()).Return(nil, nil)
+	repo.On("CreateRevision", mock.MatchedBy(func(rev *models.PostRevision) bool {
+		return rev.PostID == existing.ID && rev.Reason == models.RevisionSave && rev.Title == "Updated Title"
+	})).Return(nil)
+
+	saved, err := service.CreatePost(postReq, user)
 	assert.NoError(t, err)
-	assert.Equal(t, existing.ID.String(), id)
+	assert.Equal(t, existing.ID.String(), saved.PostID)
 
 	repo.AssertExpectations(t)
 }
@@ -235,11 +403,11 @@ func TestRecordPostView_Success(t *testing.T) {
 
 	// Mock expectations
 	repo.On("GetByID", postID).Return(&models.Post{ID: uuid.MustParse(postID)}, nil)
-	repo.On("RecordPostView", postID, mock.AnythingOfType("*string"), fingerprint, ipAddress, userAgent).Return(nil)
+	repo.On("RecordPostView", postID, mock.AnythingOfType("*string"), fingerprint, ipAddress, userAgent, "google.com").Return(nil)
 	repo.On("GetPostViews", postID).Return(42, nil)
 
-	// Test
-	result, err := service.RecordPostView(postID, user, fingerprint, ipAddress, userAgent)
+	// Test (referrer ถูกเก็บเป็น host ตัด www. ออก)
+	result, err := service.RecordPostView(postID, user, "", fingerprint, ipAddress, userAgent, "https://www.Google.com/search?q=go")
 
 	// Assertions
 	assert.NoError(t, err)
@@ -266,11 +434,11 @@ func TestRecordPostView_AnonymousUser(t *testing.T) {
 
 	// Mock expectations
 	repo.On("GetByID", postID).Return(&models.Post{ID: uuid.MustParse(postID)}, nil)
-	repo.On("RecordPostView", postID, (*string)(nil), fingerprint, ipAddress, userAgent).Return(nil)
+	repo.On("RecordPostView", postID, (*string)(nil), fingerprint, ipAddress, userAgent, "").Return(nil)
 	repo.On("GetPostViews", postID).Return(15, nil)
 
 	// Test
-	result, err := service.RecordPostView(postID, nil, fingerprint, ipAddress, userAgent)
+	result, err := service.RecordPostView(postID, nil, "", fingerprint, ipAddress, userAgent, "")
 
 	// Assertions
 	assert.NoError(t, err)
@@ -299,7 +467,7 @@ func TestRecordPostView_PostNotFound(t *testing.T) {
 	repo.On("GetByID", postID).Return(nil, assert.AnError)
 
 	// Test
-	result, err := service.RecordPostView(postID, nil, fingerprint, ipAddress, userAgent)
+	result, err := service.RecordPostView(postID, nil, "", fingerprint, ipAddress, userAgent, "")
 
 	// Assertions
 	assert.Error(t, err)
@@ -363,9 +531,12 @@ func TestCreatePost_NewPost_UpdatesImageUsageStatus(t *testing.T) {
 
 	// Mock expectations
 	repo.On("GetByShortSlug", slug).Return((*models.Post)(nil), gorm.ErrRecordNotFound) // No existing post
+	repo.On("GetCollaboratedPostByShortSlug", postReq.ShortSlug, user.ID.String()).Return(nil, gorm.ErrRecordNotFound)
 	repo.On("Create", mock.AnythingOfType("*models.Post")).Return(postID, nil)
 	repo.On("GetByID", postID).Return(createdPost, nil)
 	// repo.On("DeleteEmbeddingsByPostID", postID).Return(nil)
+	repo.On("GetLatestRevision", postID).Return(nil, nil)
+	repo.On("CreateRevision", mock.AnythingOfType("*models.PostRevision")).Return(nil)
 
 	// Mock media service calls for UpdateImageUsageStatus
 	media.On("GetImagesByPostID", createdPost.ID).Return([]models.ImageUpload{imageUpload}, nil)
@@ -375,11 +546,12 @@ func TestCreatePost_NewPost_UpdatesImageUsageStatus(t *testing.T) {
 	})).Return(nil)
 
 	// Test
-	id, err := service.CreatePost(postReq, user)
+	saved, err := service.CreatePost(postReq, user)
 
 	// Assertions
 	assert.NoError(t, err)
-	assert.Equal(t, postID, id)
+	assert.Equal(t, postID, saved.PostID)
+	assert.Equal(t, 1, saved.Version)
 
 	repo.AssertExpectations(t)
 	media.AssertExpectations(t)
@@ -591,3 +763,640 @@ func TestGetPopularPosts_DifferentLimits(t *testing.T) {
 
 	repo.AssertExpectations(t)
 }
+
+// Test case: trending ใช้ window เริ่มต้นเมื่อไม่ระบุ และปฏิเสธ window ที่ไม่รองรับ
+func TestGetTrendingPosts_ValidatesWindow(t *testing.T) {
+	repo := new(MockPostRepository)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	trending := []models.Post{{ID: uuid.New(), Title: "Hot Post", Slug: "hot-post"}}
+	repo.On("GetTrendingPosts", post.DefaultTrendingWindow, 5).Return(trending, nil)
+
+	result, err := service.GetTrendingPosts("", 5)
+	assert.NoError(t, err)
+	assert.Len(t, result.Posts, 1)
+	assert.Equal(t, "Hot Post", result.Posts[0].Title)
+
+	result, err = service.GetTrendingPosts("1y", 5)
+	assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+	assert.Nil(t, result)
+
+	repo.AssertExpectations(t)
+}
+
+// Test case: flag bookmarked มีเฉพาะเมื่อรู้ตัวผู้ใช้
+func TestMarkBookmarked_FlagsPostsForKnownUser(t *testing.T) {
+	repo := new(MockPostRepository)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	user := &models.User{ID: uuid.New()}
+	saved := post.PostSummaryDTO{ID: uuid.New()}
+	other := post.PostSummaryDTO{ID: uuid.New()}
+	posts := []post.PostSummaryDTO{saved, other}
+
+	// ไม่ได้ login: ไม่มี flag และไม่ query
+	service.MarkBookmarked(nil, posts)
+	assert.Nil(t, posts[0].Bookmarked)
+
+	repo.On("GetBookmarkedPostIDs", user.ID.String(), []uuid.UUID{saved.ID, other.ID}).Return([]uuid.UUID{saved.ID}, nil)
+	service.MarkBookmarked(user, posts)
+
+	assert.True(t, *posts[0].Bookmarked)
+	assert.False(t, *posts[1].Bookmarked)
+	repo.AssertExpectations(t)
+}
+
+// Test case: cursor ของหน้าถัดไปพาไปต่อจากโพสต์สุดท้าย และ cursor ที่ถูกแก้ใช้ไม่ได้
+func TestGetPostsByAuthor_CursorPagination(t *testing.T) {
+	repo := new(MockPostRepository)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{})
+
+	publishedAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	last := models.Post{ID: uuid.New(), Title: "Older Post", PublishedAt: &publishedAt}
+
+	repo.On("GetPublishedPostsByAuthor", "john", post.PostListQuery{Limit: 1, Count: false}).
+		Return(&post.PostRepositoryQuery{Limit: 1, Page: 1, HasNext: true, Posts: []models.Post{last}}, nil)
+
+	first, err := service.GetPostsByAuthor("john", post.PostListOptions{Page: 1, Limit: 1})
+	assert.NoError(t, err)
+	assert.True(t, first.Meta.HasNextPage)
+	assert.NotEmpty(t, first.Meta.NextCursor)
+
+	next := post.PostListQuery{Limit: 1, After: &post.PostPosition{PublishedAt: publishedAt, ID: last.ID}}
+	repo.On("GetPublishedPostsByAuthor", "john", next).
+		Return(&post.PostRepositoryQuery{Limit: 1, Page: 1}, nil)
+
+	// page ถูกข้ามเมื่อส่ง cursor มา
+	second, err := service.GetPostsByAuthor("john", post.PostListOptions{Page: 3, Limit: 1, Cursor: first.Meta.NextCursor})
+	assert.NoError(t, err)
+	assert.False(t, second.Meta.HasNextPage)
+	assert.Empty(t, second.Meta.NextCursor)
+
+	_, err = service.GetPostsByAuthor("john", post.PostListOptions{Page: 1, Limit: 1, Cursor: first.Meta.NextCursor + "x"})
+	assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+
+	repo.AssertExpectations(t)
+}
+
+// Mock for post.EmbeddingEnqueuer
+type MockEmbeddingEnqueuer struct {
+	mock.Mock
+}
+
+func (m *MockEmbeddingEnqueuer) EnqueuePostEmbedding(post *models.Post, user *models.User) (*asynq.TaskInfo, error) {
+	args := m.Called(post, user)
+	return nil, args.Error(1)
+}
+
+// Test case: กู้โพสต์จากถังขยะ ผูกรูปในเนื้อหากลับ และสร้าง embedding ใหม่เมื่อเปิด AI mode ไว้
+func TestRestorePost_RelinksImagesAndRequeuesEmbedding(t *testing.T) {
+	logger.Log = zap.NewNop()
+	repo := new(MockPostRepository)
+	media := new(MockMediaService)
+	embeddings := new(MockEmbeddingEnqueuer)
+	service := post.NewPostService(repo, media, &post.TaskEnqueuer{}).(*post.PostService)
+	service.SetEmbeddingEnqueuer(embeddings)
+
+	owner := &models.User{ID: uuid.New()}
+	stranger := &models.User{ID: uuid.New()}
+	trashed := &models.Post{
+		ID:         uuid.New(),
+		AuthorID:   owner.ID,
+		ShortSlug:  "abc-" + owner.ID.String(),
+		Content:    `{"type":"doc","content":[{"type":"image","attrs":{"src":"https://img/a.png"}}]}`,
+		AIChatOpen: true,
+	}
+	image := models.ImageUpload{ID: uuid.New(), ImageURL: "https://img/a.png", IsUsed: false}
+
+	repo.On("GetTrashedPostByID", trashed.ID.String()).Return(trashed, nil)
+	repo.On("GetCollaboratorRole", trashed.ID.String(), stranger.ID.String()).Return(models.CollaboratorRole(""), gorm.ErrRecordNotFound)
+
+	// ผู้อื่นกู้คืนไม่ได้
+	_, err := service.RestorePost(trashed.ID.String(), stranger)
+	assert.ErrorIs(t, err, errs.ErrUnauthorized)
+
+	repo.On("RestorePost", trashed).Return(nil)
+	repo.On("GetByID", trashed.ID.String()).Return(trashed, nil)
+	media.On("GetImagesByPostID", trashed.ID).Return([]models.ImageUpload{image}, nil)
+	media.On("UpdateImageUsage", mock.MatchedBy(func(img *models.ImageUpload) bool {
+		return img.ID == image.ID && img.IsUsed
+	})).Return(nil)
+	embeddings.On("EnqueuePostEmbedding", trashed, owner).Return(nil, nil)
+
+	restored, err := service.RestorePost(trashed.ID.String(), owner)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", restored.ShortSlug)
+
+	// โพสต์ที่ไม่อยู่ในถังขยะ (หรือถูกลบถาวรไปแล้ว)
+	missing := uuid.New().String()
+	repo.On("GetTrashedPostByID", missing).Return(nil, gorm.ErrRecordNotFound)
+	_, err = service.RestorePost(missing, owner)
+	assert.ErrorIs(t, err, errs.ErrPostNotFound)
+
+	repo.AssertExpectations(t)
+	media.AssertExpectations(t)
+	embeddings.AssertExpectations(t)
+}
+
+// Test case: รูปที่เป็น thumbnail ถูกผูกกลับด้วย รูปที่ไม่อยู่ในเนื้อหาแล้วถูกปลด
+// และสร้าง embedding ใหม่เฉพาะโพสต์ที่เปิด AI mode (enqueue ล้มไม่ทำให้กู้คืนล้ม)
+func TestRestorePost_ImageUsageAndEmbeddingRules(t *testing.T) {
+	logger.Log = zap.NewNop()
+	owner := &models.User{ID: uuid.New()}
+	thumbnail := models.ImageUpload{ID: uuid.New(), ImageURL: "https://img/cover.png"}
+	inContent := models.ImageUpload{ID: uuid.New(), ImageURL: "https://img/a.png"}
+	removed := models.ImageUpload{ID: uuid.New(), ImageURL: "https://img/old.png", IsUsed: true}
+
+	cases := []struct {
+		name       string
+		aiChatOpen bool
+		enqueueErr error
+		enqueued   bool
+	}{
+		{"AI mode off", false, nil, false},
+		{"AI mode on", true, nil, true},
+		{"enqueue fails", true, errors.New("redis down"), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockPostRepository)
+			media := new(MockMediaService)
+			embeddings := new(MockEmbeddingEnqueuer)
+			service := post.NewPostService(repo, media, &post.TaskEnqueuer{}).(*post.PostService)
+			service.SetEmbeddingEnqueuer(embeddings)
+
+			trashed := &models.Post{
+				ID:         uuid.New(),
+				AuthorID:   owner.ID,
+				ShortSlug:  "abc-" + owner.ID.String(),
+				Thumbnail:  thumbnail.ImageURL,
+				Content:    `{"type":"doc","content":[{"type":"image","attrs":{"src":"https://img/a.png"}}]}`,
+				AIChatOpen: tc.aiChatOpen,
+			}
+			repo.On("GetTrashedPostByID", trashed.ID.String()).Return(trashed, nil)
+			repo.On("RestorePost", trashed).Return(nil)
+			repo.On("GetByID", trashed.ID.String()).Return(trashed, nil)
+			media.On("GetImagesByPostID", trashed.ID).Return([]models.ImageUpload{thumbnail, inContent, removed}, nil)
+			media.On("UpdateImageUsage", mock.Anything).Return(nil)
+			embeddings.On("EnqueuePostEmbedding", trashed, owner).Return(nil, tc.enqueueErr)
+
+			_, err := service.RestorePost(trashed.ID.String(), owner)
+			require.NoError(t, err)
+
+			usage := map[string]bool{}
+			for _, call := range media.Calls {
+				if call.Method == "UpdateImageUsage" {
+					img := call.Arguments.Get(0).(*models.ImageUpload)
+					usage[img.ImageURL] = img.IsUsed
+					assert.NotNil(t, img.UsedAt, img.ImageURL)
+				}
+			}
+			assert.Equal(t, map[string]bool{thumbnail.ImageURL: true, inContent.ImageURL: true, removed.ImageURL: false}, usage)
+
+			if tc.enqueued {
+				embeddings.AssertCalled(t, "EnqueuePostEmbedding", trashed, owner)
+			} else {
+				embeddings.AssertNotCalled(t, "EnqueuePostEmbedding", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// Test case: autosave ที่เนื้อหาไม่เปลี่ยนต้องไม่สร้าง revision ซ้ำ
+func TestCreatePost_SkipsDuplicateRevision(t *testing.T) {
+	repo := new(MockPostRepository)
+	media := new(MockMediaService)
+	service := post.NewPostService(repo, media, &post.TaskEnqueuer{})
+
+	user := &models.User{ID: uuid.New()}
+	content := post.PostContentStructure{Type: "paragraph", Text: "Hello"}
+	postReq := post.CreatePostRequest{ShortSlug: "same", Title: "Same Title", Content: content, Version: 1}
+
+	slug := postReq.ShortSlug + "-" + user.ID.String()
+	existing := &models.Post{ID: uuid.New(), Slug: slug, ShortSlug: slug, AuthorID: user.ID, Version: 1}
+
+	repo.On("GetByShortSlug", slug).Return(existing, nil)
+	repo.On("Update", mock.AnythingOfType("*models.Post")).Return(nil)
+	repo.On("GetByID", mock.Anything).Return(existing, nil)
+	media.On("GetImagesByPostID", existing.ID).Return([]models.ImageUpload{}, nil)
+	repo.On("GetLatestRevision", existing.ID.String()).Return(&models.PostRevision{
+		PostID:  existing.ID,
+		Title:   "Same Title",
+		Content: `{"type":"paragraph","text":"Hello"}`,
+	}, nil)
+
+	_, err := service.CreatePost(postReq, user)
+
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "CreateRevision", mock.Anything)
+}
+
+// Test case: บันทึกจาก version เก่า (อีกแท็บบันทึกแทรกหลังอ่าน) ต้องได้ conflict พร้อมเนื้อหาล่าสุด
+func TestCreatePost_StaleVersionReturnsConflict(t *testing.T) {
+	repo := new(MockPostRepository)
+	media := new(MockMediaService)
+	service := post.NewPostService(repo, media, &post.TaskEnqueuer{})
+
+	user := &models.User{ID: uuid.New()}
+	content := post.PostContentStructure{Type: "paragraph", Text: "Mine"}
+	postReq := post.CreatePostRequest{ShortSlug: "draft", Title: "Mine", Content: content, Version: 2}
+
+	slug := postReq.ShortSlug + "-" + user.ID.String()
+	existing := &models.Post{ID: uuid.New(), Slug: slug, ShortSlug: slug, AuthorID: user.ID, Version: 2}
+	current := &models.Post{ID: existing.ID, AuthorID: user.ID, Version: 3, Title: "Theirs", Content: `{"type":"paragraph","text":"Theirs"}`}
+
+	repo.On("GetByShortSlug", slug).Return(existing, nil)
+	repo.On("Update", mock.MatchedBy(func(p *models.Post) bool { return p.Version == 2 })).Return(errs.ErrPostVersionConflict)
+	repo.On("GetByID", existing.ID.String()).Return(current, nil)
+
+	saved, err := service.CreatePost(postReq, user)
+
+	assert.Nil(t, saved)
+	assert.ErrorIs(t, err, errs.ErrPostVersionConflict)
+	var conflict *post.VersionConflictError
+	if assert.ErrorAs(t, err, &conflict) {
+		assert.Equal(t, 3, conflict.Current.Version)
+		assert.Equal(t, "Theirs", conflict.Current.Title)
+		assert.JSONEq(t, current.Content, string(conflict.Current.Content))
+	}
+	repo.AssertNotCalled(t, "CreateRevision", mock.Anything)
+	media.AssertNotCalled(t, "GetImagesByPostID", mock.Anything)
+
+	// version ที่ไม่ตรงตั้งแต่ตอนอ่านถูกปฏิเสธโดยไม่เขียนอะไรเลย
+	repo2 := new(MockPostRepository)
+	service2 := post.NewPostService(repo2, media, &post.TaskEnqueuer{})
+	repo2.On("GetByShortSlug", slug).Return(current, nil)
+
+	_, err = service2.CreatePost(postReq, user)
+
+	assert.ErrorIs(t, err, errs.ErrPostVersionConflict)
+	repo2.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// Test case: restore revision นำเนื้อหากลับมาและบันทึก revision แบบ RESTORE
+func TestRestoreRevision_Success(t *testing.T) {
+	logger.Log = zap.NewNop()
+	repo := new(MockPostRepository)
+	media := new(MockMediaService)
+	service := post.NewPostService(repo, media, &post.TaskEnqueuer{}).(*post.PostService)
+
+	user := &models.User{ID: uuid.New()}
+	slug := "draft-" + user.ID.String()
+	existing := &models.Post{ID: uuid.New(), ShortSlug: slug, AuthorID: user.ID, Title: "Now", Content: `{"type":"doc"}`}
+	oldContent := `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"old"}]}]}`
+	revision := &models.PostRevision{ID: 7, PostID: existing.ID, Title: "Before", Content: oldContent}
+
+	repo.On("GetByShortSlug", slug).Return(existing, nil)
+	repo.On("GetRevisionByID", existing.ID.String(), uint(7)).Return(revision, nil)
+	repo.On("GetLatestRevision", existing.ID.String()).Return(nil, nil)
+	repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+	media.On("GetImagesByPostID", existing.ID).Return([]models.ImageUpload{}, nil)
+	repo.On("Update", existing).Return(nil)
+	repo.On("CreateRevision", mock.MatchedBy(func(rev *models.PostRevision) bool {
+		return rev.Reason == models.RevisionSave && rev.Title == "Now"
+	})).Return(nil).Once()
+	repo.On("CreateRevision", mock.MatchedBy(func(rev *models.PostRevision) bool {
+		return rev.Reason == models.RevisionRestore && rev.Content == oldContent
+	})).Return(nil).Once()
+
+	id, err := service.RestoreRevision("draft", "7", user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing.ID.String(), id)
+	assert.Equal(t, "Before", existing.Title)
+	assert.Equal(t, oldContent, existing.Content)
+	repo.AssertExpectations(t)
+}
+
+// Test case: publish_at ไม่ตรงกับ payload (ถูกเลื่อนเวลาแล้ว) ต้องข้าม task โดยไม่ publish
+func TestPublishScheduledPost_SkipsStaleTask(t *testing.T) {
+	logger.Log = zap.NewNop()
+	repo := new(MockPostRepository)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	publishAt := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	existing := &models.Post{ID: uuid.New(), Status: models.PostScheduled, PublishAt: &publishAt}
+	repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+
+	published, err := service.PublishScheduledPost(existing.ID.String(), publishAt.Add(-time.Hour))
+
+	assert.NoError(t, err)
+	assert.Nil(t, published)
+	assert.False(t, existing.Published)
+	repo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// Test case: ถึงเวลา publish_at แล้ว โพสต์ต้องถูก publish เหมือน PublishPost และล้างเวลาที่ตั้งไว้
+func TestPublishScheduledPost_PublishesDuePost(t *testing.T) {
+	logger.Log = zap.NewNop()
+	repo := new(MockPostRepository)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	publishAt := time.Now().Truncate(time.Second)
+	existing := &models.Post{
+		ID:        uuid.New(),
+		AuthorID:  uuid.New(),
+		Status:    models.PostScheduled,
+		PublishAt: &publishAt,
+		Content:   `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Hello world"}]}]}`,
+	}
+
+	repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+	repo.On("DeleteEmbeddingsByPostID", existing.ID.String()).Return(nil)
+	repo.On("Update", existing).Return(nil)
+	repo.On("UpdateSchedule", existing.ID.String(), (*time.Time)(nil), (*time.Time)(nil)).Return(nil)
+	repo.On("CreateRevision", mock.MatchedBy(func(rev *models.PostRevision) bool {
+		return rev.Reason == models.RevisionPublish && rev.UserID == existing.AuthorID
+	})).Return(nil)
+
+	published, err := service.PublishScheduledPost(existing.ID.String(), publishAt)
+
+	assert.NoError(t, err)
+	assert.Same(t, existing, published)
+	assert.True(t, existing.Published)
+	assert.Equal(t, models.PostPublished, existing.Status)
+	assert.Nil(t, existing.PublishAt)
+	assert.Equal(t, "<p>Hello world</p>", *existing.HTMLContent)
+	assert.Equal(t, 1.0, existing.ReadTime)
+	repo.AssertExpectations(t)
+}
+
+// Test case: tag ที่ต่างกันแค่ตัวพิมพ์ / ช่องว่าง (รวม zero-width space ในภาษาไทย) ต้องถูกรวมเป็น tag เดียว
+func TestPublishPost_AttachesNormalizedTags(t *testing.T) {
+	logger.Log = zap.NewNop()
+	repo := new(MockPostRepository)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	user := &models.User{ID: uuid.New()}
+	existing := &models.Post{
+		ID:        uuid.New(),
+		Slug:      "tags-" + user.ID.String(),
+		ShortSlug: "tags-" + user.ID.String(),
+		AuthorID:  user.ID,
+	}
+
+	repo.On("GetByShortSlug", existing.ShortSlug).Return(existing, nil)
+	repo.On("GetBySlug", "tags").Return(nil, gorm.ErrRecordNotFound)
+	repo.On("ReplacePostTags", existing, []string{"golang", "การเขียน โปรแกรม"}).Return(nil)
+	repo.On("ReplacePostCategories", existing, []string{"Backend"}).Return(nil)
+	repo.On("DeleteEmbeddingsByPostID", existing.ID.String()).Return(nil)
+	repo.On("Update", existing).Return(nil)
+	repo.On("UpdateSchedule", existing.ID.String(), (*time.Time)(nil), (*time.Time)(nil)).Return(nil)
+	repo.On("CreateRevision", mock.Anything).Return(nil)
+
+	_, err := service.PublishPost(&post.PublishPostRequestDTO{
+		Slug:       "tags",
+		Title:      "Tags",
+		Tags:       []string{" GoLang ", "#golang", "การเขียน\u200b  โปรแกรม", ""},
+		Categories: []string{"Backend", " backend "},
+	}, user, "tags")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+// Test case: ลิงก์ javascript:, รูปจาก host อื่น และ iframe ที่ไม่อยู่ใน allowlist ต้องถูกตัดออกและรายงานกลับ
+func TestPublishPost_SanitizesRenderedHTML(t *testing.T) {
+	logger.Log = zap.NewNop()
+	sanitize.Configure(sanitize.Options{ImageHosts: []string{"https://media.bsospace.com"}})
+	defer sanitize.Configure(sanitize.Options{})
+
+	youtube := "youtubeEmbed"
+	tiptap.RegisterNodeRenderer(youtube, func(w *tiptap.HTMLWriter, node tiptap.Node) {
+		w.OpenTag("iframe", map[string]string{"src": node.AttrString("src"), "onload": "alert(1)"})
+		w.CloseTag("iframe")
+	})
+
+	repo := new(MockPostRepository)
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+
+	user := &models.User{ID: uuid.New()}
+	existing := &models.Post{
+		ID:        uuid.New(),
+		Slug:      "safe-" + user.ID.String(),
+		ShortSlug: "safe-" + user.ID.String(),
+		AuthorID:  user.ID,
+		Content: `{"type":"doc","content":[` +
+			`{"type":"paragraph","content":[{"type":"text","text":"click","marks":[{"type":"link","attrs":{"href":"javascript:alert(1)"}}]},` +
+			`{"type":"text","text":" docs","marks":[{"type":"link","attrs":{"href":"https://go.dev"}}]}]},` +
+			`{"type":"image","attrs":{"src":"https://media.bsospace.com/a.png"}},` +
+			`{"type":"image","attrs":{"src":"https://evil.example.com/track.png"}},` +
+			`{"type":"youtubeEmbed","attrs":{"src":"https://www.youtube.com/embed/abc"}},` +
+			`{"type":"youtubeEmbed","attrs":{"src":"https://evil.example.com/embed"}}]}`,
+	}
+
+	repo.On("GetByShortSlug", existing.ShortSlug).Return(existing, nil)
+	repo.On("GetBySlug", "safe").Return(nil, gorm.ErrRecordNotFound)
+	repo.On("DeleteEmbeddingsByPostID", existing.ID.String()).Return(nil)
+	repo.On("Update", existing).Return(nil)
+	repo.On("UpdateSchedule", existing.ID.String(), (*time.Time)(nil), (*time.Time)(nil)).Return(nil)
+	repo.On("CreateRevision", mock.Anything).Return(nil)
+
+	rejected, err := service.PublishPost(&post.PublishPostRequestDTO{Slug: "safe", Title: "Safe"}, user, "safe")
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<p><a target="_blank">click</a><a href="https://go.dev" rel="noopener noreferrer nofollow" target="_blank"> docs</a></p>`+
+		`<img decoding="async" loading="lazy" src="https://media.bsospace.com/a.png">`+
+		`<iframe src="https://www.youtube.com/embed/abc"></iframe>`, *existing.HTMLContent)
+
+	reasons := map[string]string{}
+	for _, r := range rejected {
+		reasons[r.Element+"/"+r.Attribute+"/"+r.Value] = r.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"a/href/javascript:alert(1)":                 sanitize.ReasonInvalidValue,
+		"img/src/https://evil.example.com/track.png": sanitize.ReasonInvalidValue,
+		"img//":                  sanitize.ReasonMissingSource,
+		"iframe/onload/alert(1)": sanitize.ReasonEventHandler,
+		"iframe/src/https://evil.example.com/embed": sanitize.ReasonInvalidValue,
+		"iframe//": sanitize.ReasonMissingSource,
+	}, reasons)
+	repo.AssertExpectations(t)
+}
+
+func TestBuildSearchHighlight_MarksTermsAndEscapesHTML(t *testing.T) {
+	p := models.Post{
+		Title:      "เรียน Go <เบื้องต้น>",
+		SearchText: "บทความนี้สอน   การใช้ goroutine ใน Go แบบง่าย ๆ",
+	}
+
+	highlight := post.BuildSearchHighlight(p, "go การใช้")
+
+	assert.NotNil(t, highlight)
+	assert.Equal(t, "เรียน <mark>Go</mark> &lt;เบื้องต้น&gt;", highlight.Title)
+	assert.Equal(t, "บทความนี้สอน <mark>การใช้</mark> <mark>go</mark>routine ใน <mark>Go</mark> แบบง่าย ๆ", highlight.Snippet)
+	assert.Nil(t, post.BuildSearchHighlight(p, "   "))
+}
+
+func TestImportMarkdown_CreatesDraftWithFrontMatterAndUploadedImages(t *testing.T) {
+	logger.Log = zap.NewNop()
+	repo := new(MockPostRepository)
+	mediaService := new(MockMediaService)
+	service := post.NewPostService(repo, mediaService, &post.TaskEnqueuer{}).(*post.PostService)
+
+	user := &models.User{ID: uuid.New()}
+	postID := uuid.New()
+	markdown := "---\ntitle: Hello Markdown\ndescription: Imported draft\nslug: hello-markdown\ntags: [Go, \" go \", markdown]\n---\n" +
+		"Intro with **bold** text.\n\n![Shot](./images/shot.png)\n\n![Missing](other.png)\n\n- [x] done\n- [ ] todo\n"
+	shot := &multipart.FileHeader{Filename: "shot.png"}
+	uploadedURL := "https://cdn.example.com/shot-abc.png"
+
+	imageID := uuid.New()
+	repo.On("GetByShortSlug", mock.Anything).Return((*models.Post)(nil), gorm.ErrRecordNotFound)
+	repo.On("GetBySlug", "hello-markdown").Return(nil, gorm.ErrRecordNotFound)
+	// รูปอัปโหลดก่อนมีโพสต์ จึงยังไม่ผูก post id
+	mediaService.On("CreateMedia", shot, user, (*uuid.UUID)(nil)).
+		Return(&models.ImageUpload{ID: imageID, ImageURL: uploadedURL}, nil).Once()
+
+	// draft ถูกสร้างพร้อมเนื้อหา tag และรูปในครั้งเดียว
+	repo.On("CreateImportedPost", mock.MatchedBy(func(p *models.Post) bool {
+		return p.Title == "Hello Markdown" && p.Description == "Imported draft" && p.Slug == "hello-markdown" &&
+			p.Status == models.PostDraft && p.Version == 1 &&
+			strings.Contains(p.Content, uploadedURL) && strings.Contains(p.Content, `"taskItem"`) && !strings.Contains(p.Content, "shot.png\"")
+	}), []string{"go", "markdown"}, []uuid.UUID{imageID}).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Post).ID = postID
+	}).Return(nil)
+	repo.On("GetLatestRevision", postID.String()).Return(nil, nil)
+	repo.On("CreateRevision", mock.MatchedBy(func(rev *models.PostRevision) bool {
+		return rev.PostID == postID && rev.Reason == models.RevisionSave
+	})).Return(nil)
+
+	result, err := service.ImportMarkdown(markdown, map[string]*multipart.FileHeader{"shot.png": shot}, user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, postID.String(), result.PostID)
+	assert.Len(t, result.ShortSlug, 8)
+	assert.Equal(t, []string{"other.png"}, result.MissingImages)
+	repo.AssertExpectations(t)
+	mediaService.AssertExpectations(t)
+}
+
+// Test case: สร้าง draft ไม่สำเร็จต้องไม่เหลือ draft ค้าง และปลดรูปที่อัปโหลดไว้
+func TestImportMarkdown_FailedCreateReleasesImages(t *testing.T) {
+	logger.Log = zap.NewNop()
+	repo := new(MockPostRepository)
+	mediaService := new(MockMediaService)
+	service := post.NewPostService(repo, mediaService, &post.TaskEnqueuer{}).(*post.PostService)
+
+	user := &models.User{ID: uuid.New()}
+	shot := &multipart.FileHeader{Filename: "shot.png"}
+	image := &models.ImageUpload{ID: uuid.New(), ImageURL: "https://cdn.example.com/shot.png", IsUsed: true}
+
+	repo.On("GetByShortSlug", mock.Anything).Return((*models.Post)(nil), gorm.ErrRecordNotFound)
+	mediaService.On("CreateMedia", shot, user, (*uuid.UUID)(nil)).Return(image, nil).Once()
+	repo.On("CreateImportedPost", mock.Anything, mock.Anything, []uuid.UUID{image.ID}).Return(fmt.Errorf("db down"))
+	mediaService.On("UpdateImageUsage", mock.MatchedBy(func(img *models.ImageUpload) bool {
+		return img.ID == image.ID && !img.IsUsed
+	})).Return(nil).Once()
+
+	result, err := service.ImportMarkdown("# Title\n\n![Shot](shot.png)\n", map[string]*multipart.FileHeader{"shot.png": shot}, user)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+	repo.AssertNotCalled(t, "CreateRevision", mock.Anything)
+	mediaService.AssertExpectations(t)
+}
+
+func TestRenderPostMarkdown_RoundTripsThroughImport(t *testing.T) {
+	source := "Intro with **bold *nested*** and <u>underline</u>, `code` and [a link](https://example.com).\\\nsecond line\n\n" +
+		"## Section\n\n> quote\n\n- one\n  - nested\n- two\n\n* separate list\n\n3. three\n4. four\n\n- [x] done\n- [ ] todo\n\n" +
+		"```go\nfmt.Println(\"```\")\n```\n\n| Name | Value |\n| :--- | ---: |\n| a \\| b | `x` |\n\n![Diagram](https://cdn.example.com/d.png \"Caption\")\n\n---\n\n1986\\. not a list, # not a heading, snake_case and *stars*\n"
+	parsed, err := tiptap.ParseMarkdown(source)
+	assert.NoError(t, err)
+
+	content, err := json.Marshal(parsed.Doc)
+	assert.NoError(t, err)
+	p := &models.Post{
+		Title:       "Round: trip",
+		Description: "Exported then imported",
+		Slug:        "round-trip",
+		ShortSlug:   "abc12345-" + uuid.NewString(),
+		Content:     string(content),
+		Tags:        []models.Tag{{Name: "go"}, {Name: "markdown"}},
+	}
+
+	markdown, err := post.RenderPostMarkdown(p)
+	assert.NoError(t, err)
+
+	imported, err := tiptap.ParseMarkdown(markdown)
+	assert.NoError(t, err)
+	assert.Equal(t, tiptap.FrontMatter{
+		Title:       "Round: trip",
+		Description: "Exported then imported",
+		Slug:        "round-trip",
+		Tags:        []string{"go", "markdown"},
+	}, imported.FrontMatter)
+
+	roundTripped, err := json.Marshal(imported.Doc)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(content), string(roundTripped))
+}
+
+func TestAuthorize_CollaboratorRoles(t *testing.T) {
+	repo := new(MockPostRepository)
+	owner := &models.User{ID: uuid.New()}
+	editor := &models.User{ID: uuid.New()}
+	reviewer := &models.User{ID: uuid.New()}
+	stranger := &models.User{ID: uuid.New()}
+	p := &models.Post{ID: uuid.New(), AuthorID: owner.ID}
+
+	repo.On("GetCollaboratorRole", p.ID.String(), editor.ID.String()).Return(models.CollaboratorEditor, nil)
+	repo.On("GetCollaboratorRole", p.ID.String(), reviewer.ID.String()).Return(models.CollaboratorReviewer, nil)
+	repo.On("GetCollaboratorRole", p.ID.String(), stranger.ID.String()).Return(models.CollaboratorRole(""), gorm.ErrRecordNotFound)
+
+	// เจ้าของทำได้ทุกอย่างโดยไม่ต้องดู role
+	assert.NoError(t, post.Authorize(repo, p, owner, post.ActionDelete))
+	assert.NoError(t, post.Authorize(repo, p, owner, post.ActionManage))
+
+	assert.NoError(t, post.Authorize(repo, p, editor, post.ActionEdit))
+	assert.NoError(t, post.Authorize(repo, p, editor, post.ActionPublish))
+	assert.ErrorIs(t, post.Authorize(repo, p, editor, post.ActionDelete), errs.ErrUnauthorized)
+	assert.ErrorIs(t, post.Authorize(repo, p, editor, post.ActionManage), errs.ErrUnauthorized)
+
+	assert.NoError(t, post.Authorize(repo, p, reviewer, post.ActionView))
+	assert.ErrorIs(t, post.Authorize(repo, p, reviewer, post.ActionEdit), errs.ErrUnauthorized)
+	assert.ErrorIs(t, post.Authorize(repo, p, reviewer, post.ActionPublish), errs.ErrUnauthorized)
+
+	assert.ErrorIs(t, post.Authorize(repo, p, stranger, post.ActionView), errs.ErrUnauthorized)
+	repo.AssertNotCalled(t, "GetCollaboratorRole", p.ID.String(), owner.ID.String())
+}
+
+func TestUnlockPost_ProtectedPostRequiresKey(t *testing.T) {
+	t.Setenv("POST_ACCESS_SECRET", "test-secret")
+	repo := new(MockPostRepository)
+	cryptoService := crypto.NewCryptoService()
+	service := post.NewPostService(repo, new(MockMediaService), &post.TaskEnqueuer{}).(*post.PostService)
+	service.SetCryptoService(cryptoService)
+
+	keyHash, err := cryptoService.HashPassword("open-sesame")
+	assert.NoError(t, err)
+	owner := &models.User{ID: uuid.New()}
+	reader := &models.User{ID: uuid.New()}
+	p := &models.Post{ID: uuid.New(), AuthorID: owner.ID, Visibility: models.VisibilityProtected, Key: keyHash}
+
+	repo.On("GetPublicPostBySlugAndUsername", "secret-post", "alice").Return(p, nil)
+	repo.On("GetCollaboratorRole", p.ID.String(), reader.ID.String()).Return(models.CollaboratorRole(""), gorm.ErrRecordNotFound)
+
+	// ยังไม่ได้ปลดล็อก: ผู้อ่านทั่วไปอ่านไม่ได้ แต่เจ้าของอ่านได้
+	assert.False(t, service.CanReadPost(p, nil, ""))
+	assert.False(t, service.CanReadPost(p, reader, ""))
+	assert.True(t, service.CanReadPost(p, owner, ""))
+
+	_, _, err = service.UnlockPost("alice", "secret-post", "wrong")
+	assert.ErrorIs(t, err, errs.ErrInvalidPostKey)
+
+	token, result, err := service.UnlockPost("alice", "secret-post", "open-sesame")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, p.ID.String(), result.PostID)
+	assert.NotNil(t, result.ExpiresAt)
+
+	assert.True(t, service.CanReadPost(p, nil, token))
+	// token ผูกกับโพสต์เดียว
+	other := &models.Post{ID: uuid.New(), AuthorID: owner.ID, Visibility: models.VisibilityProtected, Key: keyHash}
+	assert.False(t, service.CanReadPost(other, nil, token))
+
+	// unlisted อ่านได้ด้วยลิงก์โดยไม่ต้องใช้ key
+	assert.True(t, service.CanReadPost(&models.Post{ID: uuid.New(), Visibility: models.VisibilityUnlisted}, nil, ""))
+}
@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// dependentTables ต้องตรงกับ trashDependentTables ใน repository ถ้าเพิ่มตารางที่นั่นแล้วไม่เพิ่มที่นี่
+// DELETE จะล้มด้วย "no such table" และ test นี้จะเตือนให้เพิ่มแถวตัวอย่าง
+var dependentTables = []string{
+	"comments", "embeddings", "post_centroids", "ai_responses", "post_views", "post_revisions",
+	"post_reactions", "series_posts", "post_collaborators", "post_daily_stats",
+	"post_referrer_daily_stats", "post_scores", "bookmarks", "reading_histories",
+	"post_tags", "post_categories",
+}
+
+func newTrashDB(t *testing.T) (*gorm.DB, post.PostRepositoryInterface) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, ddl := range []string{
+		`CREATE TABLE posts (
+			id TEXT PRIMARY KEY, slug TEXT, short_slug TEXT, title TEXT, content TEXT, thumbnail TEXT,
+			published BOOLEAN DEFAULT false, status TEXT DEFAULT 'DRAFT', published_at DATETIME, visibility TEXT DEFAULT 'public',
+			ai_chat_open BOOLEAN DEFAULT false, version INTEGER NOT NULL DEFAULT 1, author_id TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE image_uploads (
+			id TEXT PRIMARY KEY, user_id TEXT NOT NULL, post_id TEXT, image_url TEXT NOT NULL, file_id TEXT, identifier TEXT,
+			is_used BOOLEAN DEFAULT false, used_at DATETIME, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	for _, table := range dependentTables {
+		require.NoError(t, db.Exec(`CREATE TABLE `+table+` (id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL)`).Error)
+	}
+	return db, post.NewPostRepository(db)
+}
+
+func insertTrashPost(t *testing.T, db *gorm.DB, authorID uuid.UUID, slug string, deletedAt *time.Time) uuid.UUID {
+	id := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, slug, short_slug, title, author_id, deleted_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		id.String(), slug, slug+"-"+authorID.String(), slug, authorID.String(), deletedAt).Error)
+
+	for _, table := range dependentTables {
+		require.NoError(t, db.Exec(`INSERT INTO `+table+` (post_id) VALUES (?)`, id.String()).Error)
+	}
+	usedAt := time.Now().UTC()
+	require.NoError(t, db.Exec(`INSERT INTO image_uploads (id, user_id, post_id, image_url, is_used, used_at) VALUES (?, ?, ?, ?, true, ?)`,
+		uuid.NewString(), authorID.String(), id.String(), "https://img/"+slug+".png", usedAt).Error)
+	return id
+}
+
+func countRows(t *testing.T, db *gorm.DB, table string, postID uuid.UUID) int64 {
+	var count int64
+	require.NoError(t, db.Table(table).Where("post_id = ?", postID.String()).Count(&count).Error)
+	return count
+}
+
+// Test case: ลบถาวรเฉพาะโพสต์ที่อยู่ในถังขยะเกินกำหนด พร้อมแถวที่อ้างถึงในทุกตาราง และปลดรูปให้เก็บคืนได้
+func TestPurgeDeletedPosts_CascadesAndReleasesImages(t *testing.T) {
+	db, repo := newTrashDB(t)
+	author := uuid.New()
+	cutoff := time.Now().UTC().Add(-post.TrashRetention)
+	expiredAt := cutoff.Add(-time.Hour)
+	recentAt := cutoff.Add(time.Hour)
+
+	expired := insertTrashPost(t, db, author, "expired", &expiredAt)
+	recent := insertTrashPost(t, db, author, "recent", &recentAt)
+	live := insertTrashPost(t, db, author, "live", nil)
+
+	purged, err := repo.PurgeDeletedPosts(cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var remaining []string
+	require.NoError(t, db.Raw(`SELECT slug FROM posts ORDER BY slug`).Scan(&remaining).Error)
+	assert.Equal(t, []string{"live", "recent"}, remaining)
+
+	for _, table := range dependentTables {
+		assert.Zero(t, countRows(t, db, table, expired), table)
+		assert.Equal(t, int64(1), countRows(t, db, table, recent), table)
+		assert.Equal(t, int64(1), countRows(t, db, table, live), table)
+	}
+
+	var released models.ImageUpload
+	require.NoError(t, db.Where("image_url = ?", "https://img/expired.png").First(&released).Error)
+	assert.False(t, released.IsUsed)
+	assert.Nil(t, released.UsedAt)
+	assert.Nil(t, released.PostID)
+
+	var kept models.ImageUpload
+	require.NoError(t, db.Where("image_url = ?", "https://img/recent.png").First(&kept).Error)
+	assert.True(t, kept.IsUsed, "images of posts still in the trash stay used so they can be restored")
+	require.NotNil(t, kept.PostID)
+	assert.Equal(t, recent, *kept.PostID)
+
+	// รอบถัดไปไม่มีอะไรให้ลบ
+	purged, err = repo.PurgeDeletedPosts(cutoff)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+}
+
+// Test case: ตารางที่ลบไม่สำเร็จต้อง rollback ทั้งหมด โพสต์และรูปยังอยู่ให้รอบถัดไปลองใหม่
+func TestPurgeDeletedPosts_RollsBackOnFailure(t *testing.T) {
+	db, repo := newTrashDB(t)
+	author := uuid.New()
+	deletedAt := time.Now().UTC().Add(-post.TrashRetention - time.Hour)
+	expired := insertTrashPost(t, db, author, "expired", &deletedAt)
+	require.NoError(t, db.Exec(`DROP TABLE reading_histories`).Error)
+
+	purged, err := repo.PurgeDeletedPosts(time.Now().UTC().Add(-post.TrashRetention))
+	assert.Error(t, err)
+	assert.Zero(t, purged)
+
+	assert.Equal(t, int64(1), countRows(t, db, "comments", expired))
+	var image models.ImageUpload
+	require.NoError(t, db.Where("post_id = ?", expired.String()).First(&image).Error)
+	assert.True(t, image.IsUsed)
+	_, err = repo.GetTrashedPostByID(expired.String())
+	assert.NoError(t, err)
+}
+
+// Test case: กู้คืนแล้วโพสต์กลับมาให้ query ปกติเห็น และไม่อยู่ในถังขยะอีก
+func TestPostRepository_RestorePost(t *testing.T) {
+	db, repo := newTrashDB(t)
+	author := uuid.New()
+	deletedAt := time.Now().UTC().Add(-time.Hour)
+	trashed := insertTrashPost(t, db, author, "trashed", &deletedAt)
+	insertTrashPost(t, db, author, "live", nil)
+
+	inTrash, err := repo.GetTrashedPosts(author.String())
+	require.NoError(t, err)
+	require.Len(t, inTrash, 1)
+	assert.Equal(t, trashed, inTrash[0].ID)
+
+	p, err := repo.GetTrashedPostByID(trashed.String())
+	require.NoError(t, err)
+	require.NoError(t, repo.RestorePost(p))
+
+	var restored models.Post
+	require.NoError(t, db.Select("id", "deleted_at").Where("id = ?", trashed.String()).First(&restored).Error)
+	assert.False(t, restored.DeletedAt.Valid)
+	_, err = repo.GetTrashedPostByID(trashed.String())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// ข้อมูลที่อ้างถึงยังอยู่ครบระหว่างอยู่ในถังขยะ
+	assert.Equal(t, int64(1), countRows(t, db, "comments", trashed))
+}
+
+func TestPurgeTrashWorker_UsesRetention(t *testing.T) {
+	repo := new(MockPostRepository)
+	started := time.Now()
+	repo.On("PurgeDeletedPosts", mock.MatchedBy(func(before time.Time) bool {
+		age := started.Sub(before)
+		return age >= post.TrashRetention-time.Second && age <= post.TrashRetention+time.Second
+	})).Return(int64(2), nil).Once()
+
+	handler := post.PurgeTrashWorkerHandler(post.PurgeTrashWorker{Logger: zap.NewNop(), PostRepo: repo})
+	require.NoError(t, handler(context.Background(), asynq.NewTask(post.TaskTypePurgeTrash, nil)))
+	repo.AssertExpectations(t)
+
+	failure := errors.New("db down")
+	failing := new(MockPostRepository)
+	failing.On("PurgeDeletedPosts", mock.Anything).Return(int64(0), failure)
+	handler = post.PurgeTrashWorkerHandler(post.PurgeTrashWorker{Logger: zap.NewNop(), PostRepo: failing})
+	assert.ErrorIs(t, handler(context.Background(), asynq.NewTask(post.TaskTypePurgeTrash, nil)), failure, "failures are retried")
+}
@@ -0,0 +1,310 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var (
+	registerTrendingDriver sync.Once
+	postAge                = regexp.MustCompile(`EXTRACT\(EPOCH FROM \(NOW\(\) - (\w+\.\w+)\)\)`)
+	greatest               = regexp.MustCompile(`\bGREATEST\(`)
+	now                    = regexp.MustCompile(`\bNOW\(\)`)
+)
+
+// newTrendingDB SQLite ที่มี POWER และแปลงฟังก์ชันวันเวลาของ postgres ในสูตรคะแนนให้รันได้
+func newTrendingDB(t *testing.T) (*gorm.DB, post.PostRepositoryInterface) {
+	registerTrendingDriver.Do(func() {
+		sql.Register("sqlite3_trending", &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				return conn.RegisterFunc("power", func(base, exponent interface{}) float64 {
+					return math.Pow(toFloat(base), toFloat(exponent))
+				}, true)
+			},
+		})
+	})
+
+	db, err := gorm.Open(sqlite.Dialector{DriverName: "sqlite3_trending", DSN: ":memory:"}, &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.Callback().Raw().Before("gorm:raw").Register("test:postgres_functions", func(tx *gorm.DB) {
+		query := tx.Statement.SQL.String()
+		query = postAge.ReplaceAllString(query, "((julianday('now') - julianday($1)) * 86400)")
+		query = greatest.ReplaceAllString(query, "MAX(")
+		query = now.ReplaceAllString(query, "CURRENT_TIMESTAMP")
+		tx.Statement.SQL.Reset()
+		tx.Statement.SQL.WriteString(query)
+	}))
+
+	for _, ddl := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT, avatar TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE posts (
+			id TEXT PRIMARY KEY, slug TEXT, title TEXT, description TEXT, thumbnail TEXT,
+			published BOOLEAN DEFAULT false, status TEXT DEFAULT 'DRAFT', published_at DATETIME, visibility TEXT DEFAULT 'public',
+			likes INTEGER DEFAULT 0, views INTEGER DEFAULT 0, read_time REAL DEFAULT 0,
+			ai_chat_open BOOLEAN DEFAULT false, ai_ready BOOLEAN DEFAULT false, author_id TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_tags (post_id TEXT, tag_id INTEGER)`,
+		`CREATE TABLE categories (id INTEGER PRIMARY KEY, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_categories (post_id TEXT, category_id INTEGER)`,
+		`CREATE TABLE post_views (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, user_id TEXT, fingerprint TEXT,
+			viewed_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_reactions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, user_id TEXT NOT NULL, type TEXT NOT NULL, created_at DATETIME)`,
+		`CREATE TABLE ai_responses (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, user_id TEXT NOT NULL, used_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_scores (
+			post_id TEXT NOT NULL, time_window TEXT NOT NULL, score REAL NOT NULL DEFAULT 0,
+			views INTEGER NOT NULL DEFAULT 0, likes INTEGER NOT NULL DEFAULT 0, ai_chats INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME, PRIMARY KEY (post_id, time_window))`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db, post.NewPostRepository(db)
+}
+
+// toFloat SQLite ส่ง INTEGER มาเมื่อ MAX(..., 0) ได้ 0 จึงต้องแปลงเองก่อนเรียก math.Pow
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+func insertTrendingPost(t *testing.T, db *gorm.DB, title string, publishedAt time.Time) uuid.UUID {
+	authorID, postID := uuid.New(), uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, authorID.String(), "author-"+title).Error)
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, slug, title, author_id, published, status, published_at) VALUES (?, ?, ?, ?, true, ?, ?)`,
+		postID.String(), title, title, authorID.String(), models.PostPublished, publishedAt.UTC()).Error)
+	return postID
+}
+
+type activity struct {
+	views, likes, fires int
+	aiUsers, aiAsks     int // จำนวนผู้ใช้ที่คุยกับ AI และจำนวนครั้งที่ถามต่อคน
+}
+
+func insertActivity(t *testing.T, db *gorm.DB, postID uuid.UUID, at time.Time, a activity) {
+	at = at.UTC()
+	for i := 0; i < a.views; i++ {
+		require.NoError(t, db.Exec(`INSERT INTO post_views (post_id, fingerprint, viewed_at) VALUES (?, ?, ?)`, postID.String(), uuid.NewString(), at).Error)
+	}
+	for i := 0; i < a.likes; i++ {
+		require.NoError(t, db.Exec(`INSERT INTO post_reactions (post_id, user_id, type, created_at) VALUES (?, ?, ?, ?)`,
+			postID.String(), uuid.NewString(), models.ReactionLike, at).Error)
+	}
+	for i := 0; i < a.fires; i++ {
+		require.NoError(t, db.Exec(`INSERT INTO post_reactions (post_id, user_id, type, created_at) VALUES (?, ?, ?, ?)`,
+			postID.String(), uuid.NewString(), models.ReactionFire, at).Error)
+	}
+	for i := 0; i < a.aiUsers; i++ {
+		userID := uuid.NewString()
+		for j := 0; j < a.aiAsks; j++ {
+			require.NoError(t, db.Exec(`INSERT INTO ai_responses (post_id, user_id, used_at) VALUES (?, ?, ?)`, postID.String(), userID, at).Error)
+		}
+	}
+}
+
+func loadScore(t *testing.T, db *gorm.DB, postID uuid.UUID, window string) *models.PostScore {
+	var scores []models.PostScore
+	require.NoError(t, db.Where("post_id = ? AND time_window = ?", postID.String(), window).Find(&scores).Error)
+	if len(scores) == 0 {
+		return nil
+	}
+	return &scores[0]
+}
+
+// expectedScore สูตรเดียวกับ recomputeTrendingScoresSQL
+func expectedScore(views, likes, aiChats float64, age time.Duration) float64 {
+	weighted := views*post.TrendingViewWeight + likes*post.TrendingLikeWeight + aiChats*post.TrendingAIChatWeight
+	return weighted / math.Pow(math.Max(age.Hours(), 0)+2, post.TrendingGravity)
+}
+
+// Test case: คะแนน = กิจกรรมถ่วงน้ำหนัก / (อายุเป็นชั่วโมง + 2) ^ gravity
+func TestRecomputeTrendingScores_GravityFormula(t *testing.T) {
+	db, repo := newTrendingDB(t)
+	current := time.Now()
+	fresh := insertTrendingPost(t, db, "fresh", current.Add(-10*time.Hour))
+	old := insertTrendingPost(t, db, "old", current.Add(-100*time.Hour))
+	future := insertTrendingPost(t, db, "clock-skew", current.Add(time.Hour))
+
+	// AI chat นับผู้ใช้ไม่ซ้ำ และ reaction อื่นที่ไม่ใช่ like ไม่นับ
+	same := activity{views: 10, likes: 2, fires: 4, aiUsers: 1, aiAsks: 3}
+	for _, id := range []uuid.UUID{fresh, old, future} {
+		insertActivity(t, db, id, current.Add(-time.Hour), same)
+	}
+
+	scored, err := repo.RecomputeTrendingScores("7d", current.Add(-7*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), scored)
+
+	freshScore := loadScore(t, db, fresh, "7d")
+	require.NotNil(t, freshScore)
+	assert.Equal(t, int64(10), freshScore.Views)
+	assert.Equal(t, int64(2), freshScore.Likes)
+	assert.Equal(t, int64(1), freshScore.AIChats)
+	assert.InEpsilon(t, expectedScore(10, 2, 1, 10*time.Hour), freshScore.Score, 1e-3)
+
+	oldScore := loadScore(t, db, old, "7d")
+	require.NotNil(t, oldScore)
+	assert.InEpsilon(t, expectedScore(10, 2, 1, 100*time.Hour), oldScore.Score, 1e-3)
+	// กิจกรรมเท่ากัน โพสต์ที่เก่ากว่าสิบเท่าได้คะแนนน้อยลงตาม gravity
+	assert.InEpsilon(t, math.Pow(12.0/102.0, post.TrendingGravity), oldScore.Score/freshScore.Score, 1e-3)
+
+	// publish_at ในอนาคตเล็กน้อยถือว่าอายุ 0 ไม่ให้ตัวหารติดลบหรือเล็กกว่า 2
+	futureScore := loadScore(t, db, future, "7d")
+	require.NotNil(t, futureScore)
+	assert.InEpsilon(t, expectedScore(10, 2, 1, 0), futureScore.Score, 1e-9)
+}
+
+// Test case: นับเฉพาะกิจกรรมตั้งแต่ since ของแต่ละ window โพสต์ที่ไม่มีกิจกรรมไม่มีคะแนน
+// และการคำนวณใหม่แทนที่คะแนนเดิมของ window นั้นเท่านั้น
+func TestRecomputeTrendingScores_Windows(t *testing.T) {
+	db, repo := newTrendingDB(t)
+	current := time.Now()
+	published := current.Add(-60 * 24 * time.Hour)
+	today := insertTrendingPost(t, db, "today", published)
+	lastWeek := insertTrendingPost(t, db, "last-week", published)
+	lastMonth := insertTrendingPost(t, db, "last-month", published)
+	quiet := insertTrendingPost(t, db, "quiet", published)
+
+	insertActivity(t, db, today, current.Add(-2*time.Hour), activity{views: 1})
+	insertActivity(t, db, lastWeek, current.Add(-3*24*time.Hour), activity{likes: 1})
+	insertActivity(t, db, lastMonth, current.Add(-20*24*time.Hour), activity{aiUsers: 1, aiAsks: 1})
+	insertActivity(t, db, quiet, current.Add(-45*24*time.Hour), activity{views: 50})
+
+	cases := []struct {
+		window string
+		scored []uuid.UUID
+	}{
+		{"24h", []uuid.UUID{today}},
+		{"7d", []uuid.UUID{today, lastWeek}},
+		{"30d", []uuid.UUID{today, lastWeek, lastMonth}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.window, func(t *testing.T) {
+			count, err := repo.RecomputeTrendingScores(tc.window, current.Add(-post.TrendingWindows[tc.window]))
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.scored)), count)
+
+			for _, id := range []uuid.UUID{today, lastWeek, lastMonth, quiet} {
+				assert.Equal(t, contains(tc.scored, id), loadScore(t, db, id, tc.window) != nil, "%s in %s", id, tc.window)
+			}
+		})
+	}
+
+	// คำนวณ 24h ใหม่หลังจาก view หายไป ไม่กระทบคะแนนของ window อื่น
+	require.NoError(t, db.Exec(`UPDATE post_views SET deleted_at = ? WHERE post_id = ?`, current, today.String()).Error)
+	count, err := repo.RecomputeTrendingScores("24h", current.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Nil(t, loadScore(t, db, today, "24h"))
+	assert.NotNil(t, loadScore(t, db, lastWeek, "7d"))
+	assert.NotNil(t, loadScore(t, db, lastMonth, "30d"))
+}
+
+func contains(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Test case: เรียงตามคะแนน และกรองโพสต์ที่ถูก unpublish หรือซ่อนหลังรอบคำนวณล่าสุด
+func TestGetTrendingPosts_OrdersByScoreAndRechecksVisibility(t *testing.T) {
+	db, repo := newTrendingDB(t)
+	current := time.Now()
+	published := current.Add(-24 * time.Hour)
+	quiet := insertTrendingPost(t, db, "quiet", published)
+	busy := insertTrendingPost(t, db, "busy", published)
+	hidden := insertTrendingPost(t, db, "hidden", published)
+	withdrawn := insertTrendingPost(t, db, "withdrawn", published)
+
+	insertActivity(t, db, quiet, current.Add(-time.Hour), activity{views: 1})
+	insertActivity(t, db, busy, current.Add(-time.Hour), activity{views: 5, likes: 2})
+	insertActivity(t, db, hidden, current.Add(-time.Hour), activity{views: 50})
+	insertActivity(t, db, withdrawn, current.Add(-time.Hour), activity{views: 50})
+
+	_, err := repo.RecomputeTrendingScores("7d", current.Add(-7*24*time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(`UPDATE posts SET visibility = 'unlisted' WHERE id = ?`, hidden.String()).Error)
+	require.NoError(t, db.Exec(`UPDATE posts SET published = false, status = 'DRAFT' WHERE id = ?`, withdrawn.String()).Error)
+
+	posts, err := repo.GetTrendingPosts("7d", 10)
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "busy", posts[0].Title)
+	assert.Equal(t, "quiet", posts[1].Title)
+
+	limited, err := repo.GetTrendingPosts("7d", 1)
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+
+	none, err := repo.GetTrendingPosts("24h", 10)
+	require.NoError(t, err)
+	assert.Empty(t, none, "windows that were never computed have no scores")
+}
+
+// Test case: worker คำนวณทุก window โดย since ย้อนหลังตามความยาวของ window
+func TestRecomputeTrendingWorker_RecomputesEveryWindow(t *testing.T) {
+	repo := new(MockPostRepository)
+	started := time.Now()
+	for window, duration := range post.TrendingWindows {
+		duration := duration
+		repo.On("RecomputeTrendingScores", window, mock.MatchedBy(func(since time.Time) bool {
+			age := started.Sub(since)
+			return age >= duration-time.Second && age <= duration+time.Second
+		})).Return(int64(1), nil).Once()
+	}
+
+	handler := post.RecomputeTrendingWorkerHandler(post.RecomputeTrendingWorker{Logger: zap.NewNop(), PostRepo: repo})
+	require.NoError(t, handler(context.Background(), asynq.NewTask(post.TaskTypeRecomputeTrending, nil)))
+	repo.AssertExpectations(t)
+	repo.AssertNumberOfCalls(t, "RecomputeTrendingScores", len(post.TrendingWindows))
+}
+
+func TestValidTrendingWindow(t *testing.T) {
+	for _, window := range []string{"24h", "7d", "30d"} {
+		got, err := post.ValidTrendingWindow(window)
+		require.NoError(t, err)
+		assert.Equal(t, window, got)
+	}
+
+	got, err := post.ValidTrendingWindow("")
+	require.NoError(t, err)
+	assert.Equal(t, post.DefaultTrendingWindow, got)
+
+	for _, window := range []string{"1h", "7D", "90d", " 7d"} {
+		_, err := post.ValidTrendingWindow(window)
+		assert.Error(t, err, window)
+	}
+}
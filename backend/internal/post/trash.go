@@ -0,0 +1,93 @@
+package post
+
+import (
+	"encoding/json"
+	"errors"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// EmbeddingEnqueuer สร้าง embedding ของโพสต์ใหม่ (ai.TaskEnqueuer)
+// แยกเป็น interface เพราะ package ai ใช้ package post อยู่แล้ว
+type EmbeddingEnqueuer interface {
+	EnqueuePostEmbedding(post *models.Post, user *models.User) (*asynq.TaskInfo, error)
+}
+
+// SetEmbeddingEnqueuer ถ้าไม่ตั้ง โพสต์ที่กู้คืนจะต้องเปิด AI mode ใหม่เอง
+func (s *PostService) SetEmbeddingEnqueuer(enqueuer EmbeddingEnqueuer) {
+	s.Embeddings = enqueuer
+}
+
+// TrashedPostDTO โพสต์ในถังขยะ PurgeAt คือเวลาที่จะถูกลบถาวร
+type TrashedPostDTO struct {
+	MyPostsDTO
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// GetTrash โพสต์ที่ผู้ใช้ลบไปและยังกู้คืนได้
+func (s *PostService) GetTrash(user *models.User) ([]TrashedPostDTO, error) {
+	posts, err := s.Repo.GetTrashedPosts(user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TrashedPostDTO, 0, len(posts))
+	for _, p := range posts {
+		dto := TrashedPostDTO{MyPostsDTO: MapMyPostToSummaryDTO(p)}
+		dto.Role = "owner"
+		if p.DeletedAt.Valid {
+			dto.DeletedAt = p.DeletedAt.Time
+			dto.PurgeAt = p.DeletedAt.Time.Add(TrashRetention)
+		}
+		result = append(result, dto)
+	}
+	return result, nil
+}
+
+// RestorePost กู้โพสต์จากถังขยะกลับมาในสถานะเดิม ผูกรูปที่ใช้ในเนื้อหากลับเป็น used
+// และสร้าง embedding ใหม่ถ้าเปิด AI mode ไว้ (embedding ถูกลบไปตอนลบโพสต์)
+func (s *PostService) RestorePost(id string, user *models.User) (*MyPostsDTO, error) {
+	post, err := s.Repo.GetTrashedPostByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := Authorize(s.Repo, post, user, ActionDelete); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.RestorePost(post); err != nil {
+		return nil, err
+	}
+
+	// โพสต์ที่ลบก่อนมีถังขยะถูกปลดรูปไปแล้ว จึงคำนวณการใช้งานรูปจากเนื้อหาใหม่ทุกครั้ง
+	var content PostContentStructure
+	if err := json.Unmarshal([]byte(post.Content), &content); err != nil {
+		logger.Log.Warn("Failed to parse restored post content", zap.String("post_id", id), zap.Error(err))
+	} else if err := s.UpdateImageUsageStatus(post, content, post.Thumbnail); err != nil {
+		return nil, err
+	}
+
+	if post.AIChatOpen && s.Embeddings != nil {
+		if _, err := s.Embeddings.EnqueuePostEmbedding(post, user); err != nil {
+			logger.Log.Warn("Failed to re-queue embedding for restored post", zap.String("post_id", id), zap.Error(err))
+		}
+	}
+
+	if post.Published && post.Status == models.PostPublished {
+		s.notifyPublished(post)
+	}
+
+	dto := MapMyPostToSummaryDTO(*post)
+	dto.Role = "owner"
+	return &dto, nil
+}
@@ -0,0 +1,70 @@
+package post
+
+import (
+	"fmt"
+	"rag-searchbot-backend/pkg/errs"
+	"time"
+)
+
+const (
+	PopularModeAllTime  = "all-time" // เรียงตามยอด view รวม (แบบเดิม)
+	PopularModeTrending = "trending" // เรียงตามคะแนน trending
+
+	DefaultTrendingWindow = "7d"
+
+	// น้ำหนักของกิจกรรมและ gravity ของสูตรคะแนน (ดู recomputeTrendingScoresSQL)
+	TrendingViewWeight   = 1.0
+	TrendingLikeWeight   = 3.0
+	TrendingAIChatWeight = 5.0
+	TrendingGravity      = 1.8
+)
+
+// TrendingWindows ช่วงเวลาที่ใช้นับกิจกรรมของคะแนน trending
+var TrendingWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// ValidTrendingWindow ค่าว่างใช้ DefaultTrendingWindow
+func ValidTrendingWindow(window string) (string, error) {
+	if window == "" {
+		return DefaultTrendingWindow, nil
+	}
+	if _, ok := TrendingWindows[window]; !ok {
+		return "", fmt.Errorf("%w: window must be 24h, 7d or 30d", errs.ErrInvalidPayload)
+	}
+	return window, nil
+}
+
+// GetTrendingPosts โพสต์ trending ของช่วงเวลา คะแนนมาจากรอบคำนวณล่าสุดของ RecomputeTrendingWorkerHandler
+func (s *PostService) GetTrendingPosts(window string, limit int) (*PostListResponse, error) {
+	window, err := ValidTrendingWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	posts, err := s.Repo.GetTrendingPosts(window, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	postDTOs := make([]PostSummaryDTO, 0, len(posts))
+	for _, post := range posts {
+		postDTOs = append(postDTOs, MapPostToSummaryDTO(post))
+	}
+
+	return &PostListResponse{
+		Posts: postDTOs,
+		Meta: Meta{
+			Total:       int64(len(posts)),
+			HasNextPage: false,
+			Page:        1,
+			Limit:       limit,
+			TotalPage:   1,
+		},
+	}, nil
+}
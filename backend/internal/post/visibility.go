@@ -0,0 +1,176 @@
+package post
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/crypto"
+	"rag-searchbot-backend/pkg/errs"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	PostAccessTTL         = time.Hour // อายุ cookie หลังปลดล็อกโพสต์ protected
+	MinPostPasswordLength = 4
+	shareKeyBytes         = 16
+
+	postAccessCookiePrefix = "blog.pak."
+)
+
+// PostAccessCookieName ชื่อ cookie ที่เก็บ token ปลดล็อกของโพสต์หนึ่ง (แยกตามโพสต์)
+func PostAccessCookieName(postID string) string {
+	return postAccessCookiePrefix + postID
+}
+
+// ValidVisibility บอกว่าเป็น visibility ที่รองรับหรือไม่
+func ValidVisibility(visibility models.PostVisibility) bool {
+	switch visibility {
+	case models.VisibilityPublic, models.VisibilityUnlisted, models.VisibilityProtected:
+		return true
+	}
+	return false
+}
+
+// PostAccess ตรวจสิทธิ์อ่านโพสต์ตาม visibility ใช้ร่วมกันทั้งหน้าโพสต์ การนับ view และ AI chat
+type PostAccess struct {
+	Repo   PostRepositoryInterface
+	Crypto *crypto.CryptoService
+}
+
+func NewPostAccess(repo PostRepositoryInterface, cryptoService *crypto.CryptoService) *PostAccess {
+	return &PostAccess{Repo: repo, Crypto: cryptoService}
+}
+
+// CanRead public / unlisted อ่านได้ทุกคน protected อ่านได้เมื่อเป็นเจ้าของ ผู้ร่วมเขียน
+// หรือมี token ที่ได้จากการปลดล็อก (cookie) ที่ยังไม่หมดอายุ
+func (a *PostAccess) CanRead(post *models.Post, user *models.User, accessToken string) bool {
+	if post.Visibility != models.VisibilityProtected {
+		return true
+	}
+	if user != nil && Authorize(a.Repo, post, user, ActionView) == nil {
+		return true
+	}
+	return a.Crypto != nil && a.Crypto.VerifyPostAccessToken(accessToken, post.ID.String())
+}
+
+// SetCryptoService ใช้ hash share key และเซ็น token ปลดล็อก ถ้าไม่ตั้ง โพสต์ protected จะอ่านได้เฉพาะเจ้าของและผู้ร่วมเขียน
+func (s *PostService) SetCryptoService(cryptoService *crypto.CryptoService) {
+	s.Access = NewPostAccess(s.Repo, cryptoService)
+}
+
+func (s *PostService) postAccess() *PostAccess {
+	if s.Access == nil {
+		return NewPostAccess(s.Repo, nil)
+	}
+	return s.Access
+}
+
+// CanReadPost ดู PostAccess.CanRead
+func (s *PostService) CanReadPost(post *models.Post, user *models.User, accessToken string) bool {
+	return s.postAccess().CanRead(post, user, accessToken)
+}
+
+// UnlockPost ตรวจ share key / รหัสผ่านของโพสต์ protected แล้วออก token สำหรับเก็บใน cookie
+// โพสต์ที่ไม่ได้ protected คืน token ว่าง (อ่านได้อยู่แล้ว)
+func (s *PostService) UnlockPost(username string, slug string, key string) (string, *UnlockPostResponseDTO, error) {
+	post, err := s.Repo.GetPublicPostBySlugAndUsername(slug, username)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, errs.ErrPostNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	result := &UnlockPostResponseDTO{PostID: post.ID.String()}
+	if post.Visibility != models.VisibilityProtected {
+		return "", result, nil
+	}
+
+	access := s.postAccess()
+	if access.Crypto == nil || post.Key == "" || !access.Crypto.ComparePasswords(key, post.Key) {
+		return "", nil, errs.ErrInvalidPostKey
+	}
+
+	token, err := access.Crypto.GeneratePostAccessToken(post.ID.String(), PostAccessTTL)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to issue post access token: %w", err)
+	}
+
+	expiresAt := time.Now().Add(PostAccessTTL)
+	result.ExpiresAt = &expiresAt
+	return token, result, nil
+}
+
+// UpdateVisibility เปลี่ยน visibility ของโพสต์ (สิทธิ์เดียวกับการ publish)
+// protected: ใช้ password ที่ส่งมา ถ้าไม่ส่งจะใช้ key เดิม หรือสร้าง share key ใหม่ซึ่งแสดงในผลลัพธ์ครั้งเดียว
+func (s *PostService) UpdateVisibility(shortSlug string, req UpdateVisibilityRequestDTO, user *models.User) (*VisibilityResponseDTO, error) {
+	post, err := s.FindPostForUser(shortSlug, user, ActionPublish)
+	if err != nil {
+		return nil, err
+	}
+
+	visibility := models.PostVisibility(strings.ToLower(strings.TrimSpace(string(req.Visibility))))
+	if !ValidVisibility(visibility) {
+		return nil, fmt.Errorf("%w: visibility must be public, unlisted or protected", errs.ErrInvalidPayload)
+	}
+
+	result := &VisibilityResponseDTO{Visibility: visibility}
+	keyHash := ""
+	if visibility == models.VisibilityProtected {
+		keyHash = post.Key
+		secret := strings.TrimSpace(req.Password)
+		if secret != "" && utf8.RuneCountInString(secret) < MinPostPasswordLength {
+			return nil, fmt.Errorf("%w: password must be at least %d characters", errs.ErrInvalidPayload, MinPostPasswordLength)
+		}
+		if secret == "" && (keyHash == "" || req.RegenerateKey) {
+			if secret, err = generateShareKey(); err != nil {
+				return nil, err
+			}
+			result.ShareKey = secret
+		}
+
+		if secret != "" {
+			access := s.postAccess()
+			if access.Crypto == nil {
+				return nil, errors.New("crypto service is not configured")
+			}
+			if keyHash, err = access.Crypto.HashPassword(secret); err != nil {
+				return nil, fmt.Errorf("failed to hash post key: %w", err)
+			}
+		}
+	}
+
+	if err := s.Repo.UpdateVisibility(post.ID.String(), visibility, keyHash); err != nil {
+		return nil, err
+	}
+
+	previous := post.Visibility
+	post.Visibility = visibility
+	post.Key = keyHash
+	result.HasKey = keyHash != ""
+
+	// โพสต์ที่ publish อยู่จะเข้า / ออกจากรายการ ให้ listener ล้าง cache ที่เกี่ยวข้อง
+	if post.Published && previous != visibility {
+		if visibility == models.VisibilityPublic {
+			s.notifyPublished(post)
+		} else if previous == models.VisibilityPublic || previous == "" {
+			s.notifyUnpublished(post)
+		}
+	}
+
+	return result, nil
+}
+
+func generateShareKey() (string, error) {
+	bytes := make([]byte, shareKeyBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
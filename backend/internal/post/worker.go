@@ -53,7 +53,7 @@ func FilterPostContentByAIWorkerHandler(deps FilterPostWorker) asynq.HandlerFunc
 			zap.String("post_title", payload.Post.Title),
 		)
 
-		if len(*payload.Post.HTMLContent) < 100 {
+		if payload.Post.HTMLContent == nil || len(*payload.Post.HTMLContent) < 100 {
 			return handleSkippedContent(deps, t, &payload, startedAt)
 		}
 
@@ -465,3 +465,135 @@ func (deps FilterPostWorker) NotifyUser(post *models.Post, user *models.User, st
 	}
 	return nil
 }
+
+type PruneRevisionsWorker struct {
+	Logger   *zap.Logger
+	PostRepo PostRepositoryInterface
+}
+
+// PruneRevisionsWorkerHandler ลบ revision เก่าตาม retention (เก็บล่าสุด N ตัว + ทุก revision ที่ publish)
+func PruneRevisionsWorkerHandler(deps PruneRevisionsWorker) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		deleted, err := deps.PostRepo.PruneRevisions(RevisionRetentionKeepLast)
+		if err != nil {
+			deps.Logger.Error("Failed to prune post revisions", zap.Error(err))
+			return err
+		}
+
+		deps.Logger.Info("Pruned post revisions",
+			zap.Int64("deleted", deleted),
+			zap.Int("keep_last", RevisionRetentionKeepLast))
+		return nil
+	}
+}
+
+type PurgeTrashWorker struct {
+	Logger   *zap.Logger
+	PostRepo PostRepositoryInterface
+}
+
+// PurgeTrashWorkerHandler ลบโพสต์ที่อยู่ในถังขยะเกิน TrashRetention ออกถาวร
+func PurgeTrashWorkerHandler(deps PurgeTrashWorker) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		purged, err := deps.PostRepo.PurgeDeletedPosts(time.Now().Add(-TrashRetention))
+		if err != nil {
+			deps.Logger.Error("Failed to purge trashed posts", zap.Error(err))
+			return err
+		}
+
+		deps.Logger.Info("Purged trashed posts",
+			zap.Int64("purged", purged),
+			zap.Duration("retention", TrashRetention))
+		return nil
+	}
+}
+
+type RecomputeTrendingWorker struct {
+	Logger   *zap.Logger
+	PostRepo PostRepositoryInterface
+}
+
+// RecomputeTrendingWorkerHandler คำนวณคะแนน trending ของทุก window ใหม่
+func RecomputeTrendingWorkerHandler(deps RecomputeTrendingWorker) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		now := time.Now()
+		for window, duration := range TrendingWindows {
+			scored, err := deps.PostRepo.RecomputeTrendingScores(window, now.Add(-duration))
+			if err != nil {
+				deps.Logger.Error("Failed to recompute trending scores", zap.String("window", window), zap.Error(err))
+				return err
+			}
+
+			deps.Logger.Info("Recomputed trending scores",
+				zap.String("window", window),
+				zap.Int64("posts", scored))
+		}
+		return nil
+	}
+}
+
+type ScheduledPostWorker struct {
+	Logger      *zap.Logger
+	PostService *PostService
+	NotiService *notification.NotificationService
+}
+
+// ScheduledPublishWorkerHandler publish โพสต์ที่ตั้งเวลาไว้ แล้วแจ้งเตือนผู้เขียน
+func ScheduledPublishWorkerHandler(deps ScheduledPostWorker) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var payload ScheduledPostPayload
+		if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+			deps.Logger.Error("Failed to unmarshal scheduled publish payload", zap.Error(err))
+			return err
+		}
+
+		post, err := deps.PostService.PublishScheduledPost(payload.PostID, payload.RunAt)
+		if err != nil {
+			deps.Logger.Error("Failed to publish scheduled post", zap.String("post_id", payload.PostID), zap.Error(err))
+			return err
+		}
+		if post == nil {
+			deps.Logger.Info("Skipping stale scheduled publish task", zap.String("post_id", payload.PostID))
+			return nil
+		}
+
+		deps.Logger.Info("Scheduled post published", zap.String("post_id", payload.PostID))
+
+		link := "/posts/" + post.Author.UserName + "/" + post.Slug
+		message := fmt.Sprintf("Your scheduled post is now live.\n\nPost title %s", post.Title)
+		if err := deps.NotiService.Notify(&post.Author, "Your scheduled post is now live", "notification:"+TaskTypeScheduledPublish, message, &link); err != nil {
+			// โพสต์ publish ไปแล้ว ไม่ต้อง retry task เพียงเพราะแจ้งเตือนไม่สำเร็จ
+			deps.Logger.Error("Failed to notify author about scheduled publish", zap.String("post_id", payload.PostID), zap.Error(err))
+		}
+		return nil
+	}
+}
+
+// ScheduledUnpublishWorkerHandler unpublish โพสต์เมื่อถึงเวลา unpublish_at แล้วแจ้งเตือนผู้เขียน
+func ScheduledUnpublishWorkerHandler(deps ScheduledPostWorker) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var payload ScheduledPostPayload
+		if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+			deps.Logger.Error("Failed to unmarshal scheduled unpublish payload", zap.Error(err))
+			return err
+		}
+
+		post, err := deps.PostService.UnpublishScheduledPost(payload.PostID, payload.RunAt)
+		if err != nil {
+			deps.Logger.Error("Failed to unpublish scheduled post", zap.String("post_id", payload.PostID), zap.Error(err))
+			return err
+		}
+		if post == nil {
+			deps.Logger.Info("Skipping stale scheduled unpublish task", zap.String("post_id", payload.PostID))
+			return nil
+		}
+
+		deps.Logger.Info("Scheduled post unpublished", zap.String("post_id", payload.PostID))
+
+		message := fmt.Sprintf("Your post has been unpublished as scheduled.\n\nPost title %s", post.Title)
+		if err := deps.NotiService.Notify(&post.Author, "Your post has been unpublished", "notification:"+TaskTypeScheduledUnpublish, message, nil); err != nil {
+			deps.Logger.Error("Failed to notify author about scheduled unpublish", zap.String("post_id", payload.PostID), zap.Error(err))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,20 @@
+package reaction
+
+import "rag-searchbot-backend/internal/models"
+
+type ToggleReactionRequest struct {
+	Type models.ReactionType `json:"type" binding:"required"`
+}
+
+// ReactionSummaryDTO จำนวน reaction แยกตามประเภท และประเภทที่ผู้ใช้ปัจจุบันกดไว้ (ว่างถ้าไม่ได้ login)
+type ReactionSummaryDTO struct {
+	Counts  map[models.ReactionType]int64 `json:"counts"`
+	Total   int64                         `json:"total"`
+	Reacted []models.ReactionType         `json:"reacted"`
+}
+
+type ToggleReactionResponse struct {
+	Type    models.ReactionType `json:"type"`
+	Added   bool                `json:"added"`
+	Summary *ReactionSummaryDTO `json:"summary"`
+}
@@ -0,0 +1,125 @@
+package reaction
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryInterface interface {
+	Toggle(reaction *models.PostReaction) (bool, error)
+	CountByPost(postID string) (map[models.ReactionType]int64, error)
+	GetUserReactions(postID, userID string) ([]models.ReactionType, error)
+	SummarizeSince(postID, excludeUserID string, since time.Time) (map[models.ReactionType]int64, int64, error)
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+type reactionCount struct {
+	Type  models.ReactionType
+	Count int64
+}
+
+/**
+* Toggle adds the reaction if the user has not reacted with this type yet, otherwise removes it.
+* @param reaction *models.PostReaction - The post, user and reaction type
+* @return bool - true if the reaction was added, false if it was removed
+* @return error - An error if occurred
+* Post.Likes is a denormalized count of "like" reactions and is updated in the same transaction.
+**/
+
+func (r *Repository) Toggle(reaction *models.PostReaction) (bool, error) {
+	added := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		removed := tx.
+			Where("post_id = ? AND user_id = ? AND type = ?", reaction.PostID, reaction.UserID, reaction.Type).
+			Delete(&models.PostReaction{})
+		if removed.Error != nil {
+			return removed.Error
+		}
+
+		delta := 0
+		if removed.RowsAffected > 0 {
+			delta = -1
+		} else {
+			// กดพร้อมกันสองครั้ง: แถวที่ชนกับ unique index ถือว่ากดไปแล้ว ไม่ต้องนับซ้ำ
+			inserted := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+			if inserted.Error != nil {
+				return inserted.Error
+			}
+			added = true
+			if inserted.RowsAffected > 0 {
+				delta = 1
+			}
+		}
+
+		if reaction.Type != models.ReactionLike || delta == 0 {
+			return nil
+		}
+		// UpdateColumn ไม่แตะ updated_at: like ไม่ใช่การแก้โพสต์ (feed / sitemap ใช้ updated_at เป็นเวลาแก้ไข)
+		return tx.Model(&models.Post{}).
+			Where("id = ?", reaction.PostID).
+			UpdateColumn("likes", gorm.Expr("GREATEST(likes + ?, 0)", delta)).Error
+	})
+
+	return added, err
+}
+
+func toCountMap(rows []reactionCount) map[models.ReactionType]int64 {
+	counts := make(map[models.ReactionType]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts
+}
+
+func (r *Repository) CountByPost(postID string) (map[models.ReactionType]int64, error) {
+	var rows []reactionCount
+	err := r.DB.Model(&models.PostReaction{}).
+		Select("type, COUNT(*) AS count").
+		Where("post_id = ?", postID).
+		Group("type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return toCountMap(rows), nil
+}
+
+func (r *Repository) GetUserReactions(postID, userID string) ([]models.ReactionType, error) {
+	var types []models.ReactionType
+	err := r.DB.Model(&models.PostReaction{}).
+		Where("post_id = ? AND user_id = ?", postID, userID).
+		Order("type").
+		Pluck("type", &types).Error
+	return types, err
+}
+
+// SummarizeSince นับ reaction ที่ยังอยู่ตั้งแต่เวลา since แยกตามประเภท พร้อมจำนวนผู้ใช้ที่ไม่ซ้ำ
+func (r *Repository) SummarizeSince(postID, excludeUserID string, since time.Time) (map[models.ReactionType]int64, int64, error) {
+	base := func() *gorm.DB {
+		return r.DB.Model(&models.PostReaction{}).
+			Where("post_id = ? AND user_id <> ? AND created_at >= ?", postID, excludeUserID, since)
+	}
+
+	var rows []reactionCount
+	if err := base().Select("type, COUNT(*) AS count").Group("type").Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users int64
+	if err := base().Distinct("user_id").Count(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return toCountMap(rows), users, nil
+}
@@ -0,0 +1,134 @@
+package reaction
+
+import (
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AllowedReactions ประเภท reaction ที่รองรับ
+var AllowedReactions = map[models.ReactionType]bool{
+	models.ReactionLike:  true,
+	models.ReactionLove:  true,
+	models.ReactionClap:  true,
+	models.ReactionFire:  true,
+	models.ReactionLaugh: true,
+	models.ReactionWow:   true,
+}
+
+type ServiceInterface interface {
+	Toggle(postID string, reactionType models.ReactionType, user *models.User, accessToken string) (*ToggleReactionResponse, error)
+	GetSummary(postID string, user *models.User, accessToken string) (*ReactionSummaryDTO, error)
+}
+
+type Service struct {
+	Repo         RepositoryInterface
+	PostRepo     post.PostRepositoryInterface
+	TaskEnqueuer *TaskEnqueuer
+	Access       *post.PostAccess
+}
+
+func NewService(repo RepositoryInterface, postRepo post.PostRepositoryInterface, enqueuer *TaskEnqueuer, access *post.PostAccess) ServiceInterface {
+	return &Service{Repo: repo, PostRepo: postRepo, TaskEnqueuer: enqueuer, Access: access}
+}
+
+// getReadablePost โพสต์ที่ publish แล้วและผู้ขออ่านได้ตาม visibility
+func (s *Service) getReadablePost(postID string, user *models.User, accessToken string) (*models.Post, error) {
+	p, err := s.PostRepo.GetByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrPostNotFound
+		}
+		return nil, err
+	}
+	if !p.Published || p.Status != models.PostPublished {
+		return nil, errs.ErrPostNotFound
+	}
+	if s.Access == nil {
+		if p.Visibility == models.VisibilityProtected {
+			return nil, errs.ErrPostProtected
+		}
+		return p, nil
+	}
+	if !s.Access.CanRead(p, user, accessToken) {
+		return nil, errs.ErrPostProtected
+	}
+	return p, nil
+}
+
+// Toggle กด / ยกเลิก reaction แล้วคืนสรุปล่าสุด ถ้าเป็นการกดใหม่จะตั้ง digest แจ้งผู้เขียน
+func (s *Service) Toggle(postID string, reactionType models.ReactionType, user *models.User, accessToken string) (*ToggleReactionResponse, error) {
+	reactionType = models.ReactionType(strings.ToLower(strings.TrimSpace(string(reactionType))))
+	if !AllowedReactions[reactionType] {
+		return nil, fmt.Errorf("%w: unsupported reaction type %q", errs.ErrInvalidPayload, reactionType)
+	}
+
+	p, err := s.getReadablePost(postID, user, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	added, err := s.Repo.Toggle(&models.PostReaction{
+		PostID: p.ID,
+		UserID: user.ID,
+		Type:   reactionType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if added && p.AuthorID != user.ID {
+		if err := s.TaskEnqueuer.EnqueueDigest(p.ID.String(), time.Now()); err != nil {
+			// reaction บันทึกแล้ว การแจ้งเตือนเป็นเรื่องรอง
+			logger.Log.Warn("Failed to enqueue reaction digest", zap.String("post_id", postID), zap.Error(err))
+		}
+	}
+
+	summary, err := s.summarize(p.ID.String(), user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ToggleReactionResponse{Type: reactionType, Added: added, Summary: summary}, nil
+}
+
+// GetSummary จำนวน reaction ของโพสต์ และ reaction ของผู้ใช้ปัจจุบัน (user เป็น nil ได้)
+func (s *Service) GetSummary(postID string, user *models.User, accessToken string) (*ReactionSummaryDTO, error) {
+	p, err := s.getReadablePost(postID, user, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(p.ID.String(), user)
+}
+
+func (s *Service) summarize(postID string, user *models.User) (*ReactionSummaryDTO, error) {
+	counts, err := s.Repo.CountByPost(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ReactionSummaryDTO{Counts: counts, Reacted: []models.ReactionType{}}
+	for _, count := range counts {
+		summary.Total += count
+	}
+
+	if user != nil {
+		reacted, err := s.Repo.GetUserReactions(postID, user.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if reacted != nil {
+			summary.Reacted = reacted
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,47 @@
+package reaction
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const TaskTypeReactionDigest = "reaction:digest"
+
+// ReactionDigestDelay ระยะเวลารวบ reaction ก่อนแจ้งผู้เขียนหนึ่งครั้ง
+const ReactionDigestDelay = 15 * time.Minute
+
+// ReactionDigestPayload นับ reaction ของโพสต์ตั้งแต่ Since (เวลาที่ reaction แรกของรอบนี้เข้ามา)
+type ReactionDigestPayload struct {
+	PostID string    `json:"post_id"`
+	Since  time.Time `json:"since"`
+}
+
+type TaskEnqueuer struct {
+	Client *asynq.Client
+}
+
+func NewTaskEnqueuer(client *asynq.Client) *TaskEnqueuer {
+	return &TaskEnqueuer{Client: client}
+}
+
+// EnqueueDigest ตั้ง task แจ้งเตือนแบบรวบยอด ใช้ TaskID ต่อโพสต์ เพื่อให้มีได้แค่ task เดียวที่รออยู่
+// ถ้ามี task รออยู่แล้วจะไม่ทำอะไร reaction นี้จะถูกนับในรอบเดียวกัน
+func (t *TaskEnqueuer) EnqueueDigest(postID string, since time.Time) error {
+	payload, err := json.Marshal(ReactionDigestPayload{PostID: postID, Since: since})
+	if err != nil {
+		return err
+	}
+
+	_, err = t.Client.Enqueue(
+		asynq.NewTask(TaskTypeReactionDigest, payload),
+		asynq.TaskID("reaction-digest:"+postID),
+		asynq.ProcessIn(ReactionDigestDelay),
+	)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/reaction"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// SQLite ไม่มี GREATEST ของ Postgres ที่ Toggle ใช้กันจำนวน like ติดลบ จึงลงทะเบียนไว้ให้ใน driver ของ test
+func init() {
+	sql.Register("sqlite3_greatest", &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("greatest", func(a, b int64) int64 {
+				if a > b {
+					return a
+				}
+				return b
+			}, true)
+		},
+	})
+}
+
+func newReactionRepository(t *testing.T) (*gorm.DB, reaction.RepositoryInterface) {
+	db, err := gorm.Open(sqlite.Dialector{DriverName: "sqlite3_greatest", DSN: ":memory:"}, &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // :memory: แยกฐานข้อมูลต่อ connection
+
+	for _, ddl := range []string{
+		`CREATE TABLE posts (id TEXT PRIMARY KEY, likes INTEGER DEFAULT 0, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_reactions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, post_id TEXT NOT NULL, user_id TEXT NOT NULL,
+			type TEXT NOT NULL, created_at DATETIME)`,
+		`CREATE UNIQUE INDEX idx_post_reaction ON post_reactions (post_id, user_id, type)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db, reaction.NewRepository(db)
+}
+
+func likesOf(t *testing.T, db *gorm.DB, postID uuid.UUID) int64 {
+	var likes int64
+	require.NoError(t, db.Raw(`SELECT likes FROM posts WHERE id = ?`, postID.String()).Scan(&likes).Error)
+	return likes
+}
+
+func TestReactionRepository_ToggleKeepsLikeCounter(t *testing.T) {
+	db, repo := newReactionRepository(t)
+	postID, alice, bob := uuid.New(), uuid.New(), uuid.New()
+	editedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, likes, updated_at) VALUES (?, 0, ?)`, postID.String(), editedAt).Error)
+
+	steps := []struct {
+		name      string
+		user      uuid.UUID
+		typ       models.ReactionType
+		wantAdded bool
+		wantLikes int64
+	}{
+		{"alice likes", alice, models.ReactionLike, true, 1},
+		{"bob likes", bob, models.ReactionLike, true, 2},
+		{"alice loves does not touch likes", alice, models.ReactionLove, true, 2},
+		{"alice unlikes", alice, models.ReactionLike, false, 1},
+		{"alice likes again", alice, models.ReactionLike, true, 2},
+		{"bob unlikes", bob, models.ReactionLike, false, 1},
+		{"alice unloves", alice, models.ReactionLove, false, 1},
+	}
+	for _, step := range steps {
+		added, err := repo.Toggle(&models.PostReaction{PostID: postID, UserID: step.user, Type: step.typ})
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.wantAdded, added, step.name)
+		assert.Equal(t, step.wantLikes, likesOf(t, db, postID), step.name)
+	}
+
+	// like ไม่ใช่การแก้โพสต์ updated_at ต้องคงเดิม
+	var updatedAt time.Time
+	require.NoError(t, db.Raw(`SELECT updated_at FROM posts WHERE id = ?`, postID.String()).Scan(&updatedAt).Error)
+	assert.True(t, editedAt.Equal(updatedAt), "updated_at changed to %v", updatedAt)
+
+	counts, err := repo.CountByPost(postID.String())
+	require.NoError(t, err)
+	assert.Equal(t, map[models.ReactionType]int64{models.ReactionLike: 1}, counts)
+
+	reacted, err := repo.GetUserReactions(postID.String(), alice.String())
+	require.NoError(t, err)
+	assert.Equal(t, []models.ReactionType{models.ReactionLike}, reacted)
+}
+
+// Test case: counter ที่คลาดจากจำนวนแถวจริง (เช่นข้อมูลเก่า) ต้องไม่ติดลบเมื่อกดยกเลิก
+func TestReactionRepository_LikeCounterNeverNegative(t *testing.T) {
+	db, repo := newReactionRepository(t)
+	postID, alice := uuid.New(), uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO posts (id, likes) VALUES (?, 0)`, postID.String()).Error)
+	require.NoError(t, db.Exec(`INSERT INTO post_reactions (post_id, user_id, type, created_at) VALUES (?, ?, ?, ?)`,
+		postID.String(), alice.String(), models.ReactionLike, time.Now()).Error)
+
+	added, err := repo.Toggle(&models.PostReaction{PostID: postID, UserID: alice, Type: models.ReactionLike})
+	require.NoError(t, err)
+	assert.False(t, added)
+	assert.Equal(t, int64(0), likesOf(t, db, postID))
+}
+
+func TestReactionRepository_SummarizeSince(t *testing.T) {
+	db, repo := newReactionRepository(t)
+	postID, author, alice, bob := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	since := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, row := range []struct {
+		user uuid.UUID
+		typ  models.ReactionType
+		at   time.Time
+	}{
+		{alice, models.ReactionLike, since.Add(time.Minute)},
+		{alice, models.ReactionFire, since.Add(2 * time.Minute)},
+		{bob, models.ReactionLike, since.Add(3 * time.Minute)},
+		{bob, models.ReactionWow, since.Add(-time.Minute)},    // ก่อนรอบนี้
+		{author, models.ReactionLove, since.Add(time.Minute)}, // ผู้เขียนกดเอง
+	} {
+		require.NoError(t, db.Exec(`INSERT INTO post_reactions (post_id, user_id, type, created_at) VALUES (?, ?, ?, ?)`,
+			postID.String(), row.user.String(), row.typ, row.at).Error)
+	}
+
+	counts, users, err := repo.SummarizeSince(postID.String(), author.String(), since)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), users)
+	assert.Equal(t, map[models.ReactionType]int64{models.ReactionLike: 2, models.ReactionFire: 1}, counts)
+}
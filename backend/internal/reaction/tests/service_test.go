@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/reaction"
+	"rag-searchbot-backend/pkg/crypto"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MockReactionRepository struct {
+	mock.Mock
+}
+
+func (m *MockReactionRepository) Toggle(r *models.PostReaction) (bool, error) {
+	args := m.Called(r)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReactionRepository) CountByPost(postID string) (map[models.ReactionType]int64, error) {
+	args := m.Called(postID)
+	return args.Get(0).(map[models.ReactionType]int64), args.Error(1)
+}
+
+func (m *MockReactionRepository) GetUserReactions(postID, userID string) ([]models.ReactionType, error) {
+	args := m.Called(postID, userID)
+	return args.Get(0).([]models.ReactionType), args.Error(1)
+}
+
+func (m *MockReactionRepository) SummarizeSince(postID, excludeUserID string, since time.Time) (map[models.ReactionType]int64, int64, error) {
+	args := m.Called(postID, excludeUserID, since)
+	return args.Get(0).(map[models.ReactionType]int64), args.Get(1).(int64), args.Error(2)
+}
+
+// MockPostRepository mock เฉพาะ method ที่ reaction ใช้ method อื่นจะ panic ถ้าถูกเรียก
+type MockPostRepository struct {
+	post.PostRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetByID(id string) (*models.Post, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) GetCollaboratorRole(postID, userID string) (models.CollaboratorRole, error) {
+	args := m.Called(postID, userID)
+	return args.Get(0).(models.CollaboratorRole), args.Error(1)
+}
+
+func TestReactions_ProtectedPostRequiresAccess(t *testing.T) {
+	t.Setenv("POST_ACCESS_SECRET", "test-secret")
+	repo := new(MockReactionRepository)
+	postRepo := new(MockPostRepository)
+	cryptoService := crypto.NewCryptoService()
+	service := reaction.NewService(repo, postRepo, &reaction.TaskEnqueuer{}, post.NewPostAccess(postRepo, cryptoService))
+
+	owner := &models.User{ID: uuid.New()}
+	reader := &models.User{ID: uuid.New()}
+	now := time.Now()
+	p := &models.Post{
+		ID:          uuid.New(),
+		AuthorID:    owner.ID,
+		Published:   true,
+		PublishedAt: &now,
+		Status:      models.PostPublished,
+		Visibility:  models.VisibilityProtected,
+	}
+	postID := p.ID.String()
+
+	postRepo.On("GetByID", postID).Return(p, nil)
+	postRepo.On("GetCollaboratorRole", postID, reader.ID.String()).Return(models.CollaboratorRole(""), gorm.ErrRecordNotFound)
+	// reader กดซ้ำเป็นการยกเลิก จึงไม่ตั้ง digest
+	repo.On("Toggle", mock.AnythingOfType("*models.PostReaction")).Return(false, nil)
+	repo.On("CountByPost", postID).Return(map[models.ReactionType]int64{models.ReactionLike: 1}, nil)
+	repo.On("GetUserReactions", postID, mock.Anything).Return([]models.ReactionType{}, nil)
+
+	_, err := service.GetSummary(postID, nil, "")
+	assert.ErrorIs(t, err, errs.ErrPostProtected)
+	_, err = service.Toggle(postID, models.ReactionLike, reader, "")
+	assert.ErrorIs(t, err, errs.ErrPostProtected)
+	repo.AssertNotCalled(t, "Toggle", mock.Anything)
+
+	summary, err := service.GetSummary(postID, owner, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), summary.Total)
+
+	token, err := cryptoService.GeneratePostAccessToken(postID, time.Minute)
+	require.NoError(t, err)
+	_, err = service.GetSummary(postID, nil, token)
+	assert.NoError(t, err)
+	_, err = service.Toggle(postID, models.ReactionLike, reader, token)
+	assert.NoError(t, err)
+}
+
+func newReactionService(t *testing.T, enqueuer *reaction.TaskEnqueuer) (reaction.ServiceInterface, *MockReactionRepository, *MockPostRepository) {
+	logger.Log = zap.NewNop()
+	repo := new(MockReactionRepository)
+	postRepo := new(MockPostRepository)
+	return reaction.NewService(repo, postRepo, enqueuer, nil), repo, postRepo
+}
+
+func TestToggle_ValidatesType(t *testing.T) {
+	cases := []struct {
+		name    string
+		typ     models.ReactionType
+		want    models.ReactionType
+		wantErr error
+	}{
+		{"lower case", "like", models.ReactionLike, nil},
+		{"normalized", "  FIRE ", models.ReactionFire, nil},
+		{"unknown", "angry", "", errs.ErrInvalidPayload},
+		{"empty", "", "", errs.ErrInvalidPayload},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo, postRepo := newReactionService(t, &reaction.TaskEnqueuer{})
+			author := &models.User{ID: uuid.New()}
+			now := time.Now()
+			p := &models.Post{ID: uuid.New(), AuthorID: author.ID, Published: true, PublishedAt: &now, Status: models.PostPublished}
+
+			postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+			// ผู้เขียนกดเองไม่ตั้ง digest
+			repo.On("Toggle", mock.MatchedBy(func(r *models.PostReaction) bool { return r.Type == tc.want })).Return(true, nil)
+			repo.On("CountByPost", p.ID.String()).Return(map[models.ReactionType]int64{tc.want: 1}, nil)
+			repo.On("GetUserReactions", p.ID.String(), author.ID.String()).Return([]models.ReactionType{tc.want}, nil)
+
+			result, err := service.Toggle(p.ID.String(), tc.typ, author, "")
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				repo.AssertNotCalled(t, "Toggle", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, result.Type)
+			assert.True(t, result.Added)
+		})
+	}
+}
+
+func TestToggle_UnpublishedPostIsNotFound(t *testing.T) {
+	service, repo, postRepo := newReactionService(t, &reaction.TaskEnqueuer{})
+	p := &models.Post{ID: uuid.New(), Status: models.PostDraft}
+	postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+	postRepo.On("GetByID", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.Toggle(p.ID.String(), models.ReactionLike, &models.User{ID: uuid.New()}, "")
+	assert.ErrorIs(t, err, errs.ErrPostNotFound)
+	_, err = service.GetSummary("missing", nil, "")
+	assert.ErrorIs(t, err, errs.ErrPostNotFound)
+	repo.AssertNotCalled(t, "Toggle", mock.Anything)
+}
+
+// Test case: ตั้ง digest ไม่สำเร็จ (Redis ล่ม) ต้องไม่ทำให้ reaction ที่บันทึกแล้วล้มเหลว
+func TestToggle_DigestFailureDoesNotFailReaction(t *testing.T) {
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond})
+	defer client.Close()
+	service, repo, postRepo := newReactionService(t, reaction.NewTaskEnqueuer(client))
+
+	author := &models.User{ID: uuid.New()}
+	reader := &models.User{ID: uuid.New()}
+	now := time.Now()
+	p := &models.Post{ID: uuid.New(), AuthorID: author.ID, Published: true, PublishedAt: &now, Status: models.PostPublished}
+	postID := p.ID.String()
+
+	postRepo.On("GetByID", postID).Return(p, nil)
+	repo.On("Toggle", mock.AnythingOfType("*models.PostReaction")).Return(true, nil)
+	repo.On("CountByPost", postID).Return(map[models.ReactionType]int64{models.ReactionLike: 2, models.ReactionClap: 1}, nil)
+	repo.On("GetUserReactions", postID, reader.ID.String()).Return([]models.ReactionType{models.ReactionLike}, nil)
+
+	result, err := service.Toggle(postID, models.ReactionLike, reader, "")
+	require.NoError(t, err)
+	assert.True(t, result.Added)
+	assert.Equal(t, int64(3), result.Summary.Total)
+	assert.Equal(t, []models.ReactionType{models.ReactionLike}, result.Summary.Reacted)
+}
+
+func TestGetSummary_AnonymousHasNoReactedList(t *testing.T) {
+	service, repo, postRepo := newReactionService(t, &reaction.TaskEnqueuer{})
+	now := time.Now()
+	p := &models.Post{ID: uuid.New(), Published: true, PublishedAt: &now, Status: models.PostPublished}
+	postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+	repo.On("CountByPost", p.ID.String()).Return(map[models.ReactionType]int64{}, nil)
+
+	summary, err := service.GetSummary(p.ID.String(), nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), summary.Total)
+	assert.NotNil(t, summary.Reacted)
+	assert.Empty(t, summary.Reacted)
+	repo.AssertNotCalled(t, "GetUserReactions", mock.Anything, mock.Anything)
+}
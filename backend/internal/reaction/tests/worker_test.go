@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/notification"
+	"rag-searchbot-backend/internal/reaction"
+	"rag-searchbot-backend/internal/ws"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MockNotificationRepository mock เฉพาะ Create ที่ NotificationService.Notify ใช้
+type MockNotificationRepository struct {
+	notification.RepositoryInterface
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(noti *models.Notification) (*models.Notification, error) {
+	args := m.Called(noti)
+	return noti, args.Error(0)
+}
+
+func newDigestHandler() (asynq.HandlerFunc, *MockReactionRepository, *MockPostRepository, *MockNotificationRepository) {
+	repo := new(MockReactionRepository)
+	postRepo := new(MockPostRepository)
+	notiRepo := new(MockNotificationRepository)
+	handler := reaction.ReactionDigestWorkerHandler(reaction.DigestWorker{
+		Logger:       zap.NewNop(),
+		ReactionRepo: repo,
+		PostRepo:     postRepo,
+		NotiService:  &notification.NotificationService{NotiRepo: notiRepo, SocketManager: ws.NewManager()},
+	})
+	return handler, repo, postRepo, notiRepo
+}
+
+func digestTask(t *testing.T, postID string, since time.Time) *asynq.Task {
+	payload, err := json.Marshal(reaction.ReactionDigestPayload{PostID: postID, Since: since})
+	require.NoError(t, err)
+	return asynq.NewTask(reaction.TaskTypeReactionDigest, payload)
+}
+
+func TestReactionDigestWorker_NotifiesAuthorOnce(t *testing.T) {
+	handler, repo, postRepo, notiRepo := newDigestHandler()
+	author := models.User{ID: uuid.New(), UserName: "writer"}
+	p := &models.Post{ID: uuid.New(), AuthorID: author.ID, Author: author, Title: "Hello", Slug: "hello"}
+	since := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+	repo.On("SummarizeSince", p.ID.String(), author.ID.String(), since).
+		Return(map[models.ReactionType]int64{models.ReactionLike: 3, models.ReactionFire: 1}, int64(2), nil)
+	notiRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == author.ID &&
+			n.Title == "2 people reacted to your post" &&
+			n.Content == "Post title Hello\n\nfire 1, like 3" &&
+			n.Link == "/posts/writer/hello"
+	})).Return(nil).Once()
+
+	require.NoError(t, handler(context.Background(), digestTask(t, p.ID.String(), since)))
+	notiRepo.AssertExpectations(t)
+}
+
+func TestReactionDigestWorker_SkipsWithoutNotifying(t *testing.T) {
+	since := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("post deleted", func(t *testing.T) {
+		handler, repo, postRepo, notiRepo := newDigestHandler()
+		postID := uuid.NewString()
+		postRepo.On("GetByID", postID).Return(nil, gorm.ErrRecordNotFound)
+
+		// ต้องจบแบบสำเร็จ task จะไม่ถูก archive และ TaskID ว่างให้รอบใหม่หลังกู้โพสต์
+		assert.NoError(t, handler(context.Background(), digestTask(t, postID, since)))
+		repo.AssertNotCalled(t, "SummarizeSince", mock.Anything, mock.Anything, mock.Anything)
+		notiRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("all reactions withdrawn", func(t *testing.T) {
+		handler, repo, postRepo, notiRepo := newDigestHandler()
+		p := &models.Post{ID: uuid.New(), AuthorID: uuid.New()}
+		postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+		repo.On("SummarizeSince", p.ID.String(), p.AuthorID.String(), since).
+			Return(map[models.ReactionType]int64{}, int64(0), nil)
+
+		assert.NoError(t, handler(context.Background(), digestTask(t, p.ID.String(), since)))
+		notiRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestReactionDigestWorker_RetriesTransientErrors(t *testing.T) {
+	since := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	failure := errors.New("db down")
+
+	t.Run("loading the post", func(t *testing.T) {
+		handler, _, postRepo, _ := newDigestHandler()
+		postID := uuid.NewString()
+		postRepo.On("GetByID", postID).Return(nil, failure)
+
+		assert.ErrorIs(t, handler(context.Background(), digestTask(t, postID, since)), failure)
+	})
+
+	t.Run("saving the notification", func(t *testing.T) {
+		handler, repo, postRepo, notiRepo := newDigestHandler()
+		p := &models.Post{ID: uuid.New(), AuthorID: uuid.New()}
+		postRepo.On("GetByID", p.ID.String()).Return(p, nil)
+		repo.On("SummarizeSince", p.ID.String(), p.AuthorID.String(), since).
+			Return(map[models.ReactionType]int64{models.ReactionLike: 1}, int64(1), nil)
+		notiRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+			return n.Title == "1 person reacted to your post"
+		})).Return(failure)
+
+		assert.ErrorIs(t, handler(context.Background(), digestTask(t, p.ID.String(), since)), failure)
+	})
+}
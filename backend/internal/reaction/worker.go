@@ -0,0 +1,86 @@
+package reaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/notification"
+	"rag-searchbot-backend/internal/post"
+	"sort"
+	"strings"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type DigestWorker struct {
+	Logger       *zap.Logger
+	ReactionRepo RepositoryInterface
+	PostRepo     post.PostRepositoryInterface
+	NotiService  *notification.NotificationService
+}
+
+// ReactionDigestWorkerHandler แจ้งผู้เขียนหนึ่งครั้งต่อรอบ แทนการแจ้งทุกครั้งที่มีคนกด
+func ReactionDigestWorkerHandler(deps DigestWorker) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var payload ReactionDigestPayload
+		if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+			deps.Logger.Error("Failed to parse payload", zap.Error(err))
+			return err
+		}
+
+		p, err := deps.PostRepo.GetByID(payload.PostID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// โพสต์ถูกลบไปแล้ว ห้าม return error เพราะ task ที่ถูก archive จะยึด TaskID ไว้
+			// และ EnqueueDigest จะข้ามรอบใหม่ทั้งหมดหลังกู้โพสต์คืนจนกว่า archive จะหมดอายุ
+			deps.Logger.Info("Skipping reaction digest for deleted post", zap.String("post_id", payload.PostID))
+			return nil
+		}
+		if err != nil {
+			deps.Logger.Error("Failed to get post for reaction digest", zap.String("post_id", payload.PostID), zap.Error(err))
+			return err
+		}
+
+		counts, users, err := deps.ReactionRepo.SummarizeSince(payload.PostID, p.AuthorID.String(), payload.Since)
+		if err != nil {
+			deps.Logger.Error("Failed to summarize reactions", zap.String("post_id", payload.PostID), zap.Error(err))
+			return err
+		}
+
+		// reaction ทั้งหมดในรอบนี้ถูกกดยกเลิกไปแล้ว
+		if users == 0 {
+			return nil
+		}
+
+		title := "1 person reacted to your post"
+		if users > 1 {
+			title = fmt.Sprintf("%d people reacted to your post", users)
+		}
+		message := fmt.Sprintf("Post title %s\n\n%s", p.Title, formatCounts(counts))
+		link := "/posts/" + p.Author.UserName + "/" + p.Slug
+
+		if err := deps.NotiService.Notify(&p.Author, title, "notification:"+TaskTypeReactionDigest, message, &link); err != nil {
+			deps.Logger.Error("Failed to notify author about reactions", zap.String("post_id", payload.PostID), zap.Error(err))
+			return err
+		}
+		return nil
+	}
+}
+
+// formatCounts เช่น "like 3, love 1"
+func formatCounts(counts map[models.ReactionType]int64) string {
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+
+	parts := make([]string, 0, len(types))
+	for _, t := range types {
+		parts = append(parts, fmt.Sprintf("%s %d", t, counts[models.ReactionType(t)]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,13 @@
+package related
+
+import "rag-searchbot-backend/internal/post"
+
+type RelatedPostDTO struct {
+	post.PostSummaryDTO
+	Score      float64 `json:"score"`
+	SharedTags int     `json:"shared_tags"`
+}
+
+type RelatedPostsResponse struct {
+	Posts []RelatedPostDTO `json:"posts"`
+}
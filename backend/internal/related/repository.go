@@ -0,0 +1,63 @@
+package related
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Candidate โพสต์ที่ centroid ใกล้กับโพสต์ต้นทาง พร้อมจำนวน tag ที่ใช้ร่วมกัน
+type Candidate struct {
+	PostID     uuid.UUID
+	Similarity float64
+	SharedTags int
+}
+
+type RepositoryInterface interface {
+	UpsertCentroid(postID uuid.UUID, vector pgvector.Vector) error
+	FindNearest(postID uuid.UUID, excludeAuthorID *uuid.UUID, limit int) ([]Candidate, error)
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) UpsertCentroid(postID uuid.UUID, vector pgvector.Vector) error {
+	centroid := models.PostCentroid{PostID: postID, Vector: vector}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"vector", "updated_at"}),
+	}).Create(&centroid).Error
+}
+
+// FindNearest หาโพสต์ที่ publish แล้วซึ่ง centroid ใกล้กับโพสต์ต้นทางที่สุด (cosine)
+// ถ้าโพสต์ต้นทางยังไม่มี centroid จะได้ผลลัพธ์ว่าง
+func (r *Repository) FindNearest(postID uuid.UUID, excludeAuthorID *uuid.UUID, limit int) ([]Candidate, error) {
+	var candidates []Candidate
+
+	query := r.DB.Table("post_centroids AS c").
+		Select(`c.post_id, 1 - (c.vector <=> t.vector) AS similarity,
+			(SELECT COUNT(*) FROM post_tags pt
+				WHERE pt.post_id = c.post_id
+				AND pt.tag_id IN (SELECT tag_id FROM post_tags WHERE post_id = t.post_id)) AS shared_tags`).
+		Joins("JOIN post_centroids t ON t.post_id = ?", postID).
+		Joins("JOIN posts p ON p.id = c.post_id").
+		Where("c.post_id <> t.post_id").
+		Where(post.PublishedPostCondition("p")).
+		Where(post.ListedPostCondition("p"))
+
+	if excludeAuthorID != nil {
+		query = query.Where("p.author_id <> ?", *excludeAuthorID)
+	}
+
+	err := query.Order("c.vector <=> t.vector").Limit(limit).Scan(&candidates).Error
+	return candidates, err
+}
@@ -0,0 +1,199 @@
+package related
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/cache"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 5
+	MaxLimit     = 20
+
+	// TagBoost คะแนนที่บวกเพิ่มต่อ tag ที่ใช้ร่วมกัน (นับสูงสุด maxBoostedTags tag)
+	TagBoost       = 0.05
+	maxBoostedTags = 3
+
+	candidateFactor = 4 // ดึง candidate มากกว่า limit เพื่อให้ tag boost มีผลกับการจัดอันดับ
+	cachePrefix     = "cache:related:"
+)
+
+type ServiceInterface interface {
+	GetRelated(postID string, limit int, excludeAuthor bool) (*RelatedPostsResponse, error)
+	RefreshCentroid(postID uuid.UUID, embeddings []models.Embedding) error
+}
+
+type Service struct {
+	Repo     RepositoryInterface
+	PostRepo post.PostRepositoryInterface
+	Cache    cache.ServiceInterface
+}
+
+func NewService(repo RepositoryInterface, postRepo post.PostRepositoryInterface, cacheService cache.ServiceInterface) *Service {
+	return &Service{Repo: repo, PostRepo: postRepo, Cache: cacheService}
+}
+
+func cacheKey(postID string, limit int, excludeAuthor bool) string {
+	return fmt.Sprintf("%s%s:%d:%t", cachePrefix, postID, limit, excludeAuthor)
+}
+
+// centroid เฉลี่ย vector ของทุก chunk
+func centroid(embeddings []models.Embedding) []float32 {
+	var sum []float32
+	for _, embedding := range embeddings {
+		vec := embedding.Vector.Slice()
+		if sum == nil {
+			sum = make([]float32, len(vec))
+		}
+		for i := 0; i < len(sum) && i < len(vec); i++ {
+			sum[i] += vec[i]
+		}
+	}
+	for i := range sum {
+		sum[i] /= float32(len(embeddings))
+	}
+	return sum
+}
+
+// RefreshCentroid ถูกเรียกเมื่อ embed โพสต์เสร็จ เก็บ centroid ใหม่และล้าง cache ที่อาจไม่ตรงแล้ว
+func (s *Service) RefreshCentroid(postID uuid.UUID, embeddings []models.Embedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+	if err := s.Repo.UpsertCentroid(postID, pgvector.NewVector(centroid(embeddings))); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// invalidate ล้าง related posts ทุกโพสต์ เพราะโพสต์หนึ่งอาจอยู่ในรายการของโพสต์อื่นได้ทั้งหมด
+func (s *Service) invalidate() {
+	s.Cache.DeletePrefix(cachePrefix)
+}
+
+func (s *Service) OnPostPublished(p *models.Post) {
+	s.invalidate()
+}
+
+func (s *Service) OnPostUnpublished(p *models.Post) {
+	s.invalidate()
+}
+
+/**
+* GetRelated returns published posts similar to the given post.
+* @param postID string - The ID of the post being read
+* @param limit int - The maximum number of related posts
+* @param excludeAuthor bool - Skip posts written by the same author
+* @return *RelatedPostsResponse - Related posts ordered by score
+* @return error - An error if occurred
+* Candidates are the nearest centroids; each shared tag adds TagBoost to the cosine similarity
+* before the final ordering. Results are cached until any post is published or unpublished.
+**/
+
+func (s *Service) GetRelated(postID string, limit int, excludeAuthor bool) (*RelatedPostsResponse, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	key := cacheKey(postID, limit, excludeAuthor)
+	if cached, ok := s.Cache.GetString(context.Background(), key); ok {
+		var result RelatedPostsResponse
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	target, err := s.PostRepo.GetByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrPostNotFound
+		}
+		return nil, err
+	}
+	if !target.Published || target.Status != models.PostPublished {
+		return nil, errs.ErrPostNotFound
+	}
+
+	var excludeAuthorID *uuid.UUID
+	if excludeAuthor {
+		excludeAuthorID = &target.AuthorID
+	}
+
+	candidates, err := s.Repo.FindNearest(target.ID, excludeAuthorID, limit*candidateFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.rank(candidates, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		if err := s.Cache.Set(context.Background(), key, data); err != nil {
+			logger.Log.Warn("Failed to cache related posts", zap.String("post_id", postID), zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+func boostedScore(candidate Candidate) float64 {
+	shared := candidate.SharedTags
+	if shared > maxBoostedTags {
+		shared = maxBoostedTags
+	}
+	return candidate.Similarity + TagBoost*float64(shared)
+}
+
+// rank จัดอันดับ candidate ด้วยคะแนนที่รวม tag boost แล้วโหลดข้อมูลโพสต์
+func (s *Service) rank(candidates []Candidate, limit int) (*RelatedPostsResponse, error) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return boostedScore(candidates[i]) > boostedScore(candidates[j])
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	ids := make([]uuid.UUID, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.PostID)
+	}
+	posts, err := s.PostRepo.GetPublishedPostsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	postsByID := make(map[uuid.UUID]models.Post, len(posts))
+	for _, p := range posts {
+		postsByID[p.ID] = p
+	}
+
+	result := &RelatedPostsResponse{Posts: make([]RelatedPostDTO, 0, len(candidates))}
+	for _, candidate := range candidates {
+		p, ok := postsByID[candidate.PostID]
+		if !ok {
+			continue
+		}
+		result.Posts = append(result.Posts, RelatedPostDTO{
+			PostSummaryDTO: post.MapPostToSummaryDTO(p),
+			Score:          boostedScore(candidate),
+			SharedTags:     candidate.SharedTags,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/cache"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/related"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type nearestCall struct {
+	PostID          uuid.UUID
+	ExcludeAuthorID *uuid.UUID
+	Limit           int
+}
+
+// stubRepo คืน candidate ตามที่กำหนดและจดการเรียก FindNearest ไว้ตรวจ
+type stubRepo struct {
+	candidates []related.Candidate
+	calls      []nearestCall
+	centroids  map[uuid.UUID]pgvector.Vector
+}
+
+func (r *stubRepo) UpsertCentroid(postID uuid.UUID, vector pgvector.Vector) error {
+	if r.centroids == nil {
+		r.centroids = make(map[uuid.UUID]pgvector.Vector)
+	}
+	r.centroids[postID] = vector
+	return nil
+}
+
+func (r *stubRepo) FindNearest(postID uuid.UUID, excludeAuthorID *uuid.UUID, limit int) ([]related.Candidate, error) {
+	r.calls = append(r.calls, nearestCall{PostID: postID, ExcludeAuthorID: excludeAuthorID, Limit: limit})
+	// คืนสำเนาเพราะ service เรียงลำดับ slice ที่ได้รับ
+	return append([]related.Candidate(nil), r.candidates...), nil
+}
+
+// stubPostRepo เก็บโพสต์ใน memory พอสำหรับ GetRelated และการ unpublish ตามเวลา
+type stubPostRepo struct {
+	post.PostRepositoryInterface
+	posts map[uuid.UUID]*models.Post
+}
+
+func (r *stubPostRepo) add(p *models.Post) *models.Post {
+	if r.posts == nil {
+		r.posts = make(map[uuid.UUID]*models.Post)
+	}
+	r.posts[p.ID] = p
+	return p
+}
+
+func (r *stubPostRepo) GetByID(id string) (*models.Post, error) {
+	for _, p := range r.posts {
+		if p.ID.String() == id {
+			return p, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubPostRepo) GetPublishedPostsByIDs(ids []uuid.UUID) ([]models.Post, error) {
+	var result []models.Post
+	for _, id := range ids {
+		if p, ok := r.posts[id]; ok && p.Published {
+			result = append(result, *p)
+		}
+	}
+	return result, nil
+}
+
+func (r *stubPostRepo) DeleteEmbeddingsByPostID(postID string) error {
+	return nil
+}
+
+func (r *stubPostRepo) UnpublishPost(p *models.Post) error {
+	r.posts[p.ID] = p
+	return nil
+}
+
+func publishedPost(title string, authorID uuid.UUID) *models.Post {
+	return &models.Post{ID: uuid.New(), Title: title, AuthorID: authorID, Published: true, Status: models.PostPublished}
+}
+
+func newRelatedService() (*related.Service, *stubRepo, *stubPostRepo) {
+	logger.Log = zap.NewNop()
+	repo := &stubRepo{}
+	postRepo := &stubPostRepo{}
+	return related.NewService(repo, postRepo, cache.NewService(nil, time.Minute)), repo, postRepo
+}
+
+func titles(result *related.RelatedPostsResponse) []string {
+	var got []string
+	for _, p := range result.Posts {
+		got = append(got, p.Title)
+	}
+	return got
+}
+
+// Test case: tag ที่ใช้ร่วมกันเพิ่มคะแนนทีละ TagBoost (นับสูงสุด 3 tag) แล้วค่อยตัดตาม limit
+func TestGetRelated_TagBoostRanking(t *testing.T) {
+	service, repo, postRepo := newRelatedService()
+	authorID := uuid.New()
+	target := postRepo.add(publishedPost("target", authorID))
+	closest := postRepo.add(publishedPost("closest", authorID))
+	manyTags := postRepo.add(publishedPost("many tags", authorID))
+	oneTag := postRepo.add(publishedPost("one tag", authorID))
+	noTags := postRepo.add(publishedPost("no tags", authorID))
+	hidden := postRepo.add(&models.Post{ID: uuid.New(), Title: "unpublished", AuthorID: authorID})
+
+	repo.candidates = []related.Candidate{
+		{PostID: closest.ID, Similarity: 0.90},
+		{PostID: hidden.ID, Similarity: 0.88},
+		{PostID: noTags.ID, Similarity: 0.80},
+		{PostID: oneTag.ID, Similarity: 0.78, SharedTags: 1},
+		{PostID: manyTags.ID, Similarity: 0.70, SharedTags: 10},
+	}
+
+	result, err := service.GetRelated(target.ID.String(), 4, false)
+	require.NoError(t, err)
+
+	// many tags: 0.70 + 3*0.05 = 0.85, one tag: 0.78 + 0.05 = 0.83 แซง no tags (0.80)
+	// โพสต์ที่ไม่ publish กินที่ใน limit แต่ถูกตัดออกตอนโหลด
+	assert.Equal(t, []string{"closest", "many tags", "one tag"}, titles(result))
+	assert.InDelta(t, 0.90, result.Posts[0].Score, 1e-9)
+	assert.InDelta(t, 0.70+3*related.TagBoost, result.Posts[1].Score, 1e-9)
+	assert.InDelta(t, 0.78+related.TagBoost, result.Posts[2].Score, 1e-9)
+	assert.Equal(t, 10, result.Posts[1].SharedTags)
+
+	require.Len(t, repo.calls, 1)
+	assert.Equal(t, target.ID, repo.calls[0].PostID)
+	assert.Greater(t, repo.calls[0].Limit, 4, "fetches extra candidates so the tag boost can reorder them")
+}
+
+func TestGetRelated_ExcludeAuthor(t *testing.T) {
+	service, repo, postRepo := newRelatedService()
+	target := postRepo.add(publishedPost("target", uuid.New()))
+
+	_, err := service.GetRelated(target.ID.String(), 5, false)
+	require.NoError(t, err)
+	_, err = service.GetRelated(target.ID.String(), 5, true)
+	require.NoError(t, err)
+
+	// ผลของสองแบบถูก cache แยกกัน จึงต้องถาม repository ทั้งสองครั้ง
+	require.Len(t, repo.calls, 2)
+	assert.Nil(t, repo.calls[0].ExcludeAuthorID)
+	require.NotNil(t, repo.calls[1].ExcludeAuthorID)
+	assert.Equal(t, target.AuthorID, *repo.calls[1].ExcludeAuthorID)
+}
+
+func TestGetRelated_TargetNotFound(t *testing.T) {
+	service, repo, postRepo := newRelatedService()
+	draft := postRepo.add(&models.Post{ID: uuid.New(), Status: models.PostDraft})
+	scheduled := postRepo.add(&models.Post{ID: uuid.New(), Published: true, Status: models.PostScheduled})
+
+	for name, id := range map[string]string{
+		"missing":   uuid.NewString(),
+		"draft":     draft.ID.String(),
+		"scheduled": scheduled.ID.String(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.GetRelated(id, 5, false)
+			assert.ErrorIs(t, err, errs.ErrPostNotFound)
+		})
+	}
+	assert.Empty(t, repo.calls)
+}
+
+// Test case: ผลลัพธ์ถูก cache จนกว่าจะมีโพสต์ถูก unpublish ผ่าน PostService หรือ centroid ถูกคำนวณใหม่
+func TestGetRelated_CacheInvalidatedByPublicationListener(t *testing.T) {
+	service, repo, postRepo := newRelatedService()
+	postService := post.NewPostService(postRepo, nil, &post.TaskEnqueuer{}).(*post.PostService)
+	postService.AddPublicationListener(service)
+
+	authorID := uuid.New()
+	target := postRepo.add(publishedPost("target", authorID))
+	first := postRepo.add(publishedPost("first", authorID))
+	second := postRepo.add(publishedPost("second", authorID))
+	unpublishAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	first.UnpublishAt = &unpublishAt
+	repo.candidates = []related.Candidate{
+		{PostID: first.ID, Similarity: 0.9},
+		{PostID: second.ID, Similarity: 0.8},
+	}
+
+	result, err := service.GetRelated(target.ID.String(), 5, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, titles(result))
+
+	cached, err := service.GetRelated(target.ID.String(), 5, false)
+	require.NoError(t, err)
+	assert.Equal(t, titles(result), titles(cached))
+	assert.Len(t, repo.calls, 1, "second call is served from cache")
+
+	unpublished, err := postService.UnpublishScheduledPost(first.ID.String(), unpublishAt)
+	require.NoError(t, err)
+	require.NotNil(t, unpublished)
+
+	result, err = service.GetRelated(target.ID.String(), 5, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second"}, titles(result))
+	assert.Len(t, repo.calls, 2)
+
+	require.NoError(t, service.RefreshCentroid(second.ID, []models.Embedding{
+		{Vector: pgvector.NewVector([]float32{1, 0})},
+		{Vector: pgvector.NewVector([]float32{0, 1})},
+	}))
+	assert.Equal(t, []float32{0.5, 0.5}, repo.centroids[second.ID].Slice())
+
+	_, err = service.GetRelated(target.ID.String(), 5, false)
+	require.NoError(t, err)
+	assert.Len(t, repo.calls, 3, "a new centroid invalidates the cache")
+}
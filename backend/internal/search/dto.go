@@ -0,0 +1,15 @@
+package search
+
+import "rag-searchbot-backend/internal/post"
+
+type SearchResultDTO struct {
+	post.PostSummaryDTO
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet,omitempty"` // ข้อความจาก chunk ที่ใกล้เคียงคำค้นที่สุด
+}
+
+type SearchResponse struct {
+	Query   string            `json:"query"`
+	Mode    string            `json:"mode"`
+	Results []SearchResultDTO `json:"results"`
+}
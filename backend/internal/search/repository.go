@@ -0,0 +1,66 @@
+package search
+
+import (
+	"rag-searchbot-backend/internal/post"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChunkMatch chunk ของ embedding ที่ใกล้เคียงคำค้น (score คือ cosine similarity)
+type ChunkMatch struct {
+	PostID  uuid.UUID
+	Content string
+	Score   float64
+}
+
+type RepositoryInterface interface {
+	SearchChunks(vector pgvector.Vector, limit int) ([]ChunkMatch, error)
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+const (
+	defaultEfSearch = 40   // ค่าเริ่มต้นของ hnsw.ef_search ใน pgvector
+	maxEfSearch     = 1000 // ค่าสูงสุดที่ pgvector ยอมรับ
+)
+
+// efSearch จำนวน candidate ที่ HNSW ต้องหา ต้องไม่น้อยกว่า limit ไม่อย่างนั้นได้ผลไม่ครบ
+func efSearch(limit int) int {
+	return min(max(limit, defaultEfSearch), maxEfSearch)
+}
+
+// SearchChunks หา chunk ที่ใกล้เคียงที่สุดจากทุกโพสต์ที่ publish แล้ว (ใช้ HNSW index แบบ cosine)
+// HNSW คืน candidate ได้แค่ ef_search รายการก่อนกรองโพสต์ จึงตั้งค่าให้พอกับ limit เฉพาะใน transaction นี้
+func (r *Repository) SearchChunks(vector pgvector.Vector, limit int) ([]ChunkMatch, error) {
+	var matches []ChunkMatch
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// SET LOCAL รับ parameter ไม่ได้ set_config(..., true) ให้ผลเดียวกัน
+		if err := tx.Exec("SELECT set_config('hnsw.ef_search', ?, true)", strconv.Itoa(efSearch(limit))).Error; err != nil {
+			return err
+		}
+		return tx.Table("embeddings AS e").
+			Select("e.post_id, e.content, 1 - (e.vector <=> ?) AS score", vector).
+			Joins("JOIN posts p ON p.id = e.post_id").
+			Where("e.deleted_at IS NULL").
+			Where(post.PublishedPostCondition("p")).
+			Where(post.ListedPostCondition("p")).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:                "e.vector <=> ?",
+				Vars:               []interface{}{vector},
+				WithoutParentheses: true,
+			}}).
+			Limit(limit).
+			Scan(&matches).Error
+	})
+	return matches, err
+}
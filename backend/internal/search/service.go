@@ -0,0 +1,229 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"rag-searchbot-backend/internal/llm"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/pkg/errs"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+)
+
+const (
+	ModeSemantic = "semantic"
+	ModeHybrid   = "hybrid"
+
+	DefaultLimit   = 10
+	MaxLimit       = 50
+	MaxQueryLength = 500
+
+	// EmbeddingDimension ต้องตรงกับ vector(384) ของ models.Embedding
+	EmbeddingDimension = 384
+
+	ChunksPerPost = 5   // ดึง chunk มากกว่าจำนวนโพสต์ เพื่อให้ยังได้ครบ limit หลังจัดกลุ่มตามโพสต์
+	RRFK          = 60  // ค่าคงที่มาตรฐานของ reciprocal rank fusion
+	SnippetLength = 300 // ความยาวสูงสุดของ snippet (นับเป็นตัวอักษร)
+)
+
+type ServiceInterface interface {
+	Search(ctx context.Context, query, mode string, limit int) (*SearchResponse, error)
+}
+
+type Service struct {
+	Repo      RepositoryInterface
+	PostRepo  post.PostRepositoryInterface
+	LLMClient llm.LLM
+}
+
+func NewService(repo RepositoryInterface, postRepo post.PostRepositoryInterface, llmClient llm.LLM) ServiceInterface {
+	return &Service{Repo: repo, PostRepo: postRepo, LLMClient: llmClient}
+}
+
+// postMatch โพสต์หนึ่งรายการจาก semantic search พร้อม chunk ที่ได้คะแนนสูงสุด
+type postMatch struct {
+	PostID  uuid.UUID
+	Score   float64
+	Snippet string
+}
+
+// FitEmbeddingDimension ปรับความยาว vector ให้เท่ากับ column ใน DB แบบเดียวกับ ai.GetEmbedding
+func FitEmbeddingDimension(embedding []float32) []float32 {
+	switch {
+	case len(embedding) < EmbeddingDimension:
+		return append(embedding, make([]float32, EmbeddingDimension-len(embedding))...)
+	case len(embedding) > EmbeddingDimension:
+		return embedding[:EmbeddingDimension]
+	}
+	return embedding
+}
+
+// BuildSnippet ยุบ whitespace และตัด passage ให้ไม่ยาวเกิน SnippetLength
+func BuildSnippet(content string) string {
+	runes := []rune(strings.Join(strings.Fields(content), " "))
+	if len(runes) <= SnippetLength {
+		return string(runes)
+	}
+	return string(runes[:SnippetLength]) + "…"
+}
+
+// semanticMatches embed คำค้นแล้วจัดกลุ่ม chunk ตามโพสต์ โดยใช้ chunk ที่ดีที่สุดเป็นตัวแทนของโพสต์
+func (s *Service) semanticMatches(ctx context.Context, query string, limit int) ([]postMatch, error) {
+	embedding, err := s.LLMClient.GenerateEmbedding(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrSearchFailed, err)
+	}
+
+	chunks, err := s.Repo.SearchChunks(pgvector.NewVector(FitEmbeddingDimension(embedding)), limit*ChunksPerPost)
+	if err != nil {
+		return nil, err
+	}
+
+	// chunks เรียงจากใกล้ที่สุดมาแล้ว chunk แรกของแต่ละโพสต์จึงเป็น chunk ที่ดีที่สุด
+	seen := make(map[uuid.UUID]bool)
+	matches := make([]postMatch, 0, limit)
+	for _, chunk := range chunks {
+		if seen[chunk.PostID] {
+			continue
+		}
+		seen[chunk.PostID] = true
+		matches = append(matches, postMatch{
+			PostID:  chunk.PostID,
+			Score:   chunk.Score,
+			Snippet: BuildSnippet(chunk.Content),
+		})
+		if len(matches) == limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// ReciprocalRankFusion รวมผลหลายชุดด้วย score = Σ 1 / (k + rank) โดย rank เริ่มที่ 1
+// คืน id ที่เรียงตาม score ใหม่ (เท่ากันให้ลำดับที่พบก่อนมาก่อน)
+func ReciprocalRankFusion(rankings ...[]uuid.UUID) ([]uuid.UUID, map[uuid.UUID]float64) {
+	scores := make(map[uuid.UUID]float64)
+	var order []uuid.UUID
+	for _, ranking := range rankings {
+		for i, id := range ranking {
+			if _, ok := scores[id]; !ok {
+				order = append(order, id)
+			}
+			scores[id] += 1 / float64(RRFK+i+1)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	return order, scores
+}
+
+/**
+* Search finds published posts that match the query.
+* @param ctx context.Context - The request context (used for the embedding call)
+* @param query string - The search text
+* @param mode string - "semantic" (default) or "hybrid"
+* @param limit int - The maximum number of posts to return
+* @return *SearchResponse - Posts ranked by relevance with the best matching passage as snippet
+* @return error - An error if occurred
+* Semantic mode ranks posts by their best embedding chunk. Hybrid mode fuses that ranking with
+* the keyword (full-text) ranking using reciprocal rank fusion, so posts found by either side are returned.
+**/
+
+func (s *Service) Search(ctx context.Context, query, mode string, limit int) (*SearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > MaxQueryLength {
+		return nil, fmt.Errorf("%w: query must be between 1 and %d characters", errs.ErrInvalidPayload, MaxQueryLength)
+	}
+	if mode == "" {
+		mode = ModeSemantic
+	}
+	if mode != ModeSemantic && mode != ModeHybrid {
+		return nil, fmt.Errorf("%w: mode must be %q or %q", errs.ErrInvalidPayload, ModeSemantic, ModeHybrid)
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	matches, err := s.semanticMatches(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	semanticRanking := make([]uuid.UUID, 0, len(matches))
+	matchByPost := make(map[uuid.UUID]postMatch, len(matches))
+	for _, match := range matches {
+		semanticRanking = append(semanticRanking, match.PostID)
+		matchByPost[match.PostID] = match
+	}
+
+	ranking := semanticRanking
+	scores := make(map[uuid.UUID]float64, len(matches))
+	for _, match := range matches {
+		scores[match.PostID] = match.Score
+	}
+	postsByID := make(map[uuid.UUID]models.Post)
+
+	if mode == ModeHybrid {
+		// ใช้แค่อันดับของ keyword search จึงไม่ต้อง COUNT(*)
+		keyword, err := s.PostRepo.ListPublished(post.PostListQuery{Limit: limit, Search: query})
+		if err != nil {
+			return nil, err
+		}
+
+		keywordRanking := make([]uuid.UUID, 0, len(keyword.Posts))
+		for _, p := range keyword.Posts {
+			keywordRanking = append(keywordRanking, p.ID)
+			postsByID[p.ID] = p
+		}
+
+		ranking, scores = ReciprocalRankFusion(semanticRanking, keywordRanking)
+		if len(ranking) > limit {
+			ranking = ranking[:limit]
+		}
+	}
+
+	var missing []uuid.UUID
+	for _, id := range ranking {
+		if _, ok := postsByID[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	posts, err := s.PostRepo.GetPublishedPostsByIDs(missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range posts {
+		postsByID[p.ID] = p
+	}
+
+	results := make([]SearchResultDTO, 0, len(ranking))
+	for _, id := range ranking {
+		p, ok := postsByID[id]
+		if !ok {
+			continue
+		}
+
+		result := SearchResultDTO{
+			PostSummaryDTO: post.MapPostToSummaryDTO(p),
+			Score:          scores[id],
+			Snippet:        matchByPost[id].Snippet,
+		}
+		if result.Snippet == "" {
+			// โพสต์ที่เจอจาก keyword อย่างเดียวใช้ snippet แบบไฮไลต์ของ full-text search
+			result.Highlight = post.BuildSearchHighlight(p, query)
+		}
+		results = append(results, result)
+	}
+
+	return &SearchResponse{Query: query, Mode: mode, Results: results}, nil
+}
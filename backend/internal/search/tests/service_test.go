@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"rag-searchbot-backend/internal/llm"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/search"
+	"rag-searchbot-backend/pkg/errs"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReciprocalRankFusion(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	name := map[uuid.UUID]string{a: "a", b: "b", c: "c", d: "d"}
+
+	cases := []struct {
+		name     string
+		rankings [][]uuid.UUID
+		want     []string
+	}{
+		{"single ranking keeps its order", [][]uuid.UUID{{a, b, c}}, []string{"a", "b", "c"}},
+		{"found by both sides beats found by one", [][]uuid.UUID{{a, b}, {b, c}}, []string{"b", "a", "c"}},
+		{"equal scores keep first-seen order", [][]uuid.UUID{{a, b}, {b, a}}, []string{"a", "b"}},
+		{"rank one on one side ties rank one on the other", [][]uuid.UUID{{a}, {c}}, []string{"a", "c"}},
+		{"two middling ranks beat one top rank", [][]uuid.UUID{{a, b, c}, {d, c, b}}, []string{"b", "c", "a", "d"}},
+		{"empty side", [][]uuid.UUID{{a, b}, {}}, []string{"a", "b"}},
+		{"nothing found", [][]uuid.UUID{{}, {}}, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			order, scores := search.ReciprocalRankFusion(tc.rankings...)
+
+			var got []string
+			for _, id := range order {
+				got = append(got, name[id])
+			}
+			assert.Equal(t, tc.want, got)
+			assert.Len(t, scores, len(order))
+			for i := 1; i < len(order); i++ {
+				assert.GreaterOrEqual(t, scores[order[i-1]], scores[order[i]])
+			}
+		})
+	}
+}
+
+func TestReciprocalRankFusion_Scores(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	_, scores := search.ReciprocalRankFusion([]uuid.UUID{a, b}, []uuid.UUID{b})
+
+	assert.InDelta(t, 1.0/(search.RRFK+1), scores[a], 1e-12)
+	assert.InDelta(t, 1.0/(search.RRFK+2)+1.0/(search.RRFK+1), scores[b], 1e-12)
+}
+
+func TestBuildSnippet(t *testing.T) {
+	assert.Equal(t, "a b c", search.BuildSnippet("  a\n\tb   c "))
+
+	long := strings.Repeat("ก", search.SnippetLength+10)
+	snippet := search.BuildSnippet(long)
+	assert.Equal(t, search.SnippetLength+1, len([]rune(snippet)))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+}
+
+func TestFitEmbeddingDimension(t *testing.T) {
+	assert.Len(t, search.FitEmbeddingDimension(make([]float32, 10)), search.EmbeddingDimension)
+	assert.Len(t, search.FitEmbeddingDimension(make([]float32, search.EmbeddingDimension+5)), search.EmbeddingDimension)
+}
+
+type stubLLM struct {
+	llm.LLM
+}
+
+func (stubLLM) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	return []float32{1, 0, 0}, nil
+}
+
+type stubChunks []search.ChunkMatch
+
+func (s stubChunks) SearchChunks(vector pgvector.Vector, limit int) ([]search.ChunkMatch, error) {
+	return s, nil
+}
+
+// stubPostRepo คืน keyword ranking ตามที่กำหนด และโหลดโพสต์ตาม id จาก posts
+type stubPostRepo struct {
+	post.PostRepositoryInterface
+	keyword []models.Post
+	posts   map[uuid.UUID]models.Post
+	queries *[]post.PostListQuery
+}
+
+func (s stubPostRepo) ListPublished(query post.PostListQuery) (*post.PostRepositoryQuery, error) {
+	if s.queries != nil {
+		*s.queries = append(*s.queries, query)
+	}
+	return &post.PostRepositoryQuery{Posts: s.keyword}, nil
+}
+
+func (s stubPostRepo) GetPublishedPostsByIDs(ids []uuid.UUID) ([]models.Post, error) {
+	var result []models.Post
+	for _, id := range ids {
+		if p, ok := s.posts[id]; ok {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func TestSearch_HybridFusesSemanticAndKeyword(t *testing.T) {
+	semanticOnly := models.Post{ID: uuid.New(), Title: "semantic only"}
+	both := models.Post{ID: uuid.New(), Title: "both"}
+	keywordOnly := models.Post{ID: uuid.New(), Title: "keyword only", Content: "golang generics"}
+	unpublished := uuid.New()
+	var queries []post.PostListQuery
+
+	service := search.NewService(
+		stubChunks{
+			{PostID: semanticOnly.ID, Content: "first chunk", Score: 0.9},
+			{PostID: semanticOnly.ID, Content: "worse chunk", Score: 0.8},
+			{PostID: unpublished, Content: "hidden", Score: 0.7},
+			{PostID: both.ID, Content: "both chunk", Score: 0.6},
+		},
+		stubPostRepo{
+			keyword: []models.Post{both, keywordOnly},
+			posts:   map[uuid.UUID]models.Post{semanticOnly.ID: semanticOnly, both.ID: both},
+			queries: &queries,
+		},
+		stubLLM{},
+	)
+
+	result, err := service.Search(context.Background(), " golang ", search.ModeHybrid, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "golang", result.Query)
+	// ใช้แค่อันดับของ keyword จึงต้องไม่สั่ง COUNT(*)
+	assert.Equal(t, []post.PostListQuery{{Limit: 10, Search: "golang"}}, queries)
+
+	var titles []string
+	for _, r := range result.Results {
+		titles = append(titles, r.Title)
+	}
+	// โพสต์ที่ยังไม่ publish ถูกตัดทิ้งตอนโหลด
+	assert.Equal(t, []string{"both", "semantic only", "keyword only"}, titles)
+	assert.Equal(t, "both chunk", result.Results[0].Snippet)
+	assert.Equal(t, "first chunk", result.Results[1].Snippet)
+	assert.Empty(t, result.Results[2].Snippet)
+}
+
+func TestSearch_Validation(t *testing.T) {
+	service := search.NewService(stubChunks{}, stubPostRepo{}, stubLLM{})
+
+	cases := []struct {
+		name  string
+		query string
+		mode  string
+	}{
+		{"empty query", "   ", search.ModeSemantic},
+		{"too long", strings.Repeat("a", search.MaxQueryLength+1), search.ModeSemantic},
+		{"unknown mode", "go", "fuzzy"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.Search(context.Background(), tc.query, tc.mode, 10)
+			assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+		})
+	}
+}
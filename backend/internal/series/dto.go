@@ -0,0 +1,89 @@
+package series
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"time"
+)
+
+// SeriesRequestDTO สำหรับสร้าง / แก้ไข series (slug ว่าง = สร้างจาก title)
+type SeriesRequestDTO struct {
+	Title       string `json:"title" binding:"required"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+}
+
+// SeriesPostsRequestDTO ลำดับโพสต์ทั้งหมดของ series ตามที่ต้องการ (ส่งว่าง = ล้าง series)
+type SeriesPostsRequestDTO struct {
+	PostIDs []string `json:"post_ids"`
+}
+
+type SeriesDTO struct {
+	ID          string           `json:"id"`
+	Slug        string           `json:"slug"`
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	Author      string           `json:"author"`
+	PostCount   int64            `json:"post_count"`
+	Posts       []SeriesEntryDTO `json:"posts,omitempty"` // มีเฉพาะ series ของตัวเอง (รวม draft)
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// SeriesEntryDTO หนึ่งตอนใน series
+type SeriesEntryDTO struct {
+	PostID   string `json:"post_id"`
+	Slug     string `json:"slug"`
+	Title    string `json:"title"`
+	Position int    `json:"position"`
+	Status   string `json:"status,omitempty"`
+}
+
+// SeriesPageDTO หน้า public ของ series พร้อมโพสต์ที่ publish แล้วตามลำดับ
+type SeriesPageDTO struct {
+	SeriesDTO
+	Posts []post.PostSummaryDTO `json:"posts"`
+}
+
+// SeriesNavigationDTO ส่วน series ของหน้าโพสต์ public: สารบัญและตอนก่อนหน้า / ถัดไป
+type SeriesNavigationDTO struct {
+	ID              string           `json:"id"`
+	Slug            string           `json:"slug"`
+	Title           string           `json:"title"`
+	Position        int              `json:"position"`
+	Total           int              `json:"total"`
+	Previous        *SeriesEntryDTO  `json:"previous,omitempty"`
+	Next            *SeriesEntryDTO  `json:"next,omitempty"`
+	TableOfContents []SeriesEntryDTO `json:"table_of_contents"`
+}
+
+func MapSeriesToDTO(series *models.Series, postCount int64) SeriesDTO {
+	return SeriesDTO{
+		ID:          series.ID.String(),
+		Slug:        series.Slug,
+		Title:       series.Title,
+		Description: series.Description,
+		Author:      series.Author.UserName,
+		PostCount:   postCount,
+		CreatedAt:   series.CreatedAt,
+		UpdatedAt:   series.UpdatedAt,
+	}
+}
+
+// mapEntries ลำดับ (Position) นับจากรายการที่แสดงจริง เพื่อไม่ให้มีเลขข้ามเมื่อบางตอนยังไม่ publish
+func mapEntries(posts []models.Post, withStatus bool) []SeriesEntryDTO {
+	entries := make([]SeriesEntryDTO, 0, len(posts))
+	for i, p := range posts {
+		entry := SeriesEntryDTO{
+			PostID:   p.ID.String(),
+			Slug:     p.Slug,
+			Title:    p.Title,
+			Position: i + 1,
+		}
+		if withStatus {
+			entry.Status = string(p.Status)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,189 @@
+package series
+
+import (
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RepositoryInterface interface {
+	Create(series *models.Series) error
+	Update(series *models.Series) error
+	Delete(series *models.Series) error
+	GetByID(id string) (*models.Series, error)
+	GetByAuthorAndSlug(username string, slug string) (*models.Series, error)
+	GetBySlugForAuthor(authorID uuid.UUID, slug string) (*models.Series, error)
+	GetByPostID(postID uuid.UUID) (*models.Series, error)
+	ListByAuthor(authorID uuid.UUID) ([]SeriesCount, error)
+	ListPublishedByUsername(username string) ([]SeriesCount, error)
+	GetPosts(seriesID uuid.UUID, publishedOnly bool) ([]models.Post, error)
+	ReplacePosts(seriesID uuid.UUID, postIDs []uuid.UUID) error
+	CountOwnedPosts(authorID uuid.UUID, postIDs []uuid.UUID) (int64, error)
+	GetAIReadyPostIDs(seriesID uuid.UUID) ([]string, error)
+}
+
+// SeriesCount series พร้อมจำนวนโพสต์ (นับเฉพาะที่ publish แล้วเมื่อเป็นหน้า public)
+type SeriesCount struct {
+	models.Series
+	PostCount int64
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) Create(series *models.Series) error {
+	return r.DB.Create(series).Error
+}
+
+func (r *Repository) Update(series *models.Series) error {
+	return r.DB.Model(series).Select("slug", "title", "description").Updates(series).Error
+}
+
+// Delete ลบ series และลำดับโพสต์ทั้งหมด ตัวโพสต์ยังอยู่
+func (r *Repository) Delete(series *models.Series) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", series.ID).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(series).Error
+	})
+}
+
+func (r *Repository) GetByID(id string) (*models.Series, error) {
+	var series models.Series
+	if err := r.DB.Preload("Author").First(&series, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *Repository) GetByAuthorAndSlug(username string, slug string) (*models.Series, error) {
+	var series models.Series
+	err := r.DB.Preload("Author").
+		Where("slug = ? AND author_id = (SELECT id FROM users WHERE username = ? AND deleted_at IS NULL)", slug, username).
+		First(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// GetBySlugForAuthor ใช้ตรวจ slug ซ้ำก่อนสร้าง / แก้ไข
+func (r *Repository) GetBySlugForAuthor(authorID uuid.UUID, slug string) (*models.Series, error) {
+	var series models.Series
+	if err := r.DB.Where("author_id = ? AND slug = ?", authorID, slug).First(&series).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *Repository) GetByPostID(postID uuid.UUID) (*models.Series, error) {
+	var series models.Series
+	err := r.DB.Preload("Author").
+		Where("id = (SELECT series_id FROM series_posts WHERE post_id = ?)", postID).
+		First(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *Repository) ListByAuthor(authorID uuid.UUID) ([]SeriesCount, error) {
+	var result []SeriesCount
+	err := r.DB.Model(&models.Series{}).
+		Select("series.*, COUNT(posts.id) AS post_count").
+		Joins("LEFT JOIN series_posts ON series_posts.series_id = series.id").
+		Joins("LEFT JOIN posts ON posts.id = series_posts.post_id AND posts.deleted_at IS NULL").
+		Where("series.author_id = ?", authorID).
+		Group("series.id").
+		Order("series.created_at DESC").
+		Scan(&result).Error
+	return result, err
+}
+
+// ListPublishedByUsername series ของผู้เขียนที่มีโพสต์ publish แล้วอย่างน้อยหนึ่งตอน
+func (r *Repository) ListPublishedByUsername(username string) ([]SeriesCount, error) {
+	condition, published, status := post.PublishedPostCondition("posts")
+	listed, visibility := post.ListedPostCondition("posts")
+
+	var result []SeriesCount
+	err := r.DB.Model(&models.Series{}).
+		Select("series.*, COUNT(posts.id) AS post_count").
+		Joins("JOIN series_posts ON series_posts.series_id = series.id").
+		Joins("JOIN posts ON posts.id = series_posts.post_id AND "+condition+" AND "+listed, published, status, visibility).
+		Where("series.author_id = (SELECT id FROM users WHERE username = ? AND deleted_at IS NULL)", username).
+		Group("series.id").
+		Order("series.created_at DESC").
+		Scan(&result).Error
+	return result, err
+}
+
+// GetPosts โพสต์ใน series ตามลำดับ Position
+func (r *Repository) GetPosts(seriesID uuid.UUID, publishedOnly bool) ([]models.Post, error) {
+	db := r.DB.Model(&models.Post{}).
+		Select("posts.id", "posts.slug", "posts.short_slug", "posts.title", "posts.description", "posts.thumbnail",
+			"posts.published", "posts.status", "posts.published_at", "posts.author_id", "posts.likes", "posts.views",
+			"posts.read_time", "posts.ai_chat_open", "posts.ai_ready", "posts.created_at", "posts.updated_at").
+		Joins("JOIN series_posts ON series_posts.post_id = posts.id").
+		Where("series_posts.series_id = ?", seriesID).
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar")
+		}).
+		Preload("Tags")
+	if publishedOnly {
+		db = db.Where(post.PublishedPostCondition("posts")).Where(post.ListedPostCondition("posts"))
+	}
+
+	var posts []models.Post
+	err := db.Order("series_posts.position ASC").Find(&posts).Error
+	return posts, err
+}
+
+// ReplacePosts แทนที่ลำดับโพสต์ทั้งหมดของ series โพสต์ที่อยู่ใน series อื่นจะถูกย้ายมา
+func (r *Repository) ReplacePosts(seriesID uuid.UUID, postIDs []uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", seriesID).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			return nil
+		}
+		if err := tx.Where("post_id IN ?", postIDs).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+
+		entries := make([]models.SeriesPost, 0, len(postIDs))
+		for i, id := range postIDs {
+			entries = append(entries, models.SeriesPost{SeriesID: seriesID, PostID: id, Position: i + 1})
+		}
+		return tx.Create(&entries).Error
+	})
+}
+
+func (r *Repository) CountOwnedPosts(authorID uuid.UUID, postIDs []uuid.UUID) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.Post{}).
+		Where("author_id = ? AND id IN ?", authorID, postIDs).
+		Count(&count).Error
+	return count, err
+}
+
+// GetAIReadyPostIDs โพสต์ใน series ที่ publish แล้ว เปิด AI chat และ embed เสร็จแล้ว
+func (r *Repository) GetAIReadyPostIDs(seriesID uuid.UUID) ([]string, error) {
+	var ids []string
+	err := r.DB.Model(&models.Post{}).
+		Joins("JOIN series_posts ON series_posts.post_id = posts.id").
+		Where("series_posts.series_id = ? AND posts.ai_chat_open = ? AND posts.ai_ready = ?", seriesID, true, true).
+		Where(post.PublishedPostCondition("posts")).
+		Where(post.ListedPostCondition("posts")).
+		Order("series_posts.position ASC").
+		Pluck("posts.id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,334 @@
+package series
+
+import (
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/post"
+	"rag-searchbot-backend/internal/user"
+	"rag-searchbot-backend/pkg/errs"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	MaxTitleLength    = 150
+	MaxPostsPerSeries = 100
+)
+
+type ServiceInterface interface {
+	Create(req SeriesRequestDTO, user *models.User) (*SeriesDTO, error)
+	Update(id string, req SeriesRequestDTO, user *models.User) (*SeriesDTO, error)
+	Delete(id string, user *models.User) error
+	SetPosts(id string, req SeriesPostsRequestDTO, user *models.User) (*SeriesDTO, error)
+	GetMySeries(user *models.User) ([]SeriesDTO, error)
+	ListByAuthor(username string) ([]SeriesDTO, error)
+	GetPage(username string, slug string) (*SeriesPageDTO, error)
+	GetNavigation(postID uuid.UUID) (*SeriesNavigationDTO, error)
+	GetByAuthorAndSlug(username string, slug string) (*models.Series, error)
+	GetSeriesPostIDs(postID uuid.UUID) ([]string, error)
+}
+
+type Service struct {
+	Repo        RepositoryInterface
+	UserService user.ServiceInterface
+}
+
+func NewService(repo RepositoryInterface, userService user.ServiceInterface) ServiceInterface {
+	return &Service{Repo: repo, UserService: userService}
+}
+
+// Slugify ทำ slug จากชื่อ series เก็บตัวอักษร (รวมภาษาไทย) และตัวเลข คั่นคำด้วย "-"
+func Slugify(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// getOwnedSeries ดึง series และตรวจว่าเป็นของผู้ใช้
+func (s *Service) getOwnedSeries(id string, user *models.User) (*models.Series, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errs.ErrSeriesNotFound
+	}
+	series, err := s.Repo.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrSeriesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if series.AuthorID != user.ID {
+		return nil, errs.ErrUnauthorized
+	}
+	return series, nil
+}
+
+// applyRequest ตรวจชื่อ / slug แล้วใส่ค่าลงใน series
+func (s *Service) applyRequest(series *models.Series, req SeriesRequestDTO) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fmt.Errorf("%w: series title is required", errs.ErrInvalidPayload)
+	}
+	if utf8.RuneCountInString(title) > MaxTitleLength {
+		return fmt.Errorf("%w: series title is longer than %d characters", errs.ErrInvalidPayload, MaxTitleLength)
+	}
+
+	slug := Slugify(req.Slug)
+	if slug == "" {
+		slug = Slugify(title)
+	}
+	if slug == "" {
+		return fmt.Errorf("%w: series slug is required", errs.ErrInvalidPayload)
+	}
+
+	existing, err := s.Repo.GetBySlugForAuthor(series.AuthorID, slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != series.ID {
+		return errs.ErrSeriesExists
+	}
+
+	series.Title = title
+	series.Slug = slug
+	series.Description = strings.TrimSpace(req.Description)
+	return nil
+}
+
+func (s *Service) Create(req SeriesRequestDTO, user *models.User) (*SeriesDTO, error) {
+	series := &models.Series{AuthorID: user.ID}
+	if err := s.applyRequest(series, req); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Create(series); err != nil {
+		return nil, err
+	}
+
+	series.Author = *user
+	dto := MapSeriesToDTO(series, 0)
+	return &dto, nil
+}
+
+func (s *Service) Update(id string, req SeriesRequestDTO, user *models.User) (*SeriesDTO, error) {
+	series, err := s.getOwnedSeries(id, user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequest(series, req); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Update(series); err != nil {
+		return nil, err
+	}
+	return s.ownerDTO(series)
+}
+
+func (s *Service) Delete(id string, user *models.User) error {
+	series, err := s.getOwnedSeries(id, user)
+	if err != nil {
+		return err
+	}
+	return s.Repo.Delete(series)
+}
+
+// SetPosts กำหนดโพสต์และลำดับของ series ใหม่ทั้งหมด ใช้ทั้งเพิ่ม ลบ และจัดลำดับ
+// โพสต์ต้องเป็นของเจ้าของ series และถ้าอยู่ใน series อื่นอยู่แล้วจะถูกย้ายมา
+func (s *Service) SetPosts(id string, req SeriesPostsRequestDTO, user *models.User) (*SeriesDTO, error) {
+	series, err := s.getOwnedSeries(id, user)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.PostIDs) > MaxPostsPerSeries {
+		return nil, fmt.Errorf("%w: a series can have at most %d posts", errs.ErrInvalidPayload, MaxPostsPerSeries)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.PostIDs))
+	postIDs := make([]uuid.UUID, 0, len(req.PostIDs))
+	for _, raw := range req.PostIDs {
+		postID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid post id %q", errs.ErrInvalidPayload, raw)
+		}
+		if seen[postID] {
+			return nil, fmt.Errorf("%w: post %s is listed more than once", errs.ErrInvalidPayload, raw)
+		}
+		seen[postID] = true
+		postIDs = append(postIDs, postID)
+	}
+
+	if len(postIDs) > 0 {
+		owned, err := s.Repo.CountOwnedPosts(user.ID, postIDs)
+		if err != nil {
+			return nil, err
+		}
+		if owned != int64(len(postIDs)) {
+			return nil, errs.ErrPostNotFound
+		}
+	}
+
+	if err := s.Repo.ReplacePosts(series.ID, postIDs); err != nil {
+		return nil, err
+	}
+	return s.ownerDTO(series)
+}
+
+// ownerDTO series ในมุมของเจ้าของ รวมโพสต์ที่ยังไม่ publish พร้อมสถานะ
+func (s *Service) ownerDTO(series *models.Series) (*SeriesDTO, error) {
+	posts, err := s.Repo.GetPosts(series.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	dto := MapSeriesToDTO(series, int64(len(posts)))
+	dto.Posts = mapEntries(posts, true)
+	return &dto, nil
+}
+
+func (s *Service) GetMySeries(user *models.User) ([]SeriesDTO, error) {
+	list, err := s.Repo.ListByAuthor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SeriesDTO, 0, len(list))
+	for _, item := range list {
+		item.Series.Author = *user
+		dto, err := s.ownerDTO(&item.Series)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *dto)
+	}
+	return result, nil
+}
+
+// ListByAuthor series ของผู้เขียนที่มีตอน publish แล้ว พร้อมจำนวนตอน
+func (s *Service) ListByAuthor(username string) ([]SeriesDTO, error) {
+	exists, err := s.UserService.GetExistingUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	list, err := s.Repo.ListPublishedByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SeriesDTO, 0, len(list))
+	for _, item := range list {
+		item.Series.Author = models.User{UserName: username}
+		result = append(result, MapSeriesToDTO(&item.Series, item.PostCount))
+	}
+	return result, nil
+}
+
+func (s *Service) GetByAuthorAndSlug(username string, slug string) (*models.Series, error) {
+	series, err := s.Repo.GetByAuthorAndSlug(username, slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrSeriesNotFound
+	}
+	return series, err
+}
+
+// GetPage หน้า public ของ series แสดงเฉพาะตอนที่ publish แล้ว
+func (s *Service) GetPage(username string, slug string) (*SeriesPageDTO, error) {
+	series, err := s.GetByAuthorAndSlug(username, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.Repo.GetPosts(series.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, errs.ErrSeriesNotFound
+	}
+
+	page := &SeriesPageDTO{
+		SeriesDTO: MapSeriesToDTO(series, int64(len(posts))),
+		Posts:     make([]post.PostSummaryDTO, 0, len(posts)),
+	}
+	for _, p := range posts {
+		page.Posts = append(page.Posts, post.MapPostToSummaryDTO(p))
+	}
+	return page, nil
+}
+
+// GetNavigation สารบัญและตอนก่อนหน้า / ถัดไปของโพสต์ที่ publish แล้ว คืน nil ถ้าโพสต์ไม่อยู่ใน series
+func (s *Service) GetNavigation(postID uuid.UUID) (*SeriesNavigationDTO, error) {
+	series, err := s.Repo.GetByPostID(postID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.Repo.GetPosts(series.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	nav := &SeriesNavigationDTO{
+		ID:              series.ID.String(),
+		Slug:            series.Slug,
+		Title:           series.Title,
+		Total:           len(posts),
+		TableOfContents: mapEntries(posts, false),
+	}
+	for i, entry := range nav.TableOfContents {
+		if entry.PostID != postID.String() {
+			continue
+		}
+		nav.Position = entry.Position
+		if i > 0 {
+			nav.Previous = &nav.TableOfContents[i-1]
+		}
+		if i+1 < len(nav.TableOfContents) {
+			nav.Next = &nav.TableOfContents[i+1]
+		}
+	}
+	return nav, nil
+}
+
+// GetSeriesPostIDs โพสต์ที่พร้อมใช้กับ AI chat ใน series เดียวกับโพสต์นี้ (รวมตัวเอง)
+// คืนเฉพาะโพสต์นี้เมื่อไม่ได้อยู่ใน series
+func (s *Service) GetSeriesPostIDs(postID uuid.UUID) ([]string, error) {
+	series, err := s.Repo.GetByPostID(postID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []string{postID.String()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.Repo.GetAIReadyPostIDs(series.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if id == postID.String() {
+			return ids, nil
+		}
+	}
+	return append([]string{postID.String()}, ids...), nil
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/series"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newSeriesRepository(t *testing.T) (*gorm.DB, series.RepositoryInterface) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, ddl := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT, avatar TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE posts (
+			id TEXT PRIMARY KEY, slug TEXT, short_slug TEXT, title TEXT, description TEXT, thumbnail TEXT,
+			published BOOLEAN DEFAULT false, status TEXT DEFAULT 'DRAFT', published_at DATETIME,
+			visibility TEXT DEFAULT 'public', likes INTEGER DEFAULT 0, views INTEGER DEFAULT 0, read_time REAL DEFAULT 0,
+			ai_chat_open BOOLEAN DEFAULT false, ai_ready BOOLEAN DEFAULT false, author_id TEXT NOT NULL,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE post_tags (post_id TEXT, tag_id INTEGER)`,
+		`CREATE TABLE series (
+			id TEXT PRIMARY KEY, slug TEXT NOT NULL, title TEXT NOT NULL, description TEXT, author_id TEXT NOT NULL,
+			created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE series_posts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, series_id TEXT NOT NULL, post_id TEXT NOT NULL UNIQUE, position INTEGER NOT NULL)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db, series.NewRepository(db)
+}
+
+type postRow struct {
+	published  bool
+	visibility models.PostVisibility
+	aiReady    bool
+	deleted    bool
+}
+
+func insertPost(t *testing.T, db *gorm.DB, authorID uuid.UUID, slug string, row postRow) uuid.UUID {
+	id := uuid.New()
+	now := time.Now()
+	status, publishedAt := models.PostDraft, (*time.Time)(nil)
+	if row.published {
+		status, publishedAt = models.PostPublished, &now
+	}
+	visibility := row.visibility
+	if visibility == "" {
+		visibility = models.VisibilityPublic
+	}
+	var deletedAt *time.Time
+	if row.deleted {
+		deletedAt = &now
+	}
+	require.NoError(t, db.Exec(
+		`INSERT INTO posts (id, slug, short_slug, title, published, status, published_at, visibility, ai_chat_open, ai_ready, author_id, created_at, updated_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.String(), slug, slug, slug, row.published, status, publishedAt, visibility, row.aiReady, row.aiReady,
+		authorID.String(), now, now, deletedAt).Error)
+	return id
+}
+
+func insertSeries(t *testing.T, db *gorm.DB, authorID uuid.UUID, slug string) uuid.UUID {
+	id := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO series (id, slug, title, author_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		id.String(), slug, slug, authorID.String(), time.Now(), time.Now()).Error)
+	return id
+}
+
+func slugs(posts []models.Post) []string {
+	result := make([]string, 0, len(posts))
+	for _, p := range posts {
+		result = append(result, p.Slug)
+	}
+	return result
+}
+
+// Test case: ReplacePosts เขียนลำดับใหม่ทั้งหมด และย้ายโพสต์ที่อยู่ใน series อื่นมา
+func TestSeriesRepository_ReplacePostsOrdering(t *testing.T) {
+	db, repo := newSeriesRepository(t)
+	author := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, author.String(), "alice").Error)
+
+	one := insertPost(t, db, author, "part-1", postRow{published: true})
+	two := insertPost(t, db, author, "part-2", postRow{published: true})
+	draft := insertPost(t, db, author, "part-3-draft", postRow{})
+	unlisted := insertPost(t, db, author, "part-4-unlisted", postRow{published: true, visibility: models.VisibilityUnlisted})
+
+	tutorial := insertSeries(t, db, author, "tutorial")
+	other := insertSeries(t, db, author, "other")
+
+	require.NoError(t, repo.ReplacePosts(other, []uuid.UUID{two}))
+	require.NoError(t, repo.ReplacePosts(tutorial, []uuid.UUID{draft, two, one, unlisted}))
+
+	posts, err := repo.GetPosts(tutorial, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"part-3-draft", "part-2", "part-1", "part-4-unlisted"}, slugs(posts))
+	assert.Equal(t, "alice", posts[0].Author.UserName)
+
+	published, err := repo.GetPosts(tutorial, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"part-2", "part-1"}, slugs(published))
+
+	moved, err := repo.GetPosts(other, false)
+	require.NoError(t, err)
+	assert.Empty(t, moved, "part-2 moved to the tutorial")
+
+	found, err := repo.GetByPostID(two)
+	require.NoError(t, err)
+	assert.Equal(t, tutorial, found.ID)
+
+	// จัดลำดับใหม่ แล้วล้าง series
+	require.NoError(t, repo.ReplacePosts(tutorial, []uuid.UUID{one, two}))
+	posts, err = repo.GetPosts(tutorial, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"part-1", "part-2"}, slugs(posts))
+
+	var positions []int
+	require.NoError(t, db.Raw(`SELECT position FROM series_posts WHERE series_id = ? ORDER BY position`, tutorial.String()).Scan(&positions).Error)
+	assert.Equal(t, []int{1, 2}, positions)
+
+	require.NoError(t, repo.ReplacePosts(tutorial, nil))
+	posts, err = repo.GetPosts(tutorial, false)
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+}
+
+func TestSeriesRepository_CountOwnedPosts(t *testing.T) {
+	db, repo := newSeriesRepository(t)
+	alice, bob := uuid.New(), uuid.New()
+
+	mine := insertPost(t, db, alice, "mine", postRow{})
+	deleted := insertPost(t, db, alice, "deleted", postRow{deleted: true})
+	theirs := insertPost(t, db, bob, "theirs", postRow{})
+
+	cases := []struct {
+		name string
+		ids  []uuid.UUID
+		want int64
+	}{
+		{"own post", []uuid.UUID{mine}, 1},
+		{"someone else's post", []uuid.UUID{mine, theirs}, 1},
+		{"deleted post", []uuid.UUID{mine, deleted}, 1},
+		{"unknown id", []uuid.UUID{uuid.New()}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			count, err := repo.CountOwnedPosts(alice, tc.ids)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, count)
+		})
+	}
+}
+
+func TestSeriesRepository_AIReadyPostIDsKeepSeriesOrder(t *testing.T) {
+	db, repo := newSeriesRepository(t)
+	author := uuid.New()
+
+	first := insertPost(t, db, author, "first", postRow{published: true, aiReady: true})
+	notReady := insertPost(t, db, author, "not-ready", postRow{published: true})
+	draft := insertPost(t, db, author, "draft", postRow{aiReady: true})
+	last := insertPost(t, db, author, "last", postRow{published: true, aiReady: true})
+
+	id := insertSeries(t, db, author, "s")
+	require.NoError(t, repo.ReplacePosts(id, []uuid.UUID{last, notReady, draft, first}))
+
+	ids, err := repo.GetAIReadyPostIDs(id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{last.String(), first.String()}, ids)
+}
+
+func TestSeriesRepository_DeleteKeepsPosts(t *testing.T) {
+	db, repo := newSeriesRepository(t)
+	author := uuid.New()
+	p := insertPost(t, db, author, "p", postRow{published: true})
+	id := insertSeries(t, db, author, "s")
+	require.NoError(t, repo.ReplacePosts(id, []uuid.UUID{p}))
+
+	require.NoError(t, repo.Delete(&models.Series{ID: id}))
+
+	var entries, posts int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM series_posts`).Scan(&entries).Error)
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM posts`).Scan(&posts).Error)
+	assert.Zero(t, entries)
+	assert.Equal(t, int64(1), posts)
+
+	_, err := repo.GetByPostID(p)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
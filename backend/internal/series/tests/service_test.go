@@ -0,0 +1,334 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/series"
+	"rag-searchbot-backend/internal/user"
+	"rag-searchbot-backend/pkg/errs"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockSeriesRepository mock เฉพาะ method ที่ test ใช้ method อื่นจะ panic ถ้าถูกเรียก
+type MockSeriesRepository struct {
+	series.RepositoryInterface
+	mock.Mock
+}
+
+func (m *MockSeriesRepository) Create(s *models.Series) error {
+	return m.Called(s).Error(0)
+}
+
+func (m *MockSeriesRepository) Update(s *models.Series) error {
+	return m.Called(s).Error(0)
+}
+
+func (m *MockSeriesRepository) Delete(s *models.Series) error {
+	return m.Called(s).Error(0)
+}
+
+func (m *MockSeriesRepository) GetByID(id string) (*models.Series, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Series), args.Error(1)
+}
+
+func (m *MockSeriesRepository) GetBySlugForAuthor(authorID uuid.UUID, slug string) (*models.Series, error) {
+	args := m.Called(authorID, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Series), args.Error(1)
+}
+
+func (m *MockSeriesRepository) GetByPostID(postID uuid.UUID) (*models.Series, error) {
+	args := m.Called(postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Series), args.Error(1)
+}
+
+func (m *MockSeriesRepository) GetPosts(seriesID uuid.UUID, publishedOnly bool) ([]models.Post, error) {
+	args := m.Called(seriesID, publishedOnly)
+	return args.Get(0).([]models.Post), args.Error(1)
+}
+
+func (m *MockSeriesRepository) ReplacePosts(seriesID uuid.UUID, postIDs []uuid.UUID) error {
+	return m.Called(seriesID, postIDs).Error(0)
+}
+
+func (m *MockSeriesRepository) CountOwnedPosts(authorID uuid.UUID, postIDs []uuid.UUID) (int64, error) {
+	args := m.Called(authorID, postIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSeriesRepository) GetAIReadyPostIDs(seriesID uuid.UUID) ([]string, error) {
+	args := m.Called(seriesID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type stubUserService struct {
+	user.ServiceInterface
+}
+
+func newSeriesService() (series.ServiceInterface, *MockSeriesRepository) {
+	repo := new(MockSeriesRepository)
+	return series.NewService(repo, stubUserService{}), repo
+}
+
+func TestSlugify(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"Go Concurrency 101", "go-concurrency-101"},
+		{"  --Hello,   World!--  ", "hello-world"},
+		{"เรียน Go ภาษาไทย", "เรียน-go-ภาษาไทย"},
+		{"C++ & Rust", "c-rust"},
+		{"!!!", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.want, series.Slugify(tc.input))
+		})
+	}
+}
+
+func TestCreate_Validation(t *testing.T) {
+	owner := &models.User{ID: uuid.New(), UserName: "alice"}
+	taken := &models.Series{ID: uuid.New(), AuthorID: owner.ID, Slug: "taken"}
+
+	cases := []struct {
+		name     string
+		req      series.SeriesRequestDTO
+		wantSlug string
+		wantErr  error
+	}{
+		{"slug from title", series.SeriesRequestDTO{Title: " Go Basics "}, "go-basics", nil},
+		{"explicit slug is normalized", series.SeriesRequestDTO{Title: "Go", Slug: "My Series"}, "my-series", nil},
+		{"blank title", series.SeriesRequestDTO{Title: "   "}, "", errs.ErrInvalidPayload},
+		{"title too long", series.SeriesRequestDTO{Title: strings.Repeat("ก", series.MaxTitleLength+1)}, "", errs.ErrInvalidPayload},
+		{"title without slug characters", series.SeriesRequestDTO{Title: "!!!"}, "", errs.ErrInvalidPayload},
+		{"slug already used by the author", series.SeriesRequestDTO{Title: "Taken"}, "", errs.ErrSeriesExists},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo := newSeriesService()
+			repo.On("GetBySlugForAuthor", owner.ID, "taken").Return(taken, nil)
+			repo.On("GetBySlugForAuthor", owner.ID, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+			repo.On("Create", mock.AnythingOfType("*models.Series")).Return(nil)
+
+			dto, err := service.Create(tc.req, owner)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSlug, dto.Slug)
+			assert.Equal(t, "alice", dto.Author)
+		})
+	}
+}
+
+func TestUpdate_KeepsOwnSlug(t *testing.T) {
+	service, repo := newSeriesService()
+	owner := &models.User{ID: uuid.New()}
+	existing := &models.Series{ID: uuid.New(), AuthorID: owner.ID, Slug: "go-basics", Title: "Go Basics"}
+
+	repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+	repo.On("GetBySlugForAuthor", owner.ID, "go-basics").Return(existing, nil)
+	repo.On("Update", existing).Return(nil)
+	repo.On("GetPosts", existing.ID, false).Return([]models.Post{}, nil)
+
+	dto, err := service.Update(existing.ID.String(), series.SeriesRequestDTO{Title: "Go Basics", Description: " updated "}, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", dto.Description)
+}
+
+// Test case: series ของคนอื่นแก้ / ลบ / จัดลำดับไม่ได้
+func TestOwnership(t *testing.T) {
+	owner := &models.User{ID: uuid.New()}
+	other := &models.User{ID: uuid.New()}
+	existing := &models.Series{ID: uuid.New(), AuthorID: owner.ID, Slug: "s", Title: "S"}
+
+	cases := []struct {
+		name    string
+		id      string
+		user    *models.User
+		wantErr error
+	}{
+		{"not the owner", existing.ID.String(), other, errs.ErrUnauthorized},
+		{"malformed id", "not-a-uuid", owner, errs.ErrSeriesNotFound},
+		{"missing series", uuid.NewString(), owner, errs.ErrSeriesNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo := newSeriesService()
+			repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+			repo.On("GetByID", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+			_, err := service.Update(tc.id, series.SeriesRequestDTO{Title: "New"}, tc.user)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.ErrorIs(t, service.Delete(tc.id, tc.user), tc.wantErr)
+			_, err = service.SetPosts(tc.id, series.SeriesPostsRequestDTO{}, tc.user)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			repo.AssertNotCalled(t, "Update", mock.Anything)
+			repo.AssertNotCalled(t, "Delete", mock.Anything)
+			repo.AssertNotCalled(t, "ReplacePosts", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSetPosts(t *testing.T) {
+	owner := &models.User{ID: uuid.New()}
+	existing := &models.Series{ID: uuid.New(), AuthorID: owner.ID}
+	a, b := uuid.New(), uuid.New()
+
+	tooMany := make([]string, series.MaxPostsPerSeries+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+
+	cases := []struct {
+		name    string
+		ids     []string
+		owned   int64
+		wantErr error
+	}{
+		{"reorder", []string{b.String(), a.String()}, 2, nil},
+		{"clear", []string{}, 0, nil},
+		{"duplicate post", []string{a.String(), a.String()}, 0, errs.ErrInvalidPayload},
+		{"malformed id", []string{"x"}, 0, errs.ErrInvalidPayload},
+		{"too many posts", tooMany, 0, errs.ErrInvalidPayload},
+		{"post of someone else", []string{a.String(), b.String()}, 1, errs.ErrPostNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo := newSeriesService()
+			repo.On("GetByID", existing.ID.String()).Return(existing, nil)
+			repo.On("CountOwnedPosts", owner.ID, mock.Anything).Return(tc.owned, nil)
+			repo.On("ReplacePosts", existing.ID, mock.Anything).Return(nil)
+			repo.On("GetPosts", existing.ID, false).Return([]models.Post{
+				{ID: b, Slug: "b", Status: models.PostPublished},
+				{ID: a, Slug: "a", Status: models.PostDraft},
+			}, nil)
+
+			dto, err := service.SetPosts(existing.ID.String(), series.SeriesPostsRequestDTO{PostIDs: tc.ids}, owner)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				repo.AssertNotCalled(t, "ReplacePosts", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+
+			want := make([]uuid.UUID, 0, len(tc.ids))
+			for _, id := range tc.ids {
+				want = append(want, uuid.MustParse(id))
+			}
+			repo.AssertCalled(t, "ReplacePosts", existing.ID, want)
+			if len(tc.ids) == 0 {
+				repo.AssertNotCalled(t, "CountOwnedPosts", mock.Anything, mock.Anything)
+			}
+			require.Len(t, dto.Posts, 2)
+			assert.Equal(t, series.SeriesEntryDTO{PostID: b.String(), Slug: "b", Position: 1, Status: string(models.PostPublished)}, dto.Posts[0])
+			assert.Equal(t, 2, dto.Posts[1].Position)
+		})
+	}
+}
+
+func TestGetNavigation(t *testing.T) {
+	s := &models.Series{ID: uuid.New(), Slug: "tutorial", Title: "Tutorial"}
+	posts := []models.Post{{ID: uuid.New(), Slug: "one"}, {ID: uuid.New(), Slug: "two"}, {ID: uuid.New(), Slug: "three"}}
+
+	cases := []struct {
+		name     string
+		postID   uuid.UUID
+		position int
+		previous string
+		next     string
+	}{
+		{"first", posts[0].ID, 1, "", "two"},
+		{"middle", posts[1].ID, 2, "one", "three"},
+		{"last", posts[2].ID, 3, "two", ""},
+		// โพสต์ที่ไม่ได้ publish แบบ public ไม่อยู่ในสารบัญ จึงไม่มีตำแหน่ง
+		{"not listed", uuid.New(), 0, "", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo := newSeriesService()
+			repo.On("GetByPostID", tc.postID).Return(s, nil)
+			repo.On("GetPosts", s.ID, true).Return(posts, nil)
+
+			nav, err := service.GetNavigation(tc.postID)
+			require.NoError(t, err)
+			assert.Equal(t, "tutorial", nav.Slug)
+			assert.Equal(t, 3, nav.Total)
+			assert.Len(t, nav.TableOfContents, 3)
+			assert.Equal(t, tc.position, nav.Position)
+
+			slugOf := func(entry *series.SeriesEntryDTO) string {
+				if entry == nil {
+					return ""
+				}
+				return entry.Slug
+			}
+			assert.Equal(t, tc.previous, slugOf(nav.Previous))
+			assert.Equal(t, tc.next, slugOf(nav.Next))
+		})
+	}
+}
+
+func TestGetNavigation_PostWithoutSeries(t *testing.T) {
+	service, repo := newSeriesService()
+	postID := uuid.New()
+	repo.On("GetByPostID", postID).Return(nil, gorm.ErrRecordNotFound)
+
+	nav, err := service.GetNavigation(postID)
+	require.NoError(t, err)
+	assert.Nil(t, nav)
+
+	ids, err := service.GetSeriesPostIDs(postID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{postID.String()}, ids)
+}
+
+func TestGetSeriesPostIDs(t *testing.T) {
+	s := &models.Series{ID: uuid.New()}
+	current, other := uuid.New(), uuid.New()
+
+	cases := []struct {
+		name  string
+		ready []string
+		want  []string
+	}{
+		{"current post is ready", []string{other.String(), current.String()}, []string{other.String(), current.String()}},
+		{"current post is always searched", []string{other.String()}, []string{current.String(), other.String()}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, repo := newSeriesService()
+			repo.On("GetByPostID", current).Return(s, nil)
+			repo.On("GetAIReadyPostIDs", s.ID).Return(tc.ready, nil)
+
+			ids, err := service.GetSeriesPostIDs(current)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, ids)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package sitemap
+
+import (
+	"rag-searchbot-backend/internal/post"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PostEntry ข้อมูลของโพสต์ที่ใช้สร้าง URL ใน sitemap
+type PostEntry struct {
+	ID        uuid.UUID
+	Slug      string
+	Username  string
+	Thumbnail string
+	UpdatedAt time.Time
+}
+
+// AuthorEntry ผู้เขียนที่มีโพสต์ publish อย่างน้อยหนึ่งโพสต์ (lastmod = โพสต์ที่แก้ไขล่าสุด)
+type AuthorEntry struct {
+	Username  string
+	UpdatedAt time.Time
+}
+
+type RepositoryInterface interface {
+	CountPublishedPosts() (int64, error)
+	GetPublishedPosts(offset, limit int) ([]PostEntry, error)
+	GetUsedImages(postIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+	GetPostPosition(postID uuid.UUID) (int64, error)
+	CountAuthors() (int64, error)
+	GetAuthors(offset, limit int) ([]AuthorEntry, error)
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) publishedPosts() *gorm.DB {
+	return r.DB.Table("posts AS p").
+		Where(post.PublishedPostCondition("p")).
+		Where(post.ListedPostCondition("p"))
+}
+
+func (r *Repository) CountPublishedPosts() (int64, error) {
+	var count int64
+	err := r.publishedPosts().Count(&count).Error
+	return count, err
+}
+
+// GetPublishedPosts เรียงตาม created_at ซึ่งไม่เปลี่ยน ทำให้โพสต์อยู่หน้าเดิมเสมอแม้ถูก publish ซ้ำ
+func (r *Repository) GetPublishedPosts(offset, limit int) ([]PostEntry, error) {
+	var entries []PostEntry
+	err := r.publishedPosts().
+		Select("p.id, p.slug, u.username, p.thumbnail, p.updated_at").
+		Joins("JOIN users u ON u.id = p.author_id").
+		Order("p.created_at ASC, p.id ASC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&entries).Error
+	return entries, err
+}
+
+// GetUsedImages รูปที่ถูกใช้ในเนื้อหาของแต่ละโพสต์
+func (r *Repository) GetUsedImages(postIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	images := make(map[uuid.UUID][]string)
+	if len(postIDs) == 0 {
+		return images, nil
+	}
+
+	var rows []struct {
+		PostID   uuid.UUID
+		ImageURL string
+	}
+	err := r.DB.Table("image_uploads").
+		Select("post_id, image_url").
+		Where("post_id IN ?", postIDs).
+		Where("is_used = ? AND deleted_at IS NULL", true).
+		Order("uploaded_at ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		images[row.PostID] = append(images[row.PostID], row.ImageURL)
+	}
+	return images, nil
+}
+
+// GetPostPosition จำนวนโพสต์ที่ publish แล้วซึ่งอยู่ก่อนโพสต์นี้ตามลำดับของ sitemap
+// ใช้ได้แม้โพสต์นี้จะถูก unpublish หรือ soft delete ไปแล้ว
+func (r *Repository) GetPostPosition(postID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.publishedPosts().
+		Where("(p.created_at, p.id) < (SELECT created_at, id FROM posts WHERE id = ?)", postID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *Repository) CountAuthors() (int64, error) {
+	var count int64
+	err := r.publishedPosts().Distinct("p.author_id").Count(&count).Error
+	return count, err
+}
+
+func (r *Repository) GetAuthors(offset, limit int) ([]AuthorEntry, error) {
+	var entries []AuthorEntry
+	err := r.publishedPosts().
+		Select("u.username, MAX(p.updated_at) AS updated_at").
+		Joins("JOIN users u ON u.id = p.author_id").
+		Group("u.username").
+		Order("u.username ASC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&entries).Error
+	return entries, err
+}
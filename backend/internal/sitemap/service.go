@@ -0,0 +1,202 @@
+package sitemap
+
+import (
+	"context"
+	"fmt"
+	"rag-searchbot-backend/internal/cache"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	PageSize = 1000 // จำนวน URL ต่อหนึ่ง sitemap (โปรโตคอลรับได้สูงสุด 50,000)
+
+	cachePrefix       = "cache:sitemap:"
+	indexCacheKey     = cachePrefix + "index"
+	postsCachePrefix  = cachePrefix + "posts:"
+	authorCachePrefix = cachePrefix + "authors:"
+)
+
+type ServiceInterface interface {
+	Index() ([]byte, error)
+	PostsPage(page int) ([]byte, error)
+	AuthorsPage(page int) ([]byte, error)
+	Robots() string
+}
+
+type Service struct {
+	Repo    RepositoryInterface
+	Cache   cache.ServiceInterface
+	SiteURL string // URL ของหน้าเว็บ (ลิงก์ไปยังโพสต์และโปรไฟล์)
+	CoreURL string // URL ของ API ที่ให้บริการไฟล์ sitemap
+}
+
+func NewService(repo RepositoryInterface, cacheService cache.ServiceInterface, siteURL, coreURL string) *Service {
+	return &Service{
+		Repo:    repo,
+		Cache:   cacheService,
+		SiteURL: strings.TrimRight(siteURL, "/"),
+		CoreURL: strings.TrimRight(coreURL, "/"),
+	}
+}
+
+func pageCount(total int64) int {
+	return int((total + PageSize - 1) / PageSize)
+}
+
+// cached คืน sitemap จาก cache ถ้ามี ไม่เช่นนั้นสร้างใหม่แล้วเก็บไว้จนกว่าจะถูก invalidate
+func (s *Service) cached(key string, build func() ([]byte, error)) ([]byte, error) {
+	if body, ok := s.Cache.GetString(context.Background(), key); ok {
+		return []byte(body), nil
+	}
+
+	body, err := build()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Cache.Set(context.Background(), key, string(body)); err != nil {
+		logger.Log.Warn("Failed to cache sitemap", zap.String("key", key), zap.Error(err))
+	}
+	return body, nil
+}
+
+// Index รายการ sitemap ย่อยทั้งหมด (โพสต์แบ่งหน้า และโปรไฟล์ผู้เขียน)
+func (s *Service) Index() ([]byte, error) {
+	return s.cached(indexCacheKey, func() ([]byte, error) {
+		totalPosts, err := s.Repo.CountPublishedPosts()
+		if err != nil {
+			return nil, err
+		}
+		totalAuthors, err := s.Repo.CountAuthors()
+		if err != nil {
+			return nil, err
+		}
+
+		index := sitemapIndex{}
+		for page := 1; page <= pageCount(totalPosts); page++ {
+			index.Sitemaps = append(index.Sitemaps, sitemapRef{Loc: fmt.Sprintf("%s/sitemaps/posts/%d.xml", s.CoreURL, page)})
+		}
+		for page := 1; page <= pageCount(totalAuthors); page++ {
+			index.Sitemaps = append(index.Sitemaps, sitemapRef{Loc: fmt.Sprintf("%s/sitemaps/authors/%d.xml", s.CoreURL, page)})
+		}
+		return marshal(index)
+	})
+}
+
+// PostsPage sitemap ของโพสต์หน้าที่ page พร้อม thumbnail และรูปในเนื้อหาเป็น image entry
+func (s *Service) PostsPage(page int) ([]byte, error) {
+	if page < 1 {
+		return nil, errs.ErrPageNotFound
+	}
+
+	return s.cached(fmt.Sprintf("%s%d", postsCachePrefix, page), func() ([]byte, error) {
+		entries, err := s.Repo.GetPublishedPosts((page-1)*PageSize, PageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return nil, errs.ErrPageNotFound
+		}
+
+		ids := make([]uuid.UUID, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		images, err := s.Repo.GetUsedImages(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		set := urlSet{ImageNS: "http://www.google.com/schemas/sitemap-image/1.1"}
+		for _, entry := range entries {
+			url := urlEntry{
+				Loc:     fmt.Sprintf("%s/posts/%s/%s", s.SiteURL, entry.Username, entry.Slug),
+				LastMod: lastMod(entry.UpdatedAt),
+			}
+
+			seen := make(map[string]bool)
+			for _, image := range append([]string{entry.Thumbnail}, images[entry.ID]...) {
+				if image == "" || seen[image] {
+					continue
+				}
+				seen[image] = true
+				url.Images = append(url.Images, imageEntry{Loc: image})
+			}
+			set.URLs = append(set.URLs, url)
+		}
+		return marshal(set)
+	})
+}
+
+// AuthorsPage sitemap ของหน้าโปรไฟล์ผู้เขียนที่มีโพสต์ publish แล้ว
+func (s *Service) AuthorsPage(page int) ([]byte, error) {
+	if page < 1 {
+		return nil, errs.ErrPageNotFound
+	}
+
+	return s.cached(fmt.Sprintf("%s%d", authorCachePrefix, page), func() ([]byte, error) {
+		entries, err := s.Repo.GetAuthors((page-1)*PageSize, PageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return nil, errs.ErrPageNotFound
+		}
+
+		set := urlSet{ImageNS: "http://www.google.com/schemas/sitemap-image/1.1"}
+		for _, entry := range entries {
+			set.URLs = append(set.URLs, urlEntry{
+				Loc:     s.SiteURL + "/" + entry.Username,
+				LastMod: lastMod(entry.UpdatedAt),
+			})
+		}
+		return marshal(set)
+	})
+}
+
+func (s *Service) Robots() string {
+	return fmt.Sprintf("User-agent: *\nAllow: /\n\nSitemap: %s/sitemap.xml\n", s.CoreURL)
+}
+
+/**
+* invalidatePost clears only the cached sitemaps affected by a post changing visibility.
+* @param postID uuid.UUID - The post that was published, unpublished or deleted
+* Posts are ordered by created_at, so only the page holding the post and the pages after it
+* shift. The index and the author sitemaps are cleared too because page counts and lastmod change.
+**/
+
+func (s *Service) invalidatePost(postID uuid.UUID) {
+	s.Cache.Delete(indexCacheKey)
+	s.Cache.DeletePrefix(authorCachePrefix)
+
+	position, err := s.Repo.GetPostPosition(postID)
+	if err != nil {
+		logger.Log.Warn("Failed to locate post in sitemap, clearing all pages",
+			zap.String("post_id", postID.String()), zap.Error(err))
+		s.Cache.DeletePrefix(postsCachePrefix)
+		return
+	}
+	total, err := s.Repo.CountPublishedPosts()
+	if err != nil {
+		s.Cache.DeletePrefix(postsCachePrefix)
+		return
+	}
+
+	// +1 เผื่อหน้าสุดท้ายที่หายไปเมื่อจำนวนโพสต์ลดลง
+	for page := int(position/PageSize) + 1; page <= pageCount(total)+1; page++ {
+		s.Cache.Delete(fmt.Sprintf("%s%d", postsCachePrefix, page))
+	}
+}
+
+func (s *Service) OnPostPublished(post *models.Post) {
+	s.invalidatePost(post.ID)
+}
+
+func (s *Service) OnPostUnpublished(post *models.Post) {
+	s.invalidatePost(post.ID)
+}
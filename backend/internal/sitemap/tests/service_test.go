@@ -0,0 +1,258 @@
+package tests
+
+import (
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/cache"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/internal/sitemap"
+	"rag-searchbot-backend/pkg/errs"
+	"rag-searchbot-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRepository เก็บโพสต์ที่ publish แล้วตามลำดับของ sitemap และนับการ query ต่อ offset
+type fakeRepository struct {
+	posts   []sitemap.PostEntry
+	images  map[uuid.UUID][]string
+	authors []sitemap.AuthorEntry
+	loads   map[int]int
+	// ตำแหน่งเดิมของโพสต์ที่ถูก unpublish แล้ว (repository จริงหาจาก created_at ได้เสมอ)
+	removed map[uuid.UUID]int64
+}
+
+func newFakeRepository(posts, authors int) *fakeRepository {
+	repo := &fakeRepository{images: make(map[uuid.UUID][]string), loads: make(map[int]int), removed: make(map[uuid.UUID]int64)}
+	for i := 0; i < posts; i++ {
+		repo.posts = append(repo.posts, sitemap.PostEntry{
+			ID:        uuid.New(),
+			Slug:      fmt.Sprintf("post-%d", i),
+			Username:  "alice",
+			UpdatedAt: time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+		})
+	}
+	for i := 0; i < authors; i++ {
+		repo.authors = append(repo.authors, sitemap.AuthorEntry{Username: fmt.Sprintf("author-%04d", i)})
+	}
+	return repo
+}
+
+func window(total, offset, limit int) (int, int) {
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
+
+func (r *fakeRepository) CountPublishedPosts() (int64, error) {
+	return int64(len(r.posts)), nil
+}
+
+func (r *fakeRepository) GetPublishedPosts(offset, limit int) ([]sitemap.PostEntry, error) {
+	r.loads[offset]++
+	start, end := window(len(r.posts), offset, limit)
+	return r.posts[start:end], nil
+}
+
+func (r *fakeRepository) GetUsedImages(postIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	return r.images, nil
+}
+
+func (r *fakeRepository) GetPostPosition(postID uuid.UUID) (int64, error) {
+	for i, p := range r.posts {
+		if p.ID == postID {
+			return int64(i), nil
+		}
+	}
+	if position, ok := r.removed[postID]; ok {
+		return position, nil
+	}
+	return 0, fmt.Errorf("post %s not found", postID)
+}
+
+func (r *fakeRepository) unpublish(index int) uuid.UUID {
+	id := r.posts[index].ID
+	r.removed[id] = int64(index)
+	r.posts = append(r.posts[:index:index], r.posts[index+1:]...)
+	return id
+}
+
+func (r *fakeRepository) CountAuthors() (int64, error) {
+	return int64(len(r.authors)), nil
+}
+
+func (r *fakeRepository) GetAuthors(offset, limit int) ([]sitemap.AuthorEntry, error) {
+	start, end := window(len(r.authors), offset, limit)
+	return r.authors[start:end], nil
+}
+
+type sitemapIndex struct {
+	Locs []string `xml:"sitemap>loc"`
+}
+
+type urlSet struct {
+	URLs []struct {
+		Loc     string   `xml:"loc"`
+		LastMod string   `xml:"lastmod"`
+		Images  []string `xml:"image>loc"`
+	} `xml:"url"`
+}
+
+func newService(repo sitemap.RepositoryInterface) *sitemap.Service {
+	logger.Log = zap.NewNop()
+	return sitemap.NewService(repo, cache.NewService(nil, time.Minute), "https://blog.example.com/", "https://api.example.com/")
+}
+
+func TestIndex_PageCount(t *testing.T) {
+	cases := []struct {
+		name    string
+		posts   int
+		authors int
+		want    []string
+	}{
+		{"empty site", 0, 0, nil},
+		{"one partial page", 1, 1, []string{"posts/1", "authors/1"}},
+		{"exactly one page", sitemap.PageSize, 1, []string{"posts/1", "authors/1"}},
+		{"one over a page", sitemap.PageSize + 1, sitemap.PageSize + 1, []string{"posts/1", "posts/2", "authors/1", "authors/2"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := newService(newFakeRepository(tc.posts, tc.authors)).Index()
+			require.NoError(t, err)
+
+			var index sitemapIndex
+			require.NoError(t, xml.Unmarshal(body, &index))
+			var want []string
+			for _, ref := range tc.want {
+				want = append(want, "https://api.example.com/sitemaps/"+ref+".xml")
+			}
+			assert.Equal(t, want, index.Locs)
+		})
+	}
+}
+
+func TestPostsPage_Paging(t *testing.T) {
+	repo := newFakeRepository(sitemap.PageSize+5, 0)
+	service := newService(repo)
+
+	cases := []struct {
+		page    int
+		want    int
+		first   string
+		wantErr error
+	}{
+		{page: 1, want: sitemap.PageSize, first: "post-0"},
+		{page: 2, want: 5, first: fmt.Sprintf("post-%d", sitemap.PageSize)},
+		{page: 3, wantErr: errs.ErrPageNotFound},
+		{page: 0, wantErr: errs.ErrPageNotFound},
+		{page: -1, wantErr: errs.ErrPageNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("page %d", tc.page), func(t *testing.T) {
+			body, err := service.PostsPage(tc.page)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var set urlSet
+			require.NoError(t, xml.Unmarshal(body, &set))
+			require.Len(t, set.URLs, tc.want)
+			assert.Equal(t, "https://blog.example.com/posts/alice/"+tc.first, set.URLs[0].Loc)
+		})
+	}
+}
+
+func TestPostsPage_ImagesAndLastMod(t *testing.T) {
+	repo := newFakeRepository(2, 0)
+	repo.posts[0].Thumbnail = "https://cdn.example.com/thumb.png"
+	repo.images[repo.posts[0].ID] = []string{"https://cdn.example.com/a.png", "https://cdn.example.com/thumb.png"}
+
+	body, err := newService(repo).PostsPage(1)
+	require.NoError(t, err)
+
+	var set urlSet
+	require.NoError(t, xml.Unmarshal(body, &set))
+	require.Len(t, set.URLs, 2)
+	assert.Equal(t, []string{"https://cdn.example.com/thumb.png", "https://cdn.example.com/a.png"}, set.URLs[0].Images)
+	assert.Empty(t, set.URLs[1].Images)
+	assert.Equal(t, "2026-01-01T00:00:00Z", set.URLs[0].LastMod)
+}
+
+func TestAuthorsPage_Paging(t *testing.T) {
+	service := newService(newFakeRepository(0, sitemap.PageSize+1))
+
+	body, err := service.AuthorsPage(2)
+	require.NoError(t, err)
+	var set urlSet
+	require.NoError(t, xml.Unmarshal(body, &set))
+	require.Len(t, set.URLs, 1)
+	assert.Equal(t, fmt.Sprintf("https://blog.example.com/author-%04d", sitemap.PageSize), set.URLs[0].Loc)
+	assert.Empty(t, set.URLs[0].LastMod)
+
+	_, err = service.AuthorsPage(3)
+	assert.ErrorIs(t, err, errs.ErrPageNotFound)
+}
+
+// Test case: publish หรือ unpublish โพสต์ ต้องล้างเฉพาะหน้าที่โพสต์นั้นอยู่และหน้าหลังจากนั้น
+func TestInvalidatePost_ClearsOnlyShiftedPages(t *testing.T) {
+	repo := newFakeRepository(3*sitemap.PageSize, 0)
+	service := newService(repo)
+
+	for page := 1; page <= 3; page++ {
+		_, err := service.PostsPage(page)
+		require.NoError(t, err)
+	}
+	// หน้าเดิมมาจาก cache
+	_, err := service.PostsPage(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.loads[0])
+
+	// โพสต์แรกของหน้าที่ 2 ถูก unpublish: หน้า 2 และ 3 เลื่อน หน้าที่ 4 ว่าง
+	removed := repo.unpublish(sitemap.PageSize)
+	service.OnPostUnpublished(&models.Post{ID: removed})
+	for page := 1; page <= 3; page++ {
+		_, err := service.PostsPage(page)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, repo.loads[0], "page before the post stays cached")
+	assert.Equal(t, 2, repo.loads[sitemap.PageSize])
+	assert.Equal(t, 2, repo.loads[2*sitemap.PageSize])
+
+	body, err := service.PostsPage(3)
+	require.NoError(t, err)
+	var set urlSet
+	require.NoError(t, xml.Unmarshal(body, &set))
+	assert.Len(t, set.URLs, sitemap.PageSize-1)
+}
+
+func TestInvalidatePost_UnknownPositionClearsAllPages(t *testing.T) {
+	repo := newFakeRepository(sitemap.PageSize+1, 1)
+	service := newService(repo)
+
+	for page := 1; page <= 2; page++ {
+		_, err := service.PostsPage(page)
+		require.NoError(t, err)
+	}
+	service.OnPostPublished(&models.Post{ID: uuid.New()})
+	for page := 1; page <= 2; page++ {
+		_, err := service.PostsPage(page)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, repo.loads[0])
+	assert.Equal(t, 2, repo.loads[sitemap.PageSize])
+}
@@ -0,0 +1,19 @@
+package taxonomy
+
+// TagSuggestionDTO ผลลัพธ์ของ tag autocomplete
+type TagSuggestionDTO struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	PostCount int64  `json:"post_count"`
+}
+
+type CategoryDTO struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	PostCount int64  `json:"post_count"`
+}
+
+// CategoryRequestDTO สำหรับสร้าง / แก้ไขชื่อ category (admin)
+type CategoryRequestDTO struct {
+	Name string `json:"name" binding:"required"`
+}
@@ -0,0 +1,101 @@
+package taxonomy
+
+import (
+	"fmt"
+	"rag-searchbot-backend/pkg/errs"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	MaxTagsPerPost = 10 // จำนวน tag สูงสุดต่อโพสต์
+	MaxNameLength  = 50 // ความยาวสูงสุดของชื่อ tag / category (นับเป็นตัวอักษร)
+)
+
+// isInvisible คือตัวอักษรที่มองไม่เห็นซึ่งมักติดมากับข้อความภาษาไทย (zero-width space ใช้ตัดคำ) หรือ BOM
+func isInvisible(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+		return true
+	}
+	return false
+}
+
+// normalizeSpaces ทำ NFC ลบตัวอักษรที่มองไม่เห็น และยุบ whitespace ทุกชนิด (รวม NBSP) ให้เหลือช่องว่างเดียว
+func normalizeSpaces(name string) string {
+	name = norm.NFC.String(name)
+
+	var b strings.Builder
+	pendingSpace := false
+	for _, r := range name {
+		if isInvisible(r) {
+			continue
+		}
+		if unicode.IsSpace(r) {
+			pendingSpace = b.Len() > 0
+			continue
+		}
+		if pendingSpace {
+			b.WriteRune(' ')
+			pendingSpace = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// NormalizeTagName ทำให้ชื่อ tag อยู่ในรูปแบบเดียวกัน เช่น " #Go  Lang " และ "go lang" จะได้ "go lang"
+func NormalizeTagName(name string) string {
+	name = normalizeSpaces(name)
+	name = strings.TrimLeft(name, "#")
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NormalizeTagNames normalize และตัดชื่อซ้ำ โดยคงลำดับเดิมไว้
+func NormalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+
+	for _, name := range names {
+		normalized := NormalizeTagName(name)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		if utf8.RuneCountInString(normalized) > MaxNameLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", errs.ErrInvalidPayload, normalized, MaxNameLength)
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+
+	if len(result) > MaxTagsPerPost {
+		return nil, fmt.Errorf("%w: a post can have at most %d tags", errs.ErrInvalidPayload, MaxTagsPerPost)
+	}
+	return result, nil
+}
+
+// NormalizeCategoryName จัด whitespace ของชื่อ category แต่คงตัวพิมพ์ไว้ตามที่ admin ตั้ง
+// การเทียบชื่อซ้ำของ category ใช้แบบไม่สนตัวพิมพ์ที่ระดับ repository
+func NormalizeCategoryName(name string) string {
+	return normalizeSpaces(name)
+}
+
+// NormalizeCategoryNames normalize และตัดชื่อ category ที่ซ้ำกัน (ไม่สนตัวพิมพ์)
+func NormalizeCategoryNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+
+	for _, name := range names {
+		normalized := NormalizeCategoryName(name)
+		key := strings.ToLower(normalized)
+		if normalized == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, normalized)
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package taxonomy
+
+import (
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+type RepositoryInterface interface {
+	SearchTags(prefix string, limit int) ([]TagCount, error)
+	GetTagByName(name string) (*models.Tag, error)
+	ListCategories() ([]CategoryCount, error)
+	GetCategoryByID(id uint) (*models.Category, error)
+	GetCategoryByName(name string) (*models.Category, error)
+	CreateCategory(category *models.Category) error
+	UpdateCategory(category *models.Category) error
+	DeleteCategory(category *models.Category) error
+}
+
+// TagCount tag พร้อมจำนวนโพสต์ที่ publish แล้วซึ่งใช้ tag นี้
+type TagCount struct {
+	ID        uint
+	Name      string
+	PostCount int64
+}
+
+// CategoryCount category พร้อมจำนวนโพสต์ที่ publish แล้วใน category นี้
+type CategoryCount struct {
+	ID        uint
+	Name      string
+	PostCount int64
+}
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) RepositoryInterface {
+	return &Repository{DB: db}
+}
+
+// publishedPostJoin นับเฉพาะโพสต์ที่ publish แล้ว ยังไม่ถูกลบ และแสดงในรายการ (public) ให้ตรงกับหน้า tag / category
+const publishedPostJoin = "LEFT JOIN posts ON posts.id = %s.post_id AND posts.published = true AND posts.deleted_at IS NULL AND posts.visibility = 'public'"
+
+func (r *Repository) SearchTags(prefix string, limit int) ([]TagCount, error) {
+	var tags []TagCount
+	err := r.DB.Model(&models.Tag{}).
+		Select("tags.id, tags.name, COUNT(posts.id) AS post_count").
+		Joins("LEFT JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins(fmt.Sprintf(publishedPostJoin, "post_tags")).
+		Where("tags.name LIKE ?", utils.EscapeLike(prefix)+"%").
+		Group("tags.id, tags.name").
+		Order("post_count DESC, tags.name ASC").
+		Limit(limit).
+		Scan(&tags).Error
+	return tags, err
+}
+
+func (r *Repository) GetTagByName(name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.DB.Where("name = ?", name).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *Repository) ListCategories() ([]CategoryCount, error) {
+	var categories []CategoryCount
+	err := r.DB.Model(&models.Category{}).
+		Select("categories.id, categories.name, COUNT(posts.id) AS post_count").
+		Joins("LEFT JOIN post_categories ON post_categories.category_id = categories.id").
+		Joins(fmt.Sprintf(publishedPostJoin, "post_categories")).
+		Group("categories.id, categories.name").
+		Order("categories.name ASC").
+		Scan(&categories).Error
+	return categories, err
+}
+
+func (r *Repository) GetCategoryByID(id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.DB.First(&category, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetCategoryByName ค้นหาแบบไม่สนตัวพิมพ์ เพื่อกันชื่อซ้ำอย่าง "Go" กับ "go"
+func (r *Repository) GetCategoryByName(name string) (*models.Category, error) {
+	var category models.Category
+	if err := r.DB.Where("LOWER(name) = LOWER(?)", name).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *Repository) CreateCategory(category *models.Category) error {
+	return r.DB.Create(category).Error
+}
+
+func (r *Repository) UpdateCategory(category *models.Category) error {
+	return r.DB.Model(category).Update("name", category.Name).Error
+}
+
+// DeleteCategory ถอด category ออกจากทุกโพสต์แล้วลบถาวร (ชื่อเป็น unique จึงไม่ใช้ soft delete)
+func (r *Repository) DeleteCategory(category *models.Category) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(category).Association("Posts").Clear(); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(category).Error
+	})
+}
@@ -0,0 +1,149 @@
+package taxonomy
+
+import (
+	"errors"
+	"fmt"
+	"rag-searchbot-backend/internal/models"
+	"rag-searchbot-backend/pkg/errs"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultSuggestionLimit = 10
+	MaxSuggestionLimit     = 50
+)
+
+type ServiceInterface interface {
+	SuggestTags(query string, limit int) ([]TagSuggestionDTO, error)
+	GetTagByName(name string) (*models.Tag, error)
+	ListCategories() ([]CategoryDTO, error)
+	GetCategoryByName(name string) (*models.Category, error)
+	CreateCategory(req CategoryRequestDTO) (*CategoryDTO, error)
+	UpdateCategory(id uint, req CategoryRequestDTO) (*CategoryDTO, error)
+	DeleteCategory(id uint) error
+}
+
+type Service struct {
+	Repo RepositoryInterface
+}
+
+func NewService(repo RepositoryInterface) ServiceInterface {
+	return &Service{Repo: repo}
+}
+
+// SuggestTags ค้นหา tag ที่ขึ้นต้นด้วยคำค้น เรียงตามจำนวนโพสต์ที่ใช้
+func (s *Service) SuggestTags(query string, limit int) ([]TagSuggestionDTO, error) {
+	if limit <= 0 {
+		limit = DefaultSuggestionLimit
+	}
+	if limit > MaxSuggestionLimit {
+		limit = MaxSuggestionLimit
+	}
+
+	tags, err := s.Repo.SearchTags(NormalizeTagName(query), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TagSuggestionDTO, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, TagSuggestionDTO{ID: t.ID, Name: t.Name, PostCount: t.PostCount})
+	}
+	return result, nil
+}
+
+func (s *Service) GetTagByName(name string) (*models.Tag, error) {
+	tag, err := s.Repo.GetTagByName(NormalizeTagName(name))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrTagNotFound
+	}
+	return tag, err
+}
+
+func (s *Service) ListCategories() ([]CategoryDTO, error) {
+	categories, err := s.Repo.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CategoryDTO, 0, len(categories))
+	for _, c := range categories {
+		result = append(result, CategoryDTO{ID: c.ID, Name: c.Name, PostCount: c.PostCount})
+	}
+	return result, nil
+}
+
+func (s *Service) GetCategoryByName(name string) (*models.Category, error) {
+	category, err := s.Repo.GetCategoryByName(NormalizeCategoryName(name))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// validateCategoryName normalize ชื่อและตรวจว่าไม่ชนกับ category อื่น (ไม่สนตัวพิมพ์)
+func (s *Service) validateCategoryName(name string, currentID uint) (string, error) {
+	name = NormalizeCategoryName(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: category name is required", errs.ErrInvalidPayload)
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return "", fmt.Errorf("%w: category name is longer than %d characters", errs.ErrInvalidPayload, MaxNameLength)
+	}
+
+	existing, err := s.Repo.GetCategoryByName(name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if existing != nil && existing.ID != currentID {
+		return "", errs.ErrCategoryExists
+	}
+	return name, nil
+}
+
+func (s *Service) CreateCategory(req CategoryRequestDTO) (*CategoryDTO, error) {
+	name, err := s.validateCategoryName(req.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &models.Category{Name: name}
+	if err := s.Repo.CreateCategory(category); err != nil {
+		return nil, err
+	}
+	return &CategoryDTO{ID: category.ID, Name: category.Name}, nil
+}
+
+func (s *Service) UpdateCategory(id uint, req CategoryRequestDTO) (*CategoryDTO, error) {
+	category, err := s.Repo.GetCategoryByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.validateCategoryName(req.Name, category.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	category.Name = name
+	if err := s.Repo.UpdateCategory(category); err != nil {
+		return nil, err
+	}
+	return &CategoryDTO{ID: category.ID, Name: category.Name}, nil
+}
+
+func (s *Service) DeleteCategory(id uint) error {
+	category, err := s.Repo.GetCategoryByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.Repo.DeleteCategory(category)
+}
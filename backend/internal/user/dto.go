@@ -32,6 +32,7 @@ type UserProfileResponse struct {
 	Following   int64            `json:"following"`
 	SocialMedia SocialMediaLinks `json:"social_media,omitempty"`
 	CanEdit     bool             `json:"can_edit"`
+	IsFollowing bool             `json:"is_following"` // ผู้ใช้ที่ login อยู่ติดตามโปรไฟล์นี้หรือไม่
 }
 
 type SocialMediaLinks struct {
@@ -3,6 +3,7 @@ package user
 import (
 	"rag-searchbot-backend/internal/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -14,6 +15,8 @@ type RepositoryInterface interface {
 	GetUserByUsername(username string) (bool, error)
 	GetUserProfileByUsername(username string) (*models.User, error)
 	UpdateUser(user *models.User) error
+	CountFollows(userID uuid.UUID) (followers int64, following int64, err error)
+	IsFollowing(followerID, followeeID uuid.UUID) (bool, error)
 }
 
 type Repository struct {
@@ -114,3 +117,29 @@ func (r *Repository) UpdateUser(user *models.User) error {
 			"new_user":   false,
 		}).Error
 }
+
+// CountFollows จำนวนผู้ติดตาม และจำนวนคนที่ผู้ใช้ติดตาม ไม่นับบัญชีที่ถูกลบแล้ว
+func (r *Repository) CountFollows(userID uuid.UUID) (int64, int64, error) {
+	var followers, following int64
+	if err := r.DB.Model(&models.Follow{}).
+		Joins("JOIN users AS other_user ON other_user.id = follows.follower_id AND other_user.deleted_at IS NULL").
+		Where("follows.followee_id = ?", userID).
+		Count(&followers).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := r.DB.Model(&models.Follow{}).
+		Joins("JOIN users AS other_user ON other_user.id = follows.followee_id AND other_user.deleted_at IS NULL").
+		Where("follows.follower_id = ?", userID).
+		Count(&following).Error; err != nil {
+		return 0, 0, err
+	}
+	return followers, following, nil
+}
+
+func (r *Repository) IsFollowing(followerID, followeeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.DB.Model(&models.Follow{}).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Count(&count).Error
+	return count > 0, err
+}
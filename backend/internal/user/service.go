@@ -357,27 +357,35 @@ func (s *Service) GetUserProfileByUsername(username string, currentUserID *uuid.
 
 	// Check if current user can edit this profile
 	canEdit := false
+	isFollowing := false
 	if currentUserID != nil {
 		canEdit = user.ID == *currentUserID
+		if !canEdit {
+			if isFollowing, err = s.Repo.IsFollowing(*currentUserID, user.ID); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	// Get followers and following count (placeholder for now)
-	followers := int64(0)
-	following := int64(0)
+	followers, following, err := s.Repo.CountFollows(user.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	profile := &UserProfileResponse{
-		Username:  user.UserName,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Avatar:    user.Avatar,
-		Bio:       user.Bio,
-		Role:      string(user.Role),
-		Location:  user.Location,
-		Website:   user.Website,
-		JoinedAt:  user.CreatedAt.Format("January 2006"),
-		Followers: followers,
-		Following: following,
-		CanEdit:   canEdit,
+		Username:    user.UserName,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Avatar:      user.Avatar,
+		Bio:         user.Bio,
+		Role:        string(user.Role),
+		Location:    user.Location,
+		Website:     user.Website,
+		JoinedAt:    user.CreatedAt.Format("January 2006"),
+		Followers:   followers,
+		Following:   following,
+		CanEdit:     canEdit,
+		IsFollowing: isFollowing,
 		SocialMedia: SocialMediaLinks{
 			GitHub:    user.GitHub,
 			Twitter:   user.Twitter,
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/internal/user"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestUserRepository_CountFollows(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	for _, ddl := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE follows (id INTEGER PRIMARY KEY AUTOINCREMENT, follower_id TEXT, followee_id TEXT, created_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+
+	ids := map[string]uuid.UUID{}
+	for _, name := range []string{"alice", "bob", "carol", "gone"} {
+		ids[name] = uuid.New()
+		require.NoError(t, db.Exec(`INSERT INTO users (id, username) VALUES (?, ?)`, ids[name].String(), name).Error)
+	}
+	for _, pair := range [][2]string{{"bob", "alice"}, {"carol", "alice"}, {"gone", "alice"}, {"alice", "bob"}, {"alice", "gone"}} {
+		require.NoError(t, db.Exec(`INSERT INTO follows (follower_id, followee_id, created_at) VALUES (?, ?, ?)`,
+			ids[pair[0]].String(), ids[pair[1]].String(), time.Now()).Error)
+	}
+	require.NoError(t, db.Exec(`UPDATE users SET deleted_at = ? WHERE username = 'gone'`, time.Now()).Error)
+
+	repo := user.NewRepository(db)
+	cases := []struct {
+		name      string
+		followers int64
+		following int64
+	}{
+		{"alice", 2, 1},
+		{"bob", 1, 1},
+		{"carol", 0, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			followers, following, err := repo.CountFollows(ids[tc.name])
+			require.NoError(t, err)
+			assert.Equal(t, tc.followers, followers)
+			assert.Equal(t, tc.following, following)
+		})
+	}
+
+	following, err := repo.IsFollowing(ids["bob"], ids["alice"])
+	require.NoError(t, err)
+	assert.True(t, following)
+	following, err = repo.IsFollowing(ids["alice"], ids["carol"])
+	require.NoError(t, err)
+	assert.False(t, following)
+}
@@ -165,3 +165,45 @@ func (cs *CryptoService) GenerateSocketToken() (string, error) {
 	token = strings.TrimRight(token, "=")
 	return token, nil
 }
+
+// GeneratePostAccessToken creates a short-lived token that unlocks a protected post
+func (cs *CryptoService) GeneratePostAccessToken(postID string, expiry time.Duration) (string, error) {
+	cfg := config.LoadConfig()
+	if cfg.PostAccessSecret == "" {
+		return "", errors.New("POST_ACCESS_SECRET is not configured")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  postID,
+		"iss":  cfg.AppUrl,
+		"iat":  time.Now().Unix(),
+		"exp":  time.Now().Add(expiry).Unix(),
+		"type": "post_access",
+	})
+
+	return token.SignedString([]byte(cfg.PostAccessSecret))
+}
+
+// VerifyPostAccessToken checks that the token was issued for this post and has not expired
+func (cs *CryptoService) VerifyPostAccessToken(tokenString, postID string) bool {
+	cfg := config.LoadConfig()
+	if cfg.PostAccessSecret == "" || tokenString == "" {
+		return false
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(cfg.PostAccessSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return false
+	}
+	return claims["type"] == "post_access" && claims["sub"] == postID
+}
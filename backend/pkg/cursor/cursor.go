@@ -0,0 +1,93 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"rag-searchbot-backend/pkg/errs"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cursor ตำแหน่งของรายการสุดท้ายในหน้าก่อนหน้า สำหรับรายการที่เรียงตามเวลาใหม่ไปเก่า
+// ใช้ ID ตัดสินเมื่อเวลาเท่ากัน
+type Cursor struct {
+	Time     time.Time `json:"t"`
+	ID       string    `json:"id"`
+	IssuedAt int64     `json:"iat"` // unix วินาทีที่ออก cursor ใช้ตัดสินว่าหมดอายุหรือยัง
+}
+
+// MaxAge อายุของ cursor หลังจากนั้น client ต้องเริ่มจากหน้าแรกใหม่
+// กันลิงก์ "หน้าถัดไป" เก่าที่ถูกเก็บไว้ถูกใช้ไล่ดึงข้อมูลได้ตลอดไป
+const MaxAge = 24 * time.Hour
+
+var (
+	mu     sync.RWMutex
+	secret = randomSecret()
+	now    = time.Now
+)
+
+// randomSecret ใช้เมื่อไม่ได้ตั้ง CURSOR_SECRET cursor ที่ออกไปแล้วจะใช้ไม่ได้หลัง restart
+func randomSecret() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// Configure ตั้ง key ที่ใช้เซ็น cursor (ว่าง = ใช้ key สุ่มของ process นี้)
+// ทุก instance ของ server ต้องใช้ key เดียวกัน ไม่งั้น cursor จากอีก instance จะถูกปฏิเสธ
+func Configure(key string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if key == "" {
+		secret = randomSecret()
+		return
+	}
+	secret = []byte(key)
+}
+
+func sign(payload string) string {
+	mu.RLock()
+	mac := hmac.New(sha256.New, secret)
+	mu.RUnlock()
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Encode แปลง cursor เป็น string แบบ opaque พร้อมลายเซ็น client แก้ค่าเองไม่ได้
+func Encode(t time.Time, id string) string {
+	data, _ := json.Marshal(Cursor{Time: t, ID: id, IssuedAt: now().Unix()})
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + sign(payload)
+}
+
+// Decode คืน nil เมื่อไม่ได้ส่ง cursor มา (หน้าแรก) และ errs.ErrInvalidCursor เมื่อลายเซ็นไม่ตรงหรือเกิน MaxAge
+func Decode(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	payload, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(payload))) {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, errs.ErrInvalidCursor
+	}
+	if now().Sub(time.Unix(c.IssuedAt, 0)) > MaxAge {
+		return nil, errs.ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -0,0 +1,146 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"rag-searchbot-backend/pkg/errs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withClock ตรึงเวลาที่ Encode / Decode เห็น แล้วคืนค่าเดิมเมื่อจบ test
+func withClock(t *testing.T, at *time.Time) {
+	t.Helper()
+	previous := now
+	now = func() time.Time { return *at }
+	t.Cleanup(func() { now = previous })
+}
+
+func withKey(t *testing.T, key string) {
+	t.Helper()
+	Configure(key)
+	t.Cleanup(func() { Configure("") })
+}
+
+// signed เซ็น payload ที่สร้างเอง ใช้จำลอง cursor ที่ลายเซ็นถูกแต่เนื้อหาผิดรูป
+func signed(raw string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(raw))
+	return payload + "." + sign(payload)
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	withKey(t, "test-key")
+	at := time.Date(2026, 5, 1, 8, 30, 0, 123456789, time.UTC)
+
+	c, err := Decode(Encode(at, "42"))
+	require.NoError(t, err)
+	assert.True(t, at.Equal(c.Time))
+	assert.Equal(t, "42", c.ID)
+
+	empty, err := Decode("")
+	assert.NoError(t, err)
+	assert.Nil(t, empty, "no cursor means the first page")
+}
+
+func TestDecode_RejectsTampering(t *testing.T) {
+	withKey(t, "test-key")
+	valid := Encode(time.Now(), "42")
+	payload, signature, _ := strings.Cut(valid, ".")
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	require.NoError(t, err)
+	var c Cursor
+	require.NoError(t, json.Unmarshal(data, &c))
+	c.ID = "41"
+	forged, err := json.Marshal(c)
+	require.NoError(t, err)
+	forgedPayload := base64.RawURLEncoding.EncodeToString(forged)
+
+	flipped := []byte(signature)
+	flipped[0] ^= 1
+
+	cases := map[string]string{
+		"no signature":         payload,
+		"empty signature":      payload + ".",
+		"payload changed":      forgedPayload + "." + signature,
+		"signature changed":    payload + "." + string(flipped),
+		"signature truncated":  payload + "." + signature[:len(signature)-2],
+		"signature of another": payload + "." + sign(forgedPayload),
+		"extra segment":        valid + ".x",
+		"garbage":              "not-a-cursor",
+		"signed but not json":  signed("{"),
+		"signed without id":    signed(`{"t":"2026-01-01T00:00:00Z","iat":` + strconv.FormatInt(time.Now().Unix(), 10) + `}`),
+		"signed bad base64":    "@@@." + sign("@@@"),
+	}
+
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := Decode(value)
+			assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+			assert.Nil(t, c)
+		})
+	}
+}
+
+func TestDecode_RejectsOtherKeys(t *testing.T) {
+	withKey(t, "key-one")
+	value := Encode(time.Now(), "42")
+
+	Configure("key-two")
+	_, err := Decode(value)
+	assert.ErrorIs(t, err, errs.ErrInvalidCursor, "another instance's key must not verify")
+
+	Configure("key-one")
+	_, err = Decode(value)
+	assert.NoError(t, err)
+
+	// ไม่ตั้ง key ใช้ key สุ่ม cursor เดิมจึงใช้ไม่ได้หลัง restart
+	Configure("")
+	_, err = Decode(value)
+	assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+}
+
+func TestDecode_Expiry(t *testing.T) {
+	withKey(t, "test-key")
+	issued := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	clock := issued
+	withClock(t, &clock)
+	value := Encode(issued.Add(-30*24*time.Hour), "42")
+
+	cases := []struct {
+		name  string
+		after time.Duration
+		valid bool
+	}{
+		{"just issued", 0, true},
+		{"within max age", MaxAge - time.Minute, true},
+		{"at max age", MaxAge, true},
+		{"past max age", MaxAge + time.Second, false},
+		{"long expired", 7 * MaxAge, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// อายุนับจากเวลาที่ออก cursor ไม่ใช่เวลาของรายการ
+			clock = issued.Add(tc.after)
+			c, err := Decode(value)
+			if tc.valid {
+				require.NoError(t, err)
+				assert.Equal(t, "42", c.ID)
+				return
+			}
+			assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+		})
+	}
+
+	// cursor ที่ออกก่อนมี iat ถือว่าหมดอายุ
+	clock = issued
+	_, err := Decode(signed(`{"t":"2026-01-01T00:00:00Z","id":"42"}`))
+	assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+}
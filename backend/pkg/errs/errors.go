@@ -4,7 +4,33 @@ import "errors"
 
 // Shared business errors
 var (
-	ErrPostNotFound   = errors.New("post not found")
-	ErrUnauthorized   = errors.New("unauthorized access")
-	ErrInvalidPayload = errors.New("invalid payload")
+	ErrPostNotFound     = errors.New("post not found")
+	ErrUnauthorized     = errors.New("unauthorized access")
+	ErrInvalidPayload   = errors.New("invalid payload")
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+	ErrNotScheduled     = errors.New("post has no pending schedule")
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrCommentsLocked   = errors.New("comments are locked for this post")
+	ErrSearchFailed     = errors.New("search is unavailable")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrPageNotFound     = errors.New("page not found")
+	ErrInvalidContent   = errors.New("invalid post content")
+	ErrSeriesNotFound   = errors.New("series not found")
+	ErrSeriesExists     = errors.New("series slug already exists")
+
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrCollaboratorExists = errors.New("user is already a collaborator")
+	ErrPostProtected      = errors.New("post is protected")
+	ErrInvalidPostKey     = errors.New("invalid post key or password")
+
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrBookmarkNotFound     = errors.New("bookmark not found")
+	ErrBookmarkFolderExists = errors.New("bookmark folder already exists")
+	ErrFolderNotFound       = errors.New("bookmark folder not found")
+	ErrHistoryEntryNotFound = errors.New("post is not in reading history")
+	ErrPostVersionConflict  = errors.New("post was modified by another editor")
 )
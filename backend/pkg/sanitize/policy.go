@@ -0,0 +1,259 @@
+package sanitize
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Options are the configurable parts of the post content policy.
+type Options struct {
+	// IframeHosts lists embed sources as "host" or "host/path-prefix", e.g. "www.youtube.com/embed/".
+	IframeHosts []string
+	// ImageHosts lists the hosts images may be loaded from. Empty allows any http(s) host.
+	ImageHosts []string
+	// LinkRel is forced on every link that leaves the site.
+	LinkRel string
+}
+
+// DefaultIframeHosts are the embeds allowed when no list is configured.
+var DefaultIframeHosts = []string{
+	"www.youtube.com/embed/",
+	"www.youtube-nocookie.com/embed/",
+	"gist.github.com",
+}
+
+// DefaultLinkRel is used when Options.LinkRel is empty.
+const DefaultLinkRel = "noopener noreferrer nofollow"
+
+// attrRule ตรวจและ normalize ค่า attribute คืน false ถ้าค่าไม่ผ่าน policy
+type attrRule func(p *Policy, value string) (string, bool)
+
+// Policy is an allowlist of elements and attributes.
+// Anything not listed is dropped and reported.
+type Policy struct {
+	elements    map[string]map[string]attrRule
+	iframeHosts []string
+	imageHosts  map[string]bool
+	linkRel     string
+}
+
+var (
+	defaultMu     sync.RWMutex
+	contentPolicy = NewContentPolicy(Options{})
+)
+
+// Configure replaces the policy used by Content, e.g. with the hosts from the environment.
+func Configure(opts Options) {
+	policy := NewContentPolicy(opts)
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	contentPolicy = policy
+}
+
+// Content sanitizes post HTML with the configured content policy.
+func Content(input string) Result {
+	defaultMu.RLock()
+	policy := contentPolicy
+	defaultMu.RUnlock()
+	return policy.Sanitize(input)
+}
+
+// Notification strips markup from notification content.
+// Clients render notifications as plain text, so entities are decoded instead of escaped.
+func Notification(input string) string {
+	return PlainText(input)
+}
+
+// NewContentPolicy returns the policy for published post HTML.
+// It covers every element the TipTap renderer emits plus iframes from the allowed embed hosts.
+func NewContentPolicy(opts Options) *Policy {
+	p := newPolicy(opts)
+
+	for _, tag := range []string{"strong", "em", "s", "u", "mark", "sub", "sup", "blockquote",
+		"pre", "hr", "br", "table", "thead", "tbody", "tr", "label", "div"} {
+		p.allow(tag, nil)
+	}
+	p.allow("p", map[string]attrRule{"style": textAlignRule})
+	for _, tag := range []string{"h1", "h2", "h3", "h4", "h5", "h6"} {
+		p.allow(tag, map[string]attrRule{"id": anchorRule, "style": textAlignRule})
+	}
+	p.allow("a", map[string]attrRule{
+		"href":       linkRule,
+		"target":     enumRule("_blank"),
+		"class":      plainRule,
+		"aria-label": plainRule,
+	})
+	p.allow("code", map[string]attrRule{"class": plainRule, "data-language": plainRule})
+	p.allow("ol", map[string]attrRule{"start": numberRule})
+	p.allow("ul", map[string]attrRule{"data-type": enumRule("taskList")})
+	p.allow("li", map[string]attrRule{"data-type": enumRule("taskItem"), "data-checked": enumRule("true", "false")})
+	p.allow("input", map[string]attrRule{"type": enumRule("checkbox"), "disabled": plainRule, "checked": plainRule})
+	p.allow("th", map[string]attrRule{"colspan": numberRule, "rowspan": numberRule})
+	p.allow("td", map[string]attrRule{"colspan": numberRule, "rowspan": numberRule})
+	p.allow("img", map[string]attrRule{
+		"src":        imageRule,
+		"alt":        plainRule,
+		"title":      plainRule,
+		"data-align": enumRule("left", "center", "right"),
+		"loading":    enumRule("lazy", "eager"),
+		"decoding":   enumRule("async", "sync", "auto"),
+		"width":      numberRule,
+		"height":     numberRule,
+	})
+	p.allow("iframe", map[string]attrRule{
+		"src":             iframeRule,
+		"title":           plainRule,
+		"width":           numberRule,
+		"height":          numberRule,
+		"allowfullscreen": plainRule,
+		"frameborder":     numberRule,
+		"loading":         enumRule("lazy", "eager"),
+	})
+	return p
+}
+
+func newPolicy(opts Options) *Policy {
+	p := &Policy{
+		elements:    make(map[string]map[string]attrRule),
+		iframeHosts: normalizeHosts(opts.IframeHosts),
+		imageHosts:  make(map[string]bool),
+		linkRel:     strings.TrimSpace(opts.LinkRel),
+	}
+	if len(p.iframeHosts) == 0 {
+		p.iframeHosts = DefaultIframeHosts
+	}
+	for _, host := range normalizeHosts(opts.ImageHosts) {
+		p.imageHosts[host] = true
+	}
+	if p.linkRel == "" {
+		p.linkRel = DefaultLinkRel
+	}
+	return p
+}
+
+func (p *Policy) allow(tag string, attrs map[string]attrRule) {
+	if attrs == nil {
+		attrs = map[string]attrRule{}
+	}
+	p.elements[tag] = attrs
+}
+
+// normalizeHosts ตัด scheme / ช่องว่างออก และทำเป็นตัวพิมพ์เล็ก เพื่อให้ใส่ทั้ง URL หรือ host ใน env ได้
+func normalizeHosts(hosts []string) []string {
+	var result []string
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+		if host != "" {
+			result = append(result, host)
+		}
+	}
+	return result
+}
+
+var (
+	textAlignPattern = regexp.MustCompile(`^text-align:\s*(left|right|center|justify);?$`)
+	numberPattern    = regexp.MustCompile(`^[0-9]{1,5}$`)
+	anchorPattern    = regexp.MustCompile(`^[\p{L}\p{M}\p{N}_-]+$`)
+)
+
+func plainRule(_ *Policy, value string) (string, bool) {
+	return value, true
+}
+
+func textAlignRule(_ *Policy, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	return value, textAlignPattern.MatchString(value)
+}
+
+func numberRule(_ *Policy, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	return value, numberPattern.MatchString(value)
+}
+
+func anchorRule(_ *Policy, value string) (string, bool) {
+	return value, anchorPattern.MatchString(value)
+}
+
+func enumRule(allowed ...string) attrRule {
+	return func(_ *Policy, value string) (string, bool) {
+		for _, a := range allowed {
+			if strings.EqualFold(value, a) {
+				return a, true
+			}
+		}
+		return value, false
+	}
+}
+
+// parseURL ตัดอักขระควบคุมและช่องว่างที่ browser มองข้าม ก่อนตรวจ scheme (กัน "java\tscript:")
+func parseURL(value string) (*url.URL, string, bool) {
+	cleaned := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value))
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return nil, "", false
+	}
+	return u, cleaned, true
+}
+
+func linkRule(_ *Policy, value string) (string, bool) {
+	u, cleaned, ok := parseURL(value)
+	if !ok {
+		return value, false
+	}
+	switch u.Scheme {
+	case "", "http", "https", "mailto":
+		return cleaned, true
+	}
+	return value, false
+}
+
+func imageRule(p *Policy, value string) (string, bool) {
+	u, cleaned, ok := parseURL(value)
+	if !ok || (u.Scheme != "https" && u.Scheme != "http") {
+		return value, false
+	}
+	if len(p.imageHosts) > 0 && !p.imageHosts[strings.ToLower(u.Host)] {
+		return value, false
+	}
+	return cleaned, true
+}
+
+func iframeRule(p *Policy, value string) (string, bool) {
+	u, cleaned, ok := parseURL(value)
+	if !ok || u.Scheme != "https" || hasDotSegment(u.Path) {
+		return value, false
+	}
+	target := strings.ToLower(u.Host) + u.Path
+	for _, allowed := range p.iframeHosts {
+		if target == allowed || strings.HasPrefix(target, allowed) && (strings.HasSuffix(allowed, "/") || strings.HasPrefix(target[len(allowed):], "/")) {
+			return cleaned, true
+		}
+	}
+	return value, false
+}
+
+// hasDotSegment ตรวจ "." / ".." ใน path (รวม %2e ที่ url.Parse decode แล้ว)
+// browser ตัด segment เหล่านี้ก่อนโหลด "/embed/../x" จึงหลุดออกนอก prefix ที่อนุญาตได้
+func hasDotSegment(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." || segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// isExternalLink บอกว่าลิงก์ออกนอกเว็บหรือไม่ (ลิงก์ภายในเช่น "#heading" ไม่ต้องใส่ rel)
+func isExternalLink(href string) bool {
+	u, err := url.Parse(href)
+	return err == nil && (u.Host != "" || u.Scheme != "")
+}
@@ -0,0 +1,283 @@
+package sanitize
+
+import (
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// Rejection describes markup removed by the policy.
+// Identical rejections are merged and counted.
+type Rejection struct {
+	Element   string `json:"element"`
+	Attribute string `json:"attribute,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Reason    string `json:"reason"`
+	Count     int    `json:"count"`
+}
+
+// Result is the sanitized HTML together with what was removed from it.
+type Result struct {
+	HTML     string      `json:"html"`
+	Rejected []Rejection `json:"rejected,omitempty"`
+}
+
+const (
+	ReasonElementNotAllowed   = "element not allowed"
+	ReasonAttributeNotAllowed = "attribute not allowed"
+	ReasonEventHandler        = "event handler attributes are not allowed"
+	ReasonInvalidValue        = "value not allowed by policy"
+	ReasonMissingSource       = "element requires an allowed src"
+)
+
+// dropContent elements ที่ต้องทิ้งทั้งเนื้อหาข้างใน ไม่ใช่แค่ตัด tag ออก
+var dropContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "select": true, "title": true,
+	"svg": true, "math": true, "frameset": true, "frame": true, "applet": true,
+}
+
+var voidElements = map[string]bool{
+	"br": true, "hr": true, "img": true, "input": true, "wbr": true,
+	"area": true, "base": true, "col": true, "embed": true, "link": true,
+	"meta": true, "source": true, "track": true, "param": true,
+}
+
+// requiredSource elements ที่ไม่มีความหมายถ้า src ไม่ผ่าน policy จึงตัดทิ้งทั้ง element
+var requiredSource = map[string]bool{"img": true, "iframe": true}
+
+const maxReportedValue = 120
+
+type report struct {
+	index map[Rejection]int
+	items []Rejection
+}
+
+func (r *report) add(element, attribute, value, reason string) {
+	if len(value) > maxReportedValue {
+		value = value[:maxReportedValue] + "…"
+	}
+	key := Rejection{Element: element, Attribute: attribute, Value: value, Reason: reason}
+	if i, ok := r.index[key]; ok {
+		r.items[i].Count++
+		return
+	}
+	r.index[key] = len(r.items)
+	key.Count = 1
+	r.items = append(r.items, key)
+}
+
+// Sanitize rewrites input so it only contains elements and attributes allowed by the policy.
+// Unknown elements are unwrapped (their text is kept) except script-like elements, which are removed with their content.
+// Unclosed elements are closed at the end so the output is always balanced.
+func (p *Policy) Sanitize(input string) Result {
+	z := xhtml.NewTokenizer(strings.NewReader(input))
+	rep := &report{index: make(map[Rejection]int)}
+
+	var b strings.Builder
+	var open []string
+	skipTag := ""
+	skipDepth := 0
+
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			if z.Err() != io.EOF {
+				rep.add("", "", "", z.Err().Error())
+			}
+			break
+		}
+
+		switch tt {
+		case xhtml.TextToken:
+			if skipDepth == 0 {
+				b.WriteString(escapeText(string(z.Text())))
+			}
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			tok := z.Token()
+			name := tok.Data
+			if skipDepth > 0 {
+				if name == skipTag && tt == xhtml.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+
+			allowedAttrs, allowed := p.elements[name]
+			if !allowed {
+				rep.add(name, "", "", ReasonElementNotAllowed)
+				if dropContent[name] && tt == xhtml.StartTagToken && !voidElements[name] {
+					skipTag, skipDepth = name, 1
+				}
+				continue
+			}
+
+			attrs, ok := p.filterAttrs(name, tok.Attr, allowedAttrs, rep)
+			if !ok {
+				if dropContent[name] && tt == xhtml.StartTagToken {
+					skipTag, skipDepth = name, 1
+				}
+				continue
+			}
+
+			b.WriteString("<" + name + attrs + ">")
+			if voidElements[name] {
+				continue
+			}
+			if tt == xhtml.SelfClosingTagToken {
+				b.WriteString("</" + name + ">")
+				continue
+			}
+			open = append(open, name)
+
+		case xhtml.EndTagToken:
+			nameBytes, _ := z.TagName()
+			name := string(nameBytes)
+			if skipDepth > 0 {
+				if name == skipTag {
+					skipDepth--
+				}
+				continue
+			}
+
+			// ปิดเฉพาะ tag ที่เปิดไว้จริง ถ้าข้ามชั้นมาให้ปิด tag ที่อยู่ข้างในก่อน
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != name {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+		// comment และ doctype ถูกทิ้งเสมอ
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+
+	return Result{HTML: b.String(), Rejected: rep.items}
+}
+
+// PlainText removes every tag and returns the decoded text.
+// Script-like elements are dropped with their content and <br> becomes a newline.
+// The result is text, not HTML: it must be escaped again before being written into markup.
+func PlainText(input string) string {
+	z := xhtml.NewTokenizer(strings.NewReader(input))
+
+	var b strings.Builder
+	skipTag := ""
+	skipDepth := 0
+
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+
+		switch tt {
+		case xhtml.TextToken:
+			if skipDepth == 0 {
+				b.Write(z.Text())
+			}
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			nameBytes, _ := z.TagName()
+			name := string(nameBytes)
+			if skipDepth > 0 {
+				if name == skipTag && tt == xhtml.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			if dropContent[name] && tt == xhtml.StartTagToken && !voidElements[name] {
+				skipTag, skipDepth = name, 1
+				continue
+			}
+			if name == "br" {
+				b.WriteString("\n")
+			}
+
+		case xhtml.EndTagToken:
+			nameBytes, _ := z.TagName()
+			if skipDepth > 0 && string(nameBytes) == skipTag {
+				skipDepth--
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// filterAttrs คืน attribute ที่ผ่าน policy ในรูป string พร้อมเขียนต่อท้าย tag
+// คืน false เมื่อ element ต้องถูกตัดทิ้ง (เช่น img / iframe ที่ src ไม่อยู่ใน host ที่อนุญาต)
+func (p *Policy) filterAttrs(element string, attrs []xhtml.Attribute, rules map[string]attrRule, rep *report) (string, bool) {
+	kept := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" {
+			rep.add(element, attr.Namespace+":"+key, "", ReasonAttributeNotAllowed)
+			continue
+		}
+		if strings.HasPrefix(key, "on") {
+			rep.add(element, key, attr.Val, ReasonEventHandler)
+			continue
+		}
+		if element == "a" && key == "rel" {
+			continue // rel ถูกกำหนดโดย policy
+		}
+		rule, ok := rules[key]
+		if !ok {
+			rep.add(element, key, "", ReasonAttributeNotAllowed)
+			continue
+		}
+		value, ok := rule(p, attr.Val)
+		if !ok {
+			rep.add(element, key, attr.Val, ReasonInvalidValue)
+			continue
+		}
+		kept[key] = value
+	}
+
+	if requiredSource[element] && kept["src"] == "" {
+		rep.add(element, "", "", ReasonMissingSource)
+		return "", false
+	}
+	if element == "input" && kept["type"] != "checkbox" {
+		rep.add(element, "type", "", ReasonInvalidValue)
+		return "", false
+	}
+	if element == "a" && isExternalLink(kept["href"]) {
+		kept["rel"] = p.linkRel
+	}
+
+	keys := make([]string, 0, len(kept))
+	for key := range kept {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		if kept[key] == "" {
+			b.WriteString(" " + key)
+			continue
+		}
+		b.WriteString(" " + key + `="` + html.EscapeString(kept[key]) + `"`)
+	}
+	return b.String(), true
+}
+
+// escapeText escape เฉพาะอักขระที่จำเป็นในเนื้อหา เพื่อไม่ให้ข้อความธรรมดาเปลี่ยนรูปเกินจำเป็น
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
@@ -0,0 +1,147 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"rag-searchbot-backend/pkg/sanitize"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain text unchanged", "New comment on your post", "New comment on your post"},
+		{"ampersand is not escaped", "Tips & Tricks", "Tips & Tricks"},
+		{"less than in text", "a < b and c > d", "a < b and c > d"},
+		{"entities are decoded", "Tips &amp; Tricks &lt;3", "Tips & Tricks <3"},
+		{"tags are stripped", `<p>Hello <strong>world</strong> <a href="/x">link</a></p>`, "Hello world link"},
+		{"script content dropped", "hi<script>alert(1)</script> there", "hi there"},
+		{"script ends at first close tag", "<script><script>x</script>y</script>z", "yz"},
+		{"event handlers dropped with tag", `<img src=x onerror="alert(1)">caption`, "caption"},
+		{"br becomes newline", "line one<br>line two", "line one\nline two"},
+		{"comments dropped", "a<!-- hidden -->b", "ab"},
+		{"thai text", "มีคนตอบกลับ “บทความ” ของคุณ", "มีคนตอบกลับ “บทความ” ของคุณ"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, sanitize.PlainText(tc.input))
+		})
+	}
+}
+
+func TestNotification_StoresPlainText(t *testing.T) {
+	assert.Equal(t, `user commented on "Tips & Tricks"`, sanitize.Notification(`user commented on "Tips & Tricks"`))
+	assert.Equal(t, "1 < 2", sanitize.Notification("1 < 2"))
+	assert.Equal(t, "click", sanitize.Notification(`<a href="javascript:alert(1)">click</a>`))
+}
+
+func TestContentPolicy_Bypass(t *testing.T) {
+	policy := sanitize.NewContentPolicy(sanitize.Options{ImageHosts: []string{"https://cdn.example.com"}})
+	const rel = ` rel="noopener noreferrer nofollow"`
+
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		// ลิงก์
+		{"javascript scheme", `<a href="javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"mixed case and leading space", `<a href=" JaVaScRiPt:alert(1)">x</a>`, "<a>x</a>"},
+		{"entity encoded scheme", `<a href="&#106;avascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"named entity colon", `<a href="javascript&colon;alert(1)">x</a>`, "<a>x</a>"},
+		{"tab inside scheme", `<a href="java&#x09;script:alert(1)">x</a>`, "<a>x</a>"},
+		{"newline inside scheme", "<a href=\"java\nscript:alert(1)\">x</a>", "<a>x</a>"},
+		{"vbscript scheme", `<a href="vbscript:msgbox(1)">x</a>`, "<a>x</a>"},
+		{"data url link", `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`, "<a>x</a>"},
+		{"rel and target are forced", `<a href="https://example.com" rel="opener" target="_self">x</a>`, `<a href="https://example.com"` + rel + `>x</a>`},
+		{"protocol relative link is external", `<a href="//evil.example">x</a>`, `<a href="//evil.example"` + rel + `>x</a>`},
+		{"internal anchor has no rel", `<a href="#setup">x</a>`, `<a href="#setup">x</a>`},
+		{"quotes in href stay inside the attribute", `<a href="https://example.com/?q=&quot;&gt;&lt;script&gt;">x</a>`, `<a href="https://example.com/?q=&#34;&gt;&lt;script&gt;"` + rel + `>x</a>`},
+
+		// event handler และ attribute อื่น
+		{"event handler", `<p onclick="alert(1)">x</p>`, "<p>x</p>"},
+		{"upper case event handler", `<strong OnMouseOver="alert(1)">x</strong>`, "<strong>x</strong>"},
+		{"namespaced attribute", `<a xlink:href="javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"style beyond text-align", `<p style="text-align: left; background: url(javascript:alert(1))">x</p>`, "<p>x</p>"},
+		{"text-align is kept", `<p style="text-align: center">x</p>`, `<p style="text-align: center">x</p>`},
+		{"heading id breaking out of the attribute", `<h2 id="a&quot; onmouseover=&quot;alert(1)">x</h2>`, "<h2>x</h2>"},
+		{"thai heading id", `<h2 id="ตั้งค่า-ระบบ">x</h2>`, `<h2 id="ตั้งค่า-ระบบ">x</h2>`},
+
+		// รูปภาพ
+		{"data url image", `<img src="data:image/svg+xml,<svg onload=alert(1)>">`, ""},
+		{"image from another host", `<img src="https://evil.example/a.png">`, ""},
+		{"image host is case insensitive", `<img src="https://CDN.example.com/a.png">`, `<img src="https://CDN.example.com/a.png">`},
+		{"image with srcset and onerror", `<img src="https://cdn.example.com/a.png" srcset="javascript:alert(1)" onerror="alert(1)">`, `<img src="https://cdn.example.com/a.png">`},
+
+		// iframe
+		{"allowed embed", `<iframe src="https://www.youtube.com/embed/abc" allowfullscreen></iframe>`, `<iframe allowfullscreen src="https://www.youtube.com/embed/abc"></iframe>`},
+		{"embed over http", `<iframe src="http://www.youtube.com/embed/abc"></iframe>`, ""},
+		{"embed host suffix", `<iframe src="https://www.youtube.com.evil.example/embed/abc"></iframe>`, ""},
+		{"gist host suffix", `<iframe src="https://gist.github.com.evil.example/x"></iframe>`, ""},
+		{"embed path traversal", `<iframe src="https://www.youtube.com/embed/../../redirect?q=https://evil.example"></iframe>`, ""},
+		{"encoded embed path traversal", `<iframe src="https://www.youtube.com/embed/%2e%2e/redirect"></iframe>`, ""},
+		{"srcdoc", `<iframe src="https://www.youtube.com/embed/abc" srcdoc="<script>alert(1)</script>"></iframe>`, `<iframe src="https://www.youtube.com/embed/abc"></iframe>`},
+		{"rejected iframe drops its content", `<iframe src="https://evil.example"><p>fallback</p></iframe>after`, "after"},
+
+		// element ที่ถูกตัด
+		{"script", `a<script>alert(1)</script>b`, "ab"},
+		{"upper case script", `a<SCRIPT>alert(1)</SCRIPT>b`, "ab"},
+		{"split script tag", `<scr<script>ipt>alert(1)</script>`, "ipt&gt;alert(1)"},
+		{"svg with style confusion", `<svg><style></svg><img src=x onerror=alert(1)></style></svg>after`, "after"},
+		{"math", `<math><mi xlink:href="javascript:alert(1)">x</mi></math>after`, "after"},
+		{"style element", `<style>body{display:none}</style>after`, "after"},
+		{"comment hides script", `a<!--<script>alert(1)</script>-->b`, "ab"},
+		{"form controls are unwrapped", `<form action="https://evil.example"><button formaction="javascript:alert(1)">x</button></form>`, "x"},
+		{"meta refresh and base", `<meta http-equiv="refresh" content="0;url=javascript:alert(1)"><base href="https://evil.example/">ok`, "ok"},
+		{"text input", `<input type="text" value="x"><input type="checkbox" checked disabled>`, `<input checked disabled type="checkbox">`},
+
+		// โครงสร้าง
+		{"unclosed elements are closed", `<p>unclosed <strong>bold`, "<p>unclosed <strong>bold</strong></p>"},
+		{"stray end tags are ignored", `</div><p>x</em></p></p>`, "<p>x</p>"},
+		{"misnested end tag closes inner elements", `<p><strong>a</p>b`, "<p><strong>a</strong></p>b"},
+		{"text is escaped", `1 &lt; 2 &amp;&amp; 3 > 2`, "1 &lt; 2 &amp;&amp; 3 &gt; 2"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, policy.Sanitize(tc.input).HTML)
+		})
+	}
+}
+
+func TestContentPolicy_ReportsRejections(t *testing.T) {
+	result := sanitize.NewContentPolicy(sanitize.Options{}).Sanitize(
+		`<p onclick="a()">x</p><p onclick="a()">y</p><a href="javascript:alert(1)">z</a><script>s</script>`)
+
+	assert.Equal(t, []sanitize.Rejection{
+		{Element: "p", Attribute: "onclick", Value: "a()", Reason: sanitize.ReasonEventHandler, Count: 2},
+		{Element: "a", Attribute: "href", Value: "javascript:alert(1)", Reason: sanitize.ReasonInvalidValue, Count: 1},
+		{Element: "script", Reason: sanitize.ReasonElementNotAllowed, Count: 1},
+	}, result.Rejected)
+}
+
+func TestContentPolicy_ConfiguredIframeHosts(t *testing.T) {
+	policy := sanitize.NewContentPolicy(sanitize.Options{IframeHosts: []string{"https://player.example.com/embed"}})
+
+	cases := []struct {
+		src     string
+		allowed bool
+	}{
+		{"https://player.example.com/embed", true},
+		{"https://player.example.com/embed/123", true},
+		{"https://player.example.com/embedded", false},
+		{"https://player.example.com/embed/../admin", false},
+		{"https://www.youtube.com/embed/abc", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.src, func(t *testing.T) {
+			got := policy.Sanitize(`<iframe src="` + tc.src + `"></iframe>`).HTML
+			assert.Equal(t, tc.allowed, got != "", got)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package tiptap
+
+import (
+	"encoding/json"
+)
+
+type ChangeOp string
+
+const (
+	ChangeEqual  ChangeOp = "equal"
+	ChangeInsert ChangeOp = "insert"
+	ChangeDelete ChangeOp = "delete"
+	ChangeUpdate ChangeOp = "update"
+)
+
+// NodeChange describes what happened to one node between two documents.
+// Path is the index chain in the new document, or in the old one for deletes.
+type NodeChange struct {
+	Op       ChangeOp     `json:"op"`
+	Type     string       `json:"type"`
+	Path     []int        `json:"path"`
+	OldText  string       `json:"old_text,omitempty"`
+	NewText  string       `json:"new_text,omitempty"`
+	Children []NodeChange `json:"children,omitempty"`
+}
+
+type DiffResult struct {
+	Changes  []NodeChange `json:"changes"`
+	Inserted int          `json:"inserted"`
+	Deleted  int          `json:"deleted"`
+	Updated  int          `json:"updated"`
+}
+
+// Diff compares two TipTap JSON documents block by block.
+// Blocks are matched with a longest common subsequence; a removed and an added
+// block of the same type at the same spot are reported as one update, and
+// container blocks (lists, blockquotes, tables) are diffed recursively.
+func Diff(oldContent, newContent string) (*DiffResult, error) {
+	oldDoc, err := ParseDocument(oldContent)
+	if err != nil {
+		return nil, err
+	}
+	newDoc, err := ParseDocument(newContent)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiffResult{}
+	result.Changes = diffNodes(oldDoc.Content, newDoc.Content, nil, result)
+	return result, nil
+}
+
+func diffNodes(oldNodes, newNodes []Node, parent []int, result *DiffResult) []NodeChange {
+	oldKeys := nodeKeys(oldNodes)
+	newKeys := nodeKeys(newNodes)
+
+	// ตาราง LCS: lcs[i][j] คือความยาว subsequence ที่ตรงกันของ old[i:] และ new[j:]
+	lcs := make([][]int, len(oldKeys)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newKeys)+1)
+	}
+	for i := len(oldKeys) - 1; i >= 0; i-- {
+		for j := len(newKeys) - 1; j >= 0; j-- {
+			if oldKeys[i] == newKeys[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var changes []NodeChange
+	var deleted, inserted []int
+
+	flush := func() {
+		changes = append(changes, pairChanges(oldNodes, newNodes, deleted, inserted, parent, result)...)
+		deleted, inserted = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(oldKeys) || j < len(newKeys) {
+		switch {
+		case i < len(oldKeys) && j < len(newKeys) && oldKeys[i] == newKeys[j]:
+			flush()
+			changes = append(changes, NodeChange{
+				Op:      ChangeEqual,
+				Type:    newNodes[j].Type,
+				Path:    childPath(parent, j),
+				NewText: newNodes[j].PlainText(),
+			})
+			i++
+			j++
+		case j < len(newKeys) && (i == len(oldKeys) || lcs[i][j+1] >= lcs[i+1][j]):
+			inserted = append(inserted, j)
+			j++
+		default:
+			deleted = append(deleted, i)
+			i++
+		}
+	}
+	flush()
+
+	return changes
+}
+
+// pairChanges turns a run of deletions and insertions between two equal blocks
+// into updates where a removed block lines up with an added block of the same type.
+func pairChanges(oldNodes, newNodes []Node, deleted, inserted []int, parent []int, result *DiffResult) []NodeChange {
+	var changes []NodeChange
+
+	insertAt := func(j int) {
+		result.Inserted++
+		changes = append(changes, NodeChange{
+			Op:      ChangeInsert,
+			Type:    newNodes[j].Type,
+			Path:    childPath(parent, j),
+			NewText: newNodes[j].PlainText(),
+		})
+	}
+
+	next := 0
+	for _, i := range deleted {
+		oldNode := oldNodes[i]
+
+		match := -1
+		for k := next; k < len(inserted); k++ {
+			if newNodes[inserted[k]].Type == oldNode.Type {
+				match = k
+				break
+			}
+		}
+
+		if match < 0 {
+			result.Deleted++
+			changes = append(changes, NodeChange{
+				Op:      ChangeDelete,
+				Type:    oldNode.Type,
+				Path:    childPath(parent, i),
+				OldText: oldNode.PlainText(),
+			})
+			continue
+		}
+
+		for ; next < match; next++ {
+			insertAt(inserted[next])
+		}
+
+		newNode := newNodes[inserted[match]]
+		change := NodeChange{
+			Op:      ChangeUpdate,
+			Type:    newNode.Type,
+			Path:    childPath(parent, inserted[match]),
+			OldText: oldNode.PlainText(),
+			NewText: newNode.PlainText(),
+		}
+		if isContainer(oldNode) && isContainer(newNode) {
+			change.Children = diffNodes(oldNode.Content, newNode.Content, change.Path, result)
+		}
+		result.Updated++
+		changes = append(changes, change)
+		next = match + 1
+	}
+
+	for ; next < len(inserted); next++ {
+		insertAt(inserted[next])
+	}
+
+	return changes
+}
+
+// isContainer reports whether the node holds other blocks rather than inline text.
+func isContainer(n Node) bool {
+	for _, child := range n.Content {
+		if len(child.Content) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeKeys(nodes []Node) []string {
+	keys := make([]string, len(nodes))
+	for i, n := range nodes {
+		raw, _ := json.Marshal(n)
+		keys[i] = string(raw)
+	}
+	return keys
+}
+
+func childPath(parent []int, index int) []int {
+	path := make([]int, len(parent)+1)
+	copy(path, parent)
+	path[len(parent)] = index
+	return path
+}
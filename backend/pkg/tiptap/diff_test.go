@@ -0,0 +1,147 @@
+package tiptap
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func para(text string) Node {
+	return Node{Type: "paragraph", Content: []Node{{Type: "text", Text: text}}}
+}
+
+func heading(text string) Node {
+	return Node{Type: "heading", Attrs: map[string]interface{}{"level": 2}, Content: []Node{{Type: "text", Text: text}}}
+}
+
+func bullets(items ...string) Node {
+	list := Node{Type: "bulletList"}
+	for _, item := range items {
+		list.Content = append(list.Content, Node{Type: "listItem", Content: []Node{para(item)}})
+	}
+	return list
+}
+
+func docString(t *testing.T, blocks ...Node) string {
+	raw, err := json.Marshal(Node{Type: "doc", Content: blocks})
+	require.NoError(t, err)
+	return string(raw)
+}
+
+// summarize แปลง change เป็น "op type path" (ลูกอยู่ถัดจากแม่) เพื่อเทียบง่าย
+func summarize(changes []NodeChange) []string {
+	var out []string
+	for _, c := range changes {
+		out = append(out, fmt.Sprintf("%s %s %v", c.Op, c.Type, c.Path))
+		out = append(out, summarize(c.Children)...)
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name                      string
+		old, new                  []Node
+		want                      []string
+		inserted, deleted, update int
+	}{
+		{
+			name: "identical documents",
+			old:  []Node{heading("Title"), para("a")},
+			new:  []Node{heading("Title"), para("a")},
+			want: []string{"equal heading [0]", "equal paragraph [1]"},
+		},
+		{
+			name:     "block appended",
+			old:      []Node{para("a")},
+			new:      []Node{para("a"), para("b")},
+			want:     []string{"equal paragraph [0]", "insert paragraph [1]"},
+			inserted: 1,
+		},
+		{
+			name:    "block removed keeps old path",
+			old:     []Node{para("a"), para("b"), para("c")},
+			new:     []Node{para("a"), para("c")},
+			want:    []string{"equal paragraph [0]", "delete paragraph [1]", "equal paragraph [1]"},
+			deleted: 1,
+		},
+		{
+			name:   "edited block is an update",
+			old:    []Node{para("a"), para("before"), para("c")},
+			new:    []Node{para("a"), para("after"), para("c")},
+			want:   []string{"equal paragraph [0]", "update paragraph [1]", "equal paragraph [2]"},
+			update: 1,
+		},
+		{
+			name:     "different type is delete plus insert",
+			old:      []Node{para("a"), para("b")},
+			new:      []Node{para("a"), heading("b")},
+			want:     []string{"equal paragraph [0]", "delete paragraph [1]", "insert heading [1]"},
+			inserted: 1, deleted: 1,
+		},
+		{
+			name:     "insert before an update of the same type",
+			old:      []Node{heading("h"), para("old")},
+			new:      []Node{heading("h"), heading("new h"), para("new")},
+			want:     []string{"equal heading [0]", "insert heading [1]", "update paragraph [2]"},
+			inserted: 1, update: 1,
+		},
+		{
+			name:     "moved block follows the longest common subsequence",
+			old:      []Node{para("a"), para("b"), para("c")},
+			new:      []Node{para("c"), para("a"), para("b")},
+			want:     []string{"insert paragraph [0]", "equal paragraph [1]", "equal paragraph [2]", "delete paragraph [2]"},
+			inserted: 1, deleted: 1,
+		},
+		{
+			name: "container diffed recursively",
+			old:  []Node{bullets("one", "two", "three")},
+			new:  []Node{bullets("one", "2", "three", "four")},
+			want: []string{
+				"update bulletList [0]",
+				"equal listItem [0 0]",
+				"update listItem [0 1]",
+				"update paragraph [0 1 0]",
+				"equal listItem [0 2]",
+				"insert listItem [0 3]",
+			},
+			inserted: 1, update: 3,
+		},
+		{
+			name:     "empty old document",
+			old:      nil,
+			new:      []Node{para("a"), para("b")},
+			want:     []string{"insert paragraph [0]", "insert paragraph [1]"},
+			inserted: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Diff(docString(t, tc.old...), docString(t, tc.new...))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, summarize(result.Changes))
+			assert.Equal(t, tc.inserted, result.Inserted, "inserted")
+			assert.Equal(t, tc.deleted, result.Deleted, "deleted")
+			assert.Equal(t, tc.update, result.Updated, "updated")
+		})
+	}
+}
+
+func TestDiff_UpdateCarriesText(t *testing.T) {
+	result, err := Diff(docString(t, para("before")), docString(t, para("after")))
+	require.NoError(t, err)
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, "before", result.Changes[0].OldText)
+	assert.Equal(t, "after", result.Changes[0].NewText)
+}
+
+func TestDiff_InvalidDocument(t *testing.T) {
+	_, err := Diff("{", docString(t))
+	assert.Error(t, err)
+	_, err = Diff(docString(t), "not json")
+	assert.Error(t, err)
+}
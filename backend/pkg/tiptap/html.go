@@ -0,0 +1,311 @@
+package tiptap
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// htmlMarkTags maps TipTap marks to the inline element the editor renders for them.
+var htmlMarkTags = map[string]string{
+	"bold":        "strong",
+	"italic":      "em",
+	"strike":      "s",
+	"code":        "code",
+	"underline":   "u",
+	"highlight":   "mark",
+	"subscript":   "sub",
+	"superscript": "sup",
+}
+
+// NodeRenderFunc renders one node into the writer.
+// Renderers for container nodes call w.RenderChildren to render nested content.
+type NodeRenderFunc func(w *HTMLWriter, node Node)
+
+// HTMLRenderer produces canonical HTML from TipTap JSON.
+// Node types are looked up in a registry, so custom editor extensions can plug in their own renderer;
+// unknown node types render their children only.
+type HTMLRenderer struct {
+	mu        sync.RWMutex
+	renderers map[string]NodeRenderFunc
+}
+
+// HTMLWriter is the per-render state handed to node renderers.
+type HTMLWriter struct {
+	renderer *HTMLRenderer
+	b        strings.Builder
+	anchors  map[string]int
+}
+
+var defaultHTMLRenderer = NewHTMLRenderer()
+
+// NewHTMLRenderer returns a renderer with the built-in nodes of the editor registered.
+func NewHTMLRenderer() *HTMLRenderer {
+	r := &HTMLRenderer{renderers: make(map[string]NodeRenderFunc)}
+	r.Register("text", renderTextNode)
+	r.Register("paragraph", renderParagraph)
+	r.Register("heading", renderHeading)
+	r.Register("blockquote", containerRenderer("blockquote", nil))
+	r.Register("bulletList", containerRenderer("ul", nil))
+	r.Register("orderedList", renderOrderedList)
+	r.Register("listItem", containerRenderer("li", nil))
+	r.Register("taskList", containerRenderer("ul", map[string]string{"data-type": "taskList"}))
+	r.Register("taskItem", renderTaskItem)
+	r.Register("codeBlock", renderCodeBlock)
+	r.Register("horizontalRule", func(w *HTMLWriter, _ Node) { w.WriteRaw("<hr>") })
+	r.Register("hardBreak", func(w *HTMLWriter, _ Node) { w.WriteRaw("<br>") })
+	r.Register("image", renderImage)
+	r.Register("table", renderTable)
+	r.Register("tableRow", containerRenderer("tr", nil))
+	r.Register("tableHeader", tableCellRenderer("th"))
+	r.Register("tableCell", tableCellRenderer("td"))
+	return r
+}
+
+// Register sets the renderer of a node type, replacing the built-in one if any.
+func (r *HTMLRenderer) Register(nodeType string, fn NodeRenderFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.renderers[nodeType] = fn
+}
+
+func (r *HTMLRenderer) lookup(nodeType string) (NodeRenderFunc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.renderers[nodeType]
+	return fn, ok
+}
+
+// Render renders the content of a doc node.
+func (r *HTMLRenderer) Render(doc Node) string {
+	w := &HTMLWriter{renderer: r, anchors: make(map[string]int)}
+	w.RenderChildren(doc)
+	return w.b.String()
+}
+
+// RegisterNodeRenderer registers a renderer on the default renderer used by RenderHTML.
+func RegisterNodeRenderer(nodeType string, fn NodeRenderFunc) {
+	defaultHTMLRenderer.Register(nodeType, fn)
+}
+
+// RenderHTML renders a TipTap document with the default renderer.
+func RenderHTML(doc Node) string {
+	return defaultHTMLRenderer.Render(doc)
+}
+
+// RenderNode renders a node through the registry.
+func (w *HTMLWriter) RenderNode(node Node) {
+	if fn, ok := w.renderer.lookup(node.Type); ok {
+		fn(w, node)
+		return
+	}
+	w.RenderChildren(node)
+}
+
+// RenderChildren renders the content of a node.
+func (w *HTMLWriter) RenderChildren(node Node) {
+	for _, child := range node.Content {
+		w.RenderNode(child)
+	}
+}
+
+// WriteRaw writes markup as is.
+func (w *HTMLWriter) WriteRaw(s string) {
+	w.b.WriteString(s)
+}
+
+// WriteText writes escaped text.
+func (w *HTMLWriter) WriteText(s string) {
+	w.b.WriteString(html.EscapeString(s))
+}
+
+// OpenTag writes a start tag; attributes with empty values are skipped.
+func (w *HTMLWriter) OpenTag(tag string, attrs map[string]string) {
+	w.b.WriteString("<" + tag + htmlAttrs(attrs) + ">")
+}
+
+// CloseTag writes an end tag.
+func (w *HTMLWriter) CloseTag(tag string) {
+	w.b.WriteString("</" + tag + ">")
+}
+
+// Element writes tag around the rendered children of node.
+func (w *HTMLWriter) Element(tag string, attrs map[string]string, node Node) {
+	w.OpenTag(tag, attrs)
+	w.RenderChildren(node)
+	w.CloseTag(tag)
+}
+
+// Anchor returns a unique id for a heading in this document, e.g. "setup", "setup-1".
+func (w *HTMLWriter) Anchor(text string) string {
+	base := anchorSlug(text)
+	id := base
+	// ข้าม suffix ที่ถูกใช้แล้ว เช่น heading "Setup 1" ที่มาก่อน "Setup" สองอัน
+	for n := w.anchors[base]; w.anchors[id] > 0; n++ {
+		id = base + "-" + strconv.Itoa(n)
+		w.anchors[base] = n + 1
+	}
+	w.anchors[id]++
+	return id
+}
+
+// anchorSlug ทำ id จากข้อความ heading เก็บตัวอักษร (รวมสระ/วรรณยุกต์ไทย) และตัวเลข แทนช่องว่างด้วย "-"
+func anchorSlug(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return "section"
+	}
+	return b.String()
+}
+
+// htmlAttrs เขียน attribute เรียงตามชื่อ ข้ามค่าว่าง
+func htmlAttrs(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for key, value := range attrs {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, ` %s="%s"`, key, html.EscapeString(attrs[key]))
+	}
+	return b.String()
+}
+
+func textAlignStyle(node Node) string {
+	if align := node.AttrString("textAlign"); align != "" && align != "left" {
+		return "text-align: " + align
+	}
+	return ""
+}
+
+func containerRenderer(tag string, attrs map[string]string) NodeRenderFunc {
+	return func(w *HTMLWriter, node Node) {
+		w.Element(tag, attrs, node)
+	}
+}
+
+func renderParagraph(w *HTMLWriter, node Node) {
+	w.Element("p", map[string]string{"style": textAlignStyle(node)}, node)
+}
+
+func renderHeading(w *HTMLWriter, node Node) {
+	level := node.AttrInt("level", 1)
+	if level < 1 || level > 6 {
+		level = 1
+	}
+	tag := "h" + strconv.Itoa(level)
+	id := w.Anchor(node.PlainText())
+
+	w.OpenTag(tag, map[string]string{"id": id, "style": textAlignStyle(node)})
+	w.RenderChildren(node)
+	w.OpenTag("a", map[string]string{"class": "heading-anchor", "href": "#" + id, "aria-label": "Link to this section"})
+	w.CloseTag("a")
+	w.CloseTag(tag)
+}
+
+func renderOrderedList(w *HTMLWriter, node Node) {
+	attrs := map[string]string{}
+	if start := node.AttrInt("start", 1); start != 1 {
+		attrs["start"] = strconv.Itoa(start)
+	}
+	w.Element("ol", attrs, node)
+}
+
+func renderTaskItem(w *HTMLWriter, node Node) {
+	checked, _ := node.Attrs["checked"].(bool)
+	w.OpenTag("li", map[string]string{"data-type": "taskItem", "data-checked": strconv.FormatBool(checked)})
+	w.WriteRaw(`<label><input type="checkbox" disabled`)
+	if checked {
+		w.WriteRaw(" checked")
+	}
+	w.WriteRaw("></label>")
+	w.Element("div", nil, node)
+	w.CloseTag("li")
+}
+
+func renderCodeBlock(w *HTMLWriter, node Node) {
+	language := node.AttrString("language")
+	class := ""
+	if language != "" {
+		class = "language-" + language
+	}
+	w.OpenTag("pre", nil)
+	w.OpenTag("code", map[string]string{"class": class, "data-language": language})
+	w.WriteText(node.PlainText())
+	w.CloseTag("code")
+	w.CloseTag("pre")
+}
+
+func renderImage(w *HTMLWriter, node Node) {
+	w.OpenTag("img", map[string]string{
+		"src":        node.AttrString("src"),
+		"alt":        node.AttrString("alt"),
+		"title":      node.AttrString("title"),
+		"data-align": node.AttrString("align"),
+		"loading":    "lazy",
+		"decoding":   "async",
+	})
+}
+
+func renderTable(w *HTMLWriter, node Node) {
+	w.OpenTag("table", nil)
+	w.Element("tbody", nil, node)
+	w.CloseTag("table")
+}
+
+func tableCellRenderer(tag string) NodeRenderFunc {
+	return func(w *HTMLWriter, node Node) {
+		attrs := map[string]string{}
+		if colspan := node.AttrInt("colspan", 1); colspan > 1 {
+			attrs["colspan"] = strconv.Itoa(colspan)
+		}
+		if rowspan := node.AttrInt("rowspan", 1); rowspan > 1 {
+			attrs["rowspan"] = strconv.Itoa(rowspan)
+		}
+		w.Element(tag, attrs, node)
+	}
+}
+
+func renderTextNode(w *HTMLWriter, node Node) {
+	close := ""
+	for _, mark := range node.Marks {
+		if mark.Type == "link" {
+			href, _ := mark.Attrs["href"].(string)
+			w.OpenTag("a", map[string]string{
+				"href":   href,
+				"target": "_blank",
+				"rel":    "noopener noreferrer nofollow",
+			})
+			close = "</a>" + close
+			continue
+		}
+		if tag, ok := htmlMarkTags[mark.Type]; ok {
+			w.OpenTag(tag, nil)
+			close = "</" + tag + ">" + close
+		}
+	}
+
+	w.WriteText(node.Text)
+	w.WriteRaw(close)
+}
@@ -0,0 +1,152 @@
+package tiptap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func headingNode(level int, text string) Node {
+	return Node{Type: "heading", Attrs: map[string]interface{}{"level": float64(level)}, Content: []Node{txt(text)}}
+}
+
+func anchorLink(id string) string {
+	return `<a aria-label="Link to this section" class="heading-anchor" href="#` + id + `"></a>`
+}
+
+func TestRenderHTML(t *testing.T) {
+	link := Mark{Type: "link", Attrs: map[string]interface{}{"href": "https://example.com/?a=1&b=2"}}
+
+	cases := []struct {
+		name string
+		doc  []Node
+		want string
+	}{
+		{
+			"paragraph with marks",
+			[]Node{paragraph(txt("a "), txt("b", Mark{Type: "bold"}, Mark{Type: "italic"}), txt(" <c>", Mark{Type: "code"}))},
+			"<p>a <strong><em>b</em></strong><code> &lt;c&gt;</code></p>",
+		},
+		{
+			"link attributes are escaped",
+			[]Node{paragraph(txt("x", link, Mark{Type: "underline"}))},
+			`<p><a href="https://example.com/?a=1&amp;b=2" rel="noopener noreferrer nofollow" target="_blank"><u>x</u></a></p>`,
+		},
+		{
+			"unknown marks are dropped",
+			[]Node{paragraph(txt("x", Mark{Type: "textStyle"}))},
+			"<p>x</p>",
+		},
+		{
+			"aligned paragraph",
+			[]Node{{Type: "paragraph", Attrs: map[string]interface{}{"textAlign": "center"}, Content: []Node{txt("c")}}},
+			`<p style="text-align: center">c</p>`,
+		},
+		{
+			"heading anchors",
+			[]Node{headingNode(2, "Getting Started"), headingNode(9, "ตั้งค่า ระบบ")},
+			`<h2 id="getting-started">Getting Started` + anchorLink("getting-started") + `</h2>` +
+				`<h1 id="ตั้งค่า-ระบบ">ตั้งค่า ระบบ` + anchorLink("ตั้งค่า-ระบบ") + `</h1>`,
+		},
+		{
+			"code block with language",
+			[]Node{{Type: "codeBlock", Attrs: map[string]interface{}{"language": "go"}, Content: []Node{txt("if a < b {}")}}},
+			`<pre><code class="language-go" data-language="go">if a &lt; b {}</code></pre>`,
+		},
+		{
+			"code block without language",
+			[]Node{{Type: "codeBlock", Content: []Node{txt("x")}}},
+			`<pre><code>x</code></pre>`,
+		},
+		{
+			"lazy image",
+			[]Node{{Type: "image", Attrs: map[string]interface{}{"src": "https://cdn.example.com/a.png", "alt": `a "b"`}}},
+			`<img alt="a &#34;b&#34;" decoding="async" loading="lazy" src="https://cdn.example.com/a.png">`,
+		},
+		{
+			"lists",
+			[]Node{
+				{Type: "orderedList", Attrs: map[string]interface{}{"start": float64(3)}, Content: []Node{item(paragraph(txt("c")))}},
+				listOf("bulletList", item(paragraph(txt("a")))),
+			},
+			`<ol start="3"><li><p>c</p></li></ol><ul><li><p>a</p></li></ul>`,
+		},
+		{
+			"task list",
+			[]Node{listOf("taskList", Node{Type: "taskItem", Attrs: map[string]interface{}{"checked": true}, Content: []Node{paragraph(txt("done"))}})},
+			`<ul data-type="taskList"><li data-checked="true" data-type="taskItem"><label><input type="checkbox" disabled checked></label><div><p>done</p></div></li></ul>`,
+		},
+		{
+			"table with spans",
+			[]Node{{Type: "table", Content: []Node{{Type: "tableRow", Content: []Node{
+				{Type: "tableHeader", Attrs: map[string]interface{}{"colspan": float64(2), "rowspan": float64(1)}, Content: []Node{paragraph(txt("h"))}},
+			}}}}},
+			`<table><tbody><tr><th colspan="2"><p>h</p></th></tr></tbody></table>`,
+		},
+		{
+			"unknown nodes render their children",
+			[]Node{{Type: "details", Content: []Node{paragraph(txt("inside"))}}},
+			"<p>inside</p>",
+		},
+		{
+			"breaks and rules",
+			[]Node{paragraph(txt("a"), Node{Type: "hardBreak"}, txt("b")), {Type: "horizontalRule"}},
+			"<p>a<br>b</p><hr>",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, RenderHTML(Node{Type: "doc", Content: tc.doc}))
+		})
+	}
+}
+
+func TestRenderHTML_UniqueAnchors(t *testing.T) {
+	cases := []struct {
+		name     string
+		headings []string
+		want     []string
+	}{
+		{"repeated text", []string{"Setup", "Setup", "Setup"}, []string{"setup", "setup-1", "setup-2"}},
+		{"suffix already taken", []string{"Setup 1", "Setup", "Setup"}, []string{"setup-1", "setup", "setup-2"}},
+		{"no letters", []string{"!!!", "???"}, []string{"section", "section-1"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc []Node
+			want := ""
+			for i, text := range tc.headings {
+				doc = append(doc, headingNode(2, text))
+				want += `<h2 id="` + tc.want[i] + `">` + escapeHTMLText(text) + anchorLink(tc.want[i]) + "</h2>"
+			}
+			assert.Equal(t, want, RenderHTML(Node{Type: "doc", Content: doc}))
+		})
+	}
+}
+
+func escapeHTMLText(s string) string {
+	w := &HTMLWriter{}
+	w.WriteText(s)
+	return w.b.String()
+}
+
+func TestHTMLRenderer_Register(t *testing.T) {
+	renderer := NewHTMLRenderer()
+	renderer.Register("mention", func(w *HTMLWriter, node Node) {
+		w.OpenTag("span", map[string]string{"class": "mention", "data-id": node.AttrString("id")})
+		w.WriteText("@" + node.AttrString("label"))
+		w.CloseTag("span")
+	})
+	renderer.Register("horizontalRule", func(w *HTMLWriter, _ Node) { w.WriteRaw("<hr class=\"divider\">") })
+
+	doc := Node{Type: "doc", Content: []Node{
+		paragraph(Node{Type: "mention", Attrs: map[string]interface{}{"id": "42", "label": "<bob>"}}),
+		{Type: "horizontalRule"},
+	}}
+	assert.Equal(t, `<p><span class="mention" data-id="42">@&lt;bob&gt;</span></p><hr class="divider">`, renderer.Render(doc))
+
+	// renderer แยกกันไม่กระทบ default ที่ใช้ตอน publish
+	assert.Equal(t, "<p></p><hr>", RenderHTML(doc))
+}
@@ -0,0 +1,679 @@
+package tiptap
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter is the metadata block at the top of an imported Markdown file.
+type FrontMatter struct {
+	Title       string
+	Description string
+	Slug        string
+	Tags        []string
+}
+
+// MarkdownDocument is the result of converting Markdown into a TipTap document.
+type MarkdownDocument struct {
+	FrontMatter FrontMatter
+	Doc         Node
+}
+
+var (
+	atxHeadingRe    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*))?$`)
+	thematicBreakRe = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fenceRe         = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+	setextRe        = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	blockquoteRe    = regexp.MustCompile(`^ {0,3}> ?`)
+	bulletRe        = regexp.MustCompile(`^( {0,3})([-+*])([ \t]+|$)`)
+	orderedRe       = regexp.MustCompile(`^( {0,3})(\d{1,9})([.)])([ \t]+|$)`)
+	taskRe          = regexp.MustCompile(`^\[([ xX])\](?:[ \t]+|$)`)
+	tableDelimRe    = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	refDefRe        = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:[ \t]*<?([^\s>]+)>?(?:[ \t]+(?:"([^"]*)"|'([^']*)'|\(([^)]*)\)))?[ \t]*$`)
+)
+
+type linkReference struct {
+	href  string
+	title string
+}
+
+type markdownParser struct {
+	refs map[string]linkReference
+}
+
+// ParseMarkdown converts CommonMark + GFM (tables, task lists, strikethrough, autolinks)
+// into a TipTap document using the node names of the editor's extensions.
+// A leading YAML front-matter block (title, description, slug, tags) is returned separately.
+func ParseMarkdown(source string) (*MarkdownDocument, error) {
+	source = strings.TrimPrefix(source, "\ufeff")
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+
+	meta, body, err := splitFrontMatter(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &markdownParser{refs: make(map[string]linkReference)}
+	lines := p.collectReferences(splitLines(body))
+
+	content := p.parseBlocks(lines)
+	if len(content) == 0 {
+		content = []Node{{Type: "paragraph"}}
+	}
+
+	return &MarkdownDocument{
+		FrontMatter: meta,
+		Doc:         Node{Type: "doc", Content: content},
+	}, nil
+}
+
+// splitFrontMatter separates a "---" delimited YAML block from the Markdown body.
+func splitFrontMatter(source string) (FrontMatter, string, error) {
+	var meta FrontMatter
+	if !strings.HasPrefix(source, "---\n") {
+		return meta, source, nil
+	}
+
+	rest := source[len("---\n"):]
+	end := -1
+	bodyStart := 0
+	for offset := 0; offset < len(rest); {
+		lineEnd := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		next := len(rest)
+		if lineEnd >= 0 {
+			line = rest[offset : offset+lineEnd]
+			next = offset + lineEnd + 1
+		}
+		if trimmed := strings.TrimRight(line, " \t"); trimmed == "---" || trimmed == "..." {
+			end = offset
+			bodyStart = next
+			break
+		}
+		offset = next
+	}
+	if end < 0 {
+		// ไม่มีบรรทัดปิด ถือว่าเป็นเนื้อหาปกติ
+		return meta, source, nil
+	}
+
+	var raw struct {
+		Title       string      `yaml:"title"`
+		Description string      `yaml:"description"`
+		Slug        string      `yaml:"slug"`
+		Tags        interface{} `yaml:"tags"`
+	}
+	if err := yaml.Unmarshal([]byte(rest[:end]), &raw); err != nil {
+		return meta, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	meta.Title = strings.TrimSpace(raw.Title)
+	meta.Description = strings.TrimSpace(raw.Description)
+	meta.Slug = strings.TrimSpace(raw.Slug)
+	switch tags := raw.Tags.(type) {
+	case string:
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				meta.Tags = append(meta.Tags, tag)
+			}
+		}
+	case []interface{}:
+		for _, tag := range tags {
+			if s := strings.TrimSpace(fmt.Sprint(tag)); s != "" {
+				meta.Tags = append(meta.Tags, s)
+			}
+		}
+	}
+
+	return meta, rest[bodyStart:], nil
+}
+
+// splitLines splits the body into lines and expands leading tabs to 4 columns.
+func splitLines(body string) []string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lines[i] = expandLeadingTabs(line)
+	}
+	return lines
+}
+
+func expandLeadingTabs(line string) string {
+	var b strings.Builder
+	col := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			b.WriteByte(' ')
+			col++
+		case '\t':
+			n := 4 - col%4
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		default:
+			b.WriteString(line[i:])
+			return b.String()
+		}
+	}
+	return b.String()
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentWidth(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// stripIndent removes up to n leading spaces.
+func stripIndent(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return line[i:]
+}
+
+// normalizeLabel makes reference labels case-insensitive and whitespace-insensitive.
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// collectReferences removes link reference definitions ([label]: url "title") outside code fences.
+// A definition cannot interrupt a paragraph.
+func (p *markdownParser) collectReferences(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	inFence := false
+	fence := ""
+	prevBlank := true
+
+	for _, line := range lines {
+		if m := fenceRe.FindStringSubmatch(line); m != nil {
+			if !inFence {
+				inFence, fence = true, m[2]
+			} else if strings.HasPrefix(strings.TrimSpace(line), fence) && strings.Trim(strings.TrimSpace(line), fence[:1]) == "" {
+				inFence = false
+			}
+		}
+
+		if !inFence && prevBlank {
+			if m := refDefRe.FindStringSubmatch(line); m != nil {
+				label := normalizeLabel(m[1])
+				if _, exists := p.refs[label]; !exists {
+					p.refs[label] = linkReference{href: unescapeBackslashes(m[2]), title: m[3] + m[4] + m[5]}
+				}
+				continue
+			}
+		}
+
+		result = append(result, line)
+		prevBlank = isBlank(line) || (!inFence && refDefRe.MatchString(line))
+	}
+	return result
+}
+
+type listMarker struct {
+	ordered       bool
+	marker        byte // '-', '+', '*' หรือ '.', ')' สำหรับ ordered list
+	start         int
+	contentIndent int
+	rest          string
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	if m := bulletRe.FindStringSubmatch(line); m != nil {
+		return newListMarker(line, false, m[2][0], 0, len(m[1])+1, len(m[0])), true
+	}
+	if m := orderedRe.FindStringSubmatch(line); m != nil {
+		start, _ := strconv.Atoi(m[2])
+		return newListMarker(line, true, m[3][0], start, len(m[1])+len(m[2])+1, len(m[0])), true
+	}
+	return listMarker{}, false
+}
+
+func newListMarker(line string, ordered bool, marker byte, start, markerEnd, matchEnd int) listMarker {
+	spaces := matchEnd - markerEnd
+	rest := line[matchEnd:]
+	if isBlank(rest) {
+		// รายการว่าง: เนื้อหาบรรทัดถัดไปเยื้องเท่ากับ marker + 1
+		spaces = 1
+		rest = ""
+	} else if spaces > 4 {
+		// เว้นเกิน 4 ช่อง = indented code ภายใน item
+		rest = line[markerEnd+1:]
+		spaces = 1
+	}
+	return listMarker{ordered: ordered, marker: marker, start: start, contentIndent: markerEnd + spaces, rest: rest}
+}
+
+func sameList(a, b listMarker) bool {
+	return a.ordered == b.ordered && a.marker == b.marker
+}
+
+func isFenceStart(line string) bool {
+	m := fenceRe.FindStringSubmatch(line)
+	return m != nil && !(m[2][0] == '`' && strings.Contains(m[3], "`"))
+}
+
+// startsBlock reports whether the line begins a block that interrupts a paragraph.
+func startsBlock(line string) bool {
+	if atxHeadingRe.MatchString(line) || thematicBreakRe.MatchString(line) || isFenceStart(line) || blockquoteRe.MatchString(line) {
+		return true
+	}
+	if m, ok := parseListMarker(line); ok && m.rest != "" && (!m.ordered || m.start == 1) {
+		return true
+	}
+	return false
+}
+
+func (p *markdownParser) parseBlocks(lines []string) []Node {
+	var blocks []Node
+	var para []string
+
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, p.paragraph(para)...)
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case isBlank(line):
+			flush()
+			i++
+
+		case len(para) == 0 && indentWidth(line) >= 4:
+			node, next := indentedCode(lines, i)
+			blocks = append(blocks, node)
+			i = next
+
+		case isFenceStart(line):
+			flush()
+			node, next := fencedCode(lines, i)
+			blocks = append(blocks, node)
+			i = next
+
+		case atxHeadingRe.MatchString(line):
+			flush()
+			blocks = append(blocks, p.atxHeading(line))
+			i++
+
+		case len(para) > 0 && setextRe.MatchString(line):
+			level := 2
+			if strings.TrimSpace(line)[0] == '=' {
+				level = 1
+			}
+			blocks = append(blocks, p.heading(level, strings.Join(para, "\n")))
+			para = nil
+			i++
+
+		case thematicBreakRe.MatchString(line):
+			flush()
+			blocks = append(blocks, Node{Type: "horizontalRule"})
+			i++
+
+		case blockquoteRe.MatchString(line):
+			flush()
+			node, next := p.blockquote(lines, i)
+			blocks = append(blocks, node)
+			i = next
+
+		case p.isListStart(line, len(para) > 0):
+			flush()
+			node, next := p.list(lines, i)
+			blocks = append(blocks, node)
+			i = next
+
+		case len(para) == 0 && isTableStart(lines, i):
+			node, next := p.table(lines, i)
+			blocks = append(blocks, node)
+			i = next
+
+		default:
+			para = append(para, line)
+			i++
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+func (p *markdownParser) isListStart(line string, inParagraph bool) bool {
+	m, ok := parseListMarker(line)
+	if !ok {
+		return false
+	}
+	// ระหว่าง paragraph จะขึ้น list ใหม่ได้เฉพาะ item ที่มีเนื้อหา และ ordered list ต้องเริ่มที่ 1
+	return !inParagraph || (m.rest != "" && (!m.ordered || m.start == 1))
+}
+
+func (p *markdownParser) heading(level int, text string) Node {
+	return Node{
+		Type:    "heading",
+		Attrs:   map[string]interface{}{"level": level},
+		Content: p.inline(strings.TrimSpace(text)),
+	}
+}
+
+func (p *markdownParser) atxHeading(line string) Node {
+	m := atxHeadingRe.FindStringSubmatch(line)
+	text := strings.TrimRight(m[2], " \t")
+
+	// ตัด closing sequence เช่น "## Title ##"
+	if trimmed := strings.TrimRight(text, "#"); trimmed != text {
+		if trimmed == "" {
+			text = ""
+		} else if strings.HasSuffix(trimmed, " ") || strings.HasSuffix(trimmed, "\t") {
+			text = strings.TrimRight(trimmed, " \t")
+		}
+	}
+	return p.heading(len(m[1]), text)
+}
+
+func codeBlock(language string, lines []string) Node {
+	node := Node{Type: "codeBlock", Attrs: map[string]interface{}{"language": nil}}
+	if language != "" {
+		node.Attrs["language"] = language
+	}
+	if code := strings.Join(lines, "\n"); code != "" {
+		node.Content = []Node{{Type: "text", Text: code}}
+	}
+	return node
+}
+
+func indentedCode(lines []string, i int) (Node, int) {
+	var code []string
+	for ; i < len(lines) && (isBlank(lines[i]) || indentWidth(lines[i]) >= 4); i++ {
+		code = append(code, stripIndent(lines[i], 4))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+	return codeBlock("", code), i
+}
+
+func fencedCode(lines []string, i int) (Node, int) {
+	m := fenceRe.FindStringSubmatch(lines[i])
+	indent, fence := len(m[1]), m[2]
+
+	language := ""
+	if fields := strings.Fields(m[3]); len(fields) > 0 {
+		language = unescapeBackslashes(fields[0])
+	}
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimRight(lines[i], " \t")
+		if indentWidth(trimmed) <= 3 {
+			closing := strings.TrimLeft(trimmed, " ")
+			if len(closing) >= len(fence) && strings.Trim(closing, fence[:1]) == "" {
+				return codeBlock(language, code), i + 1
+			}
+		}
+		code = append(code, stripIndent(lines[i], indent))
+	}
+	// ไม่มี fence ปิด: code block ยาวถึงท้ายเอกสาร
+	return codeBlock(language, code), i
+}
+
+func (p *markdownParser) blockquote(lines []string, i int) (Node, int) {
+	var inner []string
+	for i < len(lines) {
+		line := lines[i]
+		if loc := blockquoteRe.FindStringIndex(line); loc != nil {
+			inner = append(inner, line[loc[1]:])
+			i++
+			continue
+		}
+		// lazy continuation ของ paragraph ใน quote
+		if !isBlank(line) && len(inner) > 0 && !isBlank(inner[len(inner)-1]) && !startsBlock(line) {
+			inner = append(inner, line)
+			i++
+			continue
+		}
+		break
+	}
+	return Node{Type: "blockquote", Content: p.parseBlocks(inner)}, i
+}
+
+func (p *markdownParser) list(lines []string, i int) (Node, int) {
+	first, _ := parseListMarker(lines[i])
+
+	var items [][]string
+	for i < len(lines) {
+		m, ok := parseListMarker(lines[i])
+		if !ok || !sameList(first, m) {
+			break
+		}
+
+		item := []string{m.rest}
+		for i++; i < len(lines); {
+			line := lines[i]
+			if isBlank(line) {
+				// บรรทัดว่างยังอยู่ใน item ถ้าบรรทัดถัดไปเยื้องถึงเนื้อหาของ item
+				j := i
+				for j < len(lines) && isBlank(lines[j]) {
+					j++
+				}
+				if j < len(lines) && indentWidth(lines[j]) >= m.contentIndent {
+					for ; i < j; i++ {
+						item = append(item, "")
+					}
+					continue
+				}
+				break
+			}
+			if indentWidth(line) >= m.contentIndent {
+				item = append(item, stripIndent(line, m.contentIndent))
+				i++
+				continue
+			}
+			if _, isItem := parseListMarker(line); isItem || startsBlock(line) {
+				break
+			}
+			if last := item[len(item)-1]; !isBlank(last) {
+				item = append(item, strings.TrimLeft(line, " "))
+				i++
+				continue
+			}
+			break
+		}
+		items = append(items, item)
+
+		if i < len(lines) && isBlank(lines[i]) {
+			j := i
+			for j < len(lines) && isBlank(lines[j]) {
+				j++
+			}
+			if j < len(lines) {
+				if next, ok := parseListMarker(lines[j]); ok && sameList(first, next) {
+					i = j
+					continue
+				}
+			}
+			break
+		}
+	}
+
+	return p.listNode(first, items), i
+}
+
+// listNode builds bulletList / orderedList, or taskList when every item starts with [ ] or [x].
+func (p *markdownParser) listNode(first listMarker, items [][]string) Node {
+	isTaskList := !first.ordered
+	for _, item := range items {
+		if !taskRe.MatchString(item[0]) {
+			isTaskList = false
+			break
+		}
+	}
+
+	list := Node{Type: "bulletList"}
+	itemType := "listItem"
+	switch {
+	case isTaskList:
+		list.Type, itemType = "taskList", "taskItem"
+	case first.ordered:
+		list.Type = "orderedList"
+		list.Attrs = map[string]interface{}{"start": first.start}
+	}
+
+	for _, lines := range items {
+		node := Node{Type: itemType}
+		if isTaskList {
+			m := taskRe.FindStringSubmatch(lines[0])
+			node.Attrs = map[string]interface{}{"checked": m[1] != " "}
+			lines[0] = lines[0][len(m[0]):]
+		}
+
+		// listItem ของ TipTap ต้องขึ้นต้นด้วย paragraph
+		content := p.parseBlocks(lines)
+		if len(content) == 0 || content[0].Type != "paragraph" {
+			content = append([]Node{{Type: "paragraph"}}, content...)
+		}
+		node.Content = content
+		list.Content = append(list.Content, node)
+	}
+	return list
+}
+
+// splitTableRow splits a GFM table row on unescaped pipes.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !tableDelimRe.MatchString(lines[i+1]) {
+		return false
+	}
+	return len(splitTableRow(lines[i])) == len(splitTableRow(lines[i+1]))
+}
+
+func (p *markdownParser) table(lines []string, i int) (Node, int) {
+	header := splitTableRow(lines[i])
+
+	aligns := make([]string, len(header))
+	for col, delim := range splitTableRow(lines[i+1]) {
+		switch {
+		case strings.HasPrefix(delim, ":") && strings.HasSuffix(delim, ":"):
+			aligns[col] = "center"
+		case strings.HasSuffix(delim, ":"):
+			aligns[col] = "right"
+		case strings.HasPrefix(delim, ":"):
+			aligns[col] = "left"
+		}
+	}
+
+	row := func(cells []string, cellType string) Node {
+		node := Node{Type: "tableRow"}
+		for col := range header {
+			text := ""
+			if col < len(cells) {
+				text = cells[col]
+			}
+			paragraph := Node{Type: "paragraph", Content: p.inline(text)}
+			if aligns[col] != "" {
+				paragraph.Attrs = map[string]interface{}{"textAlign": aligns[col]}
+			}
+			node.Content = append(node.Content, Node{
+				Type:    cellType,
+				Attrs:   map[string]interface{}{"colspan": 1, "rowspan": 1, "colwidth": nil},
+				Content: []Node{paragraph},
+			})
+		}
+		return node
+	}
+
+	table := Node{Type: "table", Content: []Node{row(header, "tableHeader")}}
+	for i += 2; i < len(lines) && !isBlank(lines[i]) && !startsBlock(lines[i]); i++ {
+		table.Content = append(table.Content, row(splitTableRow(lines[i]), "tableCell"))
+	}
+	return table, i
+}
+
+// paragraph builds a paragraph; images become block-level image nodes as in the editor,
+// so a paragraph containing images is split around them.
+func (p *markdownParser) paragraph(lines []string) []Node {
+	for i, line := range lines {
+		lines[i] = strings.TrimLeft(line, " ")
+	}
+	text := strings.TrimRight(strings.Join(lines, "\n"), " \t")
+
+	var blocks []Node
+	var current []Node
+	flush := func() {
+		// ตัดช่องว่างที่ขอบย่อหน้าและรอบรูปที่ถูกแยกออกไป (ช่องว่างใน code เป็นเนื้อหา ไม่ตัด)
+		if n := len(current); n > 0 {
+			if isTrimmableText(current[0]) {
+				current[0].Text = strings.TrimLeft(current[0].Text, " ")
+			}
+			if isTrimmableText(current[n-1]) {
+				current[n-1].Text = strings.TrimRight(current[n-1].Text, " ")
+			}
+		}
+		// text node ว่าง (เช่นเหลือแต่ช่องว่างก่อน link เปล่า) editor ไม่รับ
+		content := make([]Node, 0, len(current))
+		for _, node := range current {
+			if node.Type != "text" || node.Text != "" {
+				content = append(content, node)
+			}
+		}
+		if len(content) > 0 {
+			blocks = append(blocks, Node{Type: "paragraph", Content: content})
+		}
+		current = nil
+	}
+
+	for _, node := range p.inline(text) {
+		if node.Type == "image" {
+			flush()
+			blocks = append(blocks, node)
+			continue
+		}
+		current = append(current, node)
+	}
+	flush()
+
+	return blocks
+}
+
+func isTrimmableText(node Node) bool {
+	if node.Type != "text" {
+		return false
+	}
+	for _, m := range node.Marks {
+		if m.Type == "code" {
+			return false
+		}
+	}
+	return true
+}
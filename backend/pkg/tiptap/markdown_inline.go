@@ -0,0 +1,625 @@
+package tiptap
+
+import (
+	"html"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	autolinkRe      = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*)>`)
+	emailLinkRe     = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*)>`)
+	bareURLRe       = regexp.MustCompile(`^(?:https?://|www\.)[^\s<]+`)
+	bareURLPrefixRe = regexp.MustCompile(`^(?:https?://|www\.)`)
+	entityRe        = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+	linkTitleRe     = regexp.MustCompile(`^(?:"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)'|\(((?:[^()\\]|\\.)*)\))`)
+	backslashEscRe  = regexp.MustCompile(`\\([!-/:-@\[-` + "`" + `{-~])`)
+	inlineTagRe     = regexp.MustCompile(`^<(/?)(u|mark|sub|sup)>`)
+)
+
+// inlineTagMarks คือ inline HTML ที่ editor มี mark รองรับ (Markdown ไม่มี syntax ของตัวเอง)
+var inlineTagMarks = map[string]string{
+	"u":    "underline",
+	"mark": "highlight",
+	"sub":  "subscript",
+	"sup":  "superscript",
+}
+
+type inlineKind int
+
+const (
+	inlineText inlineKind = iota
+	inlineDelim
+	inlineCode
+	inlineBreak
+	inlineMark
+	inlineLink
+	inlineImage
+)
+
+// inlineNode is the intermediate tree built before flattening into TipTap text nodes with marks.
+type inlineNode struct {
+	kind     inlineKind
+	text     string
+	mark     string // inlineMark: bold, italic, strike
+	href     string // link href / image src
+	title    string
+	children []*inlineNode
+
+	// delimiter run ของ * _ ~
+	delim     byte
+	count     int
+	origCount int
+	canOpen   bool
+	canClose  bool
+}
+
+type openTag struct {
+	index int
+	name  string
+}
+
+type bracket struct {
+	index  int // ตำแหน่งของ text "[" หรือ "![" ใน items
+	srcPos int // ตำแหน่งหลัง "[" ใน source ใช้เป็น label ของ reference link
+	image  bool
+	active bool
+}
+
+func unescapeBackslashes(s string) string {
+	return backslashEscRe.ReplaceAllString(s, "$1")
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isPunctRune(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// inline parses inline Markdown into TipTap text, hardBreak and image nodes.
+func (p *markdownParser) inline(s string) []Node {
+	var out []Node
+	flattenInline(p.parseInline(s), nil, &out)
+	return mergeTextNodes(out)
+}
+
+func (p *markdownParser) parseInline(s string) []*inlineNode {
+	var items []*inlineNode
+	var brackets []bracket
+	var tags []openTag
+	var text strings.Builder
+
+	flushText := func() {
+		if text.Len() > 0 {
+			items = append(items, &inlineNode{kind: inlineText, text: text.String()})
+			text.Reset()
+		}
+	}
+	skipLeadingSpaces := func(i int) int {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		return i
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			flushText()
+			items = append(items, &inlineNode{kind: inlineBreak})
+			i = skipLeadingSpaces(i + 2)
+
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			text.WriteByte(s[i+1])
+			i += 2
+
+		case c == '\n':
+			// เว้น 2 ช่องท้ายบรรทัด = hard break, ไม่อย่างนั้นเป็น soft break
+			current := text.String()
+			trimmed := strings.TrimRight(current, " ")
+			text.Reset()
+			text.WriteString(trimmed)
+			if len(current)-len(trimmed) >= 2 {
+				flushText()
+				items = append(items, &inlineNode{kind: inlineBreak})
+			} else {
+				text.WriteByte(' ')
+			}
+			i = skipLeadingSpaces(i + 1)
+
+		case c == '`':
+			n := runLength(s, i, '`')
+			end := findBacktickRun(s, i+n, n)
+			if end < 0 {
+				text.WriteString(s[i : i+n])
+				i += n
+				break
+			}
+			flushText()
+			code := strings.ReplaceAll(s[i+n:end], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			items = append(items, &inlineNode{kind: inlineCode, text: code})
+			i = end + n
+
+		case c == '<':
+			if m := inlineTagRe.FindStringSubmatch(s[i:]); m != nil {
+				flushText()
+				if m[1] == "" {
+					tags = append(tags, openTag{index: len(items), name: m[2]})
+					items = append(items, &inlineNode{kind: inlineText, text: m[0]})
+				} else if open := lastOpenTag(tags, m[2]); open >= 0 {
+					start := tags[open].index
+					node := &inlineNode{kind: inlineMark, mark: inlineTagMarks[m[2]]}
+					node.children = processEmphasis(append([]*inlineNode(nil), items[start+1:]...))
+					items = append(items[:start], node)
+					tags = tags[:open]
+					brackets = dropAfter(brackets, start)
+				} else {
+					items = append(items, &inlineNode{kind: inlineText, text: m[0]})
+				}
+				i += len(m[0])
+			} else if m := autolinkRe.FindStringSubmatch(s[i:]); m != nil {
+				flushText()
+				items = append(items, linkNode(m[1], m[1]))
+				i += len(m[0])
+			} else if m := emailLinkRe.FindStringSubmatch(s[i:]); m != nil {
+				flushText()
+				items = append(items, linkNode("mailto:"+m[1], m[1]))
+				i += len(m[0])
+			} else {
+				text.WriteByte(c)
+				i++
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			n := runLength(s, i, c)
+			if c == '~' && n > 2 {
+				text.WriteString(s[i : i+n])
+				i += n
+				break
+			}
+			flushText()
+			items = append(items, delimiterRun(s, i, n))
+			i += n
+
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			flushText()
+			brackets = append(brackets, bracket{index: len(items), srcPos: i + 2, image: true, active: true})
+			items = append(items, &inlineNode{kind: inlineText, text: "!["})
+			i += 2
+
+		case c == '[':
+			flushText()
+			brackets = append(brackets, bracket{index: len(items), srcPos: i + 1, active: true})
+			items = append(items, &inlineNode{kind: inlineText, text: "["})
+			i++
+
+		case c == ']':
+			if len(brackets) == 0 {
+				text.WriteByte(c)
+				i++
+				break
+			}
+			opener := brackets[len(brackets)-1]
+			brackets = brackets[:len(brackets)-1]
+
+			href, title, consumed, ok := "", "", 0, false
+			if opener.active {
+				href, title, consumed, ok = p.parseLinkTail(s[i+1:], s[opener.srcPos:i])
+			}
+			if !ok {
+				text.WriteByte(c)
+				i++
+				break
+			}
+
+			flushText()
+			node := &inlineNode{kind: inlineLink, href: href, title: title}
+			if opener.image {
+				node.kind = inlineImage
+			}
+			node.children = processEmphasis(append([]*inlineNode(nil), items[opener.index+1:]...))
+			items = append(items[:opener.index], node)
+			for len(tags) > 0 && tags[len(tags)-1].index > opener.index {
+				tags = tags[:len(tags)-1]
+			}
+
+			// link ซ้อน link ไม่ได้
+			if !opener.image {
+				for j := range brackets {
+					if !brackets[j].image {
+						brackets[j].active = false
+					}
+				}
+			}
+			i += 1 + consumed
+
+		case c == '&':
+			if m := entityRe.FindString(s[i:]); m != "" {
+				text.WriteString(html.UnescapeString(m))
+				i += len(m)
+			} else {
+				text.WriteByte(c)
+				i++
+			}
+
+		case (c == 'h' || c == 'w') && len(brackets) == 0 && atWordStart(s, i):
+			if url := matchBareURL(s[i:]); url != "" {
+				flushText()
+				href := url
+				if strings.HasPrefix(href, "www.") {
+					href = "http://" + href
+				}
+				items = append(items, linkNode(href, url))
+				i += len(url)
+				break
+			}
+			text.WriteByte(c)
+			i++
+
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			text.WriteString(s[i : i+size])
+			i += size
+		}
+	}
+	flushText()
+
+	return processEmphasis(items)
+}
+
+func lastOpenTag(tags []openTag, name string) int {
+	for i := len(tags) - 1; i >= 0; i-- {
+		if tags[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// dropAfter ทิ้ง "[" ที่อยู่ในช่วงที่ถูกห่อเป็น mark แล้ว (ไม่สามารถปิดเป็น link ได้อีก)
+func dropAfter(brackets []bracket, index int) []bracket {
+	for len(brackets) > 0 && brackets[len(brackets)-1].index > index {
+		brackets = brackets[:len(brackets)-1]
+	}
+	return brackets
+}
+
+func linkNode(href, label string) *inlineNode {
+	return &inlineNode{kind: inlineLink, href: href, children: []*inlineNode{{kind: inlineText, text: label}}}
+}
+
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// findBacktickRun finds a closing run of exactly n backticks starting at from.
+func findBacktickRun(s string, from, n int) int {
+	for i := from; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		run := runLength(s, i, '`')
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+func atWordStart(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch s[i-1] {
+	case ' ', '\n', '(', '*', '_', '~':
+		return true
+	}
+	return false
+}
+
+// matchBareURL returns the GFM extended autolink at the start of s without trailing punctuation.
+func matchBareURL(s string) string {
+	url := bareURLRe.FindString(s)
+	for url != "" {
+		last := url[len(url)-1]
+		switch {
+		case strings.IndexByte("?!.,:*_~'\"", last) >= 0:
+			url = url[:len(url)-1]
+		case last == ')' && strings.Count(url, "(") < strings.Count(url, ")"):
+			url = url[:len(url)-1]
+		default:
+			return url
+		}
+	}
+	return ""
+}
+
+// delimiterRun classifies a run of * _ ~ as opener and/or closer by the CommonMark flanking rules.
+func delimiterRun(s string, i, n int) *inlineNode {
+	prev, next := ' ', ' '
+	if i > 0 {
+		prev, _ = utf8.DecodeLastRuneInString(s[:i])
+	}
+	if i+n < len(s) {
+		next, _ = utf8.DecodeRuneInString(s[i+n:])
+	}
+
+	prevSpace, nextSpace := unicode.IsSpace(prev), unicode.IsSpace(next)
+	prevPunct, nextPunct := isPunctRune(prev), isPunctRune(next)
+	left := !nextSpace && (!nextPunct || prevSpace || prevPunct)
+	right := !prevSpace && (!prevPunct || nextSpace || nextPunct)
+
+	node := &inlineNode{kind: inlineDelim, delim: s[i], count: n, origCount: n, canOpen: left, canClose: right}
+	if s[i] == '_' {
+		// _ กลางคำ (เช่น snake_case) ไม่นับเป็น emphasis
+		node.canOpen = left && (!right || prevPunct)
+		node.canClose = right && (!left || nextPunct)
+	}
+	return node
+}
+
+// parseLinkTail parses what follows "]": an inline (dest "title"), a full [ref],
+// a collapsed [] or a shortcut reference. It returns the bytes consumed after "]".
+func (p *markdownParser) parseLinkTail(s, label string) (href, title string, consumed int, ok bool) {
+	if strings.HasPrefix(s, "(") {
+		if href, title, consumed, ok = parseInlineDestination(s); ok {
+			return href, title, consumed, true
+		}
+	}
+
+	if strings.HasPrefix(s, "[") {
+		if end := strings.IndexByte(s, ']'); end > 0 {
+			ref := s[1:end]
+			if strings.TrimSpace(ref) == "" {
+				ref = label
+			}
+			if r, found := p.refs[normalizeLabel(ref)]; found {
+				return r.href, r.title, end + 1, true
+			}
+			return "", "", 0, false
+		}
+	}
+
+	if r, found := p.refs[normalizeLabel(label)]; found {
+		return r.href, r.title, 0, true
+	}
+	return "", "", 0, false
+}
+
+func parseInlineDestination(s string) (href, title string, consumed int, ok bool) {
+	i := skipSpaces(s, 1)
+
+	if i < len(s) && s[i] == '<' {
+		// ใน <...> ใช้ \< \> ได้ จึงหา > ตัวแรกที่ไม่ได้ escape
+		end := -1
+		for j := i + 1; j < len(s); j++ {
+			if s[j] == '\\' && j+1 < len(s) {
+				j++
+				continue
+			}
+			if s[j] == '>' || s[j] == '\n' || s[j] == '<' {
+				end = j
+				break
+			}
+		}
+		if end < 0 || s[end] != '>' {
+			return "", "", 0, false
+		}
+		href = s[i+1 : end]
+		i = end + 1
+	} else {
+		start, depth := i, 0
+	scan:
+		for i < len(s) {
+			switch s[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				if depth == 0 {
+					break scan
+				}
+				depth--
+			case ' ', '\t', '\n':
+				break scan
+			}
+			i++
+		}
+		if i > len(s) {
+			i = len(s)
+		}
+		href = s[start:i]
+	}
+
+	afterDest := i
+	i = skipSpaces(s, i)
+	if i > afterDest {
+		if m := linkTitleRe.FindStringSubmatch(s[i:]); m != nil {
+			title = unescapeBackslashes(m[1] + m[2] + m[3])
+			i = skipSpaces(s, i+len(m[0]))
+		}
+	}
+
+	if i >= len(s) || s[i] != ')' {
+		return "", "", 0, false
+	}
+	return unescapeBackslashes(href), title, i + 1, true
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// processEmphasis pairs delimiter runs into bold / italic / strike nodes (CommonMark "process emphasis").
+func processEmphasis(items []*inlineNode) []*inlineNode {
+	for c := 0; c < len(items); c++ {
+		closer := items[c]
+		if closer.kind != inlineDelim || !closer.canClose || closer.count == 0 {
+			continue
+		}
+
+		o := -1
+		for j := c - 1; j >= 0; j-- {
+			opener := items[j]
+			if opener.kind != inlineDelim || opener.delim != closer.delim || !opener.canOpen || opener.count == 0 {
+				continue
+			}
+			if closer.delim == '~' {
+				if opener.count != closer.count {
+					continue
+				}
+			} else if (opener.canClose || closer.canOpen) &&
+				(opener.origCount+closer.origCount)%3 == 0 &&
+				!(opener.origCount%3 == 0 && closer.origCount%3 == 0) {
+				continue
+			}
+			o = j
+			break
+		}
+		if o < 0 {
+			continue
+		}
+
+		opener := items[o]
+		use, mark := 1, "italic"
+		switch {
+		case closer.delim == '~':
+			use, mark = closer.count, "strike"
+		case opener.count >= 2 && closer.count >= 2:
+			use, mark = 2, "bold"
+		}
+		opener.count -= use
+		closer.count -= use
+
+		node := &inlineNode{kind: inlineMark, mark: mark, children: append([]*inlineNode(nil), items[o+1:c]...)}
+		items = append(items[:o+1], append([]*inlineNode{node}, items[c:]...)...)
+
+		// ตอนนี้ opener อยู่ที่ o, node ที่ o+1, closer ที่ o+2
+		closerIndex := o + 2
+		if opener.count == 0 {
+			items = append(items[:o], items[o+1:]...)
+			closerIndex--
+		}
+		if closer.count == 0 {
+			items = append(items[:closerIndex], items[closerIndex+1:]...)
+		}
+		// ตรวจ closer ตัวเดิมซ้ำถ้ายังเหลือ ไม่อย่างนั้นไปต่อที่ตัวถัดไป
+		c = closerIndex - 1
+	}
+	return items
+}
+
+// withMark adds mark unless one of the same type is already active (ProseMirror rejects duplicate mark types),
+// e.g. the inner emphasis of "*a *b* c*" stays a single italic.
+// Marks are kept in markOrder so "<mark>**b**</mark>" and "**<mark>b</mark>**" produce the same node.
+func withMark(marks []Mark, mark Mark) []Mark {
+	for _, m := range marks {
+		if m.Type == mark.Type {
+			return marks
+		}
+	}
+	at := len(marks)
+	for i, m := range marks {
+		if markRank(mark.Type) < markRank(m.Type) {
+			at = i
+			break
+		}
+	}
+	result := make([]Mark, 0, len(marks)+1)
+	result = append(result, marks[:at]...)
+	result = append(result, mark)
+	return append(result, marks[at:]...)
+}
+
+// markRank ลำดับของ mark ใน node mark ที่ไม่รู้จัก (เช่น code) อยู่ท้ายสุด
+func markRank(markType string) int {
+	if rank, ok := markOrder[markType]; ok {
+		return rank
+	}
+	return len(markOrder)
+}
+
+func plainInlineText(items []*inlineNode) string {
+	var b strings.Builder
+	for _, item := range items {
+		switch item.kind {
+		case inlineText, inlineCode:
+			b.WriteString(item.text)
+		case inlineDelim:
+			b.WriteString(strings.Repeat(string(item.delim), item.count))
+		default:
+			b.WriteString(plainInlineText(item.children))
+		}
+	}
+	return b.String()
+}
+
+func flattenInline(items []*inlineNode, marks []Mark, out *[]Node) {
+	text := func(s string, marks []Mark) {
+		if s != "" {
+			*out = append(*out, Node{Type: "text", Text: s, Marks: marks})
+		}
+	}
+
+	for _, item := range items {
+		switch item.kind {
+		case inlineText:
+			text(item.text, marks)
+		case inlineDelim:
+			text(strings.Repeat(string(item.delim), item.count), marks)
+		case inlineCode:
+			// code mark ของ editor ใช้ร่วมกับ mark อื่นไม่ได้ ยกเว้น link
+			var codeMarks []Mark
+			for _, m := range marks {
+				if m.Type == "link" {
+					codeMarks = append(codeMarks, m)
+				}
+			}
+			text(item.text, withMark(codeMarks, Mark{Type: "code"}))
+		case inlineBreak:
+			*out = append(*out, Node{Type: "hardBreak"})
+		case inlineMark:
+			flattenInline(item.children, withMark(marks, Mark{Type: item.mark}), out)
+		case inlineLink:
+			flattenInline(item.children, withMark(marks, Mark{Type: "link", Attrs: map[string]interface{}{"href": item.href}}), out)
+		case inlineImage:
+			attrs := map[string]interface{}{"src": item.href, "alt": plainInlineText(item.children)}
+			if item.title != "" {
+				attrs["title"] = item.title
+			}
+			*out = append(*out, Node{Type: "image", Attrs: attrs})
+		}
+	}
+}
+
+// mergeTextNodes joins adjacent text nodes that carry the same marks.
+func mergeTextNodes(nodes []Node) []Node {
+	merged := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		if n := len(merged); n > 0 && node.Type == "text" && merged[n-1].Type == "text" && reflect.DeepEqual(merged[n-1].Marks, node.Marks) {
+			merged[n-1].Text += node.Text
+			continue
+		}
+		merged = append(merged, node)
+	}
+	return merged
+}
@@ -0,0 +1,523 @@
+package tiptap
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// บรรทัดที่ขึ้นต้นแบบนี้จะถูกตีความเป็น block อื่นถ้าไม่ escape
+	lineStartRe   = regexp.MustCompile(`^(?:#{1,6}(?:[ \t]|$)|[-+>=]|\d{1,9}[.)])`)
+	orderedLeadRe = regexp.MustCompile(`^(\d{1,9})([.)])`)
+)
+
+// markOrder กำหนดลำดับการเปิด mark: mark ที่มักครอบช่วงยาวกว่าอยู่ด้านนอก
+var markOrder = map[string]int{
+	"link":        0,
+	"bold":        1,
+	"italic":      2,
+	"strike":      3,
+	"underline":   4,
+	"highlight":   5,
+	"subscript":   6,
+	"superscript": 7,
+}
+
+var markDelimiters = map[string][2]string{
+	"bold":        {"**", "**"},
+	"italic":      {"*", "*"},
+	"strike":      {"~~", "~~"},
+	"underline":   {"<u>", "</u>"},
+	"highlight":   {"<mark>", "</mark>"},
+	"subscript":   {"<sub>", "</sub>"},
+	"superscript": {"<sup>", "</sup>"},
+}
+
+type frontMatterYAML struct {
+	Title       string   `yaml:"title,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Slug        string   `yaml:"slug,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+}
+
+// RenderMarkdown renders a document back to Markdown, with front-matter when any field is set.
+// It is the inverse of ParseMarkdown: parsing the output yields the same TipTap tree.
+// Marks without Markdown syntax (underline, highlight, sub/superscript) are written as inline HTML.
+func RenderMarkdown(doc *MarkdownDocument) (string, error) {
+	var b strings.Builder
+
+	meta := doc.FrontMatter
+	if meta.Title != "" || meta.Description != "" || meta.Slug != "" || len(meta.Tags) > 0 {
+		out, err := yaml.Marshal(frontMatterYAML{
+			Title:       meta.Title,
+			Description: meta.Description,
+			Slug:        meta.Slug,
+			Tags:        meta.Tags,
+		})
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("---\n")
+		b.Write(out)
+		b.WriteString("---\n\n")
+	}
+
+	if body := renderMarkdownBlocks(doc.Doc.Content); body != "" {
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+func renderMarkdownBlocks(nodes []Node) string {
+	return renderMarkdownBlockList(nodes, false)
+}
+
+// renderMarkdownBlockList ใน list item (inItem) list ย่อยที่ตามหลังข้อความจะต่อบรรทัดทันที
+// ไม่เช่นนั้น CommonMark จะถือว่าทั้ง list เป็น loose list และห่อทุก item ด้วย <p>
+func renderMarkdownBlockList(nodes []Node, inItem bool) string {
+	var b strings.Builder
+	prevType := ""
+	prevList := ""
+	alternate := false
+
+	for _, node := range nodes {
+		var out string
+		switch node.Type {
+		case "bulletList", "orderedList", "taskList":
+			// list ที่ใช้ marker เดียวกันติดกันจะถูกรวมเป็น list เดียว ต้องสลับ marker เพื่อแยก
+			family := "bullet"
+			if node.Type == "orderedList" {
+				family = "ordered"
+			}
+			alternate = prevList == family && !alternate
+			out = renderMarkdownList(node, alternate)
+			prevList = family
+		default:
+			out = renderMarkdownBlock(node)
+			prevList = ""
+			alternate = false
+		}
+		if out == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			if inItem && prevType == "paragraph" && interruptsParagraph(node) {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(out)
+		prevType = node.Type
+	}
+	return b.String()
+}
+
+// interruptsParagraph list ที่ขึ้นบรรทัดต่อจาก paragraph ได้โดยไม่กลายเป็นข้อความต่อเนื่อง
+// (ordered list ต้องเริ่มที่ 1 ตาม CommonMark)
+func interruptsParagraph(node Node) bool {
+	switch node.Type {
+	case "bulletList", "taskList":
+		return len(node.Content) > 0
+	case "orderedList":
+		return len(node.Content) > 0 && node.AttrInt("start", 1) == 1
+	}
+	return false
+}
+
+func renderMarkdownBlock(node Node) string {
+	switch node.Type {
+	case "paragraph":
+		return escapeLineStarts(renderMarkdownInline(node.Content))
+	case "heading":
+		text := strings.ReplaceAll(renderMarkdownInline(node.Content), "\\\n", " ")
+		if strings.HasSuffix(text, "#") {
+			text = text[:len(text)-1] + "\\#"
+		}
+		return strings.Repeat("#", node.AttrInt("level", 1)) + " " + text
+	case "blockquote":
+		return prefixLines(renderMarkdownBlocks(node.Content), "> ", ">")
+	case "codeBlock":
+		return renderMarkdownCodeBlock(node)
+	case "horizontalRule":
+		return "---"
+	case "image":
+		return renderMarkdownImage(node)
+	case "table":
+		return renderMarkdownTable(node)
+	case "hardBreak":
+		return ""
+	case "text":
+		return escapeLineStarts(escapeMarkdownText(node.Text))
+	}
+	return renderMarkdownBlocks(node.Content)
+}
+
+func prefixLines(text, prefix, blankPrefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = blankPrefix
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderMarkdownList(list Node, alternate bool) string {
+	bullet, delim := "-", "."
+	if alternate {
+		bullet, delim = "*", ")"
+	}
+	start := list.AttrInt("start", 1)
+
+	items := make([]string, 0, len(list.Content))
+	for i, item := range list.Content {
+		marker := bullet + " "
+		indent := len(marker)
+		switch list.Type {
+		case "orderedList":
+			marker = strconv.Itoa(start+i) + delim + " "
+			indent = len(marker)
+		case "taskList":
+			if checked, _ := item.Attrs["checked"].(bool); checked {
+				marker += "[x] "
+			} else {
+				marker += "[ ] "
+			}
+		}
+
+		lines := strings.Split(renderMarkdownBlockList(item.Content, true), "\n")
+		for k := 1; k < len(lines); k++ {
+			if lines[k] != "" {
+				lines[k] = strings.Repeat(" ", indent) + lines[k]
+			}
+		}
+		items = append(items, strings.TrimRight(marker+lines[0], " ")+joinRest(lines))
+	}
+	return strings.Join(items, "\n")
+}
+
+func joinRest(lines []string) string {
+	if len(lines) < 2 {
+		return ""
+	}
+	return "\n" + strings.Join(lines[1:], "\n")
+}
+
+func longestRun(s string, c rune) int {
+	longest, current := 0, 0
+	for _, r := range s {
+		if r == c {
+			current++
+			if current > longest {
+				longest = current
+			}
+		} else {
+			current = 0
+		}
+	}
+	return longest
+}
+
+func renderMarkdownCodeBlock(node Node) string {
+	code := node.PlainText()
+	fence := strings.Repeat("`", max(3, longestRun(code, '`')+1))
+	language := node.AttrString("language")
+	if code == "" {
+		return fence + language + "\n" + fence
+	}
+	return fence + language + "\n" + code + "\n" + fence
+}
+
+func markdownDestination(dest string) string {
+	dest = strings.ReplaceAll(dest, `\`, `\\`)
+	if dest == "" || strings.ContainsAny(dest, " ()<>") {
+		return "<" + strings.NewReplacer("<", "\\<", ">", "\\>").Replace(dest) + ">"
+	}
+	return dest
+}
+
+func markdownTitle(title string) string {
+	if title == "" {
+		return ""
+	}
+	return ` "` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(title) + `"`
+}
+
+func renderMarkdownImage(node Node) string {
+	alt := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(node.AttrString("alt"))
+	return "![" + alt + "](" + markdownDestination(node.AttrString("src")) + markdownTitle(node.AttrString("title")) + ")"
+}
+
+func renderMarkdownTable(table Node) string {
+	columns := 0
+	for _, row := range table.Content {
+		columns = max(columns, len(row.Content))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	cellText := func(cell Node) string {
+		var parts []string
+		for _, block := range cell.Content {
+			text := renderMarkdownInline(block.Content)
+			if block.Type != "paragraph" && block.Type != "heading" {
+				text = escapeMarkdownText(block.PlainText())
+			}
+			parts = append(parts, strings.ReplaceAll(text, "\\\n", " "))
+		}
+		text := strings.ReplaceAll(strings.Join(parts, " "), "\n", " ")
+		return escapeTablePipes(text)
+	}
+
+	row := func(cells []string) string {
+		for len(cells) < columns {
+			cells = append(cells, "")
+		}
+		return "| " + strings.Join(cells, " | ") + " |"
+	}
+
+	var lines []string
+	aligns := make([]string, columns)
+	for r, tableRow := range table.Content {
+		cells := make([]string, 0, columns)
+		for c, cell := range tableRow.Content {
+			cells = append(cells, cellText(cell))
+			if r == 0 && len(cell.Content) > 0 {
+				aligns[c] = cell.Content[0].AttrString("textAlign")
+			}
+		}
+		lines = append(lines, row(cells))
+
+		if r == 0 {
+			delims := make([]string, columns)
+			for c, align := range aligns {
+				switch align {
+				case "left":
+					delims[c] = ":---"
+				case "center":
+					delims[c] = ":---:"
+				case "right":
+					delims[c] = "---:"
+				default:
+					delims[c] = "---"
+				}
+			}
+			lines = append(lines, "| "+strings.Join(delims, " | ")+" |")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapeTablePipes escape "|" ที่ยังไม่ถูก escape (เช่นใน code span) เพื่อไม่ให้ตัด cell
+func escapeTablePipes(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '|' && (i == 0 || text[i-1] != '\\') {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// escapeLineStarts escape ตัวอักษรต้นบรรทัดที่จะกลายเป็น heading, list, quote หรือ setext underline
+func escapeLineStarts(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if !lineStartRe.MatchString(line) {
+			continue
+		}
+		if m := orderedLeadRe.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + "\\" + line[len(m[1]):]
+		} else {
+			lines[i] = "\\" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// escapeMarkdownText backslash-escapes characters that would otherwise become inline syntax.
+func escapeMarkdownText(text string) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case strings.ContainsRune("\\`*[]<~|", r):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '_':
+			prev, _ := utf8.DecodeLastRuneInString(text[:i])
+			next, _ := utf8.DecodeRuneInString(text[i+size:])
+			// _ กลางคำไม่เป็น emphasis จึงไม่ต้อง escape (เช่น snake_case)
+			if i == 0 || i+size >= len(text) || !isWordRune(prev) || !isWordRune(next) {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case r == '&' && entityRe.MatchString(text[i:]):
+			b.WriteString("\\&")
+		case (r == 'h' || r == 'w') && atWordStart(text, i) && bareURLPrefixRe.MatchString(text[i:]):
+			// กัน URL ที่เป็นข้อความธรรมดาไม่ให้กลายเป็น autolink ตอน import กลับ
+			// เช็กแค่ prefix เพราะตัวที่ตามมาอาจกลายเป็น URL ได้หลัง escape (เช่น "https://<")
+			prefix := bareURLPrefixRe.FindString(text[i:])
+			sep := strings.IndexAny(prefix, ":.")
+			b.WriteString(prefix[:sep] + "\\" + prefix[sep:sep+1])
+			size = sep + 1
+		default:
+			b.WriteRune(r)
+		}
+		i += size
+	}
+	return b.String()
+}
+
+func codeSpan(code string) string {
+	fence := strings.Repeat("`", longestRun(code, '`')+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") || (strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ") && strings.TrimSpace(code) != "") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+func sameMark(a, b Mark) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	return a.Type != "link" || fmt.Sprint(a.Attrs["href"]) == fmt.Sprint(b.Attrs["href"])
+}
+
+func hasMark(marks []Mark, mark Mark) bool {
+	for _, m := range marks {
+		if sameMark(m, mark) {
+			return true
+		}
+	}
+	return false
+}
+
+func openMarkdownMark(mark Mark) string {
+	if mark.Type == "link" {
+		return "["
+	}
+	return markDelimiters[mark.Type][0]
+}
+
+func closeMarkdownMark(mark Mark) string {
+	if mark.Type == "link" {
+		href, _ := mark.Attrs["href"].(string)
+		title, _ := mark.Attrs["title"].(string)
+		return "](" + markdownDestination(href) + markdownTitle(title) + ")"
+	}
+	return markDelimiters[mark.Type][1]
+}
+
+// markdownMarks คืน mark ที่ Markdown เขียนได้ เรียงตาม markOrder และบอกว่ามี code mark หรือไม่
+func markdownMarks(marks []Mark) ([]Mark, bool) {
+	result := make([]Mark, 0, len(marks))
+	code := false
+	for _, m := range marks {
+		if m.Type == "code" {
+			code = true
+			continue
+		}
+		if _, ok := markOrder[m.Type]; ok {
+			result = append(result, m)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return markOrder[result[i].Type] < markOrder[result[j].Type] })
+	return result, code
+}
+
+// renderMarkdownInline writes text nodes with their marks. Marks shared by neighbouring nodes stay open,
+// and whitespace at a mark boundary is moved outside the delimiters so they keep flanking correctly.
+func renderMarkdownInline(nodes []Node) string {
+	var b strings.Builder
+	var active []Mark
+	pending := ""
+
+	closeTo := func(desired []Mark) {
+		keep := 0
+		for keep < len(active) && hasMark(desired, active[keep]) {
+			keep++
+		}
+		for i := len(active) - 1; i >= keep; i-- {
+			b.WriteString(closeMarkdownMark(active[i]))
+		}
+		active = active[:keep]
+	}
+	open := func(desired []Mark) {
+		for _, m := range desired {
+			if !hasMark(active, m) {
+				b.WriteString(openMarkdownMark(m))
+				active = append(active, m)
+			}
+		}
+	}
+	flushPending := func() {
+		b.WriteString(pending)
+		pending = ""
+	}
+
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			marks, code := markdownMarks(node.Marks)
+			if code {
+				closeTo(marks)
+				flushPending()
+				open(marks)
+				b.WriteString(codeSpan(node.Text))
+				continue
+			}
+
+			text := strings.ReplaceAll(node.Text, "\n", " ")
+			core := strings.TrimSpace(text)
+			if core == "" {
+				pending += text
+				continue
+			}
+			lead := text[:strings.Index(text, core)]
+			trail := text[len(lead)+len(core):]
+
+			closeTo(marks)
+			flushPending()
+			b.WriteString(lead)
+			open(marks)
+			b.WriteString(escapeMarkdownText(core))
+			pending = trail
+		case "hardBreak":
+			closeTo(nil)
+			flushPending()
+			b.WriteString("\\\n")
+		case "image":
+			closeTo(nil)
+			flushPending()
+			b.WriteString(renderMarkdownImage(node))
+		default:
+			closeTo(nil)
+			flushPending()
+			b.WriteString(escapeMarkdownText(node.PlainText()))
+		}
+	}
+	closeTo(nil)
+	flushPending()
+
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package tiptap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func txt(s string, marks ...Mark) Node {
+	return Node{Type: "text", Text: s, Marks: marks}
+}
+
+func paragraph(children ...Node) Node {
+	return Node{Type: "paragraph", Content: children}
+}
+
+func listOf(typ string, items ...Node) Node {
+	return Node{Type: typ, Content: items}
+}
+
+func item(children ...Node) Node {
+	return Node{Type: "listItem", Content: children}
+}
+
+func TestRenderMarkdown_Blocks(t *testing.T) {
+	cases := []struct {
+		name string
+		doc  []Node
+		want string
+	}{
+		{
+			"heading levels",
+			[]Node{
+				{Type: "heading", Attrs: map[string]interface{}{"level": float64(1)}, Content: []Node{txt("Title")}},
+				{Type: "heading", Attrs: map[string]interface{}{"level": float64(3)}, Content: []Node{txt("Issue #")}},
+			},
+			"# Title\n\n### Issue \\#\n",
+		},
+		{
+			"nested bullet list",
+			[]Node{listOf("bulletList",
+				item(paragraph(txt("one")), listOf("bulletList", item(paragraph(txt("nested"))))),
+				item(paragraph(txt("two"))),
+			)},
+			"- one\n  - nested\n- two\n",
+		},
+		{
+			"nested list after item text",
+			[]Node{listOf("orderedList",
+				item(paragraph(txt("one")), listOf("taskList", Node{Type: "taskItem", Content: []Node{paragraph(txt("sub"))}})),
+				item(paragraph(txt("two")), Node{Type: "orderedList", Attrs: map[string]interface{}{"start": float64(5)}, Content: []Node{item(paragraph(txt("five")))}}),
+			)},
+			// ordered list ที่ไม่เริ่มที่ 1 ขึ้นบรรทัดต่อจากข้อความไม่ได้ ต้องเว้นบรรทัด
+			"1. one\n   - [ ] sub\n2. two\n\n   5. five\n",
+		},
+		{
+			"ordered list keeps its start",
+			[]Node{{Type: "orderedList", Attrs: map[string]interface{}{"start": float64(3)}, Content: []Node{item(paragraph(txt("three"))), item(paragraph(txt("four")))}}},
+			"3. three\n4. four\n",
+		},
+		{
+			"adjacent lists alternate markers",
+			[]Node{listOf("bulletList", item(paragraph(txt("a")))), listOf("bulletList", item(paragraph(txt("b"))))},
+			"- a\n\n* b\n",
+		},
+		{
+			"task list",
+			[]Node{listOf("taskList",
+				Node{Type: "taskItem", Attrs: map[string]interface{}{"checked": true}, Content: []Node{paragraph(txt("done"))}},
+				Node{Type: "taskItem", Attrs: map[string]interface{}{"checked": false}, Content: []Node{paragraph(txt("todo"))}},
+			)},
+			"- [x] done\n- [ ] todo\n",
+		},
+		{
+			"code block with language and backtick fence",
+			[]Node{{Type: "codeBlock", Attrs: map[string]interface{}{"language": "go"}, Content: []Node{txt("s := \"```\"")}}},
+			"````go\ns := \"```\"\n````\n",
+		},
+		{
+			"blockquote with two paragraphs",
+			[]Node{{Type: "blockquote", Content: []Node{paragraph(txt("a")), paragraph(txt("b"))}}},
+			"> a\n>\n> b\n",
+		},
+		{
+			"image with alt and title",
+			[]Node{{Type: "image", Attrs: map[string]interface{}{"src": "https://cdn.example.com/a b.png", "alt": "A [b]", "title": "T"}}},
+			"![A \\[b\\]](<https://cdn.example.com/a b.png> \"T\")\n",
+		},
+		{
+			"horizontal rule",
+			[]Node{paragraph(txt("a")), {Type: "horizontalRule"}, paragraph(txt("b"))},
+			"a\n\n---\n\nb\n",
+		},
+		{
+			"paragraph that would become a block is escaped",
+			[]Node{paragraph(txt("# not a heading")), paragraph(txt("1986. not a list")), paragraph(txt("- not a bullet"))},
+			"\\# not a heading\n\n1986\\. not a list\n\n\\- not a bullet\n",
+		},
+		{
+			"hard break",
+			[]Node{paragraph(txt("one"), Node{Type: "hardBreak"}, txt("two"))},
+			"one\\\ntwo\n",
+		},
+		{
+			"empty document",
+			nil,
+			"",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := RenderMarkdown(&MarkdownDocument{Doc: Node{Type: "doc", Content: tc.doc}})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRenderMarkdown_Inline(t *testing.T) {
+	bold, italic := Mark{Type: "bold"}, Mark{Type: "italic"}
+	link := Mark{Type: "link", Attrs: map[string]interface{}{"href": "https://example.com"}}
+
+	cases := []struct {
+		name  string
+		nodes []Node
+		want  string
+	}{
+		{"bold", []Node{txt("b", bold)}, "**b**"},
+		{"marks spanning nodes stay open", []Node{txt("a ", bold), txt("b", bold, italic), txt(" c", bold)}, "**a *b* c**"},
+		{"whitespace moves outside marks", []Node{txt(" b ", bold)}, " **b** "},
+		{"html marks", []Node{txt("u", Mark{Type: "underline"}), txt("m", Mark{Type: "highlight"})}, "<u>u</u><mark>m</mark>"},
+		{"code with backticks", []Node{txt("a`b", Mark{Type: "code"})}, "``a`b``"},
+		{"link with bold text", []Node{txt("x", link, bold)}, "[**x**](https://example.com)"},
+		{"special characters are escaped", []Node{txt("*stars* and [brackets] <tag>")}, "\\*stars\\* and \\[brackets\\] \\<tag>"},
+		{"snake_case is left alone", []Node{txt("snake_case")}, "snake_case"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := RenderMarkdown(&MarkdownDocument{Doc: Node{Type: "doc", Content: []Node{paragraph(tc.nodes...)}}})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want+"\n", got)
+		})
+	}
+}
+
+func TestRenderMarkdown_Table(t *testing.T) {
+	// TipTap เก็บ textAlign ไว้ที่ paragraph ใน cell
+	cell := func(typ, align string, children ...Node) Node {
+		p := paragraph(children...)
+		if align != "" {
+			p.Attrs = map[string]interface{}{"textAlign": align}
+		}
+		return Node{Type: typ, Content: []Node{p}}
+	}
+	table := Node{Type: "table", Content: []Node{
+		{Type: "tableRow", Content: []Node{cell("tableHeader", "", txt("Name")), cell("tableHeader", "right", txt("Value"))}},
+		{Type: "tableRow", Content: []Node{cell("tableCell", "", txt("a | b")), cell("tableCell", "", txt("x", Mark{Type: "code"}))}},
+	}}
+
+	got, err := RenderMarkdown(&MarkdownDocument{Doc: Node{Type: "doc", Content: []Node{table}}})
+	require.NoError(t, err)
+	assert.Equal(t, "| Name | Value |\n| --- | ---: |\n| a \\| b | `x` |\n", got)
+}
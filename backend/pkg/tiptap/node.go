@@ -0,0 +1,74 @@
+package tiptap
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Node is a typed view of a TipTap JSON node.
+// It mirrors the structure the editor stores in Post.Content.
+type Node struct {
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []Node                 `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []Mark                 `json:"marks,omitempty"`
+}
+
+// Mark is an inline formatting mark such as bold or link.
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// ParseDocument decodes a stored TipTap JSON document.
+func ParseDocument(content string) (*Node, error) {
+	var doc Node
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// PlainText returns the text of the node and all its descendants.
+// Block children are separated by a single space.
+func (n Node) PlainText() string {
+	if n.Type == "text" {
+		return n.Text
+	}
+	var parts []string
+	for _, child := range n.Content {
+		if text := child.PlainText(); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if len(n.Content) > 0 && n.Content[0].Type == "text" {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts, " ")
+}
+
+// AttrString returns the attribute as a string, or "" when missing.
+func (n Node) AttrString(key string) string {
+	if n.Attrs == nil {
+		return ""
+	}
+	if v, ok := n.Attrs[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// AttrInt returns the attribute as an int, or def when missing.
+func (n Node) AttrInt(key string, def int) int {
+	if n.Attrs == nil {
+		return def
+	}
+	switch v := n.Attrs[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return def
+}
@@ -0,0 +1,10 @@
+package utils
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike ป้องกันไม่ให้ % และ _ ในคำค้นถูกตีความเป็น wildcard ของ LIKE / ILIKE
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -47,6 +47,7 @@ export interface Post extends BaseModel {
   thumbnail?: string;
   example?: string;
   content: string;
+  version?: number;
   published: boolean;
   published_at?: string | null;
   keywords?: string[];
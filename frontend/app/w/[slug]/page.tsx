@@ -2,7 +2,7 @@
 /* eslint-disable react-hooks/exhaustive-deps */
 'use client';
 
-import React, { useState, useEffect } from "react";
+import React, { useState, useEffect, useRef } from "react";
 import { JSONContent } from "@tiptap/react";
 import { useRouter, useParams } from 'next/navigation';
 import { useToast } from '@/hooks/use-toast';
@@ -15,6 +15,7 @@ import NewUserModal from "@/app/components/new-user-modal";
 import Loading from "@/app/components/loading";
 
 // Utils
+import axios from "axios";
 import { axiosInstance } from "@/lib/api";
 import { getnerateId } from "@/lib/utils";
 import { generateHtmlFromContent } from "@/app/components/tiptaps/tiptap-templates/simple/generate-html";
@@ -48,6 +49,8 @@ export default function EditPost() {
     const [lastSaved, setLastSaved] = useState<Date | null>(null);
     const [showPublishModal, setShowPublishModal] = useState(false);
     const [isLoadingOldContent, setIsLoadingOldContent] = useState(true);
+    // version ของโพสต์ที่ editor แก้อยู่ ต้องส่งไปทุกครั้งที่บันทึก
+    const versionRef = useRef(0);
     const [metadata, setMetadata] = useState<Metadata>({
         id: '',
         title: '',
@@ -89,9 +92,11 @@ export default function EditPost() {
                 short_slug: slug,
                 content: contentState,
                 title: metadata.title,
+                version: versionRef.current,
             });
 
             if (response.status === 201) {
+                versionRef.current = response.data.data.version;
                 setSaveStatus('saved');
                 setLastSaved(new Date());
                 setMetadata(prev => ({
@@ -112,10 +117,33 @@ export default function EditPost() {
 
         } catch (error) {
             setSaveStatus('error');
+            if (axios.isAxiosError(error) && error.response?.status === 409) {
+                handleVersionConflict(error.response.data.data.version);
+                return;
+            }
             console.error('Error saving content:', error);
         }
     };
 
+    // มีแท็บอื่นหรือผู้ร่วมเขียนบันทึกไปก่อน ให้เลือกโหลดฉบับล่าสุดหรือเขียนทับด้วยฉบับนี้
+    const handleVersionConflict = (serverVersion: number) => {
+        const reload = window.confirm(
+            'This post was changed in another tab or by a collaborator.\n\n' +
+            'OK: load the latest version (your unsaved changes will be lost)\n' +
+            'Cancel: keep your version and overwrite it on the next save'
+        );
+        if (reload) {
+            window.location.reload();
+            return;
+        }
+        versionRef.current = serverVersion;
+        toast({
+            title: 'Save Conflict',
+            description: 'Your version will replace the latest one on the next save.',
+            variant: 'destructive',
+        });
+    };
+
     const getPostByShortSlug = async (short_slug: string) => {
         try {
             setIsLoadingOldContent(true);
@@ -124,6 +152,7 @@ export default function EditPost() {
             if (response.status === 200) {
                 const postData = response.data.data;
                 setPost(postData);
+                versionRef.current = postData.version ?? 0;
 
                 const parsedContent = JSON.parse(postData.content);
                 setContentState(parsedContent);